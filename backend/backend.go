@@ -16,7 +16,10 @@
 package backend
 
 import (
+	"context"
 	"crypto/sha256"
+	"errors"
+	"fmt"
 	"hash"
 
 	"github.com/consensys/gnark/constraint/solver"
@@ -61,6 +64,8 @@ type ProverConfig struct {
 	KZGFoldingHash hash.Hash
 	Accelerator    string
 	StatisticalZK  bool
+	Context        context.Context // defaults to context.Background()
+	Progress       ProgressFunc    // defaults to a no-op
 }
 
 // NewProverConfig returns a default ProverConfig with given prover options opts
@@ -71,6 +76,8 @@ func NewProverConfig(opts ...ProverOption) (ProverConfig, error) {
 		// separation tags for PLONK and Groth16
 		ChallengeHash:  sha256.New(),
 		KZGFoldingHash: sha256.New(),
+		Context:        context.Background(),
+		Progress:       func(string, float64) {},
 	}
 	for _, option := range opts {
 		if err := option(&opt); err != nil {
@@ -144,6 +151,50 @@ func WithStatisticalZeroKnowledge() ProverOption {
 	}
 }
 
+// WithContext sets the context of the proof generation. The provers check it between
+// the steps of each phase (solving, multi-exponentiations, FFTs, quotient computation
+// and openings) and return ctx.Err() as soon as it is done. The Groth16 provers split
+// their multi-exponentiations in chunks and check it between them; other operations in
+// progress, such as a single FFT, are not interrupted.
+func WithContext(ctx context.Context) ProverOption {
+	return func(pc *ProverConfig) error {
+		if ctx == nil {
+			return errors.New("nil context")
+		}
+		pc.Context = ctx
+		return nil
+	}
+}
+
+// Phases of the proof generation, reported by the provers to the callback set with
+// WithProgress. The Groth16 provers report PhaseSolve, PhaseFFT and PhaseMSM; the PLONK
+// provers report PhaseSolve, PhaseMSM, PhaseFFT, PhaseQuotient and PhaseOpening.
+const (
+	PhaseSolve    = "solve"    // the constraint system is solved
+	PhaseMSM      = "msm"      // the multi-exponentiations of the witness are done
+	PhaseFFT      = "fft"      // the polynomials are evaluated on the larger domain
+	PhaseQuotient = "quotient" // the quotient polynomial is committed
+	PhaseOpening  = "opening"  // the polynomials are opened
+)
+
+// ProgressFunc receives the progress of the proof generation: it is called when a
+// phase is done, with the name of the phase and the estimated fraction of the proof
+// generation done so far, in (0, 1]. The fraction of the last phase is 1.
+type ProgressFunc func(phase string, fraction float64)
+
+// WithProgress sets the callback receiving the progress of the proof generation. The
+// callback is called from the prover goroutines, in the order of the phases, and
+// should return quickly.
+func WithProgress(f ProgressFunc) ProverOption {
+	return func(pc *ProverConfig) error {
+		if f == nil {
+			return errors.New("nil progress callback")
+		}
+		pc.Progress = f
+		return nil
+	}
+}
+
 // VerifierOption defines option for altering the behavior of the verifier. See
 // the descriptions of functions returning instances of this type for
 // implemented options.
@@ -205,3 +256,40 @@ func WithVerifierKZGFoldingHashFunction(hFunc hash.Hash) VerifierOption {
 		return nil
 	}
 }
+
+// InvalidProofError is returned by the batch verifiers (groth16.BatchVerify,
+// plonk.BatchVerify) when some of the proofs do not verify.
+type InvalidProofError struct {
+	// Indexes of the invalid proofs in the batch, in increasing order.
+	Indexes []int
+
+	// Err is the verification error of the first invalid proof.
+	Err error
+}
+
+func (e *InvalidProofError) Error() string {
+	return fmt.Sprintf("invalid proofs at indexes %v: %v", e.Indexes, e.Err)
+}
+
+func (e *InvalidProofError) Unwrap() error {
+	return e.Err
+}
+
+// NewInvalidProofError returns an *InvalidProofError for the proofs i with errs[i] != nil,
+// or nil if there is none.
+func NewInvalidProofError(errs []error) error {
+	var res *InvalidProofError
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if res == nil {
+			res = &InvalidProofError{Err: err}
+		}
+		res.Indexes = append(res.Indexes, i)
+	}
+	if res == nil {
+		return nil
+	}
+	return res
+}
@@ -0,0 +1,397 @@
+// Package cache stores compiled constraint systems and their proving and verifying keys in
+// a local directory, so that identical circuits are not recompiled and set up again on
+// every start of a service.
+//
+// A constraint system is cached under a key derived from the gnark version, the backend,
+// the scalar field, the circuit type, variables and parameters, and the compile options
+// (see Key).
+// The proving and verifying keys are cached under the content hash of the constraint
+// system (see constraint.ConstraintSystem.ContentHash), so they are only reused for the
+// exact system they were generated for.
+//
+// The cache directory is trusted: proving keys are read back without subgroup checks.
+// Circuits whose constraints depend on parameters which are not stored in the circuit
+// struct (e.g. a package variable or the state captured by a function field) must be
+// cached in distinct directories.
+//
+// Functions in this package are safe for concurrent use, including by several processes
+// sharing a directory: files are written to a temporary file and then renamed.
+package cache
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/internal/utils"
+	"github.com/consensys/gnark/logger"
+	"github.com/rs/zerolog"
+)
+
+// Cache is a compile cache in a local directory.
+type Cache struct {
+	dir string
+	log zerolog.Logger
+}
+
+// New returns a cache storing its files in dir, which is created if needed.
+func New(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &Cache{
+		dir: dir,
+		log: logger.Logger().With().Str("package", "cache").Str("dir", dir).Logger(),
+	}, nil
+}
+
+// Key returns the cache key of the constraint system of circuit compiled for the backend
+// b over the given field. It covers the gnark version, the backend, the field, the
+// circuit type, the names and visibility of its variables, the values of its other
+// fields (exported or not, e.g. a loop bound or a domain separation tag) and the compile
+// options affecting the compiled system. The values of the variables are ignored, so
+// that a circuit and its assignment have the same key.
+func Key(b backend.ID, field *big.Int, circuit frontend.Circuit, opts ...frontend.CompileOption) (string, error) {
+	var cfg frontend.CompileConfig
+	for _, o := range opts {
+		if err := o(&cfg); err != nil {
+			return "", err
+		}
+	}
+	s, err := frontend.NewSchema(circuit)
+	if err != nil {
+		return "", err
+	}
+	t := reflect.TypeOf(circuit)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	h := sha256.New()
+	writeString(h, gnark.Version.String())
+	writeString(h, b.String())
+	writeString(h, field.Text(16))
+	writeString(h, t.PkgPath()+"."+t.Name())
+	writeString(h, t.String())
+	if err := s.WriteSequence(h); err != nil {
+		return "", err
+	}
+	writeValue(h, reflect.ValueOf(circuit), make(map[uintptr]struct{}))
+	// the capacity is a hint and does not change the compiled system
+	writeUint64(h, uint64(cfg.CompressThreshold))
+	if cfg.IgnoreUnconstrainedInputs {
+		writeUint64(h, 1)
+	} else {
+		writeUint64(h, 0)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Compile returns the constraint system of circuit compiled for the backend b
+// (backend.GROTH16 or backend.PLONK) over the given field. It is read from the cache if
+// present, and compiled with frontend.Compile and cached otherwise.
+func (c *Cache) Compile(b backend.ID, field *big.Int, circuit frontend.Circuit, opts ...frontend.CompileOption) (constraint.ConstraintSystem, error) {
+	key, err := Key(b, field, circuit, opts...)
+	if err != nil {
+		return nil, err
+	}
+	curve := utils.FieldToCurve(field)
+	if curve == ecc.UNKNOWN {
+		return nil, fmt.Errorf("unsupported field %s", field.Text(16))
+	}
+
+	var (
+		ccs        constraint.ConstraintSystem
+		newBuilder frontend.NewBuilder
+	)
+	switch b {
+	case backend.GROTH16:
+		ccs, newBuilder = groth16.NewCS(curve), r1cs.NewBuilder
+	case backend.PLONK:
+		ccs, newBuilder = plonk.NewCS(curve), scs.NewBuilder
+	default:
+		return nil, fmt.Errorf("unsupported backend %s", b)
+	}
+
+	log := c.log.With().Str("key", key).Logger()
+	path := filepath.Join(c.dir, key+".ccs")
+	if err := c.read(path, ccs); err == nil {
+		log.Debug().Msg("constraint system found in cache")
+		return ccs, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		log.Warn().Err(err).Msg("reading cached constraint system, compiling")
+	}
+
+	if ccs, err = frontend.Compile(field, newBuilder, circuit, opts...); err != nil {
+		return nil, err
+	}
+	if err := c.write(path, ccs); err != nil {
+		return nil, err
+	}
+	log.Debug().Msg("constraint system cached")
+	return ccs, nil
+}
+
+// Groth16 returns the constraint system of circuit and its Groth16 proving and verifying
+// keys, from the cache if present. Otherwise, the circuit is compiled (see Compile) and
+// the keys are generated with groth16.Setup and cached.
+//
+// groth16.Setup samples a new toxic waste; cached keys are only meant for development and
+// testing, or for keys generated by a trusted setup and imported in the cache with
+// WriteGroth16Keys.
+func (c *Cache) Groth16(field *big.Int, circuit frontend.Circuit, opts ...frontend.CompileOption) (constraint.ConstraintSystem, groth16.ProvingKey, groth16.VerifyingKey, error) {
+	ccs, err := c.Compile(backend.GROTH16, field, circuit, opts...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	prefix, err := c.keysPrefix(ccs, "groth16")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	curve := utils.FieldToCurve(field)
+	pk, vk := groth16.NewProvingKey(curve), groth16.NewVerifyingKey(curve)
+	if err := c.readKeys(prefix, pk, vk); err == nil {
+		c.log.Debug().Str("keys", prefix).Msg("groth16 keys found in cache")
+		return ccs, pk, vk, nil
+	}
+
+	if pk, vk, err = groth16.Setup(ccs); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := c.writeKeys(prefix, pk, vk); err != nil {
+		return nil, nil, nil, err
+	}
+	return ccs, pk, vk, nil
+}
+
+// WriteGroth16Keys caches the Groth16 keys of the constraint system ccs, e.g. the result
+// of a MPC setup, so that they are returned by Groth16.
+func (c *Cache) WriteGroth16Keys(ccs constraint.ConstraintSystem, pk groth16.ProvingKey, vk groth16.VerifyingKey) error {
+	prefix, err := c.keysPrefix(ccs, "groth16")
+	if err != nil {
+		return err
+	}
+	return c.writeKeys(prefix, pk, vk)
+}
+
+// Plonk returns the constraint system of circuit and its PLONK proving and verifying keys
+// for the given SRS, from the cache if present. Otherwise, the circuit is compiled (see
+// Compile) and the keys are generated with plonk.Setup and cached.
+func (c *Cache) Plonk(field *big.Int, circuit frontend.Circuit, srs, srsLagrange kzg.SRS, opts ...frontend.CompileOption) (constraint.ConstraintSystem, plonk.ProvingKey, plonk.VerifyingKey, error) {
+	ccs, err := c.Compile(backend.PLONK, field, circuit, opts...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// the keys depend on the SRS
+	h := sha256.New()
+	if _, err := srs.WriteTo(h); err != nil {
+		return nil, nil, nil, err
+	}
+	if _, err := srsLagrange.WriteTo(h); err != nil {
+		return nil, nil, nil, err
+	}
+	prefix, err := c.keysPrefix(ccs, "plonk-"+hex.EncodeToString(h.Sum(nil)[:8]))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	curve := utils.FieldToCurve(field)
+	pk, vk := plonk.NewProvingKey(curve), plonk.NewVerifyingKey(curve)
+	if err := c.readKeys(prefix, pk, vk); err == nil {
+		c.log.Debug().Str("keys", prefix).Msg("plonk keys found in cache")
+		return ccs, pk, vk, nil
+	}
+
+	if pk, vk, err = plonk.Setup(ccs, srs, srsLagrange); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := c.writeKeys(prefix, pk, vk); err != nil {
+		return nil, nil, nil, err
+	}
+	return ccs, pk, vk, nil
+}
+
+// keysPrefix returns the path prefix of the key files of ccs.
+func (c *Cache) keysPrefix(ccs constraint.ConstraintSystem, scheme string) (string, error) {
+	hash, err := ccs.ContentHash()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(c.dir, hex.EncodeToString(hash)+"."+scheme), nil
+}
+
+func (c *Cache) readKeys(prefix string, pk, vk io.ReaderFrom) error {
+	if err := c.read(prefix+".vk", vk); err != nil {
+		return err
+	}
+	// the cache directory is trusted, we skip the subgroup checks of the proving key
+	if upk, ok := pk.(interface {
+		UnsafeReadFrom(io.Reader) (int64, error)
+	}); ok {
+		return c.read(prefix+".pk", readerFromFunc(upk.UnsafeReadFrom))
+	}
+	return c.read(prefix+".pk", pk)
+}
+
+func (c *Cache) writeKeys(prefix string, pk, vk io.WriterTo) error {
+	// the verifying key is read first, write it last
+	if err := c.write(prefix+".pk", pk); err != nil {
+		return err
+	}
+	return c.write(prefix+".vk", vk)
+}
+
+// read reads the object from the file path.
+func (c *Cache) read(path string, o io.ReaderFrom) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := o.ReadFrom(bufio.NewReaderSize(f, 1<<20)); err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	return nil
+}
+
+// write atomically writes the object to the file path.
+func (c *Cache) write(path string, o io.WriterTo) error {
+	f, err := os.CreateTemp(c.dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriterSize(f, 1<<20)
+	if _, err = o.WriteTo(w); err == nil {
+		err = w.Flush()
+	}
+	if errClose := f.Close(); err == nil {
+		err = errClose
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+type readerFromFunc func(io.Reader) (int64, error)
+
+func (f readerFromFunc) ReadFrom(r io.Reader) (int64, error) {
+	return f(r)
+}
+
+func writeString(w io.Writer, s string) {
+	writeUint64(w, uint64(len(s)))
+	w.Write([]byte(s))
+}
+
+func writeUint64(w io.Writer, v uint64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	w.Write(buf[:])
+}
+
+var tVariable = reflect.TypeOf((*frontend.Variable)(nil)).Elem()
+
+// writeValue writes the parameters of the circuit, that is the values of the fields
+// which are not variables, recursively. The variables are only marked, as the schema
+// already covers their names and visibility. Pointers are followed once.
+func writeValue(w io.Writer, v reflect.Value, visited map[uintptr]struct{}) {
+	if v.Type() == tVariable {
+		writeString(w, "variable")
+		return
+	}
+	writeUint64(w, uint64(v.Kind()))
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			writeUint64(w, 1)
+		} else {
+			writeUint64(w, 0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint64(w, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint64(w, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeString(w, fmt.Sprint(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		writeString(w, fmt.Sprint(v.Complex()))
+	case reflect.String:
+		writeString(w, v.String())
+	case reflect.Array, reflect.Slice:
+		writeUint64(w, uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			writeValue(w, v.Index(i), visited)
+		}
+	case reflect.Struct:
+		writeString(w, v.Type().String())
+		for i := 0; i < v.NumField(); i++ {
+			writeString(w, v.Type().Field(i).Name)
+			writeValue(w, v.Field(i), visited)
+		}
+	case reflect.Pointer:
+		if v.IsNil() {
+			writeUint64(w, 0)
+			return
+		}
+		if _, ok := visited[v.Pointer()]; ok {
+			writeUint64(w, 1)
+			return
+		}
+		visited[v.Pointer()] = struct{}{}
+		writeUint64(w, 2)
+		writeValue(w, v.Elem(), visited)
+	case reflect.Interface:
+		if v.IsNil() {
+			writeUint64(w, 0)
+			return
+		}
+		writeUint64(w, 1)
+		writeString(w, v.Elem().Type().String())
+		writeValue(w, v.Elem(), visited)
+	case reflect.Map:
+		// the entries are hashed independently and sorted, as the iteration order of
+		// maps is random
+		entries := make([]string, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			h := sha256.New()
+			writeValue(h, iter.Key(), visited)
+			writeValue(h, iter.Value(), visited)
+			entries = append(entries, string(h.Sum(nil)))
+		}
+		sort.Strings(entries)
+		writeUint64(w, uint64(len(entries)))
+		for _, e := range entries {
+			writeString(w, e)
+		}
+	default:
+		// functions, channels and unsafe pointers are only covered by their type
+		writeString(w, v.Type().String())
+	}
+}
@@ -0,0 +1,187 @@
+package cache_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/cache"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/test"
+	"github.com/consensys/gnark/test/unsafekzg"
+)
+
+type cacheCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *cacheCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(c.X, c.X, c.X)
+	cmt, err := api.(frontend.Committer).Commit(c.X, x3)
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(cmt, 0)
+	api.AssertIsEqual(api.Add(x3, c.X, 5), c.Y)
+	return nil
+}
+
+func TestCompile(t *testing.T) {
+	assert := test.NewAssert(t)
+	field := ecc.BN254.ScalarField()
+	dir := t.TempDir()
+
+	c, err := cache.New(dir)
+	assert.NoError(err)
+	ccs, err := c.Compile(backend.GROTH16, field, &cacheCircuit{})
+	assert.NoError(err)
+	hash, err := ccs.ContentHash()
+	assert.NoError(err)
+
+	// the content hash is deterministic
+	ref, err := frontend.Compile(field, r1cs.NewBuilder, &cacheCircuit{})
+	assert.NoError(err)
+	refHash, err := ref.ContentHash()
+	assert.NoError(err)
+	assert.Equal(refHash, hash)
+
+	// the cached system is reused
+	key, err := cache.Key(backend.GROTH16, field, &cacheCircuit{})
+	assert.NoError(err)
+	path := filepath.Join(dir, key+".ccs")
+	info, err := os.Stat(path)
+	assert.NoError(err)
+	cached, err := c.Compile(backend.GROTH16, field, &cacheCircuit{})
+	assert.NoError(err)
+	cachedHash, err := cached.ContentHash()
+	assert.NoError(err)
+	assert.Equal(hash, cachedHash)
+	info2, err := os.Stat(path)
+	assert.NoError(err)
+	assert.Equal(info.ModTime(), info2.ModTime(), "cached system rewritten")
+
+	// the key depends on the backend and on the compile options
+	key2, err := cache.Key(backend.PLONK, field, &cacheCircuit{})
+	assert.NoError(err)
+	assert.NotEqual(key, key2)
+	key3, err := cache.Key(backend.GROTH16, field, &cacheCircuit{}, frontend.WithCompressThreshold(10))
+	assert.NoError(err)
+	assert.NotEqual(key, key3)
+	key4, err := cache.Key(backend.GROTH16, field, &cacheCircuit{}, frontend.WithCapacity(10))
+	assert.NoError(err)
+	assert.Equal(key, key4)
+
+	// a corrupted system fails the content hash check and is compiled again
+	data, err := os.ReadFile(path)
+	assert.NoError(err)
+	data[len(data)-1] ^= 1
+	assert.NoError(os.WriteFile(path, data, 0600))
+	corrupted := groth16.NewCS(ecc.BN254)
+	_, err = corrupted.ReadFrom(bytes.NewReader(data))
+	assert.Error(err)
+	recompiled, err := c.Compile(backend.GROTH16, field, &cacheCircuit{})
+	assert.NoError(err)
+	recompiledHash, err := recompiled.ContentHash()
+	assert.NoError(err)
+	assert.Equal(hash, recompiledHash)
+}
+
+type paramCircuit struct {
+	X []frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+
+	Offset int
+	n      int
+	tag    []byte
+}
+
+func (c *paramCircuit) Define(api frontend.API) error {
+	sum := frontend.Variable(c.Offset + len(c.tag))
+	for i := 0; i < c.n; i++ {
+		sum = api.Add(sum, c.X[i])
+	}
+	api.AssertIsEqual(sum, c.Y)
+	return nil
+}
+
+func TestKeyParameters(t *testing.T) {
+	assert := test.NewAssert(t)
+	field := ecc.BN254.ScalarField()
+	key := func(c *paramCircuit) string {
+		k, err := cache.Key(backend.GROTH16, field, c)
+		assert.NoError(err)
+		return k
+	}
+	ref := key(&paramCircuit{X: make([]frontend.Variable, 4), n: 3, tag: []byte("a")})
+
+	// the values of the variables are ignored
+	assert.Equal(ref, key(&paramCircuit{X: []frontend.Variable{1, 2, 3, 4}, Y: 6, n: 3, tag: []byte("a")}))
+	// the exported and unexported parameters are covered
+	assert.NotEqual(ref, key(&paramCircuit{X: make([]frontend.Variable, 4), Offset: 1, n: 3, tag: []byte("a")}))
+	assert.NotEqual(ref, key(&paramCircuit{X: make([]frontend.Variable, 4), n: 4, tag: []byte("a")}))
+	assert.NotEqual(ref, key(&paramCircuit{X: make([]frontend.Variable, 4), n: 3, tag: []byte("b")}))
+}
+
+func TestGroth16(t *testing.T) {
+	assert := test.NewAssert(t)
+	field := ecc.BN254.ScalarField()
+	c, err := cache.New(t.TempDir())
+	assert.NoError(err)
+
+	_, _, vk, err := c.Groth16(field, &cacheCircuit{})
+	assert.NoError(err)
+	ccs, pk, cachedVK, err := c.Groth16(field, &cacheCircuit{})
+	assert.NoError(err)
+	var b1, b2 bytes.Buffer
+	_, err = vk.WriteTo(&b1)
+	assert.NoError(err)
+	_, err = cachedVK.WriteTo(&b2)
+	assert.NoError(err)
+	assert.Equal(b1.Bytes(), b2.Bytes(), "cached keys not reused")
+
+	witness, err := frontend.NewWitness(&cacheCircuit{X: 2, Y: 15}, field)
+	assert.NoError(err)
+	publicWitness, err := witness.Public()
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, witness)
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, cachedVK, publicWitness))
+}
+
+func TestPlonk(t *testing.T) {
+	assert := test.NewAssert(t)
+	field := ecc.BN254.ScalarField()
+	c, err := cache.New(t.TempDir())
+	assert.NoError(err)
+
+	ccs, err := c.Compile(backend.PLONK, field, &cacheCircuit{})
+	assert.NoError(err)
+	srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+	assert.NoError(err)
+
+	_, _, vk, err := c.Plonk(field, &cacheCircuit{}, srs, srsLagrange)
+	assert.NoError(err)
+	ccs, pk, cachedVK, err := c.Plonk(field, &cacheCircuit{}, srs, srsLagrange)
+	assert.NoError(err)
+	var b1, b2 bytes.Buffer
+	_, err = vk.WriteTo(&b1)
+	assert.NoError(err)
+	_, err = cachedVK.WriteTo(&b2)
+	assert.NoError(err)
+	assert.Equal(b1.Bytes(), b2.Bytes(), "cached keys not reused")
+
+	witness, err := frontend.NewWitness(&cacheCircuit{X: 2, Y: 15}, field)
+	assert.NoError(err)
+	publicWitness, err := witness.Public()
+	assert.NoError(err)
+	proof, err := plonk.Prove(ccs, pk, witness)
+	assert.NoError(err)
+	assert.NoError(plonk.Verify(proof, cachedVK, publicWitness))
+}
@@ -0,0 +1,50 @@
+package groth16_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/test"
+)
+
+func TestBatchVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range getCurves() {
+		assert.Run(func(assert *test.Assert) {
+			ccs, pk, vk := setupSquareCircuit(assert, curve)
+
+			const nbProofs = 6
+			proofs := make([]groth16.Proof, nbProofs)
+			publicWitnesses := make([]witness.Witness, nbProofs)
+			for i := range proofs {
+				var w witness.Witness
+				w, publicWitnesses[i] = squareWitness(assert, curve, i+2)
+				var err error
+				proofs[i], err = groth16.Prove(ccs, pk, w)
+				assert.NoError(err)
+			}
+			assert.NoError(groth16.BatchVerify(proofs, vk, publicWitnesses))
+
+			// swap the statements of two proofs
+			publicWitnesses[1], publicWitnesses[4] = publicWitnesses[4], publicWitnesses[1]
+			err := groth16.BatchVerify(proofs, vk, publicWitnesses)
+			var invalid *backend.InvalidProofError
+			assert.True(errors.As(err, &invalid), "expected an invalid proof error, got %v", err)
+			assert.Equal([]int{1, 4}, invalid.Indexes)
+
+			// a proof failing the commitment check is reported with the ones failing the pairing
+			if curve != ecc.BN254 {
+				return
+			}
+			proofs[2].(*groth16_bn254.Proof).CommitmentPok = proofs[3].(*groth16_bn254.Proof).CommitmentPok
+			err = groth16.BatchVerify(proofs, vk, publicWitnesses)
+			assert.True(errors.As(err, &invalid), "expected an invalid proof error, got %v", err)
+			assert.Equal([]int{1, 2, 4}, invalid.Indexes)
+		}, curve.String())
+	}
+}
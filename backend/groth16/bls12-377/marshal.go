@@ -21,6 +21,7 @@ import (
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/pedersen"
 	"github.com/consensys/gnark-crypto/utils/unsafe"
+	"github.com/consensys/gnark/internal/mmap"
 	"github.com/consensys/gnark/internal/utils"
 	"io"
 )
@@ -379,7 +380,160 @@ func (pk *ProvingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder))
 // correctness, but the raw bytes are platform dependent (endianness, etc.)
 func (pk *ProvingKey) WriteDump(w io.Writer) error {
 	// it behaves like WriteRawTo, excepts, the slices of points are "dumped" using gnark-crypto/utils/unsafe
+	if err := pk.writeDumpHeader(w); err != nil {
+		return err
+	}
+
+	// dump slices of points
+	if err := unsafe.WriteSlice(w, pk.G1.A); err != nil {
+		return err
+	}
+	if err := unsafe.WriteSlice(w, pk.G1.B); err != nil {
+		return err
+	}
+	if err := unsafe.WriteSlice(w, pk.G1.Z); err != nil {
+		return err
+	}
+	if err := unsafe.WriteSlice(w, pk.G1.K); err != nil {
+		return err
+	}
+	if err := unsafe.WriteSlice(w, pk.G2.B); err != nil {
+		return err
+	}
+
+	for i := range pk.CommitmentKeys {
+		if err := unsafe.WriteSlice(w, pk.CommitmentKeys[i].Basis); err != nil {
+			return err
+		}
+		if err := unsafe.WriteSlice(w, pk.CommitmentKeys[i].BasisExpSigma); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadDump reads a ProvingKey from a dump written by WriteDump.
+// This is platform dependent and very unsafe (no checks, no endianness translation, etc.)
+func (pk *ProvingKey) ReadDump(r io.Reader) error {
+	nbCommitments, err := pk.readDumpHeader(r)
+	if err != nil {
+		return err
+	}
+
+	// read slices of points
+	pk.G1.A, _, err = unsafe.ReadSlice[[]curve.G1Affine](r)
+	if err != nil {
+		return err
+	}
+	pk.G1.B, _, err = unsafe.ReadSlice[[]curve.G1Affine](r)
+	if err != nil {
+		return err
+	}
+	pk.G1.Z, _, err = unsafe.ReadSlice[[]curve.G1Affine](r)
+	if err != nil {
+		return err
+	}
+	pk.G1.K, _, err = unsafe.ReadSlice[[]curve.G1Affine](r)
+	if err != nil {
+		return err
+	}
+	pk.G2.B, _, err = unsafe.ReadSlice[[]curve.G2Affine](r)
+	if err != nil {
+		return err
+	}
+
+	pk.CommitmentKeys = make([]pedersen.ProvingKey, nbCommitments)
+	for i := range pk.CommitmentKeys {
+		pk.CommitmentKeys[i].Basis, _, err = unsafe.ReadSlice[[]curve.G1Affine](r)
+		if err != nil {
+			return err
+		}
+		pk.CommitmentKeys[i].BasisExpSigma, _, err = unsafe.ReadSlice[[]curve.G1Affine](r)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+
+}
+
+// WriteMappedDump behaves like WriteDump, excepts, the slices of points are aligned in the
+// output, so that ReadMappedDump can use them in place from a memory mapping of the file.
+// w must be at the start of the file.
+func (pk *ProvingKey) WriteMappedDump(w io.Writer) error {
+	mw := mmap.NewWriter(w)
+	if err := pk.writeDumpHeader(mw); err != nil {
+		return err
+	}
+
+	for _, s := range [][]curve.G1Affine{pk.G1.A, pk.G1.B, pk.G1.Z, pk.G1.K} {
+		if err := mmap.WriteSlice(mw, s); err != nil {
+			return err
+		}
+	}
+	if err := mmap.WriteSlice(mw, pk.G2.B); err != nil {
+		return err
+	}
+	for i := range pk.CommitmentKeys {
+		if err := mmap.WriteSlice(mw, pk.CommitmentKeys[i].Basis); err != nil {
+			return err
+		}
+		if err := mmap.WriteSlice(mw, pk.CommitmentKeys[i].BasisExpSigma); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadMappedDump maps the file at path, written by WriteMappedDump, in memory and reads the
+// ProvingKey from it. The slices of points are not copied to the heap: the provers read
+// them from the mapping, and processes mapping the same file share it through the page
+// cache. The returned io.Closer unmaps the file, after which pk must not be used.
+// As ReadDump, this is platform dependent and very unsafe.
+func (pk *ProvingKey) ReadMappedDump(path string) (io.Closer, error) {
+	m, err := mmap.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := pk.readMappedDump(mmap.NewReader(m.Bytes())); err != nil {
+		m.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+func (pk *ProvingKey) readMappedDump(r *mmap.Reader) error {
+	nbCommitments, err := pk.readDumpHeader(r)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range []*[]curve.G1Affine{&pk.G1.A, &pk.G1.B, &pk.G1.Z, &pk.G1.K} {
+		if *s, err = mmap.ReadSlice[curve.G1Affine](r); err != nil {
+			return err
+		}
+	}
+	if pk.G2.B, err = mmap.ReadSlice[curve.G2Affine](r); err != nil {
+		return err
+	}
+	pk.CommitmentKeys = make([]pedersen.ProvingKey, nbCommitments)
+	for i := range pk.CommitmentKeys {
+		if pk.CommitmentKeys[i].Basis, err = mmap.ReadSlice[curve.G1Affine](r); err != nil {
+			return err
+		}
+		if pk.CommitmentKeys[i].BasisExpSigma, err = mmap.ReadSlice[curve.G1Affine](r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
 
+// writeDumpHeader writes the unsafe marker and the ProvingKey without its slices of points.
+func (pk *ProvingKey) writeDumpHeader(w io.Writer) error {
 	// start by writing an unsafe marker to fail early.
 	if err := unsafe.WriteMarker(w); err != nil {
 		return err
@@ -417,45 +571,19 @@ func (pk *ProvingKey) WriteDump(w io.Writer) error {
 		}
 	}
 
-	// dump slices of points
-	if err := unsafe.WriteSlice(w, pk.G1.A); err != nil {
-		return err
-	}
-	if err := unsafe.WriteSlice(w, pk.G1.B); err != nil {
-		return err
-	}
-	if err := unsafe.WriteSlice(w, pk.G1.Z); err != nil {
-		return err
-	}
-	if err := unsafe.WriteSlice(w, pk.G1.K); err != nil {
-		return err
-	}
-	if err := unsafe.WriteSlice(w, pk.G2.B); err != nil {
-		return err
-	}
-
-	for i := range pk.CommitmentKeys {
-		if err := unsafe.WriteSlice(w, pk.CommitmentKeys[i].Basis); err != nil {
-			return err
-		}
-		if err := unsafe.WriteSlice(w, pk.CommitmentKeys[i].BasisExpSigma); err != nil {
-			return err
-		}
-	}
-
 	return nil
 }
 
-// ReadDump reads a ProvingKey from a dump written by WriteDump.
-// This is platform dependent and very unsafe (no checks, no endianness translation, etc.)
-func (pk *ProvingKey) ReadDump(r io.Reader) error {
+// readDumpHeader reads the header written by writeDumpHeader and returns the number of
+// commitment keys.
+func (pk *ProvingKey) readDumpHeader(r io.Reader) (uint32, error) {
 	// read the marker to fail early in case of malformed input
 	if err := unsafe.ReadMarker(r); err != nil {
-		return err
+		return 0, err
 	}
 
 	if _, err := pk.Domain.ReadFrom(r); err != nil {
-		return err
+		return 0, err
 	}
 
 	dec := curve.NewDecoder(r, curve.NoSubgroupChecks())
@@ -481,57 +609,21 @@ func (pk *ProvingKey) ReadDump(r io.Reader) error {
 
 	for _, v := range toDecode {
 		if err := dec.Decode(v); err != nil {
-			return err
+			return 0, err
 		}
 	}
 	pk.InfinityA = make([]bool, nbWires)
 	pk.InfinityB = make([]bool, nbWires)
 
 	if err := dec.Decode(&pk.InfinityA); err != nil {
-		return err
+		return 0, err
 	}
 	if err := dec.Decode(&pk.InfinityB); err != nil {
-		return err
+		return 0, err
 	}
 	if err := dec.Decode(&nbCommitments); err != nil {
-		return err
-	}
-
-	// read slices of points
-	var err error
-	pk.G1.A, _, err = unsafe.ReadSlice[[]curve.G1Affine](r)
-	if err != nil {
-		return err
-	}
-	pk.G1.B, _, err = unsafe.ReadSlice[[]curve.G1Affine](r)
-	if err != nil {
-		return err
+		return 0, err
 	}
-	pk.G1.Z, _, err = unsafe.ReadSlice[[]curve.G1Affine](r)
-	if err != nil {
-		return err
-	}
-	pk.G1.K, _, err = unsafe.ReadSlice[[]curve.G1Affine](r)
-	if err != nil {
-		return err
-	}
-	pk.G2.B, _, err = unsafe.ReadSlice[[]curve.G2Affine](r)
-	if err != nil {
-		return err
-	}
-
-	pk.CommitmentKeys = make([]pedersen.ProvingKey, nbCommitments)
-	for i := range pk.CommitmentKeys {
-		pk.CommitmentKeys[i].Basis, _, err = unsafe.ReadSlice[[]curve.G1Affine](r)
-		if err != nil {
-			return err
-		}
-		pk.CommitmentKeys[i].BasisExpSigma, _, err = unsafe.ReadSlice[[]curve.G1Affine](r)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
 
+	return nbCommitments, nil
 }
@@ -19,8 +19,10 @@ package mpcsetup
 import (
 	"crypto/sha256"
 	"errors"
+	"fmt"
 	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	mpc "github.com/consensys/gnark/internal/backend/mpcsetup/bls12-381"
 	"math"
 	"math/big"
 )
@@ -57,9 +59,9 @@ func InitPhase1(power int) (phase1 Phase1) {
 	tau.SetOne()
 	alpha.SetOne()
 	beta.SetOne()
-	phase1.PublicKeys.Tau = newPublicKey(tau, nil, 1)
-	phase1.PublicKeys.Alpha = newPublicKey(alpha, nil, 2)
-	phase1.PublicKeys.Beta = newPublicKey(beta, nil, 3)
+	phase1.PublicKeys.Tau = mpc.NewPublicKey(tau, nil, 1)
+	phase1.PublicKeys.Alpha = mpc.NewPublicKey(alpha, nil, 2)
+	phase1.PublicKeys.Beta = mpc.NewPublicKey(beta, nil, 3)
 
 	// First contribution use generators
 	_, _, g1, g2 := curve.Generators()
@@ -94,12 +96,12 @@ func (phase1 *Phase1) Contribute() {
 	tau.SetRandom()
 	alpha.SetRandom()
 	beta.SetRandom()
-	phase1.PublicKeys.Tau = newPublicKey(tau, phase1.Hash[:], 1)
-	phase1.PublicKeys.Alpha = newPublicKey(alpha, phase1.Hash[:], 2)
-	phase1.PublicKeys.Beta = newPublicKey(beta, phase1.Hash[:], 3)
+	phase1.PublicKeys.Tau = mpc.NewPublicKey(tau, phase1.Hash[:], 1)
+	phase1.PublicKeys.Alpha = mpc.NewPublicKey(alpha, phase1.Hash[:], 2)
+	phase1.PublicKeys.Beta = mpc.NewPublicKey(beta, phase1.Hash[:], 3)
 
 	// Compute powers of τ, ατ, and βτ
-	taus := powers(tau, 2*N-1)
+	taus := mpc.Powers(tau, 2*N-1)
 	alphaTau := make([]fr.Element, N)
 	betaTau := make([]fr.Element, N)
 	for i := 0; i < N; i++ {
@@ -109,10 +111,10 @@ func (phase1 *Phase1) Contribute() {
 
 	// Update using previous parameters
 	// TODO @gbotrel working with jacobian points here will help with perf.
-	scaleG1InPlace(phase1.Parameters.G1.Tau, taus)
+	mpc.ScaleG1InPlace(phase1.Parameters.G1.Tau, taus)
 	scaleG2InPlace(phase1.Parameters.G2.Tau, taus[0:N])
-	scaleG1InPlace(phase1.Parameters.G1.AlphaTau, alphaTau)
-	scaleG1InPlace(phase1.Parameters.G1.BetaTau, betaTau)
+	mpc.ScaleG1InPlace(phase1.Parameters.G1.AlphaTau, alphaTau)
+	mpc.ScaleG1InPlace(phase1.Parameters.G1.BetaTau, betaTau)
 	var betaBI big.Int
 	beta.BigInt(&betaBI)
 	phase1.Parameters.G2.Beta.ScalarMultiplication(&phase1.Parameters.G2.Beta, &betaBI)
@@ -134,55 +136,55 @@ func VerifyPhase1(c0, c1 *Phase1, c ...*Phase1) error {
 // verifyPhase1 checks that a contribution is based on a known previous Phase1 state.
 func verifyPhase1(current, contribution *Phase1) error {
 	// Compute R for τ, α, β
-	tauR := genR(contribution.PublicKeys.Tau.SG, contribution.PublicKeys.Tau.SXG, current.Hash[:], 1)
-	alphaR := genR(contribution.PublicKeys.Alpha.SG, contribution.PublicKeys.Alpha.SXG, current.Hash[:], 2)
-	betaR := genR(contribution.PublicKeys.Beta.SG, contribution.PublicKeys.Beta.SXG, current.Hash[:], 3)
+	tauR := mpc.GenR(contribution.PublicKeys.Tau.SG, contribution.PublicKeys.Tau.SXG, current.Hash[:], 1)
+	alphaR := mpc.GenR(contribution.PublicKeys.Alpha.SG, contribution.PublicKeys.Alpha.SXG, current.Hash[:], 2)
+	betaR := mpc.GenR(contribution.PublicKeys.Beta.SG, contribution.PublicKeys.Beta.SXG, current.Hash[:], 3)
 
 	// Check for knowledge of toxic parameters
-	if !sameRatio(contribution.PublicKeys.Tau.SG, contribution.PublicKeys.Tau.SXG, contribution.PublicKeys.Tau.XR, tauR) {
-		return errors.New("couldn't verify public key of τ")
+	if err := mpc.SameRatio(contribution.PublicKeys.Tau.SG, contribution.PublicKeys.Tau.SXG, contribution.PublicKeys.Tau.XR, tauR); err != nil {
+		return fmt.Errorf("couldn't verify public key of τ: %w", err)
 	}
-	if !sameRatio(contribution.PublicKeys.Alpha.SG, contribution.PublicKeys.Alpha.SXG, contribution.PublicKeys.Alpha.XR, alphaR) {
-		return errors.New("couldn't verify public key of α")
+	if err := mpc.SameRatio(contribution.PublicKeys.Alpha.SG, contribution.PublicKeys.Alpha.SXG, contribution.PublicKeys.Alpha.XR, alphaR); err != nil {
+		return fmt.Errorf("couldn't verify public key of α: %w", err)
 	}
-	if !sameRatio(contribution.PublicKeys.Beta.SG, contribution.PublicKeys.Beta.SXG, contribution.PublicKeys.Beta.XR, betaR) {
-		return errors.New("couldn't verify public key of β")
+	if err := mpc.SameRatio(contribution.PublicKeys.Beta.SG, contribution.PublicKeys.Beta.SXG, contribution.PublicKeys.Beta.XR, betaR); err != nil {
+		return fmt.Errorf("couldn't verify public key of β: %w", err)
 	}
 
 	// Check for valid updates using previous parameters
-	if !sameRatio(contribution.Parameters.G1.Tau[1], current.Parameters.G1.Tau[1], tauR, contribution.PublicKeys.Tau.XR) {
-		return errors.New("couldn't verify that [τ]₁ is based on previous contribution")
+	if err := mpc.SameRatio(contribution.Parameters.G1.Tau[1], current.Parameters.G1.Tau[1], tauR, contribution.PublicKeys.Tau.XR); err != nil {
+		return fmt.Errorf("couldn't verify that [τ]₁ is based on previous contribution: %w", err)
 	}
-	if !sameRatio(contribution.Parameters.G1.AlphaTau[0], current.Parameters.G1.AlphaTau[0], alphaR, contribution.PublicKeys.Alpha.XR) {
-		return errors.New("couldn't verify that [α]₁ is based on previous contribution")
+	if err := mpc.SameRatio(contribution.Parameters.G1.AlphaTau[0], current.Parameters.G1.AlphaTau[0], alphaR, contribution.PublicKeys.Alpha.XR); err != nil {
+		return fmt.Errorf("couldn't verify that [α]₁ is based on previous contribution: %w", err)
 	}
-	if !sameRatio(contribution.Parameters.G1.BetaTau[0], current.Parameters.G1.BetaTau[0], betaR, contribution.PublicKeys.Beta.XR) {
-		return errors.New("couldn't verify that [β]₁ is based on previous contribution")
+	if err := mpc.SameRatio(contribution.Parameters.G1.BetaTau[0], current.Parameters.G1.BetaTau[0], betaR, contribution.PublicKeys.Beta.XR); err != nil {
+		return fmt.Errorf("couldn't verify that [β]₁ is based on previous contribution: %w", err)
 	}
-	if !sameRatio(contribution.PublicKeys.Tau.SG, contribution.PublicKeys.Tau.SXG, contribution.Parameters.G2.Tau[1], current.Parameters.G2.Tau[1]) {
-		return errors.New("couldn't verify that [τ]₂ is based on previous contribution")
+	if err := mpc.SameRatio(contribution.PublicKeys.Tau.SG, contribution.PublicKeys.Tau.SXG, contribution.Parameters.G2.Tau[1], current.Parameters.G2.Tau[1]); err != nil {
+		return fmt.Errorf("couldn't verify that [τ]₂ is based on previous contribution: %w", err)
 	}
-	if !sameRatio(contribution.PublicKeys.Beta.SG, contribution.PublicKeys.Beta.SXG, contribution.Parameters.G2.Beta, current.Parameters.G2.Beta) {
-		return errors.New("couldn't verify that [β]₂ is based on previous contribution")
+	if err := mpc.SameRatio(contribution.PublicKeys.Beta.SG, contribution.PublicKeys.Beta.SXG, contribution.Parameters.G2.Beta, current.Parameters.G2.Beta); err != nil {
+		return fmt.Errorf("couldn't verify that [β]₂ is based on previous contribution: %w", err)
 	}
 
 	// Check for valid updates using powers of τ
 	_, _, g1, g2 := curve.Generators()
-	tauL1, tauL2 := linearCombinationG1(contribution.Parameters.G1.Tau)
-	if !sameRatio(tauL1, tauL2, contribution.Parameters.G2.Tau[1], g2) {
-		return errors.New("couldn't verify valid powers of τ in G₁")
+	tauL1, tauL2 := mpc.LinearCombinationG1(contribution.Parameters.G1.Tau)
+	if err := mpc.SameRatio(tauL1, tauL2, contribution.Parameters.G2.Tau[1], g2); err != nil {
+		return fmt.Errorf("couldn't verify valid powers of τ in G₁: %w", err)
 	}
-	alphaL1, alphaL2 := linearCombinationG1(contribution.Parameters.G1.AlphaTau)
-	if !sameRatio(alphaL1, alphaL2, contribution.Parameters.G2.Tau[1], g2) {
-		return errors.New("couldn't verify valid powers of α(τ) in G₁")
+	alphaL1, alphaL2 := mpc.LinearCombinationG1(contribution.Parameters.G1.AlphaTau)
+	if err := mpc.SameRatio(alphaL1, alphaL2, contribution.Parameters.G2.Tau[1], g2); err != nil {
+		return fmt.Errorf("couldn't verify valid powers of α(τ) in G₁: %w", err)
 	}
-	betaL1, betaL2 := linearCombinationG1(contribution.Parameters.G1.BetaTau)
-	if !sameRatio(betaL1, betaL2, contribution.Parameters.G2.Tau[1], g2) {
-		return errors.New("couldn't verify valid powers of α(τ) in G₁")
+	betaL1, betaL2 := mpc.LinearCombinationG1(contribution.Parameters.G1.BetaTau)
+	if err := mpc.SameRatio(betaL1, betaL2, contribution.Parameters.G2.Tau[1], g2); err != nil {
+		return fmt.Errorf("couldn't verify valid powers of α(τ) in G₁: %w", err)
 	}
 	tau2L1, tau2L2 := linearCombinationG2(contribution.Parameters.G2.Tau)
-	if !sameRatio(contribution.Parameters.G1.Tau[1], g1, tau2L1, tau2L2) {
-		return errors.New("couldn't verify valid powers of τ in G₂")
+	if err := mpc.SameRatio(contribution.Parameters.G1.Tau[1], g1, tau2L1, tau2L2); err != nil {
+		return fmt.Errorf("couldn't verify valid powers of τ in G₂: %w", err)
 	}
 
 	// Check hash of the contribution
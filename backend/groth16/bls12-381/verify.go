@@ -19,7 +19,11 @@ package groth16
 import (
 	"errors"
 	"fmt"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"hash"
 	"io"
+	"math/big"
+	"text/template"
 	"time"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -27,10 +31,10 @@ import (
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/hash_to_field"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/pedersen"
-	"github.com/consensys/gnark-crypto/utils"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/solidity"
 	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/internal/utils"
 	"github.com/consensys/gnark/logger"
 )
 
@@ -73,9 +77,161 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 		close(chDone)
 	}()
 
+	kSumAff, err := vk.publicInputsCommitment(proof, publicWitness, opt.HashToFieldFn)
+	if err != nil {
+		return err
+	}
+
+	// compute e(Σx.[Kvk(t)]1, -[γ]2)
+	right, err := curve.MillerLoop([]curve.G1Affine{kSumAff}, []curve.G2Affine{vk.G2.gammaNeg})
+	if err != nil {
+		return err
+	}
+
+	// wait for (eKrsδ, eArBs)
+	if err := <-chDone; err != nil {
+		return err
+	}
+
+	right = curve.FinalExponentiation(&right, &doubleML)
+	if !vk.e.Equal(&right) {
+		return errPairingCheckFailed
+	}
+
+	log.Debug().Dur("took", time.Since(start)).Msg("verifier done")
+	return nil
+}
+
+// BatchVerify verifies proofs with given VerifyingKey and publicWitnesses, in a single
+// multi-pairing: the pairing equations of the proofs are combined with random
+// coefficients. The proofs of knowledge of the commitments, if any, are checked one by one.
+//
+// If some proofs are invalid, it returns a *backend.InvalidProofError with the indexes of
+// all of them: the ones failing the subgroup or commitment checks, and the ones failing
+// the pairing check, found by bisection.
+func BatchVerify(proofs []*Proof, vk *VerifyingKey, publicWitnesses []fr.Vector, opts ...backend.VerifierOption) error {
+	if len(proofs) != len(publicWitnesses) {
+		return fmt.Errorf("got %d proofs and %d public witnesses", len(proofs), len(publicWitnesses))
+	}
+	if len(proofs) == 0 {
+		return nil
+	}
+	opt, err := backend.NewVerifierConfig(opts...)
+	if err != nil {
+		return fmt.Errorf("new verifier config: %w", err)
+	}
+	if opt.HashToFieldFn == nil {
+		opt.HashToFieldFn = hash_to_field.New([]byte(constraint.CommitmentDst))
+	}
+
+	nbPublicVars := len(vk.G1.K) - len(vk.PublicAndCommitmentCommitted)
+	for i := range publicWitnesses {
+		if len(publicWitnesses[i]) != nbPublicVars-1 {
+			return fmt.Errorf("invalid witness size for proof %d, got %d, expected %d (public - ONE_WIRE)", i, len(publicWitnesses[i]), len(vk.G1.K)-1)
+		}
+	}
+	log := logger.Logger().With().Str("curve", vk.CurveID().String()).Str("backend", "groth16").Int("nbProofs", len(proofs)).Logger()
+	start := time.Now()
+
+	// the proofs failing their own checks are reported along with the ones failing the
+	// pairing check, which is done on the others.
+	errs := make([]error, len(proofs))
+	valid := make([]int, 0, len(proofs))
+	kSums := make([]curve.G1Affine, 0, len(proofs))
+	for i := range proofs {
+		if !proofs[i].isValid() {
+			errs[i] = errCorrectSubgroupCheckFailed
+			continue
+		}
+		kSum, err := vk.publicInputsCommitment(proofs[i], publicWitnesses[i], opt.HashToFieldFn)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		valid = append(valid, i)
+		kSums = append(kSums, kSum)
+	}
+
+	validProofs := make([]*Proof, len(valid))
+	for j, i := range valid {
+		validProofs[j] = proofs[i]
+	}
+	check := func(start, end int) (bool, error) {
+		return vk.batchPairingCheck(validProofs[start:end], kSums[start:end])
+	}
+	if len(valid) != 0 {
+		ok, err := check(0, len(valid))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			invalid, err := utils.Bisect(len(valid), check)
+			if err != nil {
+				return err
+			}
+			for _, j := range invalid {
+				errs[valid[j]] = errPairingCheckFailed
+			}
+		}
+	}
+	if err := backend.NewInvalidProofError(errs); err != nil {
+		return err
+	}
+
+	log.Debug().Dur("took", time.Since(start)).Msg("batch verifier done")
+	return nil
+}
+
+// batchPairingCheck checks the random linear combination of the pairing equations of the
+// proofs, with random ρᵢ:
+//
+//	∏ e(ρᵢ·Arᵢ, Bsᵢ) · e(∑ ρᵢ·[Kvk(tᵢ)]₁, -[γ]₂) · e(∑ ρᵢ·Krsᵢ, -[δ]₂) · e(-(∑ ρᵢ)·[α]₁, [β]₂) = 1
+func (vk *VerifyingKey) batchPairingCheck(proofs []*Proof, kSums []curve.G1Affine) (bool, error) {
+	n := len(proofs)
+	rho := make([]fr.Element, n)
+	var rhoSum fr.Element
+	for i := range rho {
+		if _, err := rho[i].SetRandom(); err != nil {
+			return false, err
+		}
+		rhoSum.Add(&rhoSum, &rho[i])
+	}
+
+	P := make([]curve.G1Affine, n+3)
+	Q := make([]curve.G2Affine, n+3)
+	krs := make([]curve.G1Affine, n)
+	utils.Parallelize(n, func(start, end int) {
+		var r big.Int
+		for i := start; i < end; i++ {
+			P[i].ScalarMultiplication(&proofs[i].Ar, rho[i].BigInt(&r))
+			Q[i] = proofs[i].Bs
+			krs[i] = proofs[i].Krs
+		}
+	})
+	if _, err := P[n].MultiExp(kSums, rho, ecc.MultiExpConfig{}); err != nil {
+		return false, err
+	}
+	Q[n] = vk.G2.gammaNeg
+	if _, err := P[n+1].MultiExp(krs, rho, ecc.MultiExpConfig{}); err != nil {
+		return false, err
+	}
+	Q[n+1] = vk.G2.deltaNeg
+	var r big.Int
+	P[n+2].ScalarMultiplication(&vk.G1.Alpha, rhoSum.BigInt(&r))
+	P[n+2].Neg(&P[n+2])
+	Q[n+2] = vk.G2.Beta
+
+	return curve.PairingCheck(P, Q)
+}
+
+// publicInputsCommitment checks the commitments of the proof and returns
+// [Kvk(t)]₁ = [K₀]₁ + ∑ tᵢ·[Kᵢ]₁ + ∑ commitments, where t are the public inputs completed
+// with the hashes of the commitments.
+func (vk *VerifyingKey) publicInputsCommitment(proof *Proof, publicWitness fr.Vector, hashToField hash.Hash) (curve.G1Affine, error) {
+	var kSumAff curve.G1Affine
 	maxNbPublicCommitted := 0
 	for _, s := range vk.PublicAndCommitmentCommitted { // iterate over commitments
-		maxNbPublicCommitted = utils.Max(maxNbPublicCommitted, len(s))
+		maxNbPublicCommitted = max(maxNbPublicCommitted, len(s))
 	}
 	commitmentsSerialized := make([]byte, len(vk.PublicAndCommitmentCommitted)*fr.Bytes)
 	commitmentPrehashSerialized := make([]byte, curve.SizeOfG1AffineUncompressed+maxNbPublicCommitted*fr.Bytes)
@@ -86,12 +242,12 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 			copy(commitmentPrehashSerialized[offset:], publicWitness[vk.PublicAndCommitmentCommitted[i][j]-1].Marshal())
 			offset += fr.Bytes
 		}
-		opt.HashToFieldFn.Write(commitmentPrehashSerialized[:offset])
-		hashBts := opt.HashToFieldFn.Sum(nil)
-		opt.HashToFieldFn.Reset()
+		hashToField.Write(commitmentPrehashSerialized[:offset])
+		hashBts := hashToField.Sum(nil)
+		hashToField.Reset()
 		nbBuf := fr.Bytes
-		if opt.HashToFieldFn.Size() < fr.Bytes {
-			nbBuf = opt.HashToFieldFn.Size()
+		if hashToField.Size() < fr.Bytes {
+			nbBuf = hashToField.Size()
 		}
 		var res fr.Element
 		res.SetBytes(hashBts[:nbBuf])
@@ -100,17 +256,17 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 	}
 
 	if folded, err := pedersen.FoldCommitments(proof.Commitments, commitmentsSerialized); err != nil {
-		return err
+		return kSumAff, err
 	} else {
 		if err = vk.CommitmentKey.Verify(folded, proof.CommitmentPok); err != nil {
-			return err
+			return kSumAff, err
 		}
 	}
 
-	// compute e(Σx.[Kvk(t)]1, -[γ]2)
+	// compute Σx.[Kvk(t)]1
 	var kSum curve.G1Jac
 	if _, err := kSum.MultiExp(vk.G1.K[1:], publicWitness, ecc.MultiExpConfig{}); err != nil {
-		return err
+		return kSumAff, err
 	}
 	kSum.AddMixed(&vk.G1.K[0])
 
@@ -118,29 +274,82 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 		kSum.AddMixed(&proof.Commitments[i])
 	}
 
-	var kSumAff curve.G1Affine
 	kSumAff.FromJacobian(&kSum)
+	return kSumAff, nil
+}
 
-	right, err := curve.MillerLoop([]curve.G1Affine{kSumAff}, []curve.G2Affine{vk.G2.gammaNeg})
+// ExportSolidity writes a solidity Verifier contract on provided writer.
+// This is an experimental feature and gnark solidity generator as not been thoroughly tested.
+//
+// See https://github.com/ConsenSys/gnark-tests for example usage.
+func (vk *VerifyingKey) ExportSolidity(w io.Writer, exportOpts ...solidity.ExportOption) error {
+	helpers := template.FuncMap{
+		"sum": func(a, b int) int {
+			return a + b
+		},
+		"sub": func(a, b int) int {
+			return a - b
+		},
+		"mul": func(a, b int) int {
+			return a * b
+		},
+		"intRange": func(max int) []int {
+			out := make([]int, max)
+			for i := 0; i < max; i++ {
+				out[i] = i
+			}
+			return out
+		},
+		// EIP-2537 encodes base field elements on 64 bytes, which we split
+		// into the high and low 32-byte words.
+		"fphi": func(x fp.Element) string {
+			b := x.Bytes()
+			return new(big.Int).SetBytes(b[:fp.Bytes-32]).String()
+		},
+		"fplo": func(x fp.Element) string {
+			b := x.Bytes()
+			return new(big.Int).SetBytes(b[fp.Bytes-32:]).String()
+		},
+	}
+
+	cfg, err := solidity.NewExportConfig(exportOpts...)
 	if err != nil {
 		return err
 	}
 
-	// wait for (eKrsδ, eArBs)
-	if err := <-chDone; err != nil {
-		return err
+	log := logger.Logger()
+	if len(vk.PublicAndCommitmentCommitted) > 1 {
+		log.Warn().Msg("exporting solidity verifier with more than one commitment is not supported")
+	} else if len(vk.PublicAndCommitmentCommitted) == 1 && cfg.HashToField == 0 {
+		log.Warn().Msg("exporting solidity verifier with `sha256` as `HashToField`. Use solidity.WithHashToFieldFunction if the proofs are generated with another hash function.")
 	}
 
-	right = curve.FinalExponentiation(&right, &doubleML)
-	if !vk.e.Equal(&right) {
-		return errPairingCheckFailed
+	tmpl, err := template.New("").Funcs(helpers).Parse(solidityTemplate)
+	if err != nil {
+		return err
 	}
 
-	log.Debug().Dur("took", time.Since(start)).Msg("verifier done")
-	return nil
-}
+	// negate Beta, Gamma and Delta, to avoid negating proof elements in the verifier
+	var betaNeg curve.G2Affine
+	betaNeg.Neg(&vk.G2.Beta)
+	beta := vk.G2.Beta
+	vk.G2.Beta = betaNeg
+	vk.G2.Gamma, vk.G2.gammaNeg = vk.G2.gammaNeg, vk.G2.Gamma
+	vk.G2.Delta, vk.G2.deltaNeg = vk.G2.deltaNeg, vk.G2.Delta
 
-// ExportSolidity not implemented for BLS12-381
-func (vk *VerifyingKey) ExportSolidity(w io.Writer, exportOpts ...solidity.ExportOption) error {
-	return errors.New("not implemented")
+	// execute template
+	err = tmpl.Execute(w, struct {
+		Cfg solidity.ExportConfig
+		Vk  VerifyingKey
+	}{
+		Cfg: cfg,
+		Vk:  *vk,
+	})
+
+	// restore Beta, Gamma and Delta
+	vk.G2.Beta = beta
+	vk.G2.Gamma, vk.G2.gammaNeg = vk.G2.gammaNeg, vk.G2.Gamma
+	vk.G2.Delta, vk.G2.deltaNeg = vk.G2.deltaNeg, vk.G2.Delta
+
+	return err
 }
@@ -19,12 +19,14 @@ package mpcsetup
 import (
 	"crypto/sha256"
 	"errors"
+	"fmt"
 	"math/big"
 
 	curve "github.com/consensys/gnark-crypto/ecc/bls24-317"
 	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
 	"github.com/consensys/gnark/constraint"
 	cs "github.com/consensys/gnark/constraint/bls24-317"
+	mpc "github.com/consensys/gnark/internal/backend/mpcsetup/bls24-317"
 )
 
 type Phase2Evaluations struct {
@@ -171,7 +173,7 @@ func InitPhase2(r1cs *cs.R1CS, srs1 *Phase1) (Phase2, Phase2Evaluations) {
 	// Set δ public key
 	var delta fr.Element
 	delta.SetOne()
-	c2.PublicKey = newPublicKey(delta, nil, 1)
+	c2.PublicKey = mpc.NewPublicKey(delta, nil, 1)
 
 	// Hash initial contribution
 	c2.Hash = c2.hash()
@@ -189,7 +191,7 @@ func (c *Phase2) Contribute() {
 	deltaInv.BigInt(&deltaInvBI)
 
 	// Set δ public key
-	c.PublicKey = newPublicKey(delta, c.Hash, 1)
+	c.PublicKey = mpc.NewPublicKey(delta, c.Hash, 1)
 
 	// Update δ
 	c.Parameters.G1.Delta.ScalarMultiplication(&c.Parameters.G1.Delta, &deltaBI)
@@ -221,29 +223,29 @@ func VerifyPhase2(c0, c1 *Phase2, c ...*Phase2) error {
 
 func verifyPhase2(current, contribution *Phase2) error {
 	// Compute R for δ
-	deltaR := genR(contribution.PublicKey.SG, contribution.PublicKey.SXG, current.Hash[:], 1)
+	deltaR := mpc.GenR(contribution.PublicKey.SG, contribution.PublicKey.SXG, current.Hash[:], 1)
 
 	// Check for knowledge of δ
-	if !sameRatio(contribution.PublicKey.SG, contribution.PublicKey.SXG, contribution.PublicKey.XR, deltaR) {
-		return errors.New("couldn't verify knowledge of δ")
+	if err := mpc.SameRatio(contribution.PublicKey.SG, contribution.PublicKey.SXG, contribution.PublicKey.XR, deltaR); err != nil {
+		return fmt.Errorf("couldn't verify knowledge of δ: %w", err)
 	}
 
 	// Check for valid updates using previous parameters
-	if !sameRatio(contribution.Parameters.G1.Delta, current.Parameters.G1.Delta, deltaR, contribution.PublicKey.XR) {
-		return errors.New("couldn't verify that [δ]₁ is based on previous contribution")
+	if err := mpc.SameRatio(contribution.Parameters.G1.Delta, current.Parameters.G1.Delta, deltaR, contribution.PublicKey.XR); err != nil {
+		return fmt.Errorf("couldn't verify that [δ]₁ is based on previous contribution: %w", err)
 	}
-	if !sameRatio(contribution.PublicKey.SG, contribution.PublicKey.SXG, contribution.Parameters.G2.Delta, current.Parameters.G2.Delta) {
-		return errors.New("couldn't verify that [δ]₂ is based on previous contribution")
+	if err := mpc.SameRatio(contribution.PublicKey.SG, contribution.PublicKey.SXG, contribution.Parameters.G2.Delta, current.Parameters.G2.Delta); err != nil {
+		return fmt.Errorf("couldn't verify that [δ]₂ is based on previous contribution: %w", err)
 	}
 
 	// Check for valid updates of L and Z using
 	L, prevL := merge(contribution.Parameters.G1.L, current.Parameters.G1.L)
-	if !sameRatio(L, prevL, contribution.Parameters.G2.Delta, current.Parameters.G2.Delta) {
-		return errors.New("couldn't verify valid updates of L using δ⁻¹")
+	if err := mpc.SameRatio(L, prevL, contribution.Parameters.G2.Delta, current.Parameters.G2.Delta); err != nil {
+		return fmt.Errorf("couldn't verify valid updates of L using δ⁻¹: %w", err)
 	}
 	Z, prevZ := merge(contribution.Parameters.G1.Z, current.Parameters.G1.Z)
-	if !sameRatio(Z, prevZ, contribution.Parameters.G2.Delta, current.Parameters.G2.Delta) {
-		return errors.New("couldn't verify valid updates of L using δ⁻¹")
+	if err := mpc.SameRatio(Z, prevZ, contribution.Parameters.G2.Delta, current.Parameters.G2.Delta); err != nil {
+		return fmt.Errorf("couldn't verify valid updates of L using δ⁻¹: %w", err)
 	}
 
 	// Check hash of the contribution
@@ -17,6 +17,7 @@
 package groth16
 
 import (
+	"context"
 	"fmt"
 	"github.com/consensys/gnark-crypto/ecc"
 	curve "github.com/consensys/gnark-crypto/ecc/bls24-317"
@@ -34,6 +35,7 @@ import (
 	"github.com/consensys/gnark/logger"
 	"math/big"
 	"runtime"
+	"sort"
 	"time"
 
 	fcs "github.com/consensys/gnark/frontend/cs"
@@ -76,6 +78,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	proof := &Proof{Commitments: make([]curve.G1Affine, len(commitmentInfo))}
 
 	solverOpts := opt.SolverOpts[:len(opt.SolverOpts):len(opt.SolverOpts)]
+	solverOpts = append(solverOpts, solver.WithContext(opt.Context))
 
 	privateCommittedValues := make([][]fr.Element, len(commitmentInfo))
 
@@ -116,18 +119,41 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 
 	solution := _solution.(*cs.R1CSSolution)
 	wireValues := []fr.Element(solution.W)
+	if solution.Wires != nil {
+		defer solution.Wires.Close()
+	}
+	if err := opt.Context.Err(); err != nil {
+		return nil, err
+	}
+	opt.Progress(backend.PhaseSolve, 0.2)
 
 	start := time.Now()
 
 	commitmentsSerialized := make([]byte, fr.Bytes*len(commitmentInfo))
 	for i := range commitmentInfo {
-		copy(commitmentsSerialized[fr.Bytes*i:], wireValues[commitmentInfo[i].CommitmentIndex].Marshal())
+		var v fr.Element
+		if solution.Wires != nil {
+			if v, err = solution.Wires.Get(commitmentInfo[i].CommitmentIndex); err != nil {
+				return nil, err
+			}
+		} else {
+			v = wireValues[commitmentInfo[i].CommitmentIndex]
+		}
+		copy(commitmentsSerialized[fr.Bytes*i:], v.Marshal())
 	}
 
 	if proof.CommitmentPok, err = pedersen.BatchProve(pk.CommitmentKeys, privateCommittedValues, commitmentsSerialized); err != nil {
 		return nil, err
 	}
 
+	if solution.Wires != nil {
+		if err := proveFromWireStore(r1cs, pk, proof, solution, &opt); err != nil {
+			return nil, err
+		}
+		log.Debug().Dur("took", time.Since(start)).Msg("prover done")
+		return proof, nil
+	}
+
 	// H (witness reduction / FFT part)
 	var h []fr.Element
 	chHDone := make(chan struct{}, 1)
@@ -191,7 +217,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	chBs1Done := make(chan error, 1)
 	computeBS1 := func() {
 		<-chWireValuesB
-		if _, err := bs1.MultiExp(pk.G1.B, wireValuesB, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
+		if err := multiExpG1(opt.Context, &bs1, pk.G1.B, wireValuesB, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
 			chBs1Done <- err
 			close(chBs1Done)
 			return
@@ -204,7 +230,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	chArDone := make(chan error, 1)
 	computeAR1 := func() {
 		<-chWireValuesA
-		if _, err := ar.MultiExp(pk.G1.A, wireValuesA, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
+		if err := multiExpG1(opt.Context, &ar, pk.G1.A, wireValuesA, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
 			chArDone <- err
 			close(chArDone)
 			return
@@ -224,8 +250,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 		chKrs2Done := make(chan error, 1)
 		sizeH := int(pk.Domain.Cardinality - 1) // comes from the fact the deg(H)=(n-1)+(n-1)-n=n-2
 		go func() {
-			_, err := krs2.MultiExp(pk.G1.Z, h[:sizeH], ecc.MultiExpConfig{NbTasks: n / 2})
-			chKrs2Done <- err
+			chKrs2Done <- multiExpG1(opt.Context, &krs2, pk.G1.Z, h[:sizeH], ecc.MultiExpConfig{NbTasks: n / 2})
 		}()
 
 		// filter the wire values if needed
@@ -234,7 +259,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 		toRemove = append(toRemove, commitmentInfo.CommitmentIndexes())
 		_wireValues := filterHeap(wireValues[r1cs.GetNbPublicVariables():], r1cs.GetNbPublicVariables(), internal.ConcatAll(toRemove...))
 
-		if _, err := krs.MultiExp(pk.G1.K, _wireValues, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
+		if err := multiExpG1(opt.Context, &krs, pk.G1.K, _wireValues, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
 			chKrsDone <- err
 			return
 		}
@@ -280,7 +305,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 			nbTasks *= 2
 		}
 		<-chWireValuesB
-		if _, err := Bs.MultiExp(pk.G2.B, wireValuesB, ecc.MultiExpConfig{NbTasks: nbTasks}); err != nil {
+		if err := multiExpG2(opt.Context, &Bs, pk.G2.B, wireValuesB, ecc.MultiExpConfig{NbTasks: nbTasks}); err != nil {
 			return err
 		}
 
@@ -295,6 +320,10 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 
 	// wait for FFT to end, as it uses all our CPUs
 	<-chHDone
+	if err := opt.Context.Err(); err != nil {
+		return nil, err
+	}
+	opt.Progress(backend.PhaseFFT, 0.4)
 
 	// schedule our proof part computations
 	go computeKRS()
@@ -308,16 +337,240 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	if err := <-chKrsDone; err != nil {
 		return nil, err
 	}
+	opt.Progress(backend.PhaseMSM, 1)
 
 	log.Debug().Dur("took", time.Since(start)).Msg("prover done")
 
 	return proof, nil
 }
 
+// Rerandomize returns a proof of the same statement as proof, which can't be linked to it
+// without knowing the randomness. With random r, s in 𝔽ᵣ,
+//
+//	Ar' = r⁻¹·Ar, Bs' = r·Bs + rs·[δ]₂, Krs' = Krs + s·Ar
+//
+// so that e(Ar', Bs') = e(Ar, Bs)·e(s·Ar, [δ]₂) and the result verifies if proof does.
+//
+// The BSB22 commitments are bound to the public inputs through the commitment challenges,
+// and their proof of knowledge is determined by the commitments: they are kept as is, and
+// remain linkable.
+func Rerandomize(proof *Proof, vk *VerifyingKey) (*Proof, error) {
+	var r, s, rInv, rs fr.Element
+	if _, err := r.SetRandom(); err != nil {
+		return nil, err
+	}
+	if _, err := s.SetRandom(); err != nil {
+		return nil, err
+	}
+	rInv.Inverse(&r)
+	rs.Mul(&r, &s)
+
+	res := &Proof{
+		Commitments:   append([]curve.G1Affine(nil), proof.Commitments...),
+		CommitmentPok: proof.CommitmentPok,
+	}
+
+	var b big.Int
+	res.Ar.ScalarMultiplication(&proof.Ar, rInv.BigInt(&b))
+
+	var deltaRS curve.G2Affine
+	deltaRS.ScalarMultiplication(&vk.G2.Delta, rs.BigInt(&b))
+	res.Bs.ScalarMultiplication(&proof.Bs, r.BigInt(&b))
+	res.Bs.Add(&res.Bs, &deltaRS)
+
+	var sAr curve.G1Affine
+	sAr.ScalarMultiplication(&proof.Ar, s.BigInt(&b))
+	res.Krs.Add(&proof.Krs, &sAr)
+
+	return res, nil
+}
+
+// proveFromWireStore computes Ar, Bs and Krs when the wire values are in a disk-backed
+// store (see solver.WithDiskStore). The wire values are read back chunk by chunk, and the
+// multi-exps are accumulated over the chunks, so that the wire values are never all in memory.
+func proveFromWireStore(r1cs *cs.R1CS, pk *ProvingKey, proof *Proof, solution *cs.R1CSSolution, opt *backend.ProverConfig) error {
+	// H (witness reduction / FFT part)
+	h := computeH(solution.A, solution.B, solution.C, &pk.Domain)
+	solution.A = nil
+	solution.B = nil
+	solution.C = nil
+	if err := opt.Context.Err(); err != nil {
+		return err
+	}
+	opt.Progress(backend.PhaseFFT, 0.4)
+
+	// sample random r and s
+	var r, s big.Int
+	var _r, _s, _kr fr.Element
+	if _, err := _r.SetRandom(); err != nil {
+		return err
+	}
+	if _, err := _s.SetRandom(); err != nil {
+		return err
+	}
+	_kr.Mul(&_r, &_s).Neg(&_kr)
+
+	_r.BigInt(&r)
+	_s.BigInt(&s)
+
+	// computes r[δ], s[δ], kr[δ]
+	deltas := curve.BatchScalarMultiplicationG1(&pk.G1.Delta, []fr.Element{_r, _s, _kr})
+
+	// the committed wires are not in pk.G1.K
+	commitmentInfo := r1cs.CommitmentInfo.(constraint.Groth16Commitments)
+	toRemove := commitmentInfo.GetPrivateCommitted()
+	toRemove = append(toRemove, commitmentInfo.CommitmentIndexes())
+	removed := internal.ConcatAll(toRemove...)
+	sort.Ints(removed)
+	nbPublic := r1cs.GetNbPublicVariables()
+
+	var ar, bs1, krs, p1 curve.G1Jac
+	var Bs, q2 curve.G2Jac
+	var iA, iB, iK, iRemoved int // offsets in pk.G1.A, pk.G1.B and pk.G2.B, pk.G1.K and removed
+	var scalarsA, scalarsB, scalarsK []fr.Element
+	conf := ecc.MultiExpConfig{NbTasks: runtime.NumCPU()}
+
+	err := solution.Wires.ForEachChunk(func(offset int, chunk []fr.Element) error {
+		if err := opt.Context.Err(); err != nil {
+			return err
+		}
+
+		// filter the wire values of the chunk for each multi exp
+		scalarsA, scalarsB, scalarsK = scalarsA[:0], scalarsB[:0], scalarsK[:0]
+		for j := range chunk {
+			i := offset + j
+			if !pk.InfinityA[i] {
+				scalarsA = append(scalarsA, chunk[j])
+			}
+			if !pk.InfinityB[i] {
+				scalarsB = append(scalarsB, chunk[j])
+			}
+			if i < nbPublic {
+				continue
+			}
+			for iRemoved < len(removed) && removed[iRemoved] < i {
+				iRemoved++
+			}
+			if iRemoved < len(removed) && removed[iRemoved] == i {
+				continue
+			}
+			scalarsK = append(scalarsK, chunk[j])
+		}
+
+		if len(scalarsA) != 0 {
+			if _, err := p1.MultiExp(pk.G1.A[iA:iA+len(scalarsA)], scalarsA, conf); err != nil {
+				return err
+			}
+			ar.AddAssign(&p1)
+			iA += len(scalarsA)
+		}
+		if len(scalarsB) != 0 {
+			if _, err := p1.MultiExp(pk.G1.B[iB:iB+len(scalarsB)], scalarsB, conf); err != nil {
+				return err
+			}
+			bs1.AddAssign(&p1)
+			if _, err := q2.MultiExp(pk.G2.B[iB:iB+len(scalarsB)], scalarsB, conf); err != nil {
+				return err
+			}
+			Bs.AddAssign(&q2)
+			iB += len(scalarsB)
+		}
+		if len(scalarsK) != 0 {
+			if _, err := p1.MultiExp(pk.G1.K[iK:iK+len(scalarsK)], scalarsK, conf); err != nil {
+				return err
+			}
+			krs.AddAssign(&p1)
+			iK += len(scalarsK)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	ar.AddMixed(&pk.G1.Alpha)
+	ar.AddMixed(&deltas[0])
+	proof.Ar.FromJacobian(&ar)
+
+	bs1.AddMixed(&pk.G1.Beta)
+	bs1.AddMixed(&deltas[1])
+
+	var deltaS curve.G2Jac
+	deltaS.FromAffine(&pk.G2.Delta)
+	deltaS.ScalarMultiplication(&deltaS, &s)
+	Bs.AddAssign(&deltaS)
+	Bs.AddMixed(&pk.G2.Beta)
+	proof.Bs.FromJacobian(&Bs)
+
+	sizeH := int(pk.Domain.Cardinality - 1) // comes from the fact the deg(H)=(n-1)+(n-1)-n=n-2
+	if _, err := p1.MultiExp(pk.G1.Z, h[:sizeH], conf); err != nil {
+		return err
+	}
+	krs.AddAssign(&p1)
+	krs.AddMixed(&deltas[2])
+	p1.ScalarMultiplication(&ar, &s)
+	krs.AddAssign(&p1)
+	p1.ScalarMultiplication(&bs1, &r)
+	krs.AddAssign(&p1)
+	proof.Krs.FromJacobian(&krs)
+	opt.Progress(backend.PhaseMSM, 1)
+
+	return nil
+}
+
 // if len(toRemove) == 0, returns slice
 // else, returns a new slice without the indexes in toRemove. The first value in the slice is taken as indexes as sliceFirstIndex
 // this assumes len(slice) > len(toRemove)
 // filterHeap modifies toRemove
+// msmChunkSize is the minimum number of points of the chunks in which the multi-exps of
+// the prover are split, so that the context is checked while they run.
+const msmChunkSize = 1 << 16
+
+// msmChunks is the number of chunks in which the multi-exps of the prover are split.
+const msmChunks = 4
+
+// multiExpG1 sets res to the multi-exp of points and scalars. It is computed in chunks and
+// returns ctx.Err() if ctx is done between two of them.
+func multiExpG1(ctx context.Context, res *curve.G1Jac, points []curve.G1Affine, scalars []fr.Element, conf ecc.MultiExpConfig) error {
+	chunkSize := max(len(points)/msmChunks+1, msmChunkSize)
+	var p curve.G1Jac
+	res.X.SetOne()
+	res.Y.SetOne()
+	res.Z.SetZero()
+	for start := 0; start < len(points); start += chunkSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		end := min(start+chunkSize, len(points))
+		if _, err := p.MultiExp(points[start:end], scalars[start:end], conf); err != nil {
+			return err
+		}
+		res.AddAssign(&p)
+	}
+	return ctx.Err()
+}
+
+// multiExpG2 sets res to the multi-exp of points and scalars. It is computed in chunks and
+// returns ctx.Err() if ctx is done between two of them.
+func multiExpG2(ctx context.Context, res *curve.G2Jac, points []curve.G2Affine, scalars []fr.Element, conf ecc.MultiExpConfig) error {
+	chunkSize := max(len(points)/msmChunks+1, msmChunkSize)
+	var p curve.G2Jac
+	res.X.SetOne()
+	res.Y.SetOne()
+	res.Z.SetZero()
+	for start := 0; start < len(points); start += chunkSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		end := min(start+chunkSize, len(points))
+		if _, err := p.MultiExp(points[start:end], scalars[start:end], conf); err != nil {
+			return err
+		}
+		res.AddAssign(&p)
+	}
+	return ctx.Err()
+}
+
 func filterHeap(slice []fr.Element, sliceFirstIndex int, toRemove []int) (r []fr.Element) {
 
 	if len(toRemove) == 0 {
@@ -0,0 +1,17 @@
+// Package aggregate aggregates BN254 Groth16 proofs of the same circuit into a single proof of
+// logarithmic size, following SnarkPack (https://eprint.iacr.org/2021/529).
+//
+// The aggregation SRS is made of the powers of two secrets, in G1 and G2; it can be derived
+// from the output of two independent powers of tau ceremonies (see NewSRSFromPhase1).
+//
+// Aggregated proofs can only be verified off-chain, with Verify: this package has no
+// Solidity export, unlike the Groth16 verifying key. Verify folds the GT commitments of the
+// proof with about twenty GT exponentiations per round and compares them with pairings it
+// computes, while the EVM precompiled contracts only check that a product of pairings is
+// one. An on-chain verifier would implement the GT arithmetic and the pairing in the
+// contract, at more than 15M gas per round, above the block gas limit for any useful
+// number of proofs. It needs a different argument (e.g. a GIPA variant whose final checks
+// are pairing products) and is left to a separate change.
+//
+// TODO on-chain verification of aggregated proofs (Solidity export of the aggregate verifier).
+package aggregate
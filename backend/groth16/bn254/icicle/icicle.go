@@ -154,6 +154,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	proof := &groth16_bn254.Proof{Commitments: make([]curve.G1Affine, len(commitmentInfo))}
 
 	solverOpts := opt.SolverOpts[:len(opt.SolverOpts):len(opt.SolverOpts)]
+	solverOpts = append(solverOpts, solver.WithContext(opt.Context))
 
 	privateCommittedValues := make([][]fr.Element, len(commitmentInfo))
 	for i := range commitmentInfo {
@@ -199,7 +200,15 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	}
 
 	solution := _solution.(*cs.R1CSSolution)
+	if solution.Wires != nil {
+		solution.Wires.Close()
+		return nil, fmt.Errorf("icicle prover does not support the solver disk store")
+	}
 	wireValues := []fr.Element(solution.W)
+	if err := opt.Context.Err(); err != nil {
+		return nil, err
+	}
+	opt.Progress(backend.PhaseSolve, 0.2)
 
 	start := time.Now()
 
@@ -394,6 +403,16 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 
 	// wait for FFT to end
 	<-chHDone
+	if err := opt.Context.Err(); err != nil {
+		// free the device memory allocated for this proof
+		<-chWireValuesA
+		<-chWireValuesB
+		iciclegnark.FreeDevicePointer(wireValuesADevice.P)
+		iciclegnark.FreeDevicePointer(wireValuesBDevice.P)
+		iciclegnark.FreeDevicePointer(h)
+		return nil, err
+	}
+	opt.Progress(backend.PhaseFFT, 0.4)
 
 	// schedule our proof part computations
 	if err := computeAR1(); err != nil {
@@ -410,6 +429,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	}
 
 	log.Debug().Dur("took", time.Since(start)).Msg("prover done")
+	opt.Progress(backend.PhaseMSM, 1)
 
 	// free device/GPU memory that is not needed for future proofs (scalars/hpoly)
 	go func() {
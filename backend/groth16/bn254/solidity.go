@@ -13,6 +13,7 @@ const solidityTemplate = `
 {{- $numCommitments := len .Vk.PublicAndCommitmentCommitted }}
 {{- $numWitness := sub $numPublic $numCommitments }}
 {{- $PublicAndCommitmentCommitted := .Vk.PublicAndCommitmentCommitted }}
+{{- $hashToField := or .Cfg.HashToFieldName "sha256" }}
 // SPDX-License-Identifier: MIT
 
 pragma solidity {{ .Cfg.PragmaVersion }};
@@ -566,7 +567,7 @@ contract Verifier {
             {{- end }}
 
             publicCommitments[{{$i}}] = uint256(
-                sha256(
+                {{ $hashToField }}(
                     abi.encodePacked(
                         commitments[{{mul $i 2}}],
                         commitments[{{sum (mul $i 2) 1}}],
@@ -713,7 +714,7 @@ contract Verifier {
         {{- end }}
 
             publicCommitments[{{$i}}] = uint256(
-                sha256(
+                {{ $hashToField }}(
                     abi.encodePacked(
                         commitments[{{mul $i 2}}],
                         commitments[{{sum (mul $i 2) 1}}],
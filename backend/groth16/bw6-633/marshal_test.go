@@ -28,6 +28,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"math/big"
+	"path/filepath"
 
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/gen"
@@ -196,6 +197,11 @@ func TestProvingKeySerialization(t *testing.T) {
 				t.Log(err)
 				return false
 			}
+
+			if err := io.MappedDumpRoundTripCheck(&pk, func() any { return new(ProvingKey) }, filepath.Join(t.TempDir(), "pk")); err != nil {
+				t.Log(err)
+				return false
+			}
 			return true
 		},
 		GenG1(),
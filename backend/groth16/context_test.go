@@ -0,0 +1,59 @@
+package groth16_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/test"
+)
+
+func TestProveContext(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range getCurves() {
+		assert.Run(func(assert *test.Assert) {
+			ccs, pk, vk := setupSquareCircuit(assert, curve)
+			w, pubWitness := squareWitness(assert, curve, 3)
+
+			// progress of a proof
+			var phases []string
+			var fractions []float64
+			proof, err := groth16.Prove(ccs, pk, w, backend.WithProgress(func(phase string, fraction float64) {
+				phases = append(phases, phase)
+				fractions = append(fractions, fraction)
+			}))
+			assert.NoError(err)
+			assert.NoError(groth16.Verify(proof, vk, pubWitness))
+			assert.Equal([]string{backend.PhaseSolve, backend.PhaseFFT, backend.PhaseMSM}, phases)
+			assert.IsIncreasing(fractions)
+			assert.Equal(1.0, fractions[len(fractions)-1])
+
+			// cancelled before proving
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err = groth16.Prove(ccs, pk, w, backend.WithContext(ctx))
+			assert.ErrorIs(err, context.Canceled)
+
+			// cancelled once the constraint system is solved
+			ctx, cancel = context.WithCancel(context.Background())
+			defer cancel()
+			_, err = groth16.Prove(ccs, pk, w, backend.WithContext(ctx), backend.WithProgress(func(phase string, _ float64) {
+				if phase == backend.PhaseSolve {
+					cancel()
+				}
+			}))
+			assert.ErrorIs(err, context.Canceled)
+
+			// cancelled during the multi-exponentiations
+			ctx, cancel = context.WithCancel(context.Background())
+			defer cancel()
+			_, err = groth16.Prove(ccs, pk, w, backend.WithContext(ctx), backend.WithProgress(func(phase string, _ float64) {
+				if phase == backend.PhaseFFT {
+					cancel()
+				}
+			}))
+			assert.ErrorIs(err, context.Canceled)
+		}, curve.String())
+	}
+}
@@ -20,6 +20,8 @@
 package groth16
 
 import (
+	"errors"
+	"fmt"
 	"io"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -76,6 +78,7 @@ type ProvingKey interface {
 	groth16Object
 	gnarkio.UnsafeReaderFrom
 	gnarkio.BinaryDumper
+	gnarkio.MappedDumper
 
 	// NbG1 returns the number of G1 elements in the ProvingKey
 	NbG1() int
@@ -162,6 +165,65 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ..
 	}
 }
 
+// BatchVerify verifies the proofs with the public witnesses, for the same verifying key,
+// faster than calling Verify on each of them. If some proofs are invalid, the returned
+// error is a *backend.InvalidProofError giving their indexes.
+func BatchVerify(proofs []Proof, vk VerifyingKey, publicWitnesses []witness.Witness, opts ...backend.VerifierOption) error {
+	if len(proofs) != len(publicWitnesses) {
+		return fmt.Errorf("got %d proofs and %d public witnesses", len(proofs), len(publicWitnesses))
+	}
+
+	switch _vk := vk.(type) {
+	case *groth16_bls12377.VerifyingKey:
+		return batchVerify(proofs, publicWitnesses, func(p []*groth16_bls12377.Proof, w []fr_bls12377.Vector) error {
+			return groth16_bls12377.BatchVerify(p, _vk, w, opts...)
+		})
+	case *groth16_bls12381.VerifyingKey:
+		return batchVerify(proofs, publicWitnesses, func(p []*groth16_bls12381.Proof, w []fr_bls12381.Vector) error {
+			return groth16_bls12381.BatchVerify(p, _vk, w, opts...)
+		})
+	case *groth16_bn254.VerifyingKey:
+		return batchVerify(proofs, publicWitnesses, func(p []*groth16_bn254.Proof, w []fr_bn254.Vector) error {
+			return groth16_bn254.BatchVerify(p, _vk, w, opts...)
+		})
+	case *groth16_bw6761.VerifyingKey:
+		return batchVerify(proofs, publicWitnesses, func(p []*groth16_bw6761.Proof, w []fr_bw6761.Vector) error {
+			return groth16_bw6761.BatchVerify(p, _vk, w, opts...)
+		})
+	case *groth16_bls24317.VerifyingKey:
+		return batchVerify(proofs, publicWitnesses, func(p []*groth16_bls24317.Proof, w []fr_bls24317.Vector) error {
+			return groth16_bls24317.BatchVerify(p, _vk, w, opts...)
+		})
+	case *groth16_bls24315.VerifyingKey:
+		return batchVerify(proofs, publicWitnesses, func(p []*groth16_bls24315.Proof, w []fr_bls24315.Vector) error {
+			return groth16_bls24315.BatchVerify(p, _vk, w, opts...)
+		})
+	case *groth16_bw6633.VerifyingKey:
+		return batchVerify(proofs, publicWitnesses, func(p []*groth16_bw6633.Proof, w []fr_bw6633.Vector) error {
+			return groth16_bw6633.BatchVerify(p, _vk, w, opts...)
+		})
+	default:
+		panic("unrecognized R1CS curve type")
+	}
+}
+
+// batchVerify converts the proofs and the public witnesses to their curve specific types and
+// calls verify.
+func batchVerify[P Proof, W any](proofs []Proof, publicWitnesses []witness.Witness, verify func([]P, []W) error) error {
+	_proofs := make([]P, len(proofs))
+	_publicWitnesses := make([]W, len(publicWitnesses))
+	for i := range proofs {
+		var ok bool
+		if _proofs[i], ok = proofs[i].(P); !ok {
+			return fmt.Errorf("proof %d doesn't match the curve of the verifying key", i)
+		}
+		if _publicWitnesses[i], ok = publicWitnesses[i].Vector().(W); !ok {
+			return witness.ErrInvalidWitness
+		}
+	}
+	return verify(_proofs, _publicWitnesses)
+}
+
 // Prove runs the groth16.Prove algorithm.
 //
 // if the force flag is set:
@@ -200,6 +262,43 @@ func Prove(r1cs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.
 	}
 }
 
+// Rerandomize returns a proof of the same statement as proof, for the verifying key vk,
+// which can't be linked to it. The BSB22 commitments, if any, are kept as is.
+func Rerandomize(proof Proof, vk VerifyingKey) (Proof, error) {
+	switch _proof := proof.(type) {
+	case *groth16_bls12377.Proof:
+		return rerandomize(_proof, vk, groth16_bls12377.Rerandomize)
+	case *groth16_bls12381.Proof:
+		return rerandomize(_proof, vk, groth16_bls12381.Rerandomize)
+	case *groth16_bn254.Proof:
+		return rerandomize(_proof, vk, groth16_bn254.Rerandomize)
+	case *groth16_bw6761.Proof:
+		return rerandomize(_proof, vk, groth16_bw6761.Rerandomize)
+	case *groth16_bls24317.Proof:
+		return rerandomize(_proof, vk, groth16_bls24317.Rerandomize)
+	case *groth16_bls24315.Proof:
+		return rerandomize(_proof, vk, groth16_bls24315.Rerandomize)
+	case *groth16_bw6633.Proof:
+		return rerandomize(_proof, vk, groth16_bw6633.Rerandomize)
+	default:
+		panic("unrecognized R1CS curve type")
+	}
+}
+
+// rerandomize converts the verifying key to the curve specific type of the proof and calls
+// rerandomize.
+func rerandomize[P Proof, V VerifyingKey](proof P, vk VerifyingKey, rerandomize func(P, V) (P, error)) (Proof, error) {
+	_vk, ok := vk.(V)
+	if !ok {
+		return nil, errors.New("the verifying key doesn't match the curve of the proof")
+	}
+	res, err := rerandomize(proof, _vk)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 // Setup runs groth16.Setup with provided R1CS and outputs a key pair associated with the circuit.
 //
 // Note that careful consideration must be given to this step in a production environment.
@@ -1,15 +1,21 @@
 package groth16_test
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/consensys/gnark"
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/solidity"
+	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/constraint/solver"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
 	"github.com/consensys/gnark/test"
@@ -51,6 +57,67 @@ func TestCustomHashToField(t *testing.T) {
 	}
 }
 
+func TestDiskStore(t *testing.T) {
+	assert := test.NewAssert(t)
+	assignment := &diskStoreCircuit{X: 3, Y: 5, Z: 15}
+	for _, curve := range getCurves() {
+		assert.Run(func(assert *test.Assert) {
+			ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &diskStoreCircuit{})
+			assert.NoError(err)
+			pk, vk, err := groth16.Setup(ccs)
+			assert.NoError(err)
+			witness, err := frontend.NewWitness(assignment, curve.ScalarField())
+			assert.NoError(err)
+			pubWitness, err := witness.Public()
+			assert.NoError(err)
+
+			// a small memory bound to evict pages and read the wires back in several chunks
+			dir := t.TempDir()
+			proof, err := groth16.Prove(ccs, pk, witness, backend.WithSolverOptions(
+				solver.WithDiskStore(dir, 64*len(curve.ScalarField().Bytes())),
+				solver.WithProgress(time.Nanosecond),
+			))
+			assert.NoError(err)
+			assert.NoError(groth16.Verify(proof, vk, pubWitness))
+
+			entries, err := os.ReadDir(dir)
+			assert.NoError(err)
+			assert.Equal(0, len(entries), "wire store file not removed")
+		}, curve.String())
+	}
+}
+
+func TestExportSolidity(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BLS12_381} {
+		assert.Run(func(assert *test.Assert) {
+			ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &commitmentCircuit{})
+			assert.NoError(err)
+			_, vk, err := groth16.Setup(ccs)
+			assert.NoError(err)
+
+			var buf bytes.Buffer
+			err = vk.ExportSolidity(&buf, solidity.WithHashToFieldFunction(solidity.SHA256))
+			assert.NoError(err)
+			assert.Contains(buf.String(), "sha256(")
+
+			buf.Reset()
+			err = vk.ExportSolidity(&buf, solidity.WithHashToFieldFunction(solidity.Keccak256))
+			assert.NoError(err)
+			assert.Contains(buf.String(), "keccak256(")
+			assert.NotContains(buf.String(), "sha256(")
+
+			err = vk.ExportSolidity(&buf, solidity.WithHashToFieldFunction(solidity.Hash(42)))
+			assert.Error(err)
+
+			// with the solccheck and prover_checks tags, the exported contracts verify
+			// proofs made with all the supported hash to field functions.
+			assert.CheckCircuit(&squareCircuit{}, test.WithValidAssignment(&squareCircuit{X: 3, Y: 9}),
+				test.WithCurves(curve), test.WithBackends(backend.GROTH16))
+		}, curve.String())
+	}
+}
+
 //--------------------//
 //     benches		  //
 //--------------------//
@@ -168,6 +235,61 @@ func (c *commitmentCircuit) Define(api frontend.API) error {
 	return nil
 }
 
+// squareCircuit proves the knowledge of a square root X of the public input Y.
+// The BSB22 commitment to X exercises the commitment proof of knowledge.
+type squareCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *squareCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	cmt, err := api.(frontend.Committer).Commit(c.X)
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(cmt, 0)
+	return nil
+}
+
+// setupSquareCircuit compiles [squareCircuit] and runs the setup on curve.
+func setupSquareCircuit(assert *test.Assert, curve ecc.ID) (constraint.ConstraintSystem, groth16.ProvingKey, groth16.VerifyingKey) {
+	ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &squareCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	return ccs, pk, vk
+}
+
+// squareWitness returns the full and public witnesses of [squareCircuit] for
+// the square root x.
+func squareWitness(assert *test.Assert, curve ecc.ID, x int) (witness.Witness, witness.Witness) {
+	w, err := frontend.NewWitness(&squareCircuit{X: x, Y: x * x}, curve.ScalarField())
+	assert.NoError(err)
+	pub, err := w.Public()
+	assert.NoError(err)
+	return w, pub
+}
+
+type diskStoreCircuit struct {
+	X, Y frontend.Variable
+	Z    frontend.Variable `gnark:",public"`
+}
+
+func (c *diskStoreCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.Y), c.Z)
+	acc := c.X
+	for i := 0; i < 200; i++ {
+		acc = api.Add(api.Mul(acc, c.Y), i)
+	}
+	cmt, err := api.(frontend.Committer).Commit(c.X, acc)
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(cmt, acc)
+	return nil
+}
+
 type constantHash struct{}
 
 func (h constantHash) Write(p []byte) (n int, err error) { return len(p), nil }
@@ -0,0 +1,53 @@
+package groth16_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/test"
+)
+
+func TestRerandomize(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range getCurves() {
+		assert.Run(func(assert *test.Assert) {
+			// the circuit has a BSB22 commitment
+			ccs, pk, vk := setupSquareCircuit(assert, curve)
+			w, pubWitness := squareWitness(assert, curve, 3)
+			proof, err := groth16.Prove(ccs, pk, w)
+			assert.NoError(err)
+
+			marshal := func(p groth16.Proof) []byte {
+				var buf bytes.Buffer
+				_, err := p.WriteRawTo(&buf)
+				assert.NoError(err)
+				return buf.Bytes()
+			}
+
+			rerandomized, err := groth16.Rerandomize(proof, vk)
+			assert.NoError(err)
+			assert.NoError(groth16.Verify(rerandomized, vk, pubWitness))
+			assert.NotEqual(marshal(proof), marshal(rerandomized))
+
+			// rerandomizing twice gives different proofs
+			other, err := groth16.Rerandomize(rerandomized, vk)
+			assert.NoError(err)
+			assert.NoError(groth16.Verify(other, vk, pubWitness))
+			assert.NotEqual(marshal(rerandomized), marshal(other))
+
+			// the statement is unchanged
+			_, wrong := squareWitness(assert, curve, 4)
+			assert.Error(groth16.Verify(rerandomized, vk, wrong))
+
+			// a verifying key of another curve is rejected
+			otherCurve := ecc.BLS12_381
+			if curve == otherCurve {
+				otherCurve = ecc.BN254
+			}
+			_, err = groth16.Rerandomize(proof, groth16.NewVerifyingKey(otherCurve))
+			assert.Error(err)
+		}, curve.String())
+	}
+}
@@ -0,0 +1,53 @@
+package plonk_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/plonk"
+	plonk_bn254 "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/test"
+)
+
+func TestBatchVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range getCurves() {
+		assert.Run(func(assert *test.Assert) {
+			ccs, pk, vk := setupSquareCircuit(assert, curve)
+
+			const nbProofs = 6
+			proofs := make([]plonk.Proof, nbProofs)
+			publicWitnesses := make([]witness.Witness, nbProofs)
+			for i := range proofs {
+				var w witness.Witness
+				w, publicWitnesses[i] = squareWitness(assert, curve, i+2)
+				var err error
+				proofs[i], err = plonk.Prove(ccs, pk, w)
+				assert.NoError(err)
+			}
+			assert.NoError(plonk.BatchVerify(proofs, vk, publicWitnesses))
+
+			// swap the statements of two proofs
+			publicWitnesses[1], publicWitnesses[4] = publicWitnesses[4], publicWitnesses[1]
+			err := plonk.BatchVerify(proofs, vk, publicWitnesses)
+			var invalid *backend.InvalidProofError
+			assert.True(errors.As(err, &invalid), "expected an invalid proof error, got %v", err)
+			assert.Equal([]int{1, 4}, invalid.Indexes)
+
+			// tamper with the openings, which are only checked in the batched pairing
+			if curve != ecc.BN254 {
+				return
+			}
+			for _, i := range []int{2, 5} {
+				p := proofs[i].(*plonk_bn254.Proof)
+				p.ZShiftedOpening.H = p.BatchedProof.H
+			}
+			err = plonk.BatchVerify(proofs, vk, publicWitnesses)
+			assert.True(errors.As(err, &invalid), "expected an invalid proof error, got %v", err)
+			assert.Equal([]int{1, 2, 4, 5}, invalid.Indexes, "the proofs failing the algebraic relation are reported with the ones failing the openings")
+		}, curve.String())
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/consensys/gnark/io"
 	"math/big"
 	"math/rand"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -42,6 +43,7 @@ func TestProvingKeySerialization(t *testing.T) {
 	pk.randomize()
 
 	assert.NoError(t, io.RoundTripCheck(&pk, func() interface{} { return new(ProvingKey) }))
+	assert.NoError(t, io.MappedDumpRoundTripCheck(&pk, func() interface{} { return new(ProvingKey) }, filepath.Join(t.TempDir(), "pk")))
 }
 
 func TestVerifyingKeySerialization(t *testing.T) {
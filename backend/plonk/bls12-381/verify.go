@@ -19,23 +19,24 @@ package plonk
 import (
 	"errors"
 	"fmt"
+	"github.com/consensys/gnark/backend/solidity"
 	"io"
 	"math/big"
-
-	"github.com/consensys/gnark/backend/solidity"
+	"text/template"
 	"time"
 
 	"github.com/consensys/gnark-crypto/ecc"
 
 	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
 
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
-
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/hash_to_field"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
 	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
 	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/internal/utils"
 	"github.com/consensys/gnark/logger"
 )
 
@@ -54,38 +55,136 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 		return fmt.Errorf("create backend config: %w", err)
 	}
 
+	claims, err := verifyRelation(proof, vk, publicWitness, &cfg)
+	if err != nil {
+		return err
+	}
+
+	// Batch verify
+	err = kzg.BatchVerifyMultiPoints(claims.digests[:], claims.proofs[:], claims.points[:], vk.Kzg)
+
+	log.Debug().Dur("took", time.Since(start)).Msg("verifier done")
+
+	return err
+}
+
+// BatchVerify verifies proofs with given VerifyingKey and publicWitnesses. The KZG openings
+// of all the proofs are folded into a single pairing check.
+//
+// If some proofs are invalid, it returns a *backend.InvalidProofError with the indexes of
+// all of them: the ones failing the algebraic relation, and the ones failing the KZG
+// openings, found by bisection.
+func BatchVerify(proofs []*Proof, vk *VerifyingKey, publicWitnesses []fr.Vector, opts ...backend.VerifierOption) error {
+	if len(proofs) != len(publicWitnesses) {
+		return fmt.Errorf("got %d proofs and %d public witnesses", len(proofs), len(publicWitnesses))
+	}
+	if len(proofs) == 0 {
+		return nil
+	}
+
+	log := logger.Logger().With().Str("curve", "bls12-381").Str("backend", "plonk").Int("nbProofs", len(proofs)).Logger()
+	start := time.Now()
+	cfg, err := backend.NewVerifierConfig(opts...)
+	if err != nil {
+		return fmt.Errorf("create backend config: %w", err)
+	}
+
+	// the proofs failing the algebraic relation are reported along with the ones failing
+	// the KZG openings, which are checked on the others.
+	errs := make([]error, len(proofs))
+	claims := make([]kzgClaims, 0, len(proofs))
+	valid := make([]int, 0, len(proofs))
+	for i := range proofs {
+		c, err := verifyRelation(proofs[i], vk, publicWitnesses[i], &cfg)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		valid = append(valid, i)
+		claims = append(claims, c)
+	}
+
+	check := func(start, end int) (bool, error) {
+		n := 2 * (end - start)
+		digests, openings, points := make([]kzg.Digest, 0, n), make([]kzg.OpeningProof, 0, n), make([]fr.Element, 0, n)
+		for i := start; i < end; i++ {
+			digests = append(digests, claims[i].digests[:]...)
+			openings = append(openings, claims[i].proofs[:]...)
+			points = append(points, claims[i].points[:]...)
+		}
+		err := kzg.BatchVerifyMultiPoints(digests, openings, points, vk.Kzg)
+		if errors.Is(err, kzg.ErrVerifyOpeningProof) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+	if len(valid) != 0 {
+		ok, err := check(0, len(valid))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			invalid, err := utils.Bisect(len(valid), check)
+			if err != nil {
+				return err
+			}
+			for _, j := range invalid {
+				errs[valid[j]] = kzg.ErrVerifyOpeningProof
+			}
+		}
+	}
+	if err := backend.NewInvalidProofError(errs); err != nil {
+		return err
+	}
+
+	log.Debug().Dur("took", time.Since(start)).Msg("batch verifier done")
+	return nil
+}
+
+// kzgClaims are the KZG openings left to check once the algebraic relation holds: the
+// folded openings at ζ and the opening of Z at ωζ.
+type kzgClaims struct {
+	digests [2]kzg.Digest
+	proofs  [2]kzg.OpeningProof
+	points  [2]fr.Element
+}
+
+// verifyRelation checks the points of the proof, derives the challenges and checks the
+// algebraic relation at ζ. It returns the KZG openings to check.
+func verifyRelation(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, cfg *backend.VerifierConfig) (kzgClaims, error) {
+
 	if len(proof.Bsb22Commitments) != len(vk.Qcp) {
-		return errors.New("BSB22 Commitment number mismatch")
+		return kzgClaims{}, errors.New("BSB22 Commitment number mismatch")
 	}
 
 	if len(publicWitness) != int(vk.NbPublicVariables) {
-		return errInvalidWitness
+		return kzgClaims{}, errInvalidWitness
 	}
 
 	// check that the points in the proof are on the curve
 	for i := 0; i < len(proof.LRO); i++ {
 		if !proof.LRO[i].IsInSubGroup() {
-			return errInvalidPoint
+			return kzgClaims{}, errInvalidPoint
 		}
 	}
 	if !proof.Z.IsInSubGroup() {
-		return errInvalidPoint
+		return kzgClaims{}, errInvalidPoint
 	}
 	for i := 0; i < len(proof.H); i++ {
 		if !proof.H[i].IsInSubGroup() {
-			return errInvalidPoint
+			return kzgClaims{}, errInvalidPoint
 		}
 	}
 	for i := 0; i < len(proof.Bsb22Commitments); i++ {
 		if !proof.Bsb22Commitments[i].IsInSubGroup() {
-			return errInvalidPoint
+			return kzgClaims{}, errInvalidPoint
 		}
 	}
 	if !proof.BatchedProof.H.IsInSubGroup() {
-		return errInvalidPoint
+		return kzgClaims{}, errInvalidPoint
 	}
 	if !proof.ZShiftedOpening.H.IsInSubGroup() {
-		return errInvalidPoint
+		return kzgClaims{}, errInvalidPoint
 	}
 
 	// transcript to derive the challenge
@@ -95,17 +194,17 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 	// the coefficients of the circuit, and the public inputs.
 	// derive gamma from the Comm(blinded cl), Comm(blinded cr), Comm(blinded co)
 	if err := bindPublicData(fs, "gamma", vk, publicWitness); err != nil {
-		return err
+		return kzgClaims{}, err
 	}
 	gamma, err := deriveRandomness(fs, "gamma", &proof.LRO[0], &proof.LRO[1], &proof.LRO[2])
 	if err != nil {
-		return err
+		return kzgClaims{}, err
 	}
 
 	// derive beta from Comm(l), Comm(r), Comm(o)
 	beta, err := deriveRandomness(fs, "beta")
 	if err != nil {
-		return err
+		return kzgClaims{}, err
 	}
 
 	// derive alpha from Com(Z), Bsb22Commitments
@@ -116,13 +215,13 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 	alphaDeps[len(alphaDeps)-1] = &proof.Z
 	alpha, err := deriveRandomness(fs, "alpha", alphaDeps...)
 	if err != nil {
-		return err
+		return kzgClaims{}, err
 	}
 
 	// derive zeta, the point of evaluation
 	zeta, err := deriveRandomness(fs, "zeta", &proof.H[0], &proof.H[1], &proof.H[2])
 	if err != nil {
-		return err
+		return kzgClaims{}, err
 	}
 
 	// evaluation of zhZeta=ζⁿ-1
@@ -223,7 +322,7 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 	// check that the opening of the linearised polynomial is equal to -constLin
 	openingLinPol := proof.BatchedProof.ClaimedValues[0]
 	if !constLin.Equal(&openingLinPol) {
-		return errAlgebraicRelation
+		return kzgClaims{}, errAlgebraicRelation
 	}
 
 	// computing the linearised polynomial digest
@@ -280,7 +379,7 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 		zh, zetaNPlusTwoZh, zetaNPlusTwoSquareZh,
 	)
 	if _, err := linearizedPolynomialDigest.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
-		return err
+		return kzgClaims{}, err
 	}
 
 	// Fold the first proof
@@ -300,30 +399,16 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 		zu.Marshal(),
 	)
 	if err != nil {
-		return err
+		return kzgClaims{}, err
 	}
 
-	// Batch verify
 	var shiftedZeta fr.Element
 	shiftedZeta.Mul(&zeta, &vk.Generator)
-	err = kzg.BatchVerifyMultiPoints([]kzg.Digest{
-		foldedDigest,
-		proof.Z,
-	},
-		[]kzg.OpeningProof{
-			foldedProof,
-			proof.ZShiftedOpening,
-		},
-		[]fr.Element{
-			zeta,
-			shiftedZeta,
-		},
-		vk.Kzg,
-	)
-
-	log.Debug().Dur("took", time.Since(start)).Msg("verifier done")
-
-	return err
+	return kzgClaims{
+		digests: [2]kzg.Digest{foldedDigest, proof.Z},
+		proofs:  [2]kzg.OpeningProof{foldedProof, proof.ZShiftedOpening},
+		points:  [2]fr.Element{zeta, shiftedZeta},
+	}, nil
 }
 
 func bindPublicData(fs *fiatshamir.Transcript, challenge string, vk *VerifyingKey, publicInputs []fr.Element) error {
@@ -392,7 +477,59 @@ func deriveRandomness(fs *fiatshamir.Transcript, challenge string, points ...*cu
 	return r, nil
 }
 
-// ExportSolidity not implemented for BLS12-381
+// ExportSolidity exports the verifying key to a solidity smart contract.
+//
+// See https://github.com/ConsenSys/gnark-tests for example usage.
+//
+// Code has not been audited and is provided as-is, we make no guarantees or warranties to its safety and reliability.
 func (vk *VerifyingKey) ExportSolidity(w io.Writer, exportOpts ...solidity.ExportOption) error {
-	return errors.New("not implemented")
+	funcMap := template.FuncMap{
+		"hex": func(i int) string {
+			return fmt.Sprintf("0x%x", i)
+		},
+		"mul": func(a, b int) int {
+			return a * b
+		},
+		"inc": func(i int) int {
+			return i + 1
+		},
+		"frstr": func(x fr.Element) string {
+			// we use big.Int to always get a positive string.
+			// not the most efficient hack, but it works better for .sol generation.
+			bv := new(big.Int)
+			x.BigInt(bv)
+			return bv.String()
+		},
+		// EIP-2537 encodes base field elements on 64 bytes, which we split
+		// into the high and low 32-byte words.
+		"fphi": func(x fp.Element) string {
+			b := x.Bytes()
+			return new(big.Int).SetBytes(b[:fp.Bytes-32]).String()
+		},
+		"fplo": func(x fp.Element) string {
+			b := x.Bytes()
+			return new(big.Int).SetBytes(b[fp.Bytes-32:]).String()
+		},
+		"add": func(i, j int) int {
+			return i + j
+		},
+	}
+
+	t, err := template.New("t").Funcs(funcMap).Parse(tmplSolidityVerifier)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := solidity.NewExportConfig(exportOpts...)
+	if err != nil {
+		return err
+	}
+
+	return t.Execute(w, struct {
+		Cfg solidity.ExportConfig
+		Vk  VerifyingKey
+	}{
+		Cfg: cfg,
+		Vk:  *vk,
+	})
 }
@@ -124,7 +124,7 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	start := time.Now()
 
 	// init instance
-	g, ctx := errgroup.WithContext(context.Background())
+	g, ctx := errgroup.WithContext(opt.Context)
 	instance, err := newInstance(ctx, spr, pk, fullWitness, &opt)
 	if err != nil {
 		return nil, fmt.Errorf("new instance: %w", err)
@@ -157,7 +157,12 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	// Batch opening
 	g.Go(instance.batchOpening)
 
-	if err := g.Wait(); err != nil {
+	err = g.Wait()
+	if ctxErr := opt.Context.Err(); ctxErr != nil {
+		// the steps return errContextDone, the caller expects the error of its context
+		return nil, ctxErr
+	}
+	if err != nil {
 		return nil, err
 	}
 
@@ -240,6 +245,8 @@ func newInstance(ctx context.Context, spr *cs.SparseR1CS, pk *ProvingKey, fullWi
 		chRestoreLRO:           make(chan struct{}, 1),
 	}
 	s.initBSB22Commitments()
+	// stop solving if the context is done, or if another step failed
+	s.opt.SolverOpts = append(s.opt.SolverOpts, solver.WithContext(ctx))
 	s.x = make([]*iop.Polynomial, id_Qci+2*len(s.commitmentInfo))
 
 	// init fft domains
@@ -333,6 +340,7 @@ func (s *instance) solveConstraints() error {
 	if err != nil {
 		return err
 	}
+	s.opt.Progress(backend.PhaseSolve, 0.2)
 	solution := _solution.(*cs.SparseR1CSSolution)
 	evaluationLDomainSmall := []fr.Element(solution.L)
 	evaluationRDomainSmall := []fr.Element(solution.R)
@@ -535,6 +543,7 @@ func (s *instance) computeQuotient() (err error) {
 	if err != nil {
 		return err
 	}
+	s.opt.Progress(backend.PhaseFFT, 0.7)
 
 	s.h, err = divideByZH(numerator, [2]*fft.Domain{s.domain0, s.domain1})
 	if err != nil {
@@ -545,6 +554,7 @@ func (s *instance) computeQuotient() (err error) {
 	if err := commitToQuotient(s.h1(), s.h2(), s.h3(), s.proof, s.pk.Kzg); err != nil {
 		return err
 	}
+	s.opt.Progress(backend.PhaseQuotient, 0.8)
 
 	if err := s.deriveZeta(); err != nil {
 		return err
@@ -589,11 +599,14 @@ func (s *instance) buildRatioCopyConstraint() (err error) {
 	}
 
 	// commit to the blinded version of z
-	s.proof.Z, err = s.commitToPolyAndBlinding(s.x[id_Z], s.bp[id_Bz])
+	if s.proof.Z, err = s.commitToPolyAndBlinding(s.x[id_Z], s.bp[id_Bz]); err != nil {
+		return err
+	}
+	s.opt.Progress(backend.PhaseMSM, 0.4)
 
 	close(s.chZ)
 
-	return
+	return nil
 }
 
 // open Z (blinded) at ωζ
@@ -762,8 +775,12 @@ func (s *instance) batchOpening() error {
 		s.pk.Kzg,
 		s.proof.ZShiftedOpening.ClaimedValue.Marshal(),
 	)
+	if err != nil {
+		return err
+	}
+	s.opt.Progress(backend.PhaseOpening, 1)
 
-	return err
+	return nil
 }
 
 // evaluate the full set of constraints, all polynomials in x are back in
@@ -914,6 +931,13 @@ func (s *instance) computeNumerator() (*iop.Polynomial, error) {
 
 	for i := 0; i < rho; i++ {
 
+		// the FFTs of a coset take a while, stop early if the context is done
+		select {
+		case <-s.ctx.Done():
+			return nil, errContextDone
+		default:
+		}
+
 		coset.Mul(&coset, &shifters[i])
 		tmp.Exp(coset, bn).Sub(&tmp, &one)
 
@@ -20,6 +20,8 @@ import (
 	curve "github.com/consensys/gnark-crypto/ecc/bls24-317"
 
 	"github.com/consensys/gnark-crypto/ecc/bls24-317/kzg"
+	"github.com/consensys/gnark-crypto/utils/unsafe"
+	"github.com/consensys/gnark/internal/mmap"
 	"io"
 )
 
@@ -172,6 +174,57 @@ func (pk *ProvingKey) readFrom(r io.Reader, withSubgroupChecks bool) (int64, err
 	return n, err
 }
 
+// WriteMappedDump writes the ProvingKey to w, with the KZG keys as raw, aligned slices of
+// points, so that ReadMappedDump can use them in place from a memory mapping of the file.
+// w must be at the start of the file. The raw bytes are platform dependent (endianness, etc.)
+func (pk *ProvingKey) WriteMappedDump(w io.Writer) error {
+	mw := mmap.NewWriter(w)
+	// start by writing an unsafe marker to fail early.
+	if err := unsafe.WriteMarker(mw); err != nil {
+		return err
+	}
+	if _, err := pk.Vk.WriteRawTo(mw); err != nil {
+		return err
+	}
+	if err := mmap.WriteSlice(mw, pk.Kzg.G1); err != nil {
+		return err
+	}
+	return mmap.WriteSlice(mw, pk.KzgLagrange.G1)
+}
+
+// ReadMappedDump maps the file at path, written by WriteMappedDump, in memory and reads the
+// ProvingKey from it. The KZG keys are not copied to the heap: the prover reads them from
+// the mapping, and processes mapping the same file share it through the page cache. The
+// returned io.Closer unmaps the file, after which pk must not be used.
+// This is platform dependent and very unsafe (no checks, no endianness translation, etc.)
+func (pk *ProvingKey) ReadMappedDump(path string) (io.Closer, error) {
+	m, err := mmap.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := pk.readMappedDump(mmap.NewReader(m.Bytes())); err != nil {
+		m.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+func (pk *ProvingKey) readMappedDump(r *mmap.Reader) (err error) {
+	// read the marker to fail early in case of malformed input
+	if err = unsafe.ReadMarker(r); err != nil {
+		return err
+	}
+	pk.Vk = &VerifyingKey{}
+	if _, err = pk.Vk.UnsafeReadFrom(r); err != nil {
+		return err
+	}
+	if pk.Kzg.G1, err = mmap.ReadSlice[curve.G1Affine](r); err != nil {
+		return err
+	}
+	pk.KzgLagrange.G1, err = mmap.ReadSlice[curve.G1Affine](r)
+	return err
+}
+
 // WriteTo writes binary encoding of VerifyingKey to w
 func (vk *VerifyingKey) WriteTo(w io.Writer) (n int64, err error) {
 	return vk.writeTo(w)
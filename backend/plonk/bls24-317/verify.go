@@ -36,6 +36,7 @@ import (
 	"github.com/consensys/gnark-crypto/ecc/bls24-317/kzg"
 	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
 	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/internal/utils"
 	"github.com/consensys/gnark/logger"
 )
 
@@ -54,38 +55,136 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 		return fmt.Errorf("create backend config: %w", err)
 	}
 
+	claims, err := verifyRelation(proof, vk, publicWitness, &cfg)
+	if err != nil {
+		return err
+	}
+
+	// Batch verify
+	err = kzg.BatchVerifyMultiPoints(claims.digests[:], claims.proofs[:], claims.points[:], vk.Kzg)
+
+	log.Debug().Dur("took", time.Since(start)).Msg("verifier done")
+
+	return err
+}
+
+// BatchVerify verifies proofs with given VerifyingKey and publicWitnesses. The KZG openings
+// of all the proofs are folded into a single pairing check.
+//
+// If some proofs are invalid, it returns a *backend.InvalidProofError with the indexes of
+// all of them: the ones failing the algebraic relation, and the ones failing the KZG
+// openings, found by bisection.
+func BatchVerify(proofs []*Proof, vk *VerifyingKey, publicWitnesses []fr.Vector, opts ...backend.VerifierOption) error {
+	if len(proofs) != len(publicWitnesses) {
+		return fmt.Errorf("got %d proofs and %d public witnesses", len(proofs), len(publicWitnesses))
+	}
+	if len(proofs) == 0 {
+		return nil
+	}
+
+	log := logger.Logger().With().Str("curve", "bls24-317").Str("backend", "plonk").Int("nbProofs", len(proofs)).Logger()
+	start := time.Now()
+	cfg, err := backend.NewVerifierConfig(opts...)
+	if err != nil {
+		return fmt.Errorf("create backend config: %w", err)
+	}
+
+	// the proofs failing the algebraic relation are reported along with the ones failing
+	// the KZG openings, which are checked on the others.
+	errs := make([]error, len(proofs))
+	claims := make([]kzgClaims, 0, len(proofs))
+	valid := make([]int, 0, len(proofs))
+	for i := range proofs {
+		c, err := verifyRelation(proofs[i], vk, publicWitnesses[i], &cfg)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		valid = append(valid, i)
+		claims = append(claims, c)
+	}
+
+	check := func(start, end int) (bool, error) {
+		n := 2 * (end - start)
+		digests, openings, points := make([]kzg.Digest, 0, n), make([]kzg.OpeningProof, 0, n), make([]fr.Element, 0, n)
+		for i := start; i < end; i++ {
+			digests = append(digests, claims[i].digests[:]...)
+			openings = append(openings, claims[i].proofs[:]...)
+			points = append(points, claims[i].points[:]...)
+		}
+		err := kzg.BatchVerifyMultiPoints(digests, openings, points, vk.Kzg)
+		if errors.Is(err, kzg.ErrVerifyOpeningProof) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+	if len(valid) != 0 {
+		ok, err := check(0, len(valid))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			invalid, err := utils.Bisect(len(valid), check)
+			if err != nil {
+				return err
+			}
+			for _, j := range invalid {
+				errs[valid[j]] = kzg.ErrVerifyOpeningProof
+			}
+		}
+	}
+	if err := backend.NewInvalidProofError(errs); err != nil {
+		return err
+	}
+
+	log.Debug().Dur("took", time.Since(start)).Msg("batch verifier done")
+	return nil
+}
+
+// kzgClaims are the KZG openings left to check once the algebraic relation holds: the
+// folded openings at ζ and the opening of Z at ωζ.
+type kzgClaims struct {
+	digests [2]kzg.Digest
+	proofs  [2]kzg.OpeningProof
+	points  [2]fr.Element
+}
+
+// verifyRelation checks the points of the proof, derives the challenges and checks the
+// algebraic relation at ζ. It returns the KZG openings to check.
+func verifyRelation(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, cfg *backend.VerifierConfig) (kzgClaims, error) {
+
 	if len(proof.Bsb22Commitments) != len(vk.Qcp) {
-		return errors.New("BSB22 Commitment number mismatch")
+		return kzgClaims{}, errors.New("BSB22 Commitment number mismatch")
 	}
 
 	if len(publicWitness) != int(vk.NbPublicVariables) {
-		return errInvalidWitness
+		return kzgClaims{}, errInvalidWitness
 	}
 
 	// check that the points in the proof are on the curve
 	for i := 0; i < len(proof.LRO); i++ {
 		if !proof.LRO[i].IsInSubGroup() {
-			return errInvalidPoint
+			return kzgClaims{}, errInvalidPoint
 		}
 	}
 	if !proof.Z.IsInSubGroup() {
-		return errInvalidPoint
+		return kzgClaims{}, errInvalidPoint
 	}
 	for i := 0; i < len(proof.H); i++ {
 		if !proof.H[i].IsInSubGroup() {
-			return errInvalidPoint
+			return kzgClaims{}, errInvalidPoint
 		}
 	}
 	for i := 0; i < len(proof.Bsb22Commitments); i++ {
 		if !proof.Bsb22Commitments[i].IsInSubGroup() {
-			return errInvalidPoint
+			return kzgClaims{}, errInvalidPoint
 		}
 	}
 	if !proof.BatchedProof.H.IsInSubGroup() {
-		return errInvalidPoint
+		return kzgClaims{}, errInvalidPoint
 	}
 	if !proof.ZShiftedOpening.H.IsInSubGroup() {
-		return errInvalidPoint
+		return kzgClaims{}, errInvalidPoint
 	}
 
 	// transcript to derive the challenge
@@ -95,17 +194,17 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 	// the coefficients of the circuit, and the public inputs.
 	// derive gamma from the Comm(blinded cl), Comm(blinded cr), Comm(blinded co)
 	if err := bindPublicData(fs, "gamma", vk, publicWitness); err != nil {
-		return err
+		return kzgClaims{}, err
 	}
 	gamma, err := deriveRandomness(fs, "gamma", &proof.LRO[0], &proof.LRO[1], &proof.LRO[2])
 	if err != nil {
-		return err
+		return kzgClaims{}, err
 	}
 
 	// derive beta from Comm(l), Comm(r), Comm(o)
 	beta, err := deriveRandomness(fs, "beta")
 	if err != nil {
-		return err
+		return kzgClaims{}, err
 	}
 
 	// derive alpha from Com(Z), Bsb22Commitments
@@ -116,13 +215,13 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 	alphaDeps[len(alphaDeps)-1] = &proof.Z
 	alpha, err := deriveRandomness(fs, "alpha", alphaDeps...)
 	if err != nil {
-		return err
+		return kzgClaims{}, err
 	}
 
 	// derive zeta, the point of evaluation
 	zeta, err := deriveRandomness(fs, "zeta", &proof.H[0], &proof.H[1], &proof.H[2])
 	if err != nil {
-		return err
+		return kzgClaims{}, err
 	}
 
 	// evaluation of zhZeta=ζⁿ-1
@@ -223,7 +322,7 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 	// check that the opening of the linearised polynomial is equal to -constLin
 	openingLinPol := proof.BatchedProof.ClaimedValues[0]
 	if !constLin.Equal(&openingLinPol) {
-		return errAlgebraicRelation
+		return kzgClaims{}, errAlgebraicRelation
 	}
 
 	// computing the linearised polynomial digest
@@ -280,7 +379,7 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 		zh, zetaNPlusTwoZh, zetaNPlusTwoSquareZh,
 	)
 	if _, err := linearizedPolynomialDigest.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
-		return err
+		return kzgClaims{}, err
 	}
 
 	// Fold the first proof
@@ -300,30 +399,16 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 		zu.Marshal(),
 	)
 	if err != nil {
-		return err
+		return kzgClaims{}, err
 	}
 
-	// Batch verify
 	var shiftedZeta fr.Element
 	shiftedZeta.Mul(&zeta, &vk.Generator)
-	err = kzg.BatchVerifyMultiPoints([]kzg.Digest{
-		foldedDigest,
-		proof.Z,
-	},
-		[]kzg.OpeningProof{
-			foldedProof,
-			proof.ZShiftedOpening,
-		},
-		[]fr.Element{
-			zeta,
-			shiftedZeta,
-		},
-		vk.Kzg,
-	)
-
-	log.Debug().Dur("took", time.Since(start)).Msg("verifier done")
-
-	return err
+	return kzgClaims{
+		digests: [2]kzg.Digest{foldedDigest, proof.Z},
+		proofs:  [2]kzg.OpeningProof{foldedProof, proof.ZShiftedOpening},
+		points:  [2]fr.Element{zeta, shiftedZeta},
+	}, nil
 }
 
 func bindPublicData(fs *fiatshamir.Transcript, challenge string, vk *VerifyingKey, publicInputs []fr.Element) error {
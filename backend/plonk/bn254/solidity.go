@@ -146,7 +146,7 @@ contract PlonkVerifier {
   // -------- errors
   uint256 private constant ERROR_STRING_ID = 0x08c379a000000000000000000000000000000000000000000000000000000000; // selector for function Error(string)
 
-  {{ if (gt (len .Vk.CommitmentConstraintIndexes) 0 )}}
+  {{ if and (gt (len .Vk.CommitmentConstraintIndexes) 0) (eq .Cfg.HashToFieldName "") }}
   // -------- utils (for hash_fr)
 	uint256 private constant HASH_FR_BB = 340282366920938463463374607431768211456; // 2**128
 	uint256 private constant HASH_FR_ZERO_UINT256 = 0;
@@ -674,6 +674,7 @@ contract PlonkVerifier {
       
       }
 
+      {{ if eq .Cfg.HashToFieldName "" -}}
       /// @dev https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06#section-5.2
       /// @param x x coordinate of a point on Bn254(𝔽_p)
       /// @param y y coordinate of a point on Bn254(𝔽_p)
@@ -777,6 +778,25 @@ contract PlonkVerifier {
         res := addmod(res, b1, R_MOD)
 
       }
+      {{ else -}}
+      /// @param x x coordinate of a point on Bn254(𝔽_p)
+      /// @param y y coordinate of a point on Bn254(𝔽_p)
+      /// @param mPtr free memory
+      /// @return res {{ .Cfg.HashToFieldName }}(x || y) mod R_MOD
+      function hash_fr(x, y, mPtr)->res {
+        mstore(mPtr, x)
+        mstore(add(mPtr, 0x20), y)
+        {{- if eq .Cfg.HashToFieldName "keccak256" }}
+        res := mod(keccak256(mPtr, 0x40), R_MOD)
+        {{- else }}
+        let l_success := staticcall(gas(), SHA2, mPtr, 0x40, mPtr, 0x20)
+        if iszero(l_success) {
+          error_verify()
+        }
+        res := mod(mload(mPtr), R_MOD)
+        {{- end }}
+      }
+      {{ end -}}
       {{ end }}
       // END compute_pi -------------------------------------------------
 
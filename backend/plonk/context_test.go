@@ -0,0 +1,51 @@
+package plonk_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/test"
+)
+
+func TestProveContext(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range getCurves() {
+		assert.Run(func(assert *test.Assert) {
+			ccs, pk, vk := setupSquareCircuit(assert, curve)
+			w, pubWitness := squareWitness(assert, curve, 3)
+
+			// progress of a proof
+			var phases []string
+			var fractions []float64
+			proof, err := plonk.Prove(ccs, pk, w, backend.WithProgress(func(phase string, fraction float64) {
+				phases = append(phases, phase)
+				fractions = append(fractions, fraction)
+			}))
+			assert.NoError(err)
+			assert.NoError(plonk.Verify(proof, vk, pubWitness))
+			assert.Equal([]string{backend.PhaseSolve, backend.PhaseMSM, backend.PhaseFFT, backend.PhaseQuotient, backend.PhaseOpening}, phases)
+			assert.IsIncreasing(fractions)
+			assert.Equal(1.0, fractions[len(fractions)-1])
+
+			// cancelled before proving
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err = plonk.Prove(ccs, pk, w, backend.WithContext(ctx))
+			assert.ErrorIs(err, context.Canceled)
+
+			// cancelled at the end of each phase but the last one
+			for _, phase := range phases[:len(phases)-1] {
+				ctx, cancel := context.WithCancel(context.Background())
+				_, err = plonk.Prove(ccs, pk, w, backend.WithContext(ctx), backend.WithProgress(func(p string, _ float64) {
+					if p == phase {
+						cancel()
+					}
+				}))
+				cancel()
+				assert.ErrorIs(err, context.Canceled, phase)
+			}
+		}, curve.String())
+	}
+}
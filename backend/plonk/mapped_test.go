@@ -0,0 +1,35 @@
+package plonk_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/test"
+)
+
+func TestMappedProvingKey(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range getCurves() {
+		assert.Run(func(assert *test.Assert) {
+			ccs, pk, vk := setupSquareCircuit(assert, curve)
+
+			path := filepath.Join(t.TempDir(), "pk")
+			f, err := os.Create(path)
+			assert.NoError(err)
+			assert.NoError(pk.WriteMappedDump(f))
+			assert.NoError(f.Close())
+
+			mapped := plonk.NewProvingKey(curve)
+			closer, err := mapped.ReadMappedDump(path)
+			assert.NoError(err)
+			defer closer.Close()
+
+			w, pubWitness := squareWitness(assert, curve, 3)
+			proof, err := plonk.Prove(ccs, mapped, w)
+			assert.NoError(err)
+			assert.NoError(plonk.Verify(proof, vk, pubWitness))
+		}, curve.String())
+	}
+}
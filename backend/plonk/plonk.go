@@ -20,6 +20,7 @@
 package plonk
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -81,6 +82,7 @@ type ProvingKey interface {
 	io.ReaderFrom
 	gnarkio.WriterRawTo
 	gnarkio.UnsafeReaderFrom
+	gnarkio.MappedDumper
 	VerifyingKey() interface{}
 }
 
@@ -219,6 +221,66 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ..
 	}
 }
 
+// BatchVerify verifies the proofs with the public witnesses, for the same verifying key,
+// faster than calling Verify on each of them: the KZG openings of all the proofs are
+// checked with a single pairing check. If some proofs are invalid, the returned error is a
+// *backend.InvalidProofError giving their indexes.
+func BatchVerify(proofs []Proof, vk VerifyingKey, publicWitnesses []witness.Witness, opts ...backend.VerifierOption) error {
+	if len(proofs) != len(publicWitnesses) {
+		return fmt.Errorf("got %d proofs and %d public witnesses", len(proofs), len(publicWitnesses))
+	}
+
+	switch _vk := vk.(type) {
+	case *plonk_bn254.VerifyingKey:
+		return batchVerify(proofs, publicWitnesses, func(p []*plonk_bn254.Proof, w []fr_bn254.Vector) error {
+			return plonk_bn254.BatchVerify(p, _vk, w, opts...)
+		})
+	case *plonk_bls12381.VerifyingKey:
+		return batchVerify(proofs, publicWitnesses, func(p []*plonk_bls12381.Proof, w []fr_bls12381.Vector) error {
+			return plonk_bls12381.BatchVerify(p, _vk, w, opts...)
+		})
+	case *plonk_bls12377.VerifyingKey:
+		return batchVerify(proofs, publicWitnesses, func(p []*plonk_bls12377.Proof, w []fr_bls12377.Vector) error {
+			return plonk_bls12377.BatchVerify(p, _vk, w, opts...)
+		})
+	case *plonk_bw6761.VerifyingKey:
+		return batchVerify(proofs, publicWitnesses, func(p []*plonk_bw6761.Proof, w []fr_bw6761.Vector) error {
+			return plonk_bw6761.BatchVerify(p, _vk, w, opts...)
+		})
+	case *plonk_bw6633.VerifyingKey:
+		return batchVerify(proofs, publicWitnesses, func(p []*plonk_bw6633.Proof, w []fr_bw6633.Vector) error {
+			return plonk_bw6633.BatchVerify(p, _vk, w, opts...)
+		})
+	case *plonk_bls24317.VerifyingKey:
+		return batchVerify(proofs, publicWitnesses, func(p []*plonk_bls24317.Proof, w []fr_bls24317.Vector) error {
+			return plonk_bls24317.BatchVerify(p, _vk, w, opts...)
+		})
+	case *plonk_bls24315.VerifyingKey:
+		return batchVerify(proofs, publicWitnesses, func(p []*plonk_bls24315.Proof, w []fr_bls24315.Vector) error {
+			return plonk_bls24315.BatchVerify(p, _vk, w, opts...)
+		})
+	default:
+		panic("unrecognized verifying key type")
+	}
+}
+
+// batchVerify converts the proofs and the public witnesses to their curve specific types and
+// calls verify.
+func batchVerify[P Proof, W any](proofs []Proof, publicWitnesses []witness.Witness, verify func([]P, []W) error) error {
+	_proofs := make([]P, len(proofs))
+	_publicWitnesses := make([]W, len(publicWitnesses))
+	for i := range proofs {
+		var ok bool
+		if _proofs[i], ok = proofs[i].(P); !ok {
+			return fmt.Errorf("proof %d doesn't match the curve of the verifying key", i)
+		}
+		if _publicWitnesses[i], ok = publicWitnesses[i].Vector().(W); !ok {
+			return witness.ErrInvalidWitness
+		}
+	}
+	return verify(_proofs, _publicWitnesses)
+}
+
 // NewCS instantiate a concrete curved-typed SparseR1CS and return a ConstraintSystem interface
 // This method exists for (de)serialization purposes
 func NewCS(curveID ecc.ID) constraint.ConstraintSystem {
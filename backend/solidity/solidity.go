@@ -1,5 +1,13 @@
 package solidity
 
+import (
+	"crypto/sha256"
+	"fmt"
+	"hash"
+
+	"golang.org/x/crypto/sha3"
+)
+
 // ExportOption defines option for altering the behavior of the prover in
 // Prove, ReadAndProve and IsSolved methods. See the descriptions of functions
 // returning instances of this type for implemented options.
@@ -8,6 +16,7 @@ type ExportOption func(*ExportConfig) error
 // ExportConfig is the configuration for the prover with the options applied.
 type ExportConfig struct {
 	PragmaVersion string
+	HashToField   Hash // zero if not set, the verifier then uses the default of the backend
 }
 
 // NewExportConfig returns a default ExportConfig with given export options opts
@@ -31,3 +40,57 @@ func WithPragmaVersion(version string) ExportOption {
 		return nil
 	}
 }
+
+// WithHashToFieldFunction sets the hash function used for hashing the
+// commitments to the field in the solidity verifier. The proofs must be
+// generated with the same hash function, given to the prover with
+// backend.WithProverHashToFieldFunction(h.New()).
+func WithHashToFieldFunction(h Hash) ExportOption {
+	return func(cfg *ExportConfig) error {
+		if h != SHA256 && h != Keccak256 {
+			return fmt.Errorf("unsupported hash to field function %d, only SHA256 and Keccak256 are supported", h)
+		}
+		cfg.HashToField = h
+		return nil
+	}
+}
+
+// HashToFieldName returns the name of the Solidity builtin corresponding to
+// the configured hash to field function, i.e. "sha256" or "keccak256". It
+// returns the empty string if no hash function was configured, in which case
+// the verifier should use the default hash to field of the backend.
+func (cfg ExportConfig) HashToFieldName() string {
+	return cfg.HashToField.String()
+}
+
+// Hash identifies a hash function available as a Solidity builtin.
+type Hash uint8
+
+const (
+	SHA256    Hash = iota + 1 // sha256
+	Keccak256                 // keccak256, the legacy Keccak used by the EVM
+)
+
+// String returns the name of the Solidity builtin, or the empty string if h
+// is not a supported hash function.
+func (h Hash) String() string {
+	switch h {
+	case SHA256:
+		return "sha256"
+	case Keccak256:
+		return "keccak256"
+	}
+	return ""
+}
+
+// New returns a new instance of the native hash function, to give to the
+// prover and the verifier. It panics if h is not a supported hash function.
+func (h Hash) New() hash.Hash {
+	switch h {
+	case SHA256:
+		return sha256.New()
+	case Keccak256:
+		return sha3.NewLegacyKeccak256()
+	}
+	panic("unsupported hash function")
+}
@@ -0,0 +1,802 @@
+package constraint
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+// FindingKind identifies the kind of issue reported by Analyze.
+type FindingKind uint8
+
+const (
+	// UnconstrainedHintOutput is a hint output which is not uniquely determined by the
+	// constraints once the inputs are fixed. A malicious prover may pick another value.
+	UnconstrainedHintOutput FindingKind = iota
+	// UnusedPublicInput is a public input which does not appear in any constraint.
+	UnusedPublicInput
+	// UnconstrainedBoolean is a wire marked as boolean (see frontend.Builder.MarkBoolean)
+	// which is not constrained to be boolean.
+	UnconstrainedBoolean
+)
+
+func (k FindingKind) String() string {
+	switch k {
+	case UnconstrainedHintOutput:
+		return "unconstrained hint output"
+	case UnusedPublicInput:
+		return "unused public input"
+	case UnconstrainedBoolean:
+		return "unconstrained boolean"
+	default:
+		return "unknown finding"
+	}
+}
+
+// Finding is a potential soundness issue reported by Analyze.
+type Finding struct {
+	Kind FindingKind
+	// Wires are the wires the finding refers to. The outputs of a same hint call are
+	// reported in a single finding.
+	Wires []int
+	// Message describes the finding.
+	Message string
+	// Stack is the call stack of the circuit code which created (or marked) the wires,
+	// formatted as in solver errors. It is empty if the location is unknown; hint call
+	// sites are only recorded when compiling with the debug build tag.
+	Stack string
+}
+
+func (f Finding) String() string {
+	if f.Stack == "" {
+		return "[" + f.Kind.String() + "] " + f.Message
+	}
+	return "[" + f.Kind.String() + "] " + f.Message + "\n" + f.Stack
+}
+
+// Analyze performs a static analysis of a compiled constraint system and reports
+//   - hint outputs which are not uniquely determined by the constraints,
+//   - public inputs which do not appear in any constraint,
+//   - wires marked as boolean (see frontend.Builder.MarkBoolean) without booleanity constraint.
+//
+// The analysis is a heuristic. Starting from the inputs, a wire is determined when it
+// appears in a constraint where all the other wires are determined and it is not
+// multiplied by itself (the factor it is multiplied by is assumed to be non-zero), or when
+// it is part of a binary decomposition (boolean wires weighted by distinct powers of two
+// in a linear constraint). A wire b is boolean if it is constrained by b⋅(1 - b) == 0, or
+// by b⋅(t - b) == 0 where t is assumed to be boolean (as in comparisons). Outputs of
+// commitments and of blueprints other than hints are assumed to be determined. Hint outputs
+// which are only checked through a lookup argument (e.g. std/rangecheck) are reported.
+//
+// The findings on boolean wires include the call site of MarkBoolean only when the circuit
+// is compiled with the debug build tag.
+//
+// This is experimental.
+func Analyze(cs ConstraintSystem) ([]Finding, error) {
+	s, ok := cs.(interface{ getSystem() *System })
+	if !ok {
+		return nil, errors.New("constraint system does not embed constraint.System")
+	}
+	a := newAnalyzer(cs, s.getSystem())
+	a.run()
+	return a.findings(), nil
+}
+
+// getSystem is promoted to the curve-typed constraint systems.
+func (system *System) getSystem() *System {
+	return system
+}
+
+// maximum number of wire substitutions when reconstructing a binary decomposition
+const maxSubstitutions = 1024
+
+type wireOrigin uint8
+
+const (
+	originInput    wireOrigin = iota
+	originInternal            // solved from the constraint defining it
+	originHint                // output of a hint
+	originTrusted             // output of a commitment or of a custom blueprint
+)
+
+// relation is a constraint in the form Σ lᵢ⋅left × Σ rᵢ⋅right + Σ cᵢ⋅linearᵢ + k == 0.
+// Only the wires of left and right are kept, as their coefficients are not needed, and
+// the constant k is only set for linear relations.
+type relation struct {
+	cID         int
+	linear      []relationTerm
+	constant    Element
+	left, right []int
+	wires       []int // distinct wires of the relation
+}
+
+// affine is Σ cᵢ⋅termsᵢ + constant
+type affine struct {
+	terms    []relationTerm
+	constant Element
+}
+
+type relationTerm struct {
+	wire  int
+	coeff Element
+}
+
+func (r *relation) isLinear() bool {
+	return len(r.left) == 0 && len(r.right) == 0
+}
+
+type hintCall struct {
+	id    solver.HintID
+	start int
+}
+
+type analyzer struct {
+	cs     ConstraintSystem
+	system *System
+
+	origin      []wireOrigin
+	determined  []bool
+	boolean     []bool // constrained to be boolean
+	used        []bool // appears in a constraint
+	hints       map[int]hintCall
+	hintOf      []int // wire -> instruction id of the hint computing it
+	relations   []relation
+	occurrences [][]int // wire -> relations it appears in
+	definition  []int   // wire -> first relation it appears in
+	nbUnknown   []int   // relation -> number of wires not determined
+
+	// factors of relations in the form f × g == 0, with the wires of SparseR1C
+	// factors to be substituted by their definition.
+	products       [][2]affine
+	sparseProducts [][2]int
+
+	scratch map[int]Element
+}
+
+func newAnalyzer(cs ConstraintSystem, system *System) *analyzer {
+	nbInputs := system.GetNbPublicVariables() + system.GetNbSecretVariables()
+	nbWires := nbInputs + system.GetNbInternalVariables()
+	a := &analyzer{
+		cs:          cs,
+		system:      system,
+		origin:      make([]wireOrigin, nbWires),
+		determined:  make([]bool, nbWires),
+		boolean:     make([]bool, nbWires),
+		used:        make([]bool, nbWires),
+		hints:       make(map[int]hintCall),
+		hintOf:      make([]int, nbWires),
+		occurrences: make([][]int, nbWires),
+		definition:  make([]int, nbWires),
+		scratch:     make(map[int]Element),
+	}
+	for i := 0; i < nbWires; i++ {
+		a.hintOf[i] = -1
+		a.definition[i] = -1
+		if i < nbInputs {
+			a.determined[i] = true
+		} else {
+			a.origin[i] = originInternal
+		}
+	}
+
+	var (
+		r1c       R1C
+		sparseR1C SparseR1C
+		hm        HintMapping
+	)
+	for iID, pi := range system.Instructions {
+		blueprint := system.Blueprints[pi.BlueprintID]
+		inst := pi.Unpack(system)
+		cID := int(pi.ConstraintOffset)
+
+		if system.Type == SystemR1CS {
+			if bc, ok := blueprint.(BlueprintR1C); ok {
+				bc.DecompressR1C(&r1c, inst)
+				a.addR1C(cID, &r1c)
+				continue
+			}
+		} else if bc, ok := blueprint.(BlueprintSparseR1C); ok {
+			bc.DecompressSparseR1C(&sparseR1C, inst)
+			a.addSparseR1C(cID, &sparseR1C)
+			continue
+		}
+
+		if bc, ok := blueprint.(BlueprintHint); ok {
+			bc.DecompressHint(&hm, inst)
+			a.hints[iID] = hintCall{id: hm.HintID, start: int(hm.OutputRange.Start)}
+			for w := int(hm.OutputRange.Start); w < int(hm.OutputRange.End); w++ {
+				a.origin[w] = originHint
+				a.hintOf[w] = iID
+			}
+			continue
+		}
+
+		for i := 0; i < blueprint.NbOutputs(inst); i++ {
+			a.origin[int(inst.WireOffset)+i] = originTrusted
+		}
+	}
+
+	if commitments, ok := system.CommitmentInfo.(Groth16Commitments); ok {
+		for _, w := range commitments.CommitmentIndexes() {
+			a.origin[w] = originTrusted
+		}
+	}
+
+	for w := range a.origin {
+		if a.origin[w] == originTrusted {
+			a.determined[w] = true
+		}
+	}
+
+	for _, p := range a.sparseProducts {
+		a.products = append(a.products, [2]affine{a.expand(p[0]), a.expand(p[1])})
+	}
+	for _, p := range a.products {
+		if b, ok := a.booleanProduct(p[0], p[1]); ok {
+			a.boolean[b] = true
+		} else if b, ok := a.booleanProduct(p[1], p[0]); ok {
+			a.boolean[b] = true
+		}
+	}
+
+	a.nbUnknown = make([]int, len(a.relations))
+	for rID := range a.relations {
+		for _, w := range a.relations[rID].wires {
+			if !a.determined[w] {
+				a.nbUnknown[rID]++
+			}
+		}
+	}
+
+	return a
+}
+
+// isConstantWire returns true if w is the "one" wire of a R1CS
+func (a *analyzer) isConstantWire(w int) bool {
+	return a.system.Type == SystemR1CS && w == 0
+}
+
+// constantValue returns the value of l if it doesn't depend on any wire.
+func (a *analyzer) constantValue(l LinearExpression) (Element, bool) {
+	var res Element
+	for _, t := range l {
+		if t.CoeffID() == CoeffIdZero {
+			continue
+		}
+		if !a.isConstantWire(t.WireID()) {
+			return Element{}, false
+		}
+		res = a.cs.Add(res, a.cs.GetCoefficient(t.CoeffID()))
+	}
+	return res, true
+}
+
+// affine returns l in affine form.
+func (a *analyzer) affine(l LinearExpression) affine {
+	a.accumulate(a.cs.One(), l)
+	_, constant := a.split(l, -1)
+	return affine{terms: a.flush(), constant: constant}
+}
+
+// expand returns w, replaced by its definition if it is an internal wire defined by a
+// linear relation.
+func (a *analyzer) expand(w int) affine {
+	one := a.cs.One()
+	d := a.definition[w]
+	if a.origin[w] != originInternal || d == -1 || !a.relations[d].isLinear() {
+		return affine{terms: []relationTerm{{wire: w, coeff: one}}}
+	}
+	// d⋅w + Σ dᵢ⋅wᵢ + k == 0 gives w = -(Σ dᵢ⋅wᵢ + k)/d
+	r := &a.relations[d]
+	var res affine
+	var dInv Element
+	for _, t := range r.linear {
+		if t.wire == w {
+			dInv, _ = a.cs.Inverse(t.coeff)
+		}
+	}
+	f := a.cs.Neg(dInv)
+	for _, t := range r.linear {
+		if t.wire != w {
+			res.terms = append(res.terms, relationTerm{wire: t.wire, coeff: a.cs.Mul(f, t.coeff)})
+		}
+	}
+	res.constant = a.cs.Mul(f, r.constant)
+	return res
+}
+
+// booleanProduct returns b if f == c⋅b and g == k⋅(t - b) for an expression t not
+// depending on b, in which case f × g == 0 constrains b to be boolean (assuming t is).
+func (a *analyzer) booleanProduct(f, g affine) (int, bool) {
+	if len(f.terms) != 1 || !f.constant.IsZero() {
+		return 0, false
+	}
+	b := f.terms[0].wire
+	for _, t := range g.terms {
+		if t.wire == b {
+			return b, true
+		}
+	}
+	return 0, false
+}
+
+// split returns the coefficient of w and the constant part of l.
+func (a *analyzer) split(l LinearExpression, w int) (coeff, constant Element) {
+	for _, t := range l {
+		if t.CoeffID() == CoeffIdZero {
+			continue
+		}
+		c := a.cs.GetCoefficient(t.CoeffID())
+		if a.isConstantWire(t.WireID()) {
+			constant = a.cs.Add(constant, c)
+		} else if t.WireID() == w {
+			coeff = a.cs.Add(coeff, c)
+		}
+	}
+	return
+}
+
+// accumulate adds c⋅l to the scratch linear combination.
+func (a *analyzer) accumulate(c Element, l LinearExpression) {
+	for _, t := range l {
+		if t.CoeffID() == CoeffIdZero || a.isConstantWire(t.WireID()) {
+			continue
+		}
+		a.scratch[t.WireID()] = a.cs.Add(a.scratch[t.WireID()], a.cs.Mul(c, a.cs.GetCoefficient(t.CoeffID())))
+	}
+}
+
+// flush returns the non-zero terms of the scratch linear combination and resets it.
+func (a *analyzer) flush() []relationTerm {
+	res := make([]relationTerm, 0, len(a.scratch))
+	for w, c := range a.scratch {
+		if !c.IsZero() {
+			res = append(res, relationTerm{wire: w, coeff: c})
+		}
+		delete(a.scratch, w)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].wire < res[j].wire })
+	return res
+}
+
+// wiresOf returns the wires appearing in l.
+func (a *analyzer) wiresOf(l LinearExpression) []int {
+	var res []int
+	for _, t := range l {
+		if t.CoeffID() == CoeffIdZero || a.isConstantWire(t.WireID()) {
+			continue
+		}
+		res = append(res, t.WireID())
+	}
+	return res
+}
+
+func (a *analyzer) addR1C(cID int, c *R1C) {
+	r := relation{cID: cID}
+	minusOne := a.cs.Neg(a.cs.One())
+	_, o0 := a.split(c.O, -1)
+	if cL, ok := a.constantValue(c.L); ok {
+		a.accumulate(cL, c.R)
+		a.accumulate(minusOne, c.O)
+		_, r0 := a.split(c.R, -1)
+		r.constant = a.cs.Sub(a.cs.Mul(cL, r0), o0)
+	} else if cR, ok := a.constantValue(c.R); ok {
+		a.accumulate(cR, c.L)
+		a.accumulate(minusOne, c.O)
+		_, l0 := a.split(c.L, -1)
+		r.constant = a.cs.Sub(a.cs.Mul(cR, l0), o0)
+	} else {
+		r.left = a.wiresOf(c.L)
+		r.right = a.wiresOf(c.R)
+		a.accumulate(minusOne, c.O)
+		if len(a.scratch) == 0 && o0.IsZero() {
+			a.products = append(a.products, [2]affine{a.affine(c.L), a.affine(c.R)})
+		}
+	}
+	r.linear = a.flush()
+	a.addRelation(&r)
+
+	// booleanity: L, R and O only depend on a single wire b and
+	// (l₁b + l₀)(r₁b + r₀) - (o₁b + o₀) == α(b² - b)
+	if len(r.wires) == 1 {
+		b := r.wires[0]
+		l1, l0 := a.split(c.L, b)
+		r1, r0 := a.split(c.R, b)
+		o1, o0 := a.split(c.O, b)
+		alpha := a.cs.Mul(l1, r1)
+		beta := a.cs.Sub(a.cs.Add(a.cs.Mul(l1, r0), a.cs.Mul(l0, r1)), o1)
+		gamma := a.cs.Sub(a.cs.Mul(l0, r0), o0)
+		if a.isBooleanity(alpha, beta, gamma) {
+			a.boolean[b] = true
+		}
+	}
+}
+
+func (a *analyzer) addSparseR1C(cID int, c *SparseR1C) {
+	switch c.Commitment {
+	case COMMITTED:
+		// the wire is bound to the commitment, this doesn't determine it.
+		a.used[c.XA] = true
+		return
+	case COMMITMENT:
+		a.used[c.XA] = true
+		a.origin[c.XA] = originTrusted
+		return
+	}
+
+	r := relation{cID: cID}
+	one := a.cs.One()
+	a.accumulate(one, LinearExpression{{CID: c.QL, VID: c.XA}, {CID: c.QR, VID: c.XB}, {CID: c.QO, VID: c.XC}})
+	r.linear = a.flush()
+	if c.QM != CoeffIdZero {
+		r.left = []int{int(c.XA)}
+		r.right = []int{int(c.XB)}
+		if len(r.linear) == 0 && c.QC == CoeffIdZero && c.XA != c.XB {
+			a.sparseProducts = append(a.sparseProducts, [2]int{int(c.XA), int(c.XB)})
+		}
+	} else {
+		r.constant = a.cs.GetCoefficient(int(c.QC))
+	}
+	a.addRelation(&r)
+
+	// booleanity: qM⋅b² + qL⋅b + qC == 0 with qL == -qM and qC == 0
+	if c.QM != CoeffIdZero && c.XA == c.XB && len(r.wires) == 1 {
+		var beta Element
+		if len(r.linear) == 1 {
+			beta = r.linear[0].coeff
+		}
+		if a.isBooleanity(a.cs.GetCoefficient(int(c.QM)), beta, a.cs.GetCoefficient(int(c.QC))) {
+			a.boolean[c.XA] = true
+		}
+	}
+}
+
+// isBooleanity returns true if αb² + βb + γ == 0 is equivalent to b ∈ {0, 1}
+func (a *analyzer) isBooleanity(alpha, beta, gamma Element) bool {
+	sum := a.cs.Add(alpha, beta)
+	return !alpha.IsZero() && gamma.IsZero() && sum.IsZero()
+}
+
+func (a *analyzer) addRelation(r *relation) {
+	rID := len(a.relations)
+	add := func(w int) {
+		for _, v := range r.wires {
+			if v == w {
+				return
+			}
+		}
+		r.wires = append(r.wires, w)
+		a.used[w] = true
+		a.occurrences[w] = append(a.occurrences[w], rID)
+		if a.definition[w] == -1 {
+			a.definition[w] = rID
+		}
+	}
+	for _, t := range r.linear {
+		add(t.wire)
+	}
+	for _, w := range r.left {
+		add(w)
+	}
+	for _, w := range r.right {
+		add(w)
+	}
+	a.relations = append(a.relations, *r)
+}
+
+func (a *analyzer) run() {
+	a.propagate()
+	for {
+		progress := false
+		for rID := range a.relations {
+			if a.nbUnknown[rID] < 2 || !a.relations[rID].isLinear() {
+				continue
+			}
+			for _, w := range a.decomposition(rID) {
+				if !a.determined[w] {
+					a.determine(w, nil)
+					progress = true
+				}
+			}
+		}
+		if !progress {
+			return
+		}
+		a.propagate()
+	}
+}
+
+// propagate determines the wires which are the only unknown of a relation, until a fixed
+// point is reached.
+func (a *analyzer) propagate() {
+	var queue []int
+	for rID := range a.relations {
+		if a.nbUnknown[rID] == 1 {
+			queue = append(queue, rID)
+		}
+	}
+	for len(queue) > 0 {
+		rID := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		if a.nbUnknown[rID] != 1 {
+			continue
+		}
+		r := &a.relations[rID]
+		w := -1
+		for _, v := range r.wires {
+			if !a.determined[v] {
+				w = v
+				break
+			}
+		}
+		if contains(r.left, w) && contains(r.right, w) {
+			// w² appears in the relation
+			continue
+		}
+		queue = a.determine(w, queue)
+	}
+}
+
+func (a *analyzer) determine(w int, queue []int) []int {
+	a.determined[w] = true
+	for _, rID := range a.occurrences[w] {
+		a.nbUnknown[rID]--
+		if a.nbUnknown[rID] == 1 {
+			queue = append(queue, rID)
+		}
+	}
+	return queue
+}
+
+// decomposition expands the linear relation rID by substituting the unknown internal wires
+// with their (linear) definition. It returns the remaining unknown wires if they are
+// uniquely determined by the expanded relation, that is if there is only one, or if they
+// form a binary decomposition.
+func (a *analyzer) decomposition(rID int) []int {
+	expanded := make(map[int]Element)
+	for _, t := range a.relations[rID].linear {
+		if !a.determined[t.wire] {
+			expanded[t.wire] = t.coeff
+		}
+	}
+
+	substituted := make(map[int]struct{})
+	for {
+		w := -1
+		for v := range expanded {
+			d := a.definition[v]
+			if a.origin[v] == originInternal && !a.boolean[v] && d != -1 && d != rID && a.relations[d].isLinear() {
+				w = v
+				break
+			}
+		}
+		if w == -1 {
+			break
+		}
+		if _, ok := substituted[w]; ok || len(substituted) >= maxSubstitutions {
+			return nil
+		}
+		substituted[w] = struct{}{}
+
+		// c⋅w + Σ cᵢ⋅wᵢ with d⋅w + Σ dᵢ⋅wᵢ + k == 0 becomes Σ (cᵢ - c⋅dᵢ/d)⋅wᵢ
+		definition := a.relations[a.definition[w]].linear
+		var d Element
+		for _, t := range definition {
+			if t.wire == w {
+				d = t.coeff
+			}
+		}
+		dInv, _ := a.cs.Inverse(d)
+		f := a.cs.Neg(a.cs.Mul(expanded[w], dInv))
+		delete(expanded, w)
+		for _, t := range definition {
+			if t.wire == w || a.determined[t.wire] {
+				continue
+			}
+			c := a.cs.Add(expanded[t.wire], a.cs.Mul(f, t.coeff))
+			if c.IsZero() {
+				delete(expanded, t.wire)
+			} else {
+				expanded[t.wire] = c
+			}
+		}
+	}
+
+	wires := make([]int, 0, len(expanded))
+	for w := range expanded {
+		wires = append(wires, w)
+	}
+	if len(wires) <= 1 {
+		return wires
+	}
+	for _, w := range wires {
+		if !a.boolean[w] {
+			return nil
+		}
+	}
+	if !a.isBinaryDecomposition(expanded) {
+		return nil
+	}
+	return wires
+}
+
+// isBinaryDecomposition returns true if the coefficients are k⋅2ⁱ for a common k and
+// distinct i, with at most as many bits as the field. Full width decompositions are
+// assumed to be checked against the modulus (see bits.ToBinary).
+func (a *analyzer) isBinaryDecomposition(expr map[int]Element) bool {
+	var ref Element
+	for _, c := range expr {
+		ref = c
+		break
+	}
+	refInv, _ := a.cs.Inverse(ref)
+
+	exponents := make(map[int]struct{}, len(expr))
+	minExp, maxExp := 0, 0
+	for _, c := range expr {
+		ratio := a.cs.Mul(c, refInv)
+		e, ok := log2(a.cs.ToBigInt(ratio))
+		if !ok {
+			inv, _ := a.cs.Inverse(ratio)
+			if e, ok = log2(a.cs.ToBigInt(inv)); !ok {
+				return false
+			}
+			e = -e
+		}
+		if _, ok := exponents[e]; ok {
+			return false
+		}
+		exponents[e] = struct{}{}
+		minExp = min(minExp, e)
+		maxExp = max(maxExp, e)
+	}
+	return maxExp-minExp < a.system.FieldBitLen()
+}
+
+// log2 returns i if z == 2ⁱ
+func log2(z *big.Int) (int, bool) {
+	if z.Sign() <= 0 || int(z.TrailingZeroBits()) != z.BitLen()-1 {
+		return 0, false
+	}
+	return z.BitLen() - 1, true
+}
+
+func contains(s []int, v int) bool {
+	for _, w := range s {
+		if w == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *analyzer) findings() []Finding {
+	var res []Finding
+
+	// public inputs; the first public wire of a R1CS is the constant wire
+	start := 0
+	if a.system.Type == SystemR1CS {
+		start = 1
+	}
+	for w := start; w < a.system.GetNbPublicVariables(); w++ {
+		if !a.used[w] {
+			res = append(res, Finding{
+				Kind:    UnusedPublicInput,
+				Wires:   []int{w},
+				Message: fmt.Sprintf("public input %s does not appear in any constraint", a.system.Public[w]),
+			})
+		}
+	}
+
+	// hint outputs, grouped by hint call
+	var hintWires []int
+	for w, iID := range a.hintOf {
+		if iID != -1 && a.origin[w] == originHint && !a.determined[w] {
+			hintWires = append(hintWires, w)
+		}
+	}
+	for i := 0; i < len(hintWires); {
+		iID := a.hintOf[hintWires[i]]
+		j := i
+		for j < len(hintWires) && a.hintOf[hintWires[j]] == iID {
+			j++
+		}
+		res = append(res, a.hintFinding(iID, hintWires[i:j]))
+		i = j
+	}
+
+	// boolean assumptions
+	booleans := make([]int, 0, len(a.system.MBooleanAssumptions))
+	for w := range a.system.MBooleanAssumptions {
+		booleans = append(booleans, w)
+	}
+	sort.Ints(booleans)
+	for _, w := range booleans {
+		if a.boolean[w] {
+			continue
+		}
+		f := Finding{
+			Kind:    UnconstrainedBoolean,
+			Wires:   []int{w},
+			Message: fmt.Sprintf("%s is marked as boolean but is not constrained to be boolean", a.system.VariableToString(w)),
+		}
+		// the call site is recorded in debug mode only
+		if dID := a.system.MBooleanAssumptions[w]; dID >= 0 {
+			f.Stack = a.formatStack(a.system.DebugInfo[dID].Stack)
+		}
+		res = append(res, f)
+	}
+
+	return res
+}
+
+func (a *analyzer) hintFinding(iID int, wires []int) Finding {
+	call := a.hints[iID]
+	name, ok := a.system.MHintsDependencies[call.id]
+	if !ok {
+		name = strconv.Itoa(int(call.id))
+	}
+
+	outputs := make([]string, len(wires))
+	nbUnused := 0
+	for i, w := range wires {
+		outputs[i] = strconv.Itoa(w - call.start)
+		if !a.used[w] {
+			nbUnused++
+		}
+	}
+	var msg string
+	if len(wires) == 1 {
+		msg = fmt.Sprintf("output %s (%s) of hint %s is not uniquely determined by the constraints", outputs[0], a.system.VariableToString(wires[0]), name)
+	} else {
+		msg = fmt.Sprintf("outputs %s of hint %s are not uniquely determined by the constraints", strings.Join(outputs, ", "), name)
+	}
+	if nbUnused != 0 {
+		msg += fmt.Sprintf(" (%d not used in any constraint)", nbUnused)
+	}
+
+	stack := ""
+	if dID, ok := a.system.MHintsDebug[iID]; ok {
+		stack = a.formatStack(a.system.DebugInfo[dID].Stack)
+	} else {
+		// fallback on the first constraint using the outputs
+	search:
+		for _, w := range wires {
+			for _, rID := range a.occurrences[w] {
+				if dID, ok := a.system.MDebug[a.relations[rID].cID]; ok {
+					stack = a.formatStack(a.system.DebugInfo[dID].Stack)
+					break search
+				}
+			}
+		}
+	}
+
+	return Finding{
+		Kind:    UnconstrainedHintOutput,
+		Wires:   wires,
+		Message: msg,
+		Stack:   stack,
+	}
+}
+
+func (a *analyzer) formatStack(stack []int) string {
+	var sbb strings.Builder
+	for _, lID := range stack {
+		location := a.system.SymbolTable.Locations[lID]
+		function := a.system.SymbolTable.Functions[location.FunctionID]
+
+		sbb.WriteString(function.Name)
+		sbb.WriteByte('\n')
+		sbb.WriteByte('\t')
+		sbb.WriteString(function.Filename)
+		sbb.WriteByte(':')
+		sbb.WriteString(strconv.Itoa(int(location.Line)))
+		sbb.WriteByte('\n')
+	}
+	return sbb.String()
+}
@@ -0,0 +1,100 @@
+package constraint_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/debug"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	solver.RegisterHint(analysisIdentityHint)
+}
+
+func analysisIdentityHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	for i := range outputs {
+		outputs[i].Set(inputs[0])
+	}
+	return nil
+}
+
+// soundCircuit only uses hint outputs which are uniquely determined.
+type soundCircuit struct {
+	X, Y frontend.Variable `gnark:",public"`
+}
+
+func (c *soundCircuit) Define(api frontend.API) error {
+	// inverse: X * inv == 1
+	inv := api.Inverse(c.X)
+	// binary decomposition
+	b := bits.ToBinary(api, c.Y, bits.WithNbDigits(16))
+	api.AssertIsEqual(api.Mul(inv, b[3]), api.Select(b[0], inv, 0))
+	return nil
+}
+
+// unsoundCircuit has a square root hint output (two possible values), unconstrained bits, an
+// unused public input and a wire marked as boolean without constraint.
+type unsoundCircuit struct {
+	X, Y   frontend.Variable `gnark:",public"`
+	Unused frontend.Variable `gnark:",public"`
+}
+
+func (c *unsoundCircuit) Define(api frontend.API) error {
+	res, err := api.Compiler().NewHint(analysisIdentityHint, 2, c.X)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(api.Mul(res[0], res[0]), c.X)
+	api.Compiler().MarkBoolean(res[1])
+	api.AssertIsEqual(api.Select(res[1], c.X, c.Y), c.Y)
+
+	b := bits.ToBinary(api, c.Y, bits.WithNbDigits(8), bits.WithUnconstrainedOutputs())
+	api.AssertIsEqual(b[0], 1)
+	return nil
+}
+
+func TestAnalyze(t *testing.T) {
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		assert := require.New(t)
+
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &soundCircuit{})
+		assert.NoError(err)
+		findings, err := constraint.Analyze(ccs)
+		assert.NoError(err)
+		assert.Empty(findings)
+
+		ccs, err = frontend.Compile(ecc.BN254.ScalarField(), builder, &unsoundCircuit{}, frontend.IgnoreUnconstrainedInputs())
+		assert.NoError(err)
+		findings, err = constraint.Analyze(ccs)
+		assert.NoError(err)
+
+		count := make(map[constraint.FindingKind]int)
+		for _, f := range findings {
+			count[f.Kind] += len(f.Wires)
+			if f.Kind == constraint.UnconstrainedBoolean {
+				// the call site is only recorded in debug mode
+				if debug.Debug {
+					assert.Contains(f.Stack, "analysis_test.go", "finding should point to the call site")
+				} else {
+					assert.Empty(f.Stack)
+				}
+			}
+			if f.Kind == constraint.UnusedPublicInput {
+				assert.True(strings.Contains(f.Message, "Unused"))
+			}
+		}
+		// square root and 7 unconstrained bits (the first one is fixed)
+		assert.Equal(8, count[constraint.UnconstrainedHintOutput], findings)
+		assert.Equal(1, count[constraint.UnusedPublicInput], findings)
+		assert.Equal(1, count[constraint.UnconstrainedBoolean], findings)
+	}
+}
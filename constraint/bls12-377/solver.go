@@ -17,6 +17,7 @@
 package cs
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/consensys/gnark-crypto/ecc"
@@ -30,6 +31,7 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 )
@@ -39,16 +41,26 @@ type solver struct {
 	*system
 
 	// values and solved are index by the wire (variable) id
+	// if store is set, values is nil and the wire values are in the store.
 	values   []fr.Element
+	store    *WireStore
 	solved   []bool
 	nbSolved uint64
+	nbWires  int
 
 	// maps hintID to hint function
 	mHintsFunctions map[csolver.HintID]csolver.Hint
 
 	// used to out api.Println
-	logger  zerolog.Logger
-	nbTasks int
+	logger           zerolog.Logger
+	nbTasks          int
+	progressInterval time.Duration
+
+	// if set, the solver stops when it is done (see csolver.WithContext)
+	ctx context.Context
+
+	// if set, the solve is recorded (see csolver.WithTrace)
+	trace *csolver.Trace
 
 	a, b, c fr.Vector // R1CS solver will compute the a,b,c matrices
 
@@ -82,45 +94,58 @@ func newSolver(cs *system, witness fr.Vector, opts ...csolver.Option) (*solver,
 		return nil, fmt.Errorf("invalid witness size, got %d, expected %d", len(witness), expectedWitnessSize)
 	}
 
-	// check all hints are there
-	hintFunctions := opt.HintFunctions
-
+	// check all hints are there, before solving starts.
 	// hintsDependencies is from compile time; it contains the list of hints the solver **needs**
-	var missing []string
-	for hintUUID, hintID := range cs.MHintsDependencies {
-		if _, ok := hintFunctions[hintUUID]; !ok {
-			missing = append(missing, hintID)
-		}
-	}
-
-	if len(missing) > 0 {
-		return nil, fmt.Errorf("solver missing hint(s): %v", missing)
+	hintFunctions, err := opt.ResolveHints(cs.MHintsDependencies)
+	if err != nil {
+		return nil, err
 	}
 
 	s := solver{
-		system:          cs,
-		values:          make([]fr.Element, nbWires),
-		solved:          make([]bool, nbWires),
-		mHintsFunctions: hintFunctions,
-		logger:          opt.Logger,
-		nbTasks:         opt.NbTasks,
-		q:               cs.Field(),
+		system:           cs,
+		solved:           make([]bool, nbWires),
+		nbWires:          nbWires,
+		mHintsFunctions:  hintFunctions,
+		logger:           opt.Logger,
+		nbTasks:          opt.NbTasks,
+		progressInterval: opt.ProgressInterval,
+		ctx:              opt.Context,
+		q:                cs.Field(),
+	}
+	if opt.DiskStoreMemory > 0 {
+		if s.store, err = newWireStore(opt.DiskStoreDir, nbWires, opt.DiskStoreMemory); err != nil {
+			return nil, err
+		}
+	} else {
+		s.values = make([]fr.Element, nbWires)
+	}
+	if opt.Trace != nil {
+		// the trace records the steps in execution order
+		s.trace = opt.Trace
+		*s.trace = csolver.Trace{Field: s.q.String()}
+		s.nbTasks = 1
 	}
 
 	// set the witness indexes as solved
 	if witnessOffset == 1 {
 		s.solved[0] = true // ONE_WIRE
-		s.values[0].SetOne()
+		s.setValue(0, fr.One())
 	}
-	copy(s.values[witnessOffset:], witness)
 	for i := range witness {
 		s.solved[i+witnessOffset] = true
+		s.setValue(i+witnessOffset, witness[i])
 	}
 
 	// keep track of the number of wire instantiations we do, for a post solve sanity check
 	// to ensure we instantiated all wires
 	s.nbSolved += uint64(len(witness) + witnessOffset)
 
+	if s.trace != nil {
+		for i := 0; i < len(witness)+witnessOffset; i++ {
+			s.trace.Inputs = append(s.trace.Inputs, traceValue(i, s.value(i)))
+		}
+	}
+
 	if s.Type == constraint.SystemR1CS {
 		n := ecc.NextPowerOfTwo(uint64(cs.GetNbConstraints()))
 		s.a = make(fr.Vector, cs.GetNbConstraints(), n)
@@ -135,9 +160,29 @@ func (s *solver) set(id int, value fr.Element) {
 	if s.solved[id] {
 		panic("solving the same wire twice should never happen.")
 	}
-	s.values[id] = value
+	s.setValue(id, value)
 	s.solved[id] = true
 	atomic.AddUint64(&s.nbSolved, 1)
+	if s.trace != nil && len(s.trace.Steps) != 0 {
+		step := &s.trace.Steps[len(s.trace.Steps)-1]
+		step.Solved = append(step.Solved, traceValue(id, value))
+	}
+}
+
+// value returns the value of the wire id.
+func (s *solver) value(id int) fr.Element {
+	if s.store != nil {
+		return s.store.get(id)
+	}
+	return s.values[id]
+}
+
+func (s *solver) setValue(id int, value fr.Element) {
+	if s.store != nil {
+		s.store.set(id, value)
+		return
+	}
+	s.values[id] = value
 }
 
 // computeTerm computes coeff*variable
@@ -152,24 +197,20 @@ func (s *solver) computeTerm(t constraint.Term) fr.Element {
 		panic("computing a term with an unsolved wire")
 	}
 
-	switch cID {
-	case constraint.CoeffIdZero:
+	if cID == constraint.CoeffIdZero {
 		return fr.Element{}
+	}
+	res := s.value(vID)
+	switch cID {
 	case constraint.CoeffIdOne:
-		return s.values[vID]
 	case constraint.CoeffIdTwo:
-		var res fr.Element
-		res.Double(&s.values[vID])
-		return res
+		res.Double(&res)
 	case constraint.CoeffIdMinusOne:
-		var res fr.Element
-		res.Neg(&s.values[vID])
-		return res
+		res.Neg(&res)
 	default:
-		var res fr.Element
-		res.Mul(&s.Coefficients[cID], &s.values[vID])
-		return res
+		res.Mul(&s.Coefficients[cID], &res)
 	}
+	return res
 }
 
 // r += (t.coeff*t.value)
@@ -183,21 +224,21 @@ func (s *solver) accumulateInto(t constraint.Term, r *fr.Element) {
 		return
 	}
 
-	switch cID {
-	case constraint.CoeffIdZero:
+	if cID == constraint.CoeffIdZero {
 		return
+	}
+	v := s.value(vID)
+	switch cID {
 	case constraint.CoeffIdOne:
-		r.Add(r, &s.values[vID])
+		r.Add(r, &v)
 	case constraint.CoeffIdTwo:
-		var res fr.Element
-		res.Double(&s.values[vID])
-		r.Add(r, &res)
+		v.Double(&v)
+		r.Add(r, &v)
 	case constraint.CoeffIdMinusOne:
-		r.Sub(r, &s.values[vID])
+		r.Sub(r, &v)
 	default:
-		var res fr.Element
-		res.Mul(&s.Coefficients[cID], &s.values[vID])
-		r.Add(r, &res)
+		v.Mul(&s.Coefficients[cID], &v)
+		r.Add(r, &v)
 	}
 }
 
@@ -266,6 +307,28 @@ func (s *solver) printLogs(logs []constraint.LogEntry) {
 
 const unsolvedVariable = "<unsolved>"
 
+// evalLogExpression evaluates a linear expression of a log entry. It returns true if a
+// wire of the expression is not solved.
+func (s *solver) evalLogExpression(l constraint.LinearExpression) (eval fr.Element, missingValue bool) {
+	for _, t := range l {
+		// for each term in the linear expression
+		cID, vID := t.CoeffID(), t.WireID()
+		if t.IsConstant() {
+			// just add the constant
+			eval.Add(&eval, &s.Coefficients[cID])
+			continue
+		}
+
+		if !s.solved[vID] {
+			return eval, true // we can't evaluate.
+		}
+
+		tv := s.computeTerm(t)
+		eval.Add(&eval, &tv)
+	}
+	return eval, false
+}
+
 func (s *solver) logValue(log constraint.LogEntry) string {
 	var toResolve []interface{}
 	var (
@@ -273,31 +336,7 @@ func (s *solver) logValue(log constraint.LogEntry) string {
 		missingValue bool
 	)
 	for j := 0; j < len(log.ToResolve); j++ {
-		// before eval le
-
-		missingValue = false
-		eval.SetZero()
-
-		for _, t := range log.ToResolve[j] {
-			// for each term in the linear expression
-
-			cID, vID := t.CoeffID(), t.WireID()
-			if t.IsConstant() {
-				// just add the constant
-				eval.Add(&eval, &s.Coefficients[cID])
-				continue
-			}
-
-			if !s.solved[vID] {
-				missingValue = true
-				break // stop the loop we can't evaluate.
-			}
-
-			tv := s.computeTerm(t)
-			eval.Add(&eval, &tv)
-		}
-
-		// after
+		eval, missingValue = s.evalLogExpression(log.ToResolve[j])
 		if missingValue {
 			toResolve = append(toResolve, unsolvedVariable)
 		} else {
@@ -466,9 +505,27 @@ func (solver *solver) run() error {
 	}()
 
 	var scratch scratch
+	lastProgress := time.Now()
 
 	// for each level, we push the tasks
-	for _, level := range solver.Levels {
+	for iLevel, level := range solver.Levels {
+		if solver.progressInterval != 0 && time.Since(lastProgress) >= solver.progressInterval {
+			lastProgress = time.Now()
+			solver.logger.Info().
+				Int("level", iLevel).Int("nbLevels", len(solver.Levels)).
+				Uint64("nbSolved", atomic.LoadUint64(&solver.nbSolved)).Int("nbWires", solver.nbWires).
+				Msg("solver progress")
+		}
+		if solver.store != nil {
+			if err := solver.store.Err(); err != nil {
+				return err
+			}
+		}
+		if solver.ctx != nil {
+			if err := solver.ctx.Err(); err != nil {
+				return err
+			}
+		}
 
 		// max CPU to use
 		maxCPU := float64(len(level)) / minWorkPerCPU
@@ -476,7 +533,13 @@ func (solver *solver) run() error {
 		if maxCPU <= 1.0 || solver.nbTasks == 1 {
 			// we do it sequentially
 			for _, i := range level {
+				if solver.trace != nil {
+					solver.traceInstruction(iLevel, int(i))
+				}
 				if err := solver.processInstruction(solver.Instructions[i], &scratch); err != nil {
+					if solver.trace != nil {
+						solver.traceFailure(err)
+					}
 					return err
 				}
 			}
@@ -524,7 +587,13 @@ func (solver *solver) run() error {
 		}
 	}
 
-	if int(solver.nbSolved) != len(solver.values) {
+	if solver.store != nil {
+		if err := solver.store.Err(); err != nil {
+			return err
+		}
+	}
+
+	if int(solver.nbSolved) != solver.nbWires {
 		return errors.New("solver didn't assign a value to all wires")
 	}
 
@@ -648,6 +717,91 @@ func (solver *solver) wrapErrWithDebugInfo(cID uint32, err error) *UnsatisfiedCo
 	return &UnsatisfiedConstraintError{CID: int(cID), Err: err, DebugInfo: debugInfo}
 }
 
+// traceInstruction records the execution of the instruction i, at the level iLevel.
+func (solver *solver) traceInstruction(iLevel, i int) {
+	pi := solver.Instructions[i]
+	blueprint := solver.Blueprints[pi.BlueprintID]
+	inst := pi.Unpack(&solver.System)
+	step := csolver.TraceStep{
+		Instruction: i,
+		Level:       iLevel,
+		Blueprint:   int(pi.BlueprintID),
+		Type:        fmt.Sprintf("%T", blueprint),
+		Constraint:  int(inst.ConstraintOffset),
+	}
+	switch b := blueprint.(type) {
+	case constraint.BlueprintR1C:
+		var c constraint.R1C
+		b.DecompressR1C(&c, inst)
+		step.Symbolic = c.String(solver.system)
+	case constraint.BlueprintSparseR1C:
+		var c constraint.SparseR1C
+		b.DecompressSparseR1C(&c, inst)
+		step.Symbolic = c.String(solver.system)
+	case constraint.BlueprintHint:
+		var h constraint.HintMapping
+		b.DecompressHint(&h, inst)
+		step.Hint = solver.MHintsDependencies[h.HintID]
+	}
+	solver.trace.Steps = append(solver.trace.Steps, step)
+}
+
+// traceFailure records the failure of the last traced instruction.
+func (solver *solver) traceFailure(err error) {
+	iStep := len(solver.trace.Steps) - 1
+	step := &solver.trace.Steps[iStep]
+	step.Error = err.Error()
+	failure := csolver.TraceFailure{Step: iStep, Constraint: step.Constraint, Error: err.Error()}
+	var uErr *UnsatisfiedConstraintError
+	if errors.As(err, &uErr) {
+		failure.Constraint = uErr.CID
+	}
+	if dID, ok := solver.MDebug[failure.Constraint]; ok {
+		for _, lID := range solver.DebugInfo[dID].Stack {
+			location := solver.SymbolTable.Locations[lID]
+			function := solver.SymbolTable.Functions[location.FunctionID]
+			failure.Stack = append(failure.Stack, fmt.Sprintf("%s %s:%d", function.Name, function.Filename, location.Line))
+		}
+	}
+	solver.trace.Failure = &failure
+}
+
+// traceWires records the values of the named wires: the inputs and the logged expressions.
+func (solver *solver) traceWires() {
+	for i, name := range solver.Public {
+		solver.traceWire(name, i)
+	}
+	for i, name := range solver.Secret {
+		solver.traceWire(name, len(solver.Public)+i)
+	}
+	for _, log := range solver.Logs {
+		for j, name := range log.VariableNames() {
+			l := log.ToResolve[j]
+			w := csolver.TraceWire{Name: name, Wire: -1, Caller: log.Caller}
+			if len(l) == 1 && !l[0].IsConstant() && l[0].CoeffID() == constraint.CoeffIdOne {
+				w.Wire = l[0].WireID()
+			}
+			if eval, missing := solver.evalLogExpression(l); !missing {
+				w.Value = eval.BigInt(new(big.Int)).String()
+			}
+			solver.trace.Wires = append(solver.trace.Wires, w)
+		}
+	}
+}
+
+func (solver *solver) traceWire(name string, wire int) {
+	w := csolver.TraceWire{Name: name, Wire: wire}
+	if solver.solved[wire] {
+		v := solver.value(wire)
+		w.Value = v.BigInt(new(big.Int)).String()
+	}
+	solver.trace.Wires = append(solver.trace.Wires, w)
+}
+
+func traceValue(wire int, v fr.Element) csolver.TraceValue {
+	return csolver.TraceValue{Wire: wire, Value: v.BigInt(new(big.Int)).String()}
+}
+
 // temporary variables to avoid memallocs in hotloop
 type scratch struct {
 	tR1C  constraint.R1C
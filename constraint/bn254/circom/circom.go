@@ -0,0 +1,201 @@
+// Package circom reads and writes the iden3 binary formats used by circom
+// and snarkjs for BN254 constraint systems (.r1cs) and witnesses (.wtns).
+//
+// The formats are described in
+// https://github.com/iden3/r1csfile/blob/master/doc/r1cs_bin_format.md and
+// https://github.com/iden3/snarkjs (wtns.js).
+//
+// The wire layout of circom matches the one of gnark: the constant wire 1
+// first, then the public wires, the private inputs and finally the internal
+// wires. A circom circuit doesn't come with instructions to solve its
+// internal wires, so when reading a .r1cs file all the non-public wires
+// become secret inputs of the gnark constraint system. The full assignment
+// of a .wtns file is then the witness.
+package circom
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+const (
+	r1csMagic   = "r1cs"
+	r1csVersion = 1
+
+	wtnsMagic   = "wtns"
+	wtnsVersion = 2
+
+	r1csSectionHeader      = 1
+	r1csSectionConstraints = 2
+	r1csSectionWire2Label  = 3
+
+	wtnsSectionHeader = 1
+	wtnsSectionData   = 2
+)
+
+var errUnsupportedField = errors.New("only the BN254 scalar field is supported")
+
+// section is a section of an iden3 binary file.
+type section struct {
+	typ  uint32
+	data []byte
+}
+
+// readFile reads the header and the sections of an iden3 binary file. The
+// sizes read from the file are not trusted: the sections are read
+// incrementally, so that the memory used is bounded by the actual size of the
+// input.
+func readFile(r io.Reader, magic string, maxVersion uint32) (map[uint32][]byte, error) {
+	var hdr [12]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	if string(hdr[:4]) != magic {
+		return nil, fmt.Errorf("invalid file type %q, expected %q", hdr[:4], magic)
+	}
+	if version := binary.LittleEndian.Uint32(hdr[4:8]); version == 0 || version > maxVersion {
+		return nil, fmt.Errorf("unsupported %s version %d", magic, version)
+	}
+	nbSections := binary.LittleEndian.Uint32(hdr[8:12])
+
+	sections := make(map[uint32][]byte)
+	for i := uint32(0); i < nbSections; i++ {
+		var shdr [12]byte
+		if _, err := io.ReadFull(r, shdr[:]); err != nil {
+			return nil, fmt.Errorf("read section header: %w", err)
+		}
+		typ := binary.LittleEndian.Uint32(shdr[:4])
+		size := binary.LittleEndian.Uint64(shdr[4:12])
+		if _, ok := sections[typ]; ok {
+			return nil, fmt.Errorf("duplicate section %d", typ)
+		}
+		if size > math.MaxInt32 {
+			return nil, fmt.Errorf("section %d too large (%d bytes)", typ, size)
+		}
+		var data bytes.Buffer
+		if _, err := io.CopyN(&data, r, int64(size)); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, fmt.Errorf("read section %d: %w", typ, err)
+		}
+		sections[typ] = data.Bytes()
+	}
+	return sections, nil
+}
+
+// writeFile writes an iden3 binary file made of the given sections.
+func writeFile(w io.Writer, magic string, version uint32, sections ...section) error {
+	buf := make([]byte, 0, 12)
+	buf = append(buf, magic...)
+	buf = binary.LittleEndian.AppendUint32(buf, version)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(sections)))
+	if _, err := w.Write(buf); err != nil {
+		return err
+	}
+	for _, s := range sections {
+		buf = buf[:0]
+		buf = binary.LittleEndian.AppendUint32(buf, s.typ)
+		buf = binary.LittleEndian.AppendUint64(buf, uint64(len(s.data)))
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+		if _, err := w.Write(s.data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reader decodes little-endian values from a section.
+type reader struct {
+	data []byte
+	err  error
+}
+
+func (r *reader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if len(r.data) < n {
+		r.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *reader) uint32() uint32 {
+	b := r.next(4)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b)
+}
+
+func (r *reader) uint64() uint64 {
+	b := r.next(8)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(b)
+}
+
+func (r *reader) element() fr.Element {
+	b := r.next(fr.Bytes)
+	if b == nil {
+		return fr.Element{}
+	}
+	e, err := fr.LittleEndian.Element((*[fr.Bytes]byte)(b))
+	if err != nil {
+		r.err = err
+	}
+	return e
+}
+
+// readField reads the size and the modulus of the field and checks it is
+// the BN254 scalar field.
+func (r *reader) readField() {
+	n8 := r.uint32()
+	if r.err != nil {
+		return
+	}
+	if n8 != fr.Bytes {
+		r.err = errUnsupportedField
+		return
+	}
+	b := r.next(fr.Bytes)
+	if b == nil {
+		return
+	}
+	var q [fr.Bytes]byte
+	for i := range q {
+		q[i] = b[fr.Bytes-1-i]
+	}
+	if fr.Modulus().Cmp(new(big.Int).SetBytes(q[:])) != 0 {
+		r.err = errUnsupportedField
+	}
+}
+
+func appendElement(buf []byte, e *fr.Element) []byte {
+	var b [fr.Bytes]byte
+	fr.LittleEndian.PutElement(&b, *e)
+	return append(buf, b[:]...)
+}
+
+func appendField(buf []byte) []byte {
+	buf = binary.LittleEndian.AppendUint32(buf, fr.Bytes)
+	q := fr.Modulus().FillBytes(make([]byte, fr.Bytes))
+	for i := len(q) - 1; i >= 0; i-- {
+		buf = append(buf, q[i])
+	}
+	return buf
+}
@@ -0,0 +1,204 @@
+package circom_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	cs "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/constraint/bn254/circom"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/stretchr/testify/require"
+)
+
+type cubic struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *cubic) Define(api frontend.API) error {
+	x3 := api.Mul(c.X, c.X, c.X)
+	api.AssertIsEqual(c.Y, api.Add(x3, c.X, 5))
+	return nil
+}
+
+func TestRoundTrip(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &cubic{})
+	assert.NoError(err)
+	native := ccs.(*cs.R1CS)
+	fullWitness, err := frontend.NewWitness(&cubic{X: 3, Y: 35}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	var r1csBuf, wtnsBuf bytes.Buffer
+	assert.NoError(circom.WriteR1CS(&r1csBuf, native))
+	assert.NoError(circom.WriteWitness(&wtnsBuf, native, fullWitness))
+
+	// the witness can be read back for the native constraint system
+	w, err := circom.ReadWitness(bytes.NewReader(wtnsBuf.Bytes()), native)
+	assert.NoError(err)
+	assert.Equal(fullWitness.Vector(), w.Vector())
+
+	imported, err := circom.ReadR1CS(&r1csBuf)
+	assert.NoError(err)
+	assert.Equal(native.GetNbConstraints(), imported.GetNbConstraints())
+	assert.Equal(native.GetNbPublicVariables(), imported.GetNbPublicVariables())
+
+	w, err = circom.ReadWitness(&wtnsBuf, imported)
+	assert.NoError(err)
+	proveAndVerify(t, imported, w)
+
+	// the internal wires of the native constraint system are secret variables
+	// of the imported one, besides that the constraint system is unchanged.
+	var r1csBuf2, r1csBuf3 bytes.Buffer
+	assert.NoError(circom.WriteR1CS(&r1csBuf2, imported))
+	reimported, err := circom.ReadR1CS(bytes.NewReader(r1csBuf2.Bytes()))
+	assert.NoError(err)
+	assert.NoError(circom.WriteR1CS(&r1csBuf3, reimported))
+	assert.Equal(r1csBuf2.Bytes(), r1csBuf3.Bytes())
+}
+
+func TestReadCircom(t *testing.T) {
+	assert := require.New(t)
+
+	// out <== a * b, as compiled by circom: 1 public output, 2 private
+	// inputs and a single constraint (-a) * b - (-out) = 0.
+	var minusOne fr.Element
+	minusOne.SetInt64(-1)
+	hdr := appendField(nil)
+	hdr = binary.LittleEndian.AppendUint32(hdr, 4) // nbWires
+	hdr = binary.LittleEndian.AppendUint32(hdr, 1) // nbPubOut
+	hdr = binary.LittleEndian.AppendUint32(hdr, 0) // nbPubIn
+	hdr = binary.LittleEndian.AppendUint32(hdr, 2) // nbPrvIn
+	hdr = binary.LittleEndian.AppendUint64(hdr, 4) // nbLabels
+	hdr = binary.LittleEndian.AppendUint32(hdr, 1) // nbConstraints
+	var constraints []byte
+	for _, wire := range []uint32{2, 3, 1} {
+		constraints = binary.LittleEndian.AppendUint32(constraints, 1)
+		constraints = binary.LittleEndian.AppendUint32(constraints, wire)
+		if wire == 3 {
+			constraints = appendElement(constraints, fr.One())
+		} else {
+			constraints = appendElement(constraints, minusOne)
+		}
+	}
+	var labels []byte
+	for i := uint64(0); i < 4; i++ {
+		labels = binary.LittleEndian.AppendUint64(labels, i)
+	}
+	r1csFile := file("r1cs", 1, hdr, constraints, labels)
+
+	ccs, err := circom.ReadR1CS(bytes.NewReader(r1csFile))
+	assert.NoError(err)
+	assert.Equal(1, ccs.GetNbConstraints())
+	assert.Equal(2, ccs.GetNbPublicVariables())
+	assert.Equal(2, ccs.GetNbSecretVariables())
+
+	hdr = appendField(nil)
+	hdr = binary.LittleEndian.AppendUint32(hdr, 4)
+	var values []byte
+	for _, v := range []uint64{1, 42, 6, 7} {
+		values = appendElement(values, fr.NewElement(v))
+	}
+	w, err := circom.ReadWitness(bytes.NewReader(file("wtns", 2, hdr, values)), ccs)
+	assert.NoError(err)
+	proveAndVerify(t, ccs, w)
+
+	// wrong output
+	values = values[:0]
+	for _, v := range []uint64{1, 43, 6, 7} {
+		values = appendElement(values, fr.NewElement(v))
+	}
+	w, err = circom.ReadWitness(bytes.NewReader(file("wtns", 2, hdr, values)), ccs)
+	assert.NoError(err)
+	_, err = ccs.Solve(w)
+	assert.Error(err)
+}
+
+func TestReadMalformed(t *testing.T) {
+	assert := require.New(t)
+
+	hdr := appendField(nil)
+	hdr = binary.LittleEndian.AppendUint32(hdr, 2) // nbWires
+	hdr = binary.LittleEndian.AppendUint32(hdr, 1) // nbPubOut
+	hdr = binary.LittleEndian.AppendUint32(hdr, 0) // nbPubIn
+	hdr = binary.LittleEndian.AppendUint32(hdr, 0) // nbPrvIn
+	hdr = binary.LittleEndian.AppendUint64(hdr, 2) // nbLabels
+	hdr = binary.LittleEndian.AppendUint32(hdr, 1) // nbConstraints
+	valid := file("r1cs", 1, hdr, make([]byte, 12), make([]byte, 16))
+	_, err := circom.ReadR1CS(bytes.NewReader(valid))
+	assert.NoError(err)
+
+	// section sizes beyond the end of the input are not allocated upfront
+	for _, size := range []uint64{1 << 40, 1 << 62, 1<<64 - 1} {
+		f := append([]byte{}, valid[:12]...)
+		f = binary.LittleEndian.AppendUint32(f, 1)
+		f = binary.LittleEndian.AppendUint64(f, size)
+		f = append(f, hdr...)
+		_, err = circom.ReadR1CS(bytes.NewReader(f))
+		assert.Error(err, size)
+	}
+
+	// a huge number of terms in a short constraints section
+	constraints := binary.LittleEndian.AppendUint32(nil, 1<<32-1)
+	constraints = append(constraints, make([]byte, 8)...)
+	_, err = circom.ReadR1CS(bytes.NewReader(file("r1cs", 1, hdr, constraints, make([]byte, 16))))
+	assert.Error(err)
+
+	// truncated input and invalid header
+	for i := 0; i < len(valid); i += 7 {
+		_, err = circom.ReadR1CS(bytes.NewReader(valid[:i]))
+		assert.Error(err, i)
+	}
+	_, err = circom.ReadR1CS(bytes.NewReader(file("wtns", 1, hdr)))
+	assert.Error(err)
+	_, err = circom.ReadR1CS(bytes.NewReader(file("r1cs", 2, hdr)))
+	assert.Error(err)
+}
+
+func proveAndVerify(t *testing.T, ccs *cs.R1CS, fullWitness witness.Witness) {
+	t.Helper()
+	assert := require.New(t)
+
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, fullWitness)
+	assert.NoError(err)
+	publicWitness, err := fullWitness.Public()
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, publicWitness))
+}
+
+func file(magic string, version uint32, sections ...[]byte) []byte {
+	buf := []byte(magic)
+	buf = binary.LittleEndian.AppendUint32(buf, version)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(sections)))
+	for i, s := range sections {
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(i+1))
+		buf = binary.LittleEndian.AppendUint64(buf, uint64(len(s)))
+		buf = append(buf, s...)
+	}
+	return buf
+}
+
+func appendField(buf []byte) []byte {
+	buf = binary.LittleEndian.AppendUint32(buf, fr.Bytes)
+	var q [fr.Bytes]byte
+	fr.Modulus().FillBytes(q[:])
+	for i := fr.Bytes - 1; i >= 0; i-- {
+		buf = append(buf, q[i])
+	}
+	return buf
+}
+
+func appendElement(buf []byte, e fr.Element) []byte {
+	var b [fr.Bytes]byte
+	fr.LittleEndian.PutElement(&b, e)
+	return append(buf, b[:]...)
+}
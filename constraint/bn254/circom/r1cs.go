@@ -0,0 +1,186 @@
+package circom
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/constraint"
+	cs "github.com/consensys/gnark/constraint/bn254"
+)
+
+// ReadR1CS reads a constraint system in the iden3 .r1cs binary format.
+//
+// The outputs and public inputs of the circom circuit become the public
+// variables of the returned R1CS, in the same order. All other wires become
+// secret variables, see ReadWitness.
+func ReadR1CS(r io.Reader) (*cs.R1CS, error) {
+	sections, err := readFile(r, r1csMagic, r1csVersion)
+	if err != nil {
+		return nil, err
+	}
+	for typ := range sections {
+		if typ != r1csSectionHeader && typ != r1csSectionConstraints && typ != r1csSectionWire2Label {
+			return nil, fmt.Errorf("unsupported r1cs section %d (custom gates are not supported)", typ)
+		}
+	}
+
+	// header
+	data, ok := sections[r1csSectionHeader]
+	if !ok {
+		return nil, errors.New("missing r1cs header section")
+	}
+	hdr := reader{data: data}
+	hdr.readField()
+	nbWires := hdr.uint32()
+	nbPubOut := hdr.uint32()
+	nbPubIn := hdr.uint32()
+	_ = hdr.uint32() // nbPrvIn; private inputs are secret variables like the other wires
+	_ = hdr.uint64() // nbLabels
+	nbConstraints := hdr.uint32()
+	if hdr.err != nil {
+		return nil, fmt.Errorf("read r1cs header: %w", hdr.err)
+	}
+	nbPublic := 1 + uint64(nbPubOut) + uint64(nbPubIn)
+	if nbWires == 0 || nbPublic > uint64(nbWires) {
+		return nil, fmt.Errorf("invalid number of wires %d for %d public wires", nbWires, nbPublic)
+	}
+
+	// the counts are not trusted: a constraint takes at least 12 bytes
+	res := cs.NewR1CS(min(int(nbConstraints), len(sections[r1csSectionConstraints])/12))
+	blueprint := res.AddBlueprint(&constraint.BlueprintGenericR1C{})
+
+	// wires
+	res.AddPublicVariable("1")
+	for i := 1; i < int(nbWires); i++ {
+		name := fmt.Sprintf("w%d", i)
+		if uint64(i) < nbPublic {
+			res.AddPublicVariable(name)
+		} else {
+			res.AddSecretVariable(name)
+		}
+	}
+
+	// constraints
+	data, ok = sections[r1csSectionConstraints]
+	if !ok {
+		return nil, errors.New("missing r1cs constraints section")
+	}
+	cr := reader{data: data}
+	readLinearExpression := func() constraint.LinearExpression {
+		nbTerms := cr.uint32()
+		if cr.err != nil {
+			return nil
+		}
+		if uint64(nbTerms)*(4+fr.Bytes) > uint64(len(cr.data)) {
+			cr.err = io.ErrUnexpectedEOF
+			return nil
+		}
+		l := make(constraint.LinearExpression, 0, nbTerms)
+		for i := uint32(0); i < nbTerms; i++ {
+			wireID := cr.uint32()
+			coeff := cr.element()
+			if cr.err != nil {
+				return nil
+			}
+			if wireID >= nbWires {
+				cr.err = fmt.Errorf("wire %d out of range", wireID)
+				return nil
+			}
+			l = append(l, res.MakeTerm(res.FromInterface(coeff), int(wireID)))
+		}
+		return l
+	}
+	for i := uint32(0); i < nbConstraints; i++ {
+		var r1c constraint.R1C
+		r1c.L = readLinearExpression()
+		r1c.R = readLinearExpression()
+		r1c.O = readLinearExpression()
+		if cr.err != nil {
+			return nil, fmt.Errorf("read constraint %d: %w", i, cr.err)
+		}
+		res.AddR1C(r1c, blueprint)
+	}
+	if len(cr.data) != 0 {
+		return nil, errors.New("unexpected data after the constraints")
+	}
+
+	return res, nil
+}
+
+// WriteR1CS writes r1cs in the iden3 .r1cs binary format.
+//
+// The public variables of r1cs are written as public inputs of the circom
+// circuit (it has no outputs) and the secret variables as private inputs.
+// Constraint systems with commitments can't be represented in this format.
+func WriteR1CS(w io.Writer, r1cs *cs.R1CS) error {
+	if r1cs.Type != constraint.SystemR1CS {
+		return errors.New("only R1CS constraint systems are supported")
+	}
+	if len(r1cs.GetCommitments().CommitmentIndexes()) != 0 {
+		return errors.New("constraint systems with commitments are not supported")
+	}
+
+	nbPublic := r1cs.GetNbPublicVariables()
+	nbSecret := r1cs.GetNbSecretVariables()
+	nbWires := nbPublic + nbSecret + r1cs.GetNbInternalVariables()
+
+	// constraints
+	r1cList := r1cs.GetR1Cs()
+	constraints := make([]byte, 0, len(r1cList)*3*(4+4+fr.Bytes))
+	for _, r1c := range r1cList {
+		for _, l := range []constraint.LinearExpression{r1c.L, r1c.R, r1c.O} {
+			constraints = appendLinearExpression(constraints, r1cs, l)
+		}
+	}
+
+	// header
+	hdr := appendField(nil)
+	hdr = binary.LittleEndian.AppendUint32(hdr, uint32(nbWires))
+	hdr = binary.LittleEndian.AppendUint32(hdr, 0) // nbPubOut
+	hdr = binary.LittleEndian.AppendUint32(hdr, uint32(nbPublic-1))
+	hdr = binary.LittleEndian.AppendUint32(hdr, uint32(nbSecret))
+	hdr = binary.LittleEndian.AppendUint64(hdr, uint64(nbWires))
+	hdr = binary.LittleEndian.AppendUint32(hdr, uint32(len(r1cList)))
+
+	// labels; we don't have a .sym file so labels are the wire IDs
+	labels := make([]byte, 0, nbWires*8)
+	for i := 0; i < nbWires; i++ {
+		labels = binary.LittleEndian.AppendUint64(labels, uint64(i))
+	}
+
+	return writeFile(w, r1csMagic, r1csVersion,
+		section{r1csSectionHeader, hdr},
+		section{r1csSectionConstraints, constraints},
+		section{r1csSectionWire2Label, labels},
+	)
+}
+
+// appendLinearExpression appends l to buf with its terms sorted by wire and
+// merged, as circom tools expect at most one term per wire.
+func appendLinearExpression(buf []byte, r1cs *cs.R1CS, l constraint.LinearExpression) []byte {
+	coeffs := make(map[int]fr.Element, len(l))
+	for _, t := range l {
+		c := coeffs[t.WireID()]
+		c.Add(&c, &r1cs.Coefficients[t.CoeffID()])
+		coeffs[t.WireID()] = c
+	}
+	wires := make([]int, 0, len(coeffs))
+	for wireID, c := range coeffs {
+		if !c.IsZero() {
+			wires = append(wires, wireID)
+		}
+	}
+	sort.Ints(wires)
+
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(wires)))
+	for _, wireID := range wires {
+		c := coeffs[wireID]
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(wireID))
+		buf = appendElement(buf, &c)
+	}
+	return buf
+}
@@ -0,0 +1,98 @@
+package circom
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/witness"
+	cs "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+// ReadWitness reads a full assignment in the iden3 .wtns binary format and
+// returns the corresponding witness of r1cs.
+//
+// The .wtns file must assign all the wires of r1cs. If r1cs was read with
+// ReadR1CS, this is exactly the witness; otherwise the values of the
+// internal wires are dropped and recomputed by the solver.
+func ReadWitness(r io.Reader, r1cs *cs.R1CS) (witness.Witness, error) {
+	sections, err := readFile(r, wtnsMagic, wtnsVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := sections[wtnsSectionHeader]
+	if !ok {
+		return nil, errors.New("missing wtns header section")
+	}
+	hdr := reader{data: data}
+	hdr.readField()
+	nbValues := hdr.uint32()
+	if hdr.err != nil {
+		return nil, fmt.Errorf("read wtns header: %w", hdr.err)
+	}
+
+	nbPublic := r1cs.GetNbPublicVariables()
+	nbSecret := r1cs.GetNbSecretVariables()
+	if nbWires := nbPublic + nbSecret + r1cs.GetNbInternalVariables(); int(nbValues) != nbWires {
+		return nil, fmt.Errorf("witness has %d values, constraint system has %d wires", nbValues, nbWires)
+	}
+
+	data, ok = sections[wtnsSectionData]
+	if !ok {
+		return nil, errors.New("missing wtns data section")
+	}
+	dr := reader{data: data}
+	values := make([]fr.Element, nbValues)
+	for i := range values {
+		values[i] = dr.element()
+	}
+	if dr.err != nil {
+		return nil, fmt.Errorf("read wtns data: %w", dr.err)
+	}
+	if !values[0].IsOne() {
+		return nil, errors.New("the first wire must be assigned to 1")
+	}
+
+	w, err := witness.New(fr.Modulus())
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan any)
+	go func() {
+		defer close(ch)
+		for i := 1; i < nbPublic+nbSecret; i++ {
+			ch <- values[i]
+		}
+	}()
+	if err := w.Fill(nbPublic-1, nbSecret, ch); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// WriteWitness solves r1cs with the given full witness and writes the
+// resulting assignment of all the wires in the iden3 .wtns binary format.
+func WriteWitness(w io.Writer, r1cs *cs.R1CS, fullWitness witness.Witness, opts ...solver.Option) error {
+	_solution, err := r1cs.Solve(fullWitness, opts...)
+	if err != nil {
+		return err
+	}
+	solution := _solution.(*cs.R1CSSolution)
+
+	hdr := appendField(nil)
+	hdr = binary.LittleEndian.AppendUint32(hdr, uint32(len(solution.W)))
+
+	data := make([]byte, 0, len(solution.W)*fr.Bytes)
+	for i := range solution.W {
+		data = appendElement(data, &solution.W[i])
+	}
+
+	return writeFile(w, wtnsMagic, wtnsVersion,
+		section{wtnsSectionHeader, hdr},
+		section{wtnsSectionData, data},
+	)
+}
@@ -18,6 +18,8 @@ package cs
 
 import (
 	"encoding/binary"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 
@@ -26,7 +28,16 @@ import (
 
 // WriteTo encodes R1CS into provided io.Writer using cbor
 func (cs *system) WriteTo(w io.Writer) (int64, error) {
-	b, err := cs.System.ToBytes()
+	// record the content hash in the serialization header. The system is not
+	// modified, so that WriteTo can be called concurrently.
+	hash, err := cs.ContentHash()
+	if err != nil {
+		return 0, err
+	}
+	sys := cs.System
+	sys.Hash = hex.EncodeToString(hash)
+
+	b, err := sys.ToBytes()
 	if err != nil {
 		return 0, err
 	}
@@ -97,5 +108,17 @@ func (cs *system) ReadFrom(r io.Reader) (int64, error) {
 		return 0, err
 	}
 
+	// check the content hash recorded in the serialization header, if any
+	if cs.Hash != "" {
+		hash, err := cs.ContentHash()
+		if err != nil {
+			return 0, err
+		}
+		if hex.EncodeToString(hash) != cs.Hash {
+			return 0, errors.New("constraint system content hash mismatch")
+		}
+		cs.Hash = ""
+	}
+
 	return int64(totalLen) + 4*8, nil
 }
@@ -18,6 +18,7 @@ package cs_test
 
 import (
 	"bytes"
+	"github.com/consensys/gnark/constraint/solver"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
 	"github.com/consensys/gnark/frontend/cs/scs"
@@ -187,3 +188,55 @@ func BenchmarkSolve(b *testing.B) {
 	})
 
 }
+
+// BenchmarkSolveDiskStore compares solving with the wire values in memory and in a
+// disk store, either large enough to keep all the pages resident (locking cost only)
+// or small enough to evict pages while solving.
+func BenchmarkSolveDiskStore(b *testing.B) {
+	var x, y, c42 fr.Element
+	x.SetOne()
+	c42.SetUint64(42)
+	y.Set(&x)
+	for i := 0; i < n; i++ {
+		var t fr.Element
+		t.Mul(&y, &y)
+		y.Add(&t, &y).Add(&y, &c42)
+	}
+	var w circuit
+	w.X = x
+	w.Y = y
+	witness, err := frontend.NewWitness(&w, fr.Modulus())
+	if err != nil {
+		b.Fatal(err)
+	}
+	var c circuit
+	ccs, err := frontend.Compile(fr.Modulus(), r1cs.NewBuilder, &c)
+	if err != nil {
+		b.Fatal(err)
+	}
+	nbWires := ccs.GetNbInternalVariables() + ccs.GetNbSecretVariables() + ccs.GetNbPublicVariables()
+	wireBytes := len(fr.Modulus().Bytes())
+
+	for _, bc := range []struct {
+		name string
+		opts []solver.Option
+	}{
+		{"memory", nil},
+		{"disk_resident", []solver.Option{solver.WithDiskStore(b.TempDir(), 2*nbWires*wireBytes)}},
+		{"disk_evicting", []solver.Option{solver.WithDiskStore(b.TempDir(), nbWires*wireBytes/8)}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				res, err := ccs.Solve(witness, bc.opts...)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if store := res.(*cs.R1CSSolution).Wires; store != nil {
+					if err := store.Close(); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
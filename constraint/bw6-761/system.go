@@ -17,6 +17,8 @@
 package cs
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
 	"io"
 	"time"
 
@@ -82,9 +84,24 @@ func (cs *system) Solve(witness witness.Witness, opts ...csolver.Option) (any, e
 		}
 	}
 
+	// the disk-backed wire store, if any, is only handed over with a R1CS solution.
+	keepStore := false
+	if solver.store != nil {
+		defer func() {
+			if !keepStore {
+				if err := solver.store.Close(); err != nil {
+					log.Warn().Err(err).Msg("closing wire store")
+				}
+			}
+		}()
+	}
+
 	// defer log printing once all solver.values are computed
 	// (or sooner, if a constraint is not satisfied)
 	defer solver.printLogs(cs.Logs)
+	if solver.trace != nil {
+		defer solver.traceWires()
+	}
 
 	// run it.
 	if err := solver.run(); err != nil {
@@ -99,15 +116,23 @@ func (cs *system) Solve(witness witness.Witness, opts ...csolver.Option) (any, e
 	if cs.Type == constraint.SystemR1CS {
 		var res R1CSSolution
 		res.W = solver.values
+		res.Wires = solver.store
 		res.A = solver.a
 		res.B = solver.b
 		res.C = solver.c
+		keepStore = true
 		return &res, nil
 	} else {
 		// sparse R1CS
 		var res SparseR1CSSolution
 		// query l, r, o in Lagrange basis, not blinded
-		res.L, res.R, res.O = evaluateLROSmallDomain(cs, solver.values)
+		res.L, res.R, res.O = evaluateLROSmallDomain(cs, solver.value)
+		if solver.store != nil {
+			if err := solver.store.Err(); err != nil {
+				log.Err(err).Send()
+				return nil, err
+			}
+		}
 
 		return &res, nil
 	}
@@ -151,6 +176,20 @@ func (cs *system) GetCoefficient(i int) (r constraint.Element) {
 	return
 }
 
+// ContentHash returns a deterministic SHA-256 hash of the constraint system and its
+// coefficients (see constraint.ConstraintSystem).
+func (cs *system) ContentHash() ([]byte, error) {
+	h := sha256.New()
+	if err := cs.System.HashContent(h); err != nil {
+		return nil, err
+	}
+	for i := range cs.Coefficients {
+		b := cs.Coefficients[i].Bytes()
+		h.Write(b[:])
+	}
+	return h.Sum(nil), nil
+}
+
 // GetSparseR1Cs return the list of SparseR1C
 func (cs *system) GetSparseR1Cs() []constraint.SparseR1C {
 
@@ -169,8 +208,11 @@ func (cs *system) GetSparseR1Cs() []constraint.SparseR1C {
 
 // evaluateLROSmallDomain extracts the solver l, r, o, and returns it in lagrange form.
 // solver = [ public | secret | internal ]
+//
+// l, r and o are allocated in full, also when the wire values are in a disk store (see
+// solver.WithDiskStore): the store then only bounds the memory used while solving.
 // TODO @gbotrel refactor; this seems to be a small util function for plonk
-func evaluateLROSmallDomain(cs *system, solution []fr.Element) ([]fr.Element, []fr.Element, []fr.Element) {
+func evaluateLROSmallDomain(cs *system, solution func(wireID int) fr.Element) ([]fr.Element, []fr.Element, []fr.Element) {
 
 	//s := int(pk.Domain[0].Cardinality)
 	s := cs.GetNbConstraints() + len(cs.Public) // len(spr.Public) is for the placeholder constraints
@@ -180,10 +222,10 @@ func evaluateLROSmallDomain(cs *system, solution []fr.Element) ([]fr.Element, []
 	l = make([]fr.Element, s, s+4) // +4 to leave room for the blinding in plonk
 	r = make([]fr.Element, s, s+4)
 	o = make([]fr.Element, s, s+4)
-	s0 := solution[0]
+	s0 := solution(0)
 
 	for i := 0; i < len(cs.Public); i++ { // placeholders
-		l[i] = solution[i]
+		l[i] = solution(i)
 		r[i] = s0
 		o[i] = s0
 	}
@@ -197,9 +239,9 @@ func evaluateLROSmallDomain(cs *system, solution []fr.Element) ([]fr.Element, []
 		if bc, ok := blueprint.(constraint.BlueprintSparseR1C); ok {
 			bc.DecompressSparseR1C(&sparseR1C, inst.Unpack(&cs.System))
 
-			l[offset+j] = solution[sparseR1C.XA]
-			r[offset+j] = solution[sparseR1C.XB]
-			o[offset+j] = solution[sparseR1C.XC]
+			l[offset+j] = solution(int(sparseR1C.XA))
+			r[offset+j] = solution(int(sparseR1C.XB))
+			o[offset+j] = solution(int(sparseR1C.XC))
 			j++
 		}
 	}
@@ -218,13 +260,23 @@ func evaluateLROSmallDomain(cs *system, solution []fr.Element) ([]fr.Element, []
 
 // R1CSSolution represent a valid assignment to all the variables in the constraint system.
 // The vector W such that Aw o Bw - Cw = 0
+//
+// If the solver was configured with a disk store (see solver.WithDiskStore), W is nil and
+// the wire values are in Wires, which must be closed by the caller.
 type R1CSSolution struct {
 	W       fr.Vector
+	Wires   *WireStore
 	A, B, C fr.Vector
 }
 
 func (t *R1CSSolution) WriteTo(w io.Writer) (int64, error) {
-	n, err := t.W.WriteTo(w)
+	var n int64
+	var err error
+	if t.Wires != nil {
+		n, err = writeWires(w, t.Wires)
+	} else {
+		n, err = t.W.WriteTo(w)
+	}
 	if err != nil {
 		return n, err
 	}
@@ -263,6 +315,26 @@ func (t *R1CSSolution) ReadFrom(r io.Reader) (int64, error) {
 	return n, err
 }
 
+// writeWires writes the wire values of the store in the fr.Vector encoding.
+func writeWires(w io.Writer, wires *WireStore) (int64, error) {
+	if err := binary.Write(w, binary.BigEndian, uint32(wires.Len())); err != nil {
+		return 0, err
+	}
+	n := int64(4)
+	err := wires.ForEachChunk(func(_ int, chunk []fr.Element) error {
+		for i := range chunk {
+			buf := chunk[i].Bytes()
+			m, err := w.Write(buf[:])
+			n += int64(m)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return n, err
+}
+
 // SparseR1CSSolution represent a valid assignment to all the variables in the constraint system.
 type SparseR1CSSolution struct {
 	L, R, O fr.Vector
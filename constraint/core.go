@@ -1,6 +1,8 @@
 package constraint
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"math/big"
 	"strconv"
@@ -78,6 +80,10 @@ type System struct {
 	// serialization header
 	GnarkVersion string
 	ScalarField  string
+	// hex encoded content hash (see ConstraintSystem.ContentHash), only present in the
+	// serialized system: it is written by WriteTo and checked and cleared by ReadFrom.
+	// Empty for older serialized systems.
+	Hash string
 
 	Type SystemType
 
@@ -104,6 +110,12 @@ type System struct {
 	// maps constraint id to debugInfo id
 	// several constraints may point to the same debug info
 	MDebug map[int]int
+	// maps hint instruction id to debugInfo id (only set in debug mode)
+	MHintsDebug map[int]int
+	// maps wires marked as boolean by the user (see frontend.Builder.MarkBoolean) to debugInfo id,
+	// or -1 outside debug mode. This is not used by the solver but by the static analysis (see
+	// Analyze)
+	MBooleanAssumptions map[int]int
 
 	// maps hintID to hint string identifier
 	MHintsDependencies map[solver.HintID]string
@@ -132,19 +144,21 @@ type System struct {
 // NewSystem initialize the common structure among constraint system
 func NewSystem(scalarField *big.Int, capacity int, t SystemType) System {
 	system := System{
-		Type:               t,
-		SymbolTable:        debug.NewSymbolTable(),
-		MDebug:             map[int]int{},
-		GnarkVersion:       gnark.Version.String(),
-		ScalarField:        scalarField.Text(16),
-		MHintsDependencies: make(map[solver.HintID]string),
-		q:                  new(big.Int).Set(scalarField),
-		bitLen:             scalarField.BitLen(),
-		Instructions:       make([]PackedInstruction, 0, capacity),
-		CallData:           make([]uint32, 0, capacity*8),
-		lbWireLevel:        make([]Level, 0, capacity),
-		Levels:             make([][]uint32, 0, capacity/2),
-		CommitmentInfo:     NewCommitments(t),
+		Type:                t,
+		SymbolTable:         debug.NewSymbolTable(),
+		MDebug:              map[int]int{},
+		MHintsDebug:         map[int]int{},
+		MBooleanAssumptions: map[int]int{},
+		GnarkVersion:        gnark.Version.String(),
+		ScalarField:         scalarField.Text(16),
+		MHintsDependencies:  make(map[solver.HintID]string),
+		q:                   new(big.Int).Set(scalarField),
+		bitLen:              scalarField.BitLen(),
+		Instructions:        make([]PackedInstruction, 0, capacity),
+		CallData:            make([]uint32, 0, capacity*8),
+		lbWireLevel:         make([]Level, 0, capacity),
+		Levels:              make([][]uint32, 0, capacity/2),
+		CommitmentInfo:      NewCommitments(t),
 	}
 
 	system.genericHint = system.AddBlueprint(&BlueprintGenericHint{})
@@ -207,6 +221,13 @@ func (system *System) CheckSerializationHeader() error {
 	}
 	system.q = new(big.Int).Set(scalarField)
 	system.bitLen = system.q.BitLen()
+
+	// the content hash is checked against the coefficients by the curve-typed system
+	if system.Hash != "" {
+		if h, err := hex.DecodeString(system.Hash); err != nil || len(h) != sha256.Size {
+			return fmt.Errorf("invalid content hash %q", system.Hash)
+		}
+	}
 	return nil
 }
 
@@ -300,6 +321,11 @@ func (system *System) AddSolverHint(f solver.Hint, id solver.HintID, input []Lin
 	// return []uint32 to the pool
 	putBuffer(calldata)
 
+	if debug.Debug {
+		system.DebugInfo = append(system.DebugInfo, LogEntry(system.NewDebugInfo("hint", name)))
+		system.MHintsDebug[len(system.Instructions)-1] = len(system.DebugInfo) - 1
+	}
+
 	return
 }
 
@@ -327,6 +353,18 @@ func (system *System) AttachDebugInfo(debugInfo DebugInfo, constraintID []int) {
 	}
 }
 
+// AddBooleanAssumption records that the wire was marked as boolean by the user without
+// being constrained, along with the optional debug information of the call site. This is
+// used by the static analysis only (see Analyze).
+func (system *System) AddBooleanAssumption(wireID int, debugInfo ...DebugInfo) {
+	if len(debugInfo) == 0 {
+		system.MBooleanAssumptions[wireID] = -1
+		return
+	}
+	system.DebugInfo = append(system.DebugInfo, LogEntry(debugInfo[0]))
+	system.MBooleanAssumptions[wireID] = len(system.DebugInfo) - 1
+}
+
 // VariableToString implements Resolver
 func (system *System) VariableToString(vID int) string {
 	nbPublic := system.GetNbPublicVariables()
@@ -0,0 +1,403 @@
+package constraint
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SystemDiff is the difference between two constraint systems, see Diff.
+type SystemDiff struct {
+	// Identical is true if the systems have the same content hash (see
+	// ConstraintSystem.ContentHash): keys generated for one are valid for the other.
+	Identical bool
+
+	OldNbConstraints, NewNbConstraints int
+
+	// Constraints are the constraints added and removed, grouped by the function of the
+	// circuit which added them, sorted by function.
+	Constraints []ConstraintsDiff
+
+	PublicAdded, PublicRemoved []string
+	SecretAdded, SecretRemoved []string
+	// PublicReordered is true if the public inputs present in both systems are not in
+	// the same order, which changes the public witness.
+	PublicReordered bool
+
+	// OldCommitments and NewCommitments describe the commitments of the systems, if they
+	// differ.
+	OldCommitments, NewCommitments []string
+
+	// Hints are the hints whose number of calls changed, by name.
+	Hints []CountDiff
+	// Blueprints are the blueprints whose number of instructions changed, by type.
+	Blueprints []CountDiff
+}
+
+// ConstraintsDiff are the constraints added and removed by a function of the circuit.
+type ConstraintsDiff struct {
+	// Function is the innermost function of the circuit code in the stack of the
+	// constraints (the frontend API methods are skipped), or "" if the constraints have no
+	// debug information. Line numbers are ignored when matching the constraints, so that
+	// moving code around does not show up as changes.
+	Function string
+
+	// Added and Removed are the constraints only in the new (resp. old) system.
+	Added, Removed []DiffConstraint
+}
+
+// DiffConstraint is a constraint added or removed, see ConstraintsDiff.
+type DiffConstraint struct {
+	// Constraint is formatted with the inputs by name and the internal wires as "v"
+	// (their IDs are not comparable across systems).
+	Constraint string
+
+	// Location is the file:line of the constraint in Function, or "" if the constraint
+	// has no debug information.
+	Location string
+}
+
+// CountDiff is a change in the number of occurrences of a named item.
+type CountDiff struct {
+	Name     string
+	Old, New int
+}
+
+// Diff compares two constraint systems of the same type (R1CS or SparseR1CS) over the same
+// field. It reports the constraints added and removed grouped by the circuit function which
+// created them, and the changes in inputs, commitments, hints and blueprints.
+//
+// Constraints are matched by their formatted form (see DiffConstraint), as a multiset per
+// function, and reported with their file:line. Functions are only known for the constraints with debug information, which
+// most builder methods (in particular the assertions) only record when compiling with the
+// debug build tag. Without it, most constraints are grouped under "" and the diff only
+// tells how many constraints changed and what they look like, not where they come from:
+// compile both systems with -tags=debug to group them by function.
+//
+// Any difference in the constraints or in the inputs changes the proving and verifying
+// keys; Identical reports whether keys can be reused.
+func Diff(oldCS, newCS ConstraintSystem) (*SystemDiff, error) {
+	oldS, ok := oldCS.(interface{ getSystem() *System })
+	if !ok {
+		return nil, errors.New("constraint system does not embed constraint.System")
+	}
+	newS, ok := newCS.(interface{ getSystem() *System })
+	if !ok {
+		return nil, errors.New("constraint system does not embed constraint.System")
+	}
+	o, n := oldS.getSystem(), newS.getSystem()
+	if o.Type != n.Type {
+		return nil, errors.New("constraint systems are not of the same type")
+	}
+	if o.ScalarField != n.ScalarField {
+		return nil, errors.New("constraint systems are not over the same field")
+	}
+
+	oldHash, err := oldCS.ContentHash()
+	if err != nil {
+		return nil, err
+	}
+	newHash, err := newCS.ContentHash()
+	if err != nil {
+		return nil, err
+	}
+
+	d := &SystemDiff{
+		Identical:        bytes.Equal(oldHash, newHash),
+		OldNbConstraints: oldCS.GetNbConstraints(),
+		NewNbConstraints: newCS.GetNbConstraints(),
+	}
+
+	// inputs
+	d.PublicRemoved, d.PublicAdded = diffNames(o.Public, n.Public)
+	d.SecretRemoved, d.SecretAdded = diffNames(o.Secret, n.Secret)
+	d.PublicReordered = !sameOrder(o.Public, n.Public)
+
+	// commitments
+	oldCommitments, newCommitments := describeCommitments(o), describeCommitments(n)
+	if strings.Join(oldCommitments, "\n") != strings.Join(newCommitments, "\n") {
+		d.OldCommitments, d.NewCommitments = oldCommitments, newCommitments
+	}
+
+	// constraints, hints and blueprints
+	oldC, oldHints, oldBlueprints := summarize(oldCS, o)
+	newC, newHints, newBlueprints := summarize(newCS, n)
+	d.Hints = diffCounts(oldHints, newHints)
+	d.Blueprints = diffCounts(oldBlueprints, newBlueprints)
+
+	functions := make(map[string]struct{})
+	for f := range oldC {
+		functions[f] = struct{}{}
+	}
+	for f := range newC {
+		functions[f] = struct{}{}
+	}
+	for f := range functions {
+		removed, added := diffConstraints(oldC[f], newC[f])
+		if len(removed) != 0 || len(added) != 0 {
+			d.Constraints = append(d.Constraints, ConstraintsDiff{Function: f, Added: added, Removed: removed})
+		}
+	}
+	sort.Slice(d.Constraints, func(i, j int) bool { return d.Constraints[i].Function < d.Constraints[j].Function })
+
+	return d, nil
+}
+
+// String returns a report of the differences.
+func (d *SystemDiff) String() string {
+	var sbb strings.Builder
+	if d.Identical {
+		sbb.WriteString("constraint systems are identical\n")
+		return sbb.String()
+	}
+	fmt.Fprintf(&sbb, "constraints: %d -> %d (%+d)\n", d.OldNbConstraints, d.NewNbConstraints, d.NewNbConstraints-d.OldNbConstraints)
+	writeNames := func(what string, names []string) {
+		if len(names) != 0 {
+			fmt.Fprintf(&sbb, "%s: %s\n", what, strings.Join(names, ", "))
+		}
+	}
+	writeNames("public inputs added", d.PublicAdded)
+	writeNames("public inputs removed", d.PublicRemoved)
+	if d.PublicReordered {
+		sbb.WriteString("public inputs reordered\n")
+	}
+	writeNames("secret inputs added", d.SecretAdded)
+	writeNames("secret inputs removed", d.SecretRemoved)
+	if d.OldCommitments != nil || d.NewCommitments != nil {
+		sbb.WriteString("commitments changed:\n")
+		for _, c := range d.OldCommitments {
+			fmt.Fprintf(&sbb, "\t- %s\n", c)
+		}
+		for _, c := range d.NewCommitments {
+			fmt.Fprintf(&sbb, "\t+ %s\n", c)
+		}
+	}
+	writeCounts := func(what string, counts []CountDiff) {
+		if len(counts) == 0 {
+			return
+		}
+		fmt.Fprintf(&sbb, "%s:\n", what)
+		for _, c := range counts {
+			fmt.Fprintf(&sbb, "\t%s: %d -> %d\n", c.Name, c.Old, c.New)
+		}
+	}
+	writeCounts("hint calls", d.Hints)
+	writeCounts("blueprint instructions", d.Blueprints)
+	for _, c := range d.Constraints {
+		f := c.Function
+		if f == "" {
+			f = "<no debug info, compile with -tags=debug>"
+		}
+		fmt.Fprintf(&sbb, "%s: +%d -%d\n", f, len(c.Added), len(c.Removed))
+		writeConstraint := func(sign byte, dc DiffConstraint) {
+			if dc.Location == "" {
+				fmt.Fprintf(&sbb, "\t%c %s\n", sign, dc.Constraint)
+			} else {
+				fmt.Fprintf(&sbb, "\t%c %s (%s)\n", sign, dc.Constraint, dc.Location)
+			}
+		}
+		for _, dc := range c.Removed {
+			writeConstraint('-', dc)
+		}
+		for _, dc := range c.Added {
+			writeConstraint('+', dc)
+		}
+	}
+	return sbb.String()
+}
+
+// diffResolver formats the inputs by name and the internal wires as "v".
+type diffResolver struct {
+	Resolver
+	nbInputs int
+}
+
+func (r diffResolver) VariableToString(vID int) string {
+	if vID < r.nbInputs {
+		return r.Resolver.VariableToString(vID)
+	}
+	return "v"
+}
+
+// summarize returns the formatted constraints by function, and the number of hint calls
+// and blueprint instructions by name. The constraints without debug information are
+// under "".
+func summarize(cs ConstraintSystem, system *System) (constraints map[string][]DiffConstraint, hints, blueprints map[string]int) {
+	constraints = make(map[string][]DiffConstraint)
+	hints = make(map[string]int)
+	blueprints = make(map[string]int)
+	r := diffResolver{Resolver: cs, nbInputs: system.GetNbPublicVariables() + system.GetNbSecretVariables()}
+
+	var (
+		r1c       R1C
+		sparseR1C SparseR1C
+		hm        HintMapping
+	)
+	for _, pi := range system.Instructions {
+		blueprint := system.Blueprints[pi.BlueprintID]
+		inst := pi.Unpack(system)
+		bName := fmt.Sprintf("%T", blueprint)
+		blueprints[bName]++
+
+		var formatted string
+		if bc, ok := blueprint.(BlueprintR1C); ok && system.Type == SystemR1CS {
+			bc.DecompressR1C(&r1c, inst)
+			formatted = r1c.String(r)
+		} else if bc, ok := blueprint.(BlueprintSparseR1C); ok && system.Type == SystemSparseR1CS {
+			bc.DecompressSparseR1C(&sparseR1C, inst)
+			formatted = sparseR1C.String(r)
+		} else if bc, ok := blueprint.(BlueprintHint); ok {
+			bc.DecompressHint(&hm, inst)
+			name, ok := system.MHintsDependencies[hm.HintID]
+			if !ok {
+				name = fmt.Sprintf("%d", hm.HintID)
+			}
+			hints[name]++
+			continue
+		} else {
+			formatted = bName
+		}
+
+		for i := 0; i < blueprint.NbConstraints(); i++ {
+			cID := int(pi.ConstraintOffset) + i
+			f, location := "", ""
+			if dID, ok := system.MDebug[cID]; ok {
+				f, location = system.circuitLocation(system.DebugInfo[dID].Stack)
+			}
+			constraints[f] = append(constraints[f], DiffConstraint{Constraint: formatted, Location: location})
+		}
+	}
+	return
+}
+
+// circuitLocation returns the innermost function of the stack which is not a method of
+// a frontend builder, and the file:line of the call in it.
+func (system *System) circuitLocation(stack []int) (function, location string) {
+	for _, lID := range stack {
+		l := system.SymbolTable.Locations[lID]
+		f := system.SymbolTable.Functions[l.FunctionID]
+		if strings.HasPrefix(f.Name, "r1cs.(*builder)") || strings.HasPrefix(f.Name, "scs.(*builder)") {
+			continue
+		}
+		return f.Name, fmt.Sprintf("%s:%d", f.Filename, l.Line)
+	}
+	return "", ""
+}
+
+// describeCommitments returns a description of the commitments which does not depend on
+// the internal wire IDs.
+func describeCommitments(system *System) []string {
+	var res []string
+	switch c := system.CommitmentInfo.(type) {
+	case Groth16Commitments:
+		for i := range c {
+			public := make([]string, 0, c[i].NbPublicCommitted)
+			for _, w := range c[i].GetPublicCommitted() {
+				public = append(public, system.VariableToString(w))
+			}
+			res = append(res, fmt.Sprintf("commitment %d: public [%s], %d commitments, %d private wires",
+				i, strings.Join(public, ", "), len(c[i].GetCommitmentCommitted()), len(c[i].PrivateCommitted)))
+		}
+	case PlonkCommitments:
+		for i := range c {
+			res = append(res, fmt.Sprintf("commitment %d: %d committed values", i, len(c[i].Committed)))
+		}
+	}
+	return res
+}
+
+// diffNames returns the names only in a and only in b.
+func diffNames(a, b []string) (onlyA, onlyB []string) {
+	inA, inB := make(map[string]struct{}, len(a)), make(map[string]struct{}, len(b))
+	for _, s := range a {
+		inA[s] = struct{}{}
+	}
+	for _, s := range b {
+		inB[s] = struct{}{}
+	}
+	for _, s := range a {
+		if _, ok := inB[s]; !ok {
+			onlyA = append(onlyA, s)
+		}
+	}
+	for _, s := range b {
+		if _, ok := inA[s]; !ok {
+			onlyB = append(onlyB, s)
+		}
+	}
+	return
+}
+
+// sameOrder returns true if the names present in both a and b are in the same order.
+func sameOrder(a, b []string) bool {
+	inB := make(map[string]struct{}, len(b))
+	for _, s := range b {
+		inB[s] = struct{}{}
+	}
+	inA := make(map[string]struct{}, len(a))
+	for _, s := range a {
+		inA[s] = struct{}{}
+	}
+	var commonA, commonB []string
+	for _, s := range a {
+		if _, ok := inB[s]; ok {
+			commonA = append(commonA, s)
+		}
+	}
+	for _, s := range b {
+		if _, ok := inA[s]; ok {
+			commonB = append(commonB, s)
+		}
+	}
+	return strings.Join(commonA, "\x00") == strings.Join(commonB, "\x00")
+}
+
+// diffConstraints returns the constraints of a not matched in b and of b not matched in
+// a, sorted. The constraints are matched by their formatted form only, in order.
+func diffConstraints(a, b []DiffConstraint) (onlyA, onlyB []DiffConstraint) {
+	unmatched := make(map[string][]DiffConstraint)
+	for _, c := range b {
+		unmatched[c.Constraint] = append(unmatched[c.Constraint], c)
+	}
+	for _, c := range a {
+		if m := unmatched[c.Constraint]; len(m) != 0 {
+			unmatched[c.Constraint] = m[1:]
+		} else {
+			onlyA = append(onlyA, c)
+		}
+	}
+	for _, m := range unmatched {
+		onlyB = append(onlyB, m...)
+	}
+	sortConstraints(onlyA)
+	sortConstraints(onlyB)
+	return
+}
+
+func sortConstraints(c []DiffConstraint) {
+	sort.Slice(c, func(i, j int) bool {
+		if c[i].Constraint != c[j].Constraint {
+			return c[i].Constraint < c[j].Constraint
+		}
+		return c[i].Location < c[j].Location
+	})
+}
+
+// diffCounts returns the names whose count differ, sorted by name.
+func diffCounts(a, b map[string]int) []CountDiff {
+	var res []CountDiff
+	for name, c := range a {
+		if b[name] != c {
+			res = append(res, CountDiff{Name: name, Old: c, New: b[name]})
+		}
+	}
+	for name, c := range b {
+		if _, ok := a[name]; !ok {
+			res = append(res, CountDiff{Name: name, Old: 0, New: c})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
@@ -0,0 +1,102 @@
+package constraint_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/debug"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+// diffCircuit is refactored when Z is set: it has a new public input, a hint and a
+// constraint in a gadget.
+type diffCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable   `gnark:",public"`
+	Z []frontend.Variable `gnark:",public"`
+}
+
+func (c *diffCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	for i := range c.Z {
+		diffGadget(api, c.X, c.Z[i])
+	}
+	return nil
+}
+
+func diffGadget(api frontend.API, x, z frontend.Variable) {
+	res, err := api.Compiler().NewHint(analysisIdentityHint, 1, x)
+	if err != nil {
+		panic(err)
+	}
+	api.AssertIsEqual(res[0], x)
+	api.AssertIsEqual(api.Add(x, 1), z)
+}
+
+func TestDiff(t *testing.T) {
+	assert := require.New(t)
+	field := ecc.BN254.ScalarField()
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		v1, err := frontend.Compile(field, builder, &diffCircuit{})
+		assert.NoError(err)
+		v1Bis, err := frontend.Compile(field, builder, &diffCircuit{})
+		assert.NoError(err)
+		v2, err := frontend.Compile(field, builder, &diffCircuit{Z: make([]frontend.Variable, 1)})
+		assert.NoError(err)
+
+		d, err := constraint.Diff(v1, v1Bis)
+		assert.NoError(err)
+		assert.True(d.Identical)
+		assert.Empty(d.Constraints)
+
+		d, err = constraint.Diff(v1, v2)
+		assert.NoError(err)
+		assert.False(d.Identical)
+		assert.Equal([]string{"Z_0"}, d.PublicAdded)
+		assert.Empty(d.PublicRemoved)
+		assert.False(d.PublicReordered)
+		assert.Equal([]constraint.CountDiff{{Name: solver.GetHintName(analysisIdentityHint), Old: 0, New: 1}}, d.Hints)
+		assert.NotEmpty(d.Blueprints)
+
+		nbAdded, nbRemoved := 0, 0
+		var functions []string
+		for _, c := range d.Constraints {
+			nbAdded += len(c.Added)
+			nbRemoved += len(c.Removed)
+			functions = append(functions, c.Function)
+		}
+		if debug.Debug {
+			// the assertions only record their stack in debug builds
+			assert.Contains(functions, "constraint_test.diffGadget")
+			// and the added constraints keep their location in it
+			nbLocated := 0
+			for _, c := range d.Constraints {
+				if c.Function != "constraint_test.diffGadget" {
+					continue
+				}
+				for _, added := range c.Added {
+					assert.Regexp(`diff_test\.go:\d+$`, added.Location)
+					assert.Contains(d.String(), "("+added.Location+")")
+					nbLocated++
+				}
+			}
+			assert.NotZero(nbLocated)
+		} else {
+			assert.Equal([]string{""}, functions)
+			assert.Contains(d.String(), "<no debug info, compile with -tags=debug>")
+		}
+		assert.Equal(v2.GetNbConstraints()-v1.GetNbConstraints(), nbAdded-nbRemoved)
+		assert.Contains(d.String(), "public inputs added: Z_0")
+
+		// the systems must be comparable
+		other, err := frontend.Compile(ecc.BLS12_381.ScalarField(), builder, &diffCircuit{})
+		assert.NoError(err)
+		_, err = constraint.Diff(v1, other)
+		assert.Error(err)
+	}
+}
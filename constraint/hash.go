@@ -0,0 +1,131 @@
+package constraint
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/fxamacker/cbor/v2"
+)
+
+// HashContent writes a canonical encoding of the system to h, covering the type, the
+// field, the inputs, the blueprints, the instructions and their calldata, the hints and
+// the commitments. The gnark version, the levels, the debug information and the logs are
+// not covered: they do not change the relation encoded by the system.
+//
+// This is not meant to be called directly since the constraint.System is embedded in
+// a "curve-typed" system (e.g. bls12-381.system) which adds the coefficients (see
+// ConstraintSystem.ContentHash).
+func (system *System) HashContent(h io.Writer) error {
+	enc, err := cbor.CoreDetEncOptions().EncModeWithTags(getTagSet())
+	if err != nil {
+		return err
+	}
+
+	w := hashWriter{w: h}
+	w.writeString("gnark constraint system")
+	w.writeUint64(uint64(system.Type))
+	w.writeString(system.ScalarField)
+
+	w.writeUint64(uint64(len(system.Public)))
+	for _, name := range system.Public {
+		w.writeString(name)
+	}
+	w.writeUint64(uint64(len(system.Secret)))
+	for _, name := range system.Secret {
+		w.writeString(name)
+	}
+	w.writeUint64(uint64(system.NbInternalVariables))
+	w.writeUint64(uint64(system.NbConstraints))
+
+	w.writeUint64(uint64(len(system.Blueprints)))
+	for _, b := range system.Blueprints {
+		bb, err := enc.Marshal(b)
+		if err != nil {
+			return fmt.Errorf("encoding blueprint %T: %w", b, err)
+		}
+		w.writeString(reflect.TypeOf(b).String())
+		w.writeBytes(bb)
+	}
+
+	w.writeUint64(uint64(len(system.Instructions)))
+	for _, inst := range system.Instructions {
+		w.writeUint64(uint64(inst.BlueprintID))
+		w.writeUint64(uint64(inst.ConstraintOffset))
+		w.writeUint64(uint64(inst.WireOffset))
+		w.writeUint64(inst.StartCallData)
+	}
+	w.writeUint64(uint64(len(system.CallData)))
+	for _, v := range system.CallData {
+		w.writeUint32(v)
+	}
+
+	// hints, in id order
+	hintIDs := make([]solver.HintID, 0, len(system.MHintsDependencies))
+	for id := range system.MHintsDependencies {
+		hintIDs = append(hintIDs, id)
+	}
+	sort.Slice(hintIDs, func(i, j int) bool { return hintIDs[i] < hintIDs[j] })
+	w.writeUint64(uint64(len(hintIDs)))
+	for _, id := range hintIDs {
+		w.writeUint64(uint64(id))
+		w.writeString(system.MHintsDependencies[id])
+	}
+
+	commitments, err := enc.Marshal(system.CommitmentInfo)
+	if err != nil {
+		return fmt.Errorf("encoding commitments: %w", err)
+	}
+	w.writeBytes(commitments)
+	gkr, err := enc.Marshal(system.GkrInfo)
+	if err != nil {
+		return fmt.Errorf("encoding GKR info: %w", err)
+	}
+	w.writeBytes(gkr)
+
+	return w.flush()
+}
+
+// hashWriter buffers the length-prefixed and fixed-size values written to the hash.
+type hashWriter struct {
+	w   io.Writer
+	buf []byte
+	err error
+}
+
+func (w *hashWriter) writeUint64(v uint64) {
+	w.buf = binary.LittleEndian.AppendUint64(w.buf, v)
+	w.maybeFlush()
+}
+
+func (w *hashWriter) writeUint32(v uint32) {
+	w.buf = binary.LittleEndian.AppendUint32(w.buf, v)
+	w.maybeFlush()
+}
+
+func (w *hashWriter) writeBytes(b []byte) {
+	w.writeUint64(uint64(len(b)))
+	w.buf = append(w.buf, b...)
+	w.maybeFlush()
+}
+
+func (w *hashWriter) writeString(s string) {
+	w.writeBytes([]byte(s))
+}
+
+func (w *hashWriter) maybeFlush() {
+	if len(w.buf) >= 1<<16 {
+		w.flush()
+	}
+}
+
+func (w *hashWriter) flush() error {
+	if w.err == nil && len(w.buf) != 0 {
+		_, w.err = w.w.Write(w.buf)
+	}
+	w.buf = w.buf[:0]
+	return w.err
+}
@@ -15,6 +15,7 @@
 package constraint
 
 import (
+	"strconv"
 	"strings"
 )
 
@@ -35,3 +36,24 @@ func (l *LogEntry) WriteVariable(le LinearExpression, sbb *strings.Builder) {
 	sbb.WriteString("%s")
 	l.ToResolve = append(l.ToResolve, le)
 }
+
+// VariableNames returns a name for each of the linear expressions to resolve: the word
+// preceding its placeholder in the format (e.g. "x" for api.Println("x", x), or the field
+// name for a struct argument), or "caller#i" for the i-th expression if there is none.
+func (l *LogEntry) VariableNames() []string {
+	names := make([]string, len(l.ToResolve))
+	segments := strings.Split(l.Format, "%s")
+	for i := range names {
+		var name string
+		if i < len(segments) {
+			if fields := strings.Fields(segments[i]); len(fields) != 0 {
+				name = strings.Trim(fields[len(fields)-1], "{}:=,")
+			}
+		}
+		if name == "" {
+			name = l.Caller + "#" + strconv.Itoa(i)
+		}
+		names[i] = name
+	}
+	return names
+}
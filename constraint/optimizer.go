@@ -0,0 +1,893 @@
+package constraint
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/consensys/gnark/logger"
+)
+
+// OptimizationReport summarizes the changes made by Optimize.
+type OptimizationReport struct {
+	NbConstraintsBefore, NbConstraintsAfter             int
+	NbInternalVariablesBefore, NbInternalVariablesAfter int
+	NbLevelsBefore, NbLevelsAfter                       int
+
+	NbSubstitutedWires     int // internal wires eliminated by linear substitution
+	NbRemovedConstraints   int // duplicate or trivially satisfied constraints
+	NbRemovedHints         int // hints whose outputs are not used
+	NbRemovedInternalWires int // internal wires not used anymore (including substituted ones)
+}
+
+// maxSubstitutionTerms is the maximum number of terms of a linear definition substituted in
+// more than one place; it bounds the growth of the linear expressions of a R1CS.
+const maxSubstitutionTerms = 3
+
+// Optimize runs an optimization pass over a compiled constraint system, in place. Since it
+// changes the wires and constraints, it must be called before the setup of the proving
+// system. It
+//   - eliminates internal wires defined by a single linear constraint, substituting their
+//     definition in the constraints, hints and logs using them. In a SparseR1CS, only wires
+//     defined as an affine function of at most one other wire are eliminated, so that the
+//     constraints keep their shape;
+//   - removes duplicate and trivially satisfied constraints;
+//   - removes hints whose outputs are not used, and the internal wires which are not used
+//     anymore;
+//   - rebuilds the solver instruction tree.
+//
+// Input wires, boolean assumptions and the wires and constraints involved in commitments
+// are kept. Optimize returns an error if the system uses GKR.
+//
+// The instructions of blueprints other than the builtin R1C, SparseR1C and hint ones (for
+// example the lookups of std/lookup/logderivlookup) are kept as is. Since their calldata
+// can't be rewritten, all the wires are then kept with their ids: only the duplicate and
+// trivially satisfied constraints are removed.
+func Optimize(cs ConstraintSystem) (OptimizationReport, error) {
+	s, ok := cs.(interface{ getSystem() *System })
+	if !ok {
+		return OptimizationReport{}, fmt.Errorf("unsupported constraint system type %T", cs)
+	}
+	system := s.getSystem()
+	if system.GkrInfo.Is() {
+		return OptimizationReport{}, errors.New("GKR is not supported by the optimizer")
+	}
+
+	o := newOptimizer(cs, system)
+	o.report.NbConstraintsBefore = system.NbConstraints
+	o.report.NbInternalVariablesBefore = system.NbInternalVariables
+	o.report.NbLevelsBefore = len(system.Levels)
+
+	o.substitute()
+	o.removeDuplicates()
+	o.removeDeadHints()
+	o.rebuild()
+
+	o.report.NbConstraintsAfter = system.NbConstraints
+	o.report.NbInternalVariablesAfter = system.NbInternalVariables
+	o.report.NbLevelsAfter = len(system.Levels)
+	o.report.NbRemovedInternalWires = o.report.NbInternalVariablesBefore - o.report.NbInternalVariablesAfter
+
+	log := logger.Logger()
+	log.Debug().
+		Int("nbConstraintsBefore", o.report.NbConstraintsBefore).
+		Int("nbConstraintsAfter", o.report.NbConstraintsAfter).
+		Int("nbInternalVariablesBefore", o.report.NbInternalVariablesBefore).
+		Int("nbInternalVariablesAfter", o.report.NbInternalVariablesAfter).
+		Msg("constraint system optimized")
+
+	return o.report, nil
+}
+
+type optKind uint8
+
+const (
+	optR1C optKind = iota
+	optSparseR1C
+	optHint
+	optOpaque // instruction of a non-builtin blueprint, kept as is
+)
+
+// optInstruction is a decompressed instruction of the system being optimized.
+type optInstruction struct {
+	kind optKind
+	bID  BlueprintID
+	r1c  R1C
+	sr1c SparseR1C
+	hint HintMapping
+
+	calldata   []uint32 // calldata of an opaque instruction
+	wireOffset uint32   // wire offset of the instruction in the original system
+
+	cID    int // constraint id in the original system, -1 for hints
+	solves int // wire solved by the constraint, -1 if none
+
+	pinned   bool // involved in a commitment, must be kept as is
+	removed  bool
+	modified bool // the specialized blueprint may not apply anymore
+}
+
+type optimizer struct {
+	cs       ConstraintSystem
+	system   *System
+	nbInputs int
+	nbWires  int
+
+	insts     []optInstruction
+	users     [][]int // wire -> instructions referencing it (may contain duplicates)
+	protected []bool  // wires which must not be eliminated
+	defs      map[int]LinearExpression
+	opaque    bool // the system has non-builtin blueprints, all the wires are kept
+
+	report OptimizationReport
+}
+
+func newOptimizer(cs ConstraintSystem, system *System) *optimizer {
+	o := &optimizer{
+		cs:       cs,
+		system:   system,
+		nbInputs: system.GetNbPublicVariables() + system.GetNbSecretVariables(),
+		insts:    make([]optInstruction, len(system.Instructions)),
+		defs:     make(map[int]LinearExpression),
+	}
+	o.nbWires = o.nbInputs + system.NbInternalVariables
+	o.users = make([][]int, o.nbWires)
+	o.protected = make([]bool, o.nbWires)
+
+	// constraints defining committed values in PLONK
+	pinned := make(map[int]struct{})
+	if c, ok := system.CommitmentInfo.(PlonkCommitments); ok {
+		for i := range c {
+			pinned[c[i].CommitmentIndex] = struct{}{}
+			for _, cID := range c[i].Committed {
+				pinned[cID] = struct{}{}
+			}
+		}
+	}
+	if c, ok := system.CommitmentInfo.(Groth16Commitments); ok {
+		for i := range c {
+			o.protected[c[i].CommitmentIndex] = true
+			for _, w := range c[i].PrivateCommitted {
+				o.protected[w] = true
+			}
+			for _, w := range c[i].PublicAndCommitmentCommitted {
+				o.protected[w] = true
+			}
+		}
+	}
+	for w := range system.MBooleanAssumptions {
+		o.protected[w] = true
+	}
+	for _, b := range system.Blueprints {
+		if !isBuiltinBlueprint(b) {
+			o.opaque = true
+		}
+	}
+	if o.opaque {
+		for w := range o.protected {
+			o.protected[w] = true
+		}
+	}
+
+	solved := make([]bool, o.nbWires)
+	for i := 0; i < o.nbInputs; i++ {
+		solved[i] = true
+	}
+	for i, pi := range system.Instructions {
+		inst := pi.Unpack(system)
+		oi := &o.insts[i]
+		oi.bID, oi.cID, oi.solves = pi.BlueprintID, -1, -1
+		oi.wireOffset = pi.WireOffset
+		if b := system.Blueprints[pi.BlueprintID]; !isBuiltinBlueprint(b) {
+			oi.kind = optOpaque
+			oi.pinned = true
+			oi.calldata = append([]uint32(nil), inst.Calldata...)
+			if b.NbConstraints() != 0 {
+				oi.cID = int(inst.ConstraintOffset)
+			}
+			for i := 0; i < b.NbOutputs(inst); i++ {
+				solved[int(inst.WireOffset)+i] = true
+			}
+			continue
+		}
+		switch b := system.Blueprints[pi.BlueprintID].(type) {
+		case BlueprintR1C:
+			oi.kind = optR1C
+			oi.cID = int(inst.ConstraintOffset)
+			b.DecompressR1C(&oi.r1c, inst)
+		case BlueprintSparseR1C:
+			oi.kind = optSparseR1C
+			oi.cID = int(inst.ConstraintOffset)
+			b.DecompressSparseR1C(&oi.sr1c, inst)
+			oi.pinned = oi.sr1c.Commitment != NOT
+		case BlueprintHint:
+			oi.kind = optHint
+			b.DecompressHint(&oi.hint, inst)
+			for w := oi.hint.OutputRange.Start; w < oi.hint.OutputRange.End; w++ {
+				solved[w] = true
+			}
+		}
+		if _, ok := pinned[oi.cID]; ok {
+			oi.pinned = true
+		}
+
+		o.forEachWire(oi, func(w int) {
+			o.users[w] = append(o.users[w], i)
+			if oi.pinned {
+				o.protected[w] = true
+			}
+			if oi.kind != optHint && !solved[w] {
+				// the builders ensure that there is at most one unsolved wire per constraint.
+				oi.solves = w
+			}
+		})
+		if oi.solves != -1 {
+			solved[oi.solves] = true
+		}
+	}
+
+	return o
+}
+
+// forEachWire calls f on the wires referenced by the instruction. For hints, this includes
+// the outputs.
+func (o *optimizer) forEachWire(oi *optInstruction, f func(w int)) {
+	switch oi.kind {
+	case optR1C:
+		for _, l := range [3]LinearExpression{oi.r1c.L, oi.r1c.R, oi.r1c.O} {
+			for _, t := range l {
+				f(int(t.VID))
+			}
+		}
+	case optSparseR1C:
+		f(int(oi.sr1c.XA))
+		if oi.sr1c.XB != oi.sr1c.XA {
+			f(int(oi.sr1c.XB))
+		}
+		if oi.sr1c.XC != oi.sr1c.XA && oi.sr1c.XC != oi.sr1c.XB {
+			f(int(oi.sr1c.XC))
+		}
+	case optHint:
+		for _, l := range oi.hint.Inputs {
+			for _, t := range l {
+				if !t.IsConstant() {
+					f(int(t.VID))
+				}
+			}
+		}
+		for w := oi.hint.OutputRange.Start; w < oi.hint.OutputRange.End; w++ {
+			f(int(w))
+		}
+	}
+}
+
+// substitute eliminates the internal wires defined by a single linear constraint.
+//
+// Constraints are processed in order: the wires of a definition are solved before the
+// defined wire, so they are either inputs or wires which are not eliminated.
+func (o *optimizer) substitute() {
+	lastSeen := make([]int, len(o.insts))
+	for k := range o.insts {
+		oi := &o.insts[k]
+		w := oi.solves
+		if oi.removed || oi.pinned || w < o.nbInputs || o.protected[w] {
+			continue
+		}
+
+		var (
+			def LinearExpression
+			ok  bool
+		)
+		if oi.kind == optR1C {
+			def, ok = o.r1cDefinition(&oi.r1c, w)
+		} else {
+			def, ok = o.sparseR1CDefinition(&oi.sr1c, w)
+		}
+		if !ok {
+			continue
+		}
+
+		users := o.users[w][:0:0]
+		for _, j := range o.users[w] {
+			if j != k && !o.insts[j].removed && lastSeen[j] != k+1 {
+				lastSeen[j] = k + 1
+				users = append(users, j)
+			}
+		}
+		if o.system.Type == SystemR1CS && len(users) > 1 && len(def) > maxSubstitutionTerms {
+			continue
+		}
+
+		o.defs[w] = def
+		for _, j := range users {
+			o.substituteInInstruction(j, w, def)
+		}
+		oi.removed = true
+		o.users[w] = nil
+		o.report.NbSubstitutedWires++
+	}
+}
+
+// r1cDefinition returns the definition of w if r is a linear constraint L⋅R == O with L or
+// R constant.
+func (o *optimizer) r1cDefinition(r *R1C, w int) (LinearExpression, bool) {
+	var (
+		k   Element
+		lin LinearExpression
+	)
+	if isConstantR1CLinearExpression(r.L) {
+		k, lin = o.sum(r.L), r.R
+	} else if isConstantR1CLinearExpression(r.R) {
+		k, lin = o.sum(r.R), r.L
+	} else {
+		return nil, false
+	}
+
+	// k⋅lin - O == 0
+	var acc termAccumulator
+	for _, t := range lin {
+		acc.add(o.cs, t.VID, o.cs.Mul(k, o.cs.GetCoefficient(int(t.CID))))
+	}
+	for _, t := range r.O {
+		acc.add(o.cs, t.VID, o.cs.Neg(o.cs.GetCoefficient(int(t.CID))))
+	}
+	return o.definition(&acc, w)
+}
+
+// sparseR1CDefinition returns the definition of w if c is a linear constraint with at most
+// one other wire.
+func (o *optimizer) sparseR1CDefinition(c *SparseR1C, w int) (LinearExpression, bool) {
+	if qM := o.coeff(c.QM); !qM.IsZero() {
+		return nil, false
+	}
+	var acc termAccumulator
+	acc.add(o.cs, c.XA, o.coeff(c.QL))
+	acc.add(o.cs, c.XB, o.coeff(c.QR))
+	acc.add(o.cs, c.XC, o.coeff(c.QO))
+	acc.add(o.cs, math.MaxUint32, o.coeff(c.QC))
+
+	nbWires := 0
+	for _, t := range acc.terms {
+		if t.vid != math.MaxUint32 && !t.coeff.IsZero() {
+			nbWires++
+		}
+	}
+	if nbWires > 2 || (nbWires == 1 && o.nbInputs == 0) {
+		// we need an input wire to fill the slot of a substituted constant.
+		return nil, false
+	}
+	return o.definition(&acc, w)
+}
+
+// definition solves the linear relation acc == 0 for w.
+func (o *optimizer) definition(acc *termAccumulator, w int) (LinearExpression, bool) {
+	var a Element
+	found := false
+	for _, t := range acc.terms {
+		if t.vid == uint32(w) {
+			a, found = t.coeff, true
+		}
+	}
+	if !found || a.IsZero() {
+		return nil, false
+	}
+	a, _ = o.cs.Inverse(a)
+	a = o.cs.Neg(a)
+
+	def := make(LinearExpression, 0, len(acc.terms)-1)
+	for _, t := range acc.terms {
+		if t.vid == uint32(w) || t.coeff.IsZero() {
+			continue
+		}
+		def = append(def, Term{CID: o.cs.AddCoeff(o.cs.Mul(a, t.coeff)), VID: t.vid})
+	}
+	return def, true
+}
+
+// substituteInInstruction replaces w by its definition in the instruction j.
+func (o *optimizer) substituteInInstruction(j, w int, def LinearExpression) {
+	oi := &o.insts[j]
+	switch oi.kind {
+	case optR1C:
+		oi.r1c.L = o.substituteInLinearExpression(oi.r1c.L)
+		oi.r1c.R = o.substituteInLinearExpression(oi.r1c.R)
+		oi.r1c.O = o.substituteInLinearExpression(oi.r1c.O)
+	case optSparseR1C:
+		o.substituteInSparseR1C(&oi.sr1c, w, def)
+	case optHint:
+		for i := range oi.hint.Inputs {
+			oi.hint.Inputs[i] = o.substituteInLinearExpression(oi.hint.Inputs[i])
+		}
+	}
+	oi.modified = true
+	for _, t := range def {
+		if !t.IsConstant() && int(t.VID) >= o.nbInputs {
+			o.users[t.VID] = append(o.users[t.VID], j)
+		}
+	}
+}
+
+// substituteInLinearExpression returns l where the eliminated wires are replaced by their
+// definition.
+func (o *optimizer) substituteInLinearExpression(l LinearExpression) LinearExpression {
+	substituted := false
+	for _, t := range l {
+		if _, ok := o.defs[int(t.VID)]; ok && !t.IsConstant() {
+			substituted = true
+			break
+		}
+	}
+	if !substituted {
+		return l
+	}
+
+	var acc termAccumulator
+	for _, t := range l {
+		c := o.coeff(t.CID)
+		def, ok := o.defs[int(t.VID)]
+		if !ok || t.IsConstant() {
+			acc.add(o.cs, t.VID, c)
+			continue
+		}
+		for _, d := range def {
+			acc.add(o.cs, d.VID, o.cs.Mul(c, o.coeff(d.CID)))
+		}
+	}
+	return acc.linearExpression(o.cs)
+}
+
+// substituteInSparseR1C replaces w = α⋅x + β in the slots of c.
+func (o *optimizer) substituteInSparseR1C(c *SparseR1C, w int, def LinearExpression) {
+	var alpha, beta Element
+	x := uint32(0) // a constant is substituted by any input with a zero coefficient
+	for _, t := range def {
+		if t.IsConstant() {
+			beta = o.coeff(t.CID)
+		} else {
+			alpha, x = o.coeff(t.CID), t.VID
+		}
+	}
+
+	qL, qR, qO, qM, qC := o.coeff(c.QL), o.coeff(c.QR), o.coeff(c.QO), o.coeff(c.QM), o.coeff(c.QC)
+	if c.XA == uint32(w) {
+		// qL⋅xa + qM⋅xa⋅xb → qL⋅α⋅x + qL⋅β + qM⋅α⋅x⋅xb + qM⋅β⋅xb
+		qC = o.cs.Add(qC, o.cs.Mul(qL, beta))
+		qL = o.cs.Mul(qL, alpha)
+		qR = o.cs.Add(qR, o.cs.Mul(qM, beta))
+		qM = o.cs.Mul(qM, alpha)
+		c.XA = x
+	}
+	if c.XB == uint32(w) {
+		qC = o.cs.Add(qC, o.cs.Mul(qR, beta))
+		qR = o.cs.Mul(qR, alpha)
+		qL = o.cs.Add(qL, o.cs.Mul(qM, beta))
+		qM = o.cs.Mul(qM, alpha)
+		c.XB = x
+	}
+	if c.XC == uint32(w) {
+		qC = o.cs.Add(qC, o.cs.Mul(qO, beta))
+		qO = o.cs.Mul(qO, alpha)
+		c.XC = x
+	}
+	c.QL, c.QR, c.QO = o.cs.AddCoeff(qL), o.cs.AddCoeff(qR), o.cs.AddCoeff(qO)
+	c.QM, c.QC = o.cs.AddCoeff(qM), o.cs.AddCoeff(qC)
+}
+
+// removeDuplicates removes the constraints which are trivially satisfied or identical to a
+// previous one. Such constraints do not solve any wire.
+func (o *optimizer) removeDuplicates() {
+	seen := make(map[string]struct{})
+	var key []byte
+	for k := range o.insts {
+		oi := &o.insts[k]
+		if oi.removed || oi.pinned || oi.kind == optHint {
+			continue
+		}
+		if o.isTrivial(oi) {
+			oi.removed = true
+			o.report.NbRemovedConstraints++
+			continue
+		}
+		key = o.key(oi, key[:0])
+		if _, ok := seen[string(key)]; ok {
+			oi.removed = true
+			o.report.NbRemovedConstraints++
+			continue
+		}
+		seen[string(key)] = struct{}{}
+	}
+}
+
+// isTrivial returns true if the constraint holds for any value of its wires.
+func (o *optimizer) isTrivial(oi *optInstruction) bool {
+	if oi.kind == optSparseR1C {
+		c := &oi.sr1c
+		for _, cID := range [5]uint32{c.QL, c.QR, c.QO, c.QM, c.QC} {
+			if q := o.coeff(cID); !q.IsZero() {
+				return false
+			}
+		}
+		return true
+	}
+
+	r := &oi.r1c
+	var (
+		k   Element
+		lin LinearExpression
+	)
+	if isConstantR1CLinearExpression(r.L) {
+		k, lin = o.sum(r.L), r.R
+	} else if isConstantR1CLinearExpression(r.R) {
+		k, lin = o.sum(r.R), r.L
+	} else {
+		return false
+	}
+	var acc termAccumulator
+	for _, t := range lin {
+		acc.add(o.cs, t.VID, o.cs.Mul(k, o.coeff(t.CID)))
+	}
+	for _, t := range r.O {
+		acc.add(o.cs, t.VID, o.cs.Neg(o.coeff(t.CID)))
+	}
+	for _, t := range acc.terms {
+		if !t.coeff.IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+// key returns a canonical encoding of the constraint.
+func (o *optimizer) key(oi *optInstruction, buf []byte) []byte {
+	appendLinearExpression := func(buf []byte, l LinearExpression) []byte {
+		sorted := l.Clone()
+		sort.Slice(sorted, func(i, j int) bool {
+			if sorted[i].VID != sorted[j].VID {
+				return sorted[i].VID < sorted[j].VID
+			}
+			return sorted[i].CID < sorted[j].CID
+		})
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(sorted)))
+		for _, t := range sorted {
+			buf = binary.LittleEndian.AppendUint32(buf, t.CID)
+			buf = binary.LittleEndian.AppendUint32(buf, t.VID)
+		}
+		return buf
+	}
+
+	if oi.kind == optSparseR1C {
+		c := &oi.sr1c
+		buf = append(buf, byte(optSparseR1C))
+		for _, v := range [8]uint32{c.XA, c.XB, c.XC, c.QL, c.QR, c.QO, c.QM, c.QC} {
+			buf = binary.LittleEndian.AppendUint32(buf, v)
+		}
+		return buf
+	}
+
+	// L⋅R == O and R⋅L == O are the same constraint
+	buf = append(buf, byte(optR1C))
+	start := len(buf)
+	buf = appendLinearExpression(buf, oi.r1c.L)
+	buf = appendLinearExpression(buf, oi.r1c.R)
+	lr := string(buf[start:])
+	buf = appendLinearExpression(buf[:start], oi.r1c.R)
+	buf = appendLinearExpression(buf, oi.r1c.L)
+	if lr < string(buf[start:]) {
+		buf = append(buf[:start], lr...)
+	}
+	return appendLinearExpression(buf, oi.r1c.O)
+}
+
+// removeDeadHints removes the hints whose outputs are not used. Hints are processed in
+// reverse order, so that the hints only used by removed hints are removed too.
+func (o *optimizer) removeDeadHints() {
+	uses := o.countUses()
+	for k := len(o.insts) - 1; k >= 0; k-- {
+		oi := &o.insts[k]
+		if oi.removed || oi.kind != optHint {
+			continue
+		}
+		used := false
+		for w := oi.hint.OutputRange.Start; w < oi.hint.OutputRange.End; w++ {
+			if uses[w] > 0 {
+				used = true
+				break
+			}
+		}
+		if used {
+			continue
+		}
+		oi.removed = true
+		o.report.NbRemovedHints++
+		for _, l := range oi.hint.Inputs {
+			for _, t := range l {
+				if !t.IsConstant() {
+					uses[t.VID]--
+				}
+			}
+		}
+	}
+}
+
+// countUses returns the number of uses of each wire by the remaining constraints, hint
+// inputs, logs and debug information. Protected wires have at least one use.
+func (o *optimizer) countUses() []int {
+	uses := make([]int, o.nbWires)
+	for k := range o.insts {
+		oi := &o.insts[k]
+		if oi.removed {
+			continue
+		}
+		if oi.kind == optHint {
+			for _, l := range oi.hint.Inputs {
+				for _, t := range l {
+					if !t.IsConstant() {
+						uses[t.VID]++
+					}
+				}
+			}
+			continue
+		}
+		o.forEachWire(oi, func(w int) { uses[w]++ })
+		if dID, ok := o.system.MDebug[oi.cID]; ok {
+			o.countLogEntryUses(uses, &o.system.DebugInfo[dID])
+		}
+	}
+	for i := range o.system.Logs {
+		o.countLogEntryUses(uses, &o.system.Logs[i])
+	}
+	for w := range o.protected {
+		if o.protected[w] {
+			uses[w]++
+		}
+	}
+	return uses
+}
+
+func (o *optimizer) countLogEntryUses(uses []int, l *LogEntry) {
+	for i := range l.ToResolve {
+		l.ToResolve[i] = o.substituteInLinearExpression(l.ToResolve[i])
+		for _, t := range l.ToResolve[i] {
+			if !t.IsConstant() && int(t.VID) < len(uses) {
+				uses[t.VID]++
+			}
+		}
+	}
+}
+
+// rebuild compacts the internal wires and adds the remaining instructions to the system,
+// which recomputes the levels of the instruction tree.
+func (o *optimizer) rebuild() {
+	system := o.system
+	uses := o.countUses()
+
+	// new wire ids; inputs are kept.
+	wireIDs := make([]uint32, o.nbWires)
+	for i := 0; i < o.nbInputs; i++ {
+		wireIDs[i] = uint32(i)
+	}
+	for k := range o.insts {
+		oi := &o.insts[k]
+		if !oi.removed && oi.kind == optHint {
+			for w := oi.hint.OutputRange.Start; w < oi.hint.OutputRange.End; w++ {
+				uses[w]++
+			}
+		}
+	}
+	nbInternal := 0
+	for w := o.nbInputs; w < o.nbWires; w++ {
+		if uses[w] > 0 {
+			wireIDs[w] = uint32(o.nbInputs + nbInternal)
+			nbInternal++
+		} else {
+			wireIDs[w] = math.MaxUint32
+		}
+	}
+	remap := func(l LinearExpression) {
+		for i := range l {
+			if !l[i].IsConstant() {
+				l[i].VID = wireIDs[l[i].VID]
+			}
+		}
+	}
+
+	// reset the instructions and the instruction tree
+	system.Instructions = system.Instructions[:0]
+	system.CallData = system.CallData[:0]
+	system.Levels = system.Levels[:0]
+	system.lbWireLevel = system.lbWireLevel[:0]
+	system.NbConstraints = 0
+	system.NbInternalVariables = 0
+	allocate := func(nbInternal int) {
+		for system.NbInternalVariables < nbInternal {
+			system.AddInternalVariable()
+		}
+	}
+	if !o.opaque {
+		allocate(nbInternal)
+	}
+
+	cIDs := make(map[int]int) // old constraint id -> new constraint id
+	mDebug := make(map[int]int)
+	mHintsDebug := make(map[int]int)
+	var debugInfo []LogEntry
+	dIDs := make(map[int]int) // old debug info id -> new debug info id
+	addDebugInfo := func(dID int) int {
+		if id, ok := dIDs[dID]; ok {
+			return id
+		}
+		l := system.DebugInfo[dID]
+		for i := range l.ToResolve {
+			l.ToResolve[i] = o.substituteInLinearExpression(l.ToResolve[i])
+			remap(l.ToResolve[i])
+		}
+		debugInfo = append(debugInfo, l)
+		dIDs[dID] = len(debugInfo) - 1
+		return len(debugInfo) - 1
+	}
+
+	calldata := getBuffer()
+	defer putBuffer(calldata)
+	for k := range o.insts {
+		oi := &o.insts[k]
+		if oi.removed {
+			continue
+		}
+		*calldata = (*calldata)[:0]
+		bID := oi.bID
+		if o.opaque {
+			// the wires are kept with their ids: the output wires of the opaque
+			// instructions are allocated at their original offset.
+			allocate(int(oi.wireOffset) - o.nbInputs)
+		}
+		switch oi.kind {
+		case optR1C:
+			remap(oi.r1c.L)
+			remap(oi.r1c.R)
+			remap(oi.r1c.O)
+			system.Blueprints[bID].(BlueprintR1C).CompressR1C(&oi.r1c, calldata)
+		case optSparseR1C:
+			c := &oi.sr1c
+			c.XA, c.XB, c.XC = wireIDs[c.XA], wireIDs[c.XB], wireIDs[c.XC]
+			if oi.modified {
+				bID = system.blueprintOfType(&BlueprintGenericSparseR1C{})
+			}
+			system.Blueprints[bID].(BlueprintSparseR1C).CompressSparseR1C(c, calldata)
+		case optHint:
+			for i := range oi.hint.Inputs {
+				remap(oi.hint.Inputs[i])
+			}
+			oi.hint.OutputRange.Start = wireIDs[oi.hint.OutputRange.Start]
+			oi.hint.OutputRange.End = wireIDs[oi.hint.OutputRange.End-1] + 1
+			system.Blueprints[bID].(BlueprintHint).CompressHint(oi.hint, calldata)
+		case optOpaque:
+			*calldata = append(*calldata, oi.calldata...)
+		}
+
+		cID := system.NbConstraints
+		system.AddInstruction(bID, *calldata)
+
+		if oi.cID < 0 {
+			// hints and opaque instructions without constraints
+			if dID, ok := system.MHintsDebug[k]; ok {
+				mHintsDebug[len(system.Instructions)-1] = addDebugInfo(dID)
+			}
+			continue
+		}
+		cIDs[oi.cID] = cID
+		if dID, ok := system.MDebug[oi.cID]; ok {
+			mDebug[cID] = addDebugInfo(dID)
+		}
+	}
+	allocate(nbInternal)
+
+	// boolean assumptions of the remaining wires, in wire order for determinism
+	booleans := make([]int, 0, len(system.MBooleanAssumptions))
+	for w := range system.MBooleanAssumptions {
+		booleans = append(booleans, w)
+	}
+	sort.Ints(booleans)
+	mBooleanAssumptions := make(map[int]int, len(booleans))
+	for _, w := range booleans {
+		if wireIDs[w] != math.MaxUint32 {
+			dID := system.MBooleanAssumptions[w]
+			if dID >= 0 {
+				dID = addDebugInfo(dID)
+			}
+			mBooleanAssumptions[int(wireIDs[w])] = dID
+		}
+	}
+
+	for i := range system.Logs {
+		for j := range system.Logs[i].ToResolve {
+			system.Logs[i].ToResolve[j] = o.substituteInLinearExpression(system.Logs[i].ToResolve[j])
+			remap(system.Logs[i].ToResolve[j])
+		}
+	}
+
+	switch c := system.CommitmentInfo.(type) {
+	case Groth16Commitments:
+		for i := range c {
+			c[i].CommitmentIndex = int(wireIDs[c[i].CommitmentIndex])
+			for j := range c[i].PrivateCommitted {
+				c[i].PrivateCommitted[j] = int(wireIDs[c[i].PrivateCommitted[j]])
+			}
+			for j := range c[i].PublicAndCommitmentCommitted {
+				c[i].PublicAndCommitmentCommitted[j] = int(wireIDs[c[i].PublicAndCommitmentCommitted[j]])
+			}
+		}
+	case PlonkCommitments:
+		for i := range c {
+			c[i].CommitmentIndex = cIDs[c[i].CommitmentIndex]
+			for j := range c[i].Committed {
+				c[i].Committed[j] = cIDs[c[i].Committed[j]]
+			}
+		}
+	}
+
+	system.DebugInfo = debugInfo
+	system.MDebug = mDebug
+	system.MHintsDebug = mHintsDebug
+	system.MBooleanAssumptions = mBooleanAssumptions
+}
+
+func (o *optimizer) coeff(cID uint32) Element {
+	return o.cs.GetCoefficient(int(cID))
+}
+
+// sum returns the sum of the coefficients of l.
+func (o *optimizer) sum(l LinearExpression) Element {
+	var r Element
+	for _, t := range l {
+		r = o.cs.Add(r, o.coeff(t.CID))
+	}
+	return r
+}
+
+// isConstantR1CLinearExpression returns true if l only has terms on the constant wire 0 of
+// a R1CS.
+func isConstantR1CLinearExpression(l LinearExpression) bool {
+	for _, t := range l {
+		if t.VID != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// termAccumulator accumulates coefficients per wire, in order of first occurrence.
+type termAccumulator struct {
+	terms []struct {
+		vid   uint32
+		coeff Element
+	}
+	index map[uint32]int
+}
+
+func (acc *termAccumulator) add(field Field, vid uint32, coeff Element) {
+	if acc.index == nil {
+		acc.index = make(map[uint32]int)
+	}
+	if i, ok := acc.index[vid]; ok {
+		acc.terms[i].coeff = field.Add(acc.terms[i].coeff, coeff)
+		return
+	}
+	acc.index[vid] = len(acc.terms)
+	acc.terms = append(acc.terms, struct {
+		vid   uint32
+		coeff Element
+	}{vid, coeff})
+}
+
+// linearExpression returns the accumulated non-zero terms.
+func (acc *termAccumulator) linearExpression(cs ConstraintSystem) LinearExpression {
+	l := make(LinearExpression, 0, len(acc.terms))
+	for _, t := range acc.terms {
+		if !t.coeff.IsZero() {
+			l = append(l, Term{CID: cs.AddCoeff(t.coeff), VID: t.vid})
+		}
+	}
+	return l
+}
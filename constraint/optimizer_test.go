@@ -0,0 +1,172 @@
+package constraint_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/consensys/gnark/test/unsafekzg"
+	"github.com/stretchr/testify/require"
+)
+
+type optimizerSquare struct{}
+
+func (optimizerSquare) Define(api frontend.API, inputs []frontend.Variable) ([]frontend.Variable, error) {
+	return []frontend.Variable{api.Mul(inputs[0], inputs[0])}, nil
+}
+
+type optimizerCircuit struct {
+	X, Y frontend.Variable
+	Z    frontend.Variable `gnark:",public"`
+}
+
+func (c *optimizerCircuit) Define(api frontend.API) error {
+	// affine definition
+	a := api.Add(c.X, 3)
+	b := api.Mul(a, a)
+	// the scaled input is bound to a wire defined by a linear constraint
+	out, err := frontend.CallSubCircuit(api, optimizerSquare{}, api.Mul(c.Y, 2))
+	if err != nil {
+		return err
+	}
+	// duplicate constraints
+	api.AssertIsEqual(api.Add(b, out[0]), c.Z)
+	api.AssertIsEqual(api.Add(b, out[0]), c.Z)
+	// unused hint
+	if _, err := api.Compiler().NewHint(analysisIdentityHint, 2, c.X); err != nil {
+		return err
+	}
+	cmt, err := api.(frontend.Committer).Commit(c.X, b)
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(cmt, 0)
+	api.Println("b", b)
+	return nil
+}
+
+func TestOptimize(t *testing.T) {
+	assert := require.New(t)
+	field := ecc.BN254.ScalarField()
+
+	valid, err := frontend.NewWitness(&optimizerCircuit{X: 2, Y: 3, Z: 25 + 36}, field)
+	assert.NoError(err)
+	invalid, err := frontend.NewWitness(&optimizerCircuit{X: 2, Y: 3, Z: 60}, field)
+	assert.NoError(err)
+	publicWitness, err := valid.Public()
+	assert.NoError(err)
+
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(field, builder, &optimizerCircuit{})
+		assert.NoError(err)
+
+		report, err := constraint.Optimize(ccs)
+		assert.NoError(err)
+		assert.Equal(ccs.GetNbConstraints(), report.NbConstraintsAfter)
+		assert.Less(report.NbConstraintsAfter, report.NbConstraintsBefore)
+		assert.Less(report.NbInternalVariablesAfter, report.NbInternalVariablesBefore)
+		assert.Positive(report.NbSubstitutedWires)
+		assert.Equal(1, report.NbRemovedConstraints, "duplicate constraint")
+		assert.Equal(1, report.NbRemovedHints, "unused hint")
+
+		_, err = ccs.Solve(valid)
+		assert.NoError(err)
+		_, err = ccs.Solve(invalid)
+		assert.Error(err)
+
+		// commitments are remapped
+		if _, ok := ccs.GetCommitments().(constraint.Groth16Commitments); ok {
+			pk, vk, err := groth16.Setup(ccs)
+			assert.NoError(err)
+			proof, err := groth16.Prove(ccs, pk, valid)
+			assert.NoError(err)
+			assert.NoError(groth16.Verify(proof, vk, publicWitness))
+		} else {
+			srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+			assert.NoError(err)
+			pk, vk, err := plonk.Setup(ccs, srs, srsLagrange)
+			assert.NoError(err)
+			proof, err := plonk.Prove(ccs, pk, valid)
+			assert.NoError(err)
+			assert.NoError(plonk.Verify(proof, vk, publicWitness))
+		}
+	}
+}
+
+type optimizerLookupCircuit struct {
+	X, Y frontend.Variable
+	Z    frontend.Variable `gnark:",public"`
+}
+
+func (c *optimizerLookupCircuit) Define(api frontend.API) error {
+	rangecheck.New(api).Check(c.X, 8)
+	// the lookup instructions use a non-builtin blueprint
+	t := logderivlookup.New(api)
+	for i := 0; i < 4; i++ {
+		t.Insert(api.Add(c.Y, i))
+	}
+	res := t.Lookup(api.Sub(c.X, 1))
+	a := api.Add(res[0], 3)
+	b := api.Mul(a, a)
+	// duplicate constraints
+	api.AssertIsEqual(b, c.Z)
+	api.AssertIsEqual(b, c.Z)
+	return nil
+}
+
+func TestOptimizeNonBuiltinBlueprint(t *testing.T) {
+	assert := require.New(t)
+	field := ecc.BN254.ScalarField()
+
+	// X-1 = 1 selects Y+1 = 6 in the table
+	valid, err := frontend.NewWitness(&optimizerLookupCircuit{X: 2, Y: 5, Z: 81}, field)
+	assert.NoError(err)
+	invalid, err := frontend.NewWitness(&optimizerLookupCircuit{X: 3, Y: 5, Z: 81}, field)
+	assert.NoError(err)
+	outOfRange, err := frontend.NewWitness(&optimizerLookupCircuit{X: 257, Y: 5, Z: 81}, field)
+	assert.NoError(err)
+
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(field, builder, &optimizerLookupCircuit{})
+		assert.NoError(err)
+
+		report, err := constraint.Optimize(ccs)
+		assert.NoError(err)
+		assert.Equal(ccs.GetNbConstraints(), report.NbConstraintsAfter)
+		assert.Equal(1, report.NbRemovedConstraints, "duplicate constraint")
+		assert.Zero(report.NbSubstitutedWires, "the wires are kept with their ids")
+		assert.Equal(report.NbInternalVariablesBefore, report.NbInternalVariablesAfter)
+
+		_, err = ccs.Solve(valid)
+		assert.NoError(err)
+		_, err = ccs.Solve(invalid)
+		assert.Error(err)
+		_, err = ccs.Solve(outOfRange)
+		assert.Error(err)
+
+		publicWitness, err := valid.Public()
+		assert.NoError(err)
+		if _, ok := ccs.GetCommitments().(constraint.Groth16Commitments); ok {
+			pk, vk, err := groth16.Setup(ccs)
+			assert.NoError(err)
+			proof, err := groth16.Prove(ccs, pk, valid)
+			assert.NoError(err)
+			assert.NoError(groth16.Verify(proof, vk, publicWitness))
+		} else {
+			srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+			assert.NoError(err)
+			pk, vk, err := plonk.Setup(ccs, srs, srsLagrange)
+			assert.NoError(err)
+			proof, err := plonk.Prove(ccs, pk, valid)
+			assert.NoError(err)
+			assert.NoError(plonk.Verify(proof, vk, publicWitness))
+		}
+	}
+}
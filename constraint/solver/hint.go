@@ -95,10 +95,20 @@ type HintID uint32
 // the hint function hintFn to register a hint function in the package registry.
 type Hint func(field *big.Int, inputs []*big.Int, outputs []*big.Int) error
 
-// GetHintID returns the derived hint ID from the hint function reference.
+// GetHintID returns the derived hint ID from the hint function reference. If the function
+// was registered with RegisterVersionedHint, it is its versioned ID.
 func GetHintID(fn Hint) HintID {
+	if v, ok := lookupVersionedHint(fn); ok {
+		return v.id
+	}
+	return funcHintID(fn)
+}
+
+// funcHintID returns the hint ID derived from the Go function name of fn, ignoring the
+// versioned registrations.
+func funcHintID(fn Hint) HintID {
 	hf := fnv.New32a()
-	name := GetHintName(fn)
+	name := funcHintName(fn)
 
 	// TODO relying on name to derive UUID is risky; if fn is an anonymous func, wil be package.glob..funcN
 	// and if new anonymous functions are added in the package, N may change, so will UUID.
@@ -110,8 +120,16 @@ func GetHintID(fn Hint) HintID {
 // GetHintName returns the derived hint name from the hint function reference.
 // By default, it is the fully qualified name of the function. If the function
 // is anonymous, then it is the fully qualified name of the package and the
-// function index.
+// function index. If the function was registered with RegisterVersionedHint, it is its
+// qualified versioned name.
 func GetHintName(fn Hint) string {
+	if v, ok := lookupVersionedHint(fn); ok {
+		return v.name
+	}
+	return funcHintName(fn)
+}
+
+func funcHintName(fn Hint) string {
 	fnptr := reflect.ValueOf(fn).Pointer()
 	name := runtime.FuncForPC(fnptr).Name()
 	return newToOldStyle(name)
@@ -0,0 +1,332 @@
+package solver
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// HintExecutor executes hints which are not available as Go functions in the solver, for
+// example in another process (see NewStreamHintExecutor). See WithHintExecutor.
+type HintExecutor interface {
+	// ExecuteHint executes the hint identified by id and name (as recorded in the
+	// constraint system, see GetHintName) on the inputs and sets the outputs. Inputs and
+	// outputs are integers in [0, field).
+	ExecuteHint(id HintID, name string, field *big.Int, inputs []*big.Int, outputs []*big.Int) error
+}
+
+// ExecutorHint returns a hint function executing the hint identified by id and name with
+// the executor e. It can be used with OverrideHint to execute a registered hint with an
+// executor.
+func ExecutorHint(e HintExecutor, id HintID, name string) Hint {
+	return func(field *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+		return e.ExecuteHint(id, name, field, inputs, outputs)
+	}
+}
+
+// MissingHintsError is returned by the solver when the constraint system requires hints
+// which are neither registered, given as solver options nor served by a hint executor.
+// It is returned before solving starts.
+type MissingHintsError struct {
+	Names []string // sorted names of the missing hints, as recorded in the constraint system
+}
+
+func (e *MissingHintsError) Error() string {
+	return fmt.Sprintf("solver missing hint(s): %s", strings.Join(e.Names, ", "))
+}
+
+// ResolveHints returns the hint functions for the hints required by a constraint system
+// (hintID -> name, see constraint.System.MHintsDependencies), using the configured hint
+// functions then the hint executor. It returns a *MissingHintsError listing the hints
+// which can not be resolved.
+func (cfg *Config) ResolveHints(dependencies map[HintID]string) (map[HintID]Hint, error) {
+	var missing []string
+	for id, name := range dependencies {
+		if _, ok := cfg.HintFunctions[id]; ok {
+			continue
+		}
+		if cfg.HintExecutor != nil {
+			cfg.HintFunctions[id] = ExecutorHint(cfg.HintExecutor, id, name)
+			continue
+		}
+		missing = append(missing, name)
+	}
+	if len(missing) != 0 {
+		sort.Strings(missing)
+		return nil, &MissingHintsError{Names: missing}
+	}
+	return cfg.HintFunctions, nil
+}
+
+// The stream hint protocol encodes a hint execution as a request followed by a response,
+// on a byte stream (e.g. a local socket, or the stdin and stdout of a helper process).
+// All integers are unsigned big endian; field elements are encoded on the byte size of the
+// field modulus, big endian.
+//
+// Request:
+//
+//	uint32      hint id
+//	uint16      length of the hint name, followed by the name (UTF-8)
+//	uint16      byte size n of the field modulus, followed by the modulus (n bytes)
+//	uint32      number of inputs, followed by the inputs (n bytes each)
+//	uint32      number of outputs
+//
+// Response:
+//
+//	uint8       status: 0 for success, 1 for error
+//	on success: the outputs (n bytes each, in [0, modulus))
+//	on error:   uint32 length of the error message, followed by the message (UTF-8)
+//
+// Requests are sent one at a time: a request is only sent once the response to the
+// previous one was received.
+const (
+	hintStatusOK    = 0
+	hintStatusError = 1
+)
+
+// maximum length of a hint error message read from a stream
+const maxHintErrorLen = 1 << 16
+
+type streamHintExecutor struct {
+	lock sync.Mutex
+	r    *bufio.Reader
+	w    *bufio.Writer
+}
+
+// NewStreamHintExecutor returns a hint executor sending the hint requests to w and reading
+// the responses from r, using the stream hint protocol (see ServeHints). The executor
+// serializes the hint executions.
+func NewStreamHintExecutor(r io.Reader, w io.Writer) HintExecutor {
+	return &streamHintExecutor{
+		r: bufio.NewReader(r),
+		w: bufio.NewWriter(w),
+	}
+}
+
+func (e *streamHintExecutor) ExecuteHint(id HintID, name string, field *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	req := hintRequest{id: id, name: name, field: field, inputs: inputs, nbOutputs: len(outputs)}
+	if err := req.writeTo(e.w); err != nil {
+		return fmt.Errorf("hint %s: %w", name, err)
+	}
+	if err := e.w.Flush(); err != nil {
+		return fmt.Errorf("hint %s: %w", name, err)
+	}
+	if err := readHintResponse(e.r, field, outputs); err != nil {
+		return fmt.Errorf("hint %s: %w", name, err)
+	}
+	return nil
+}
+
+// ServeHints serves the hint requests read from r with the given hint functions, and
+// writes the responses to w, using the stream hint protocol (see NewStreamHintExecutor).
+// It returns nil when r reaches EOF between requests.
+//
+// Hints which are not in hints are looked up in the global registry. A hint returning an
+// error, or an unknown hint, results in an error response; ServeHints only returns on
+// I/O or protocol errors.
+func ServeHints(r io.Reader, w io.Writer, hints map[HintID]Hint) error {
+	br, bw := bufio.NewReader(r), bufio.NewWriter(w)
+	for {
+		var req hintRequest
+		if err := req.readFrom(br); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		f, ok := hints[req.id]
+		if !ok {
+			f = GetRegisteredHint(req.id)
+		}
+		outputs := make([]*big.Int, req.nbOutputs)
+		for i := range outputs {
+			outputs[i] = new(big.Int)
+		}
+		var err error
+		if f == nil {
+			err = fmt.Errorf("unknown hint %s (id %d)", req.name, req.id)
+		} else {
+			err = f(req.field, req.inputs, outputs)
+		}
+		if err := writeHintResponse(bw, req.field, outputs, err); err != nil {
+			return err
+		}
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+type hintRequest struct {
+	id        HintID
+	name      string
+	field     *big.Int
+	inputs    []*big.Int
+	nbOutputs int
+}
+
+func (req *hintRequest) writeTo(w io.Writer) error {
+	size := fieldBytes(req.field)
+	if len(req.name) > math.MaxUint16 || size > math.MaxUint16 {
+		return errors.New("hint name or field too large")
+	}
+	buf := make([]byte, 0, 16+len(req.name)+size*(1+len(req.inputs)))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(req.id))
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(req.name)))
+	buf = append(buf, req.name...)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(size))
+	buf = appendElement(buf, req.field, size)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(req.inputs)))
+	for _, in := range req.inputs {
+		if in.Sign() < 0 || in.Cmp(req.field) >= 0 {
+			return fmt.Errorf("hint input %s not in [0, field)", in)
+		}
+		buf = appendElement(buf, in, size)
+	}
+	buf = binary.BigEndian.AppendUint32(buf, uint32(req.nbOutputs))
+	_, err := w.Write(buf)
+	return err
+}
+
+func (req *hintRequest) readFrom(r io.Reader) error {
+	// io.EOF if the stream ends between requests
+	var head [6]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return err
+	}
+	req.id = HintID(binary.BigEndian.Uint32(head[:4]))
+	name := make([]byte, binary.BigEndian.Uint16(head[4:]))
+	if err := readFull(r, name); err != nil {
+		return err
+	}
+	req.name = string(name)
+
+	var u16 [2]byte
+	if err := readFull(r, u16[:]); err != nil {
+		return err
+	}
+	size := int(binary.BigEndian.Uint16(u16[:]))
+	if size == 0 {
+		return errors.New("invalid field size")
+	}
+	elem := make([]byte, size)
+	if err := readFull(r, elem); err != nil {
+		return err
+	}
+	req.field = new(big.Int).SetBytes(elem)
+
+	nbInputs, err := readUint32(r)
+	if err != nil {
+		return err
+	}
+	req.inputs = make([]*big.Int, 0, nbInputs&0xffff)
+	for i := uint32(0); i < nbInputs; i++ {
+		if err := readFull(r, elem); err != nil {
+			return err
+		}
+		req.inputs = append(req.inputs, new(big.Int).SetBytes(elem))
+	}
+	nbOutputs, err := readUint32(r)
+	if err != nil {
+		return err
+	}
+	req.nbOutputs = int(nbOutputs)
+	return nil
+}
+
+func writeHintResponse(w io.Writer, field *big.Int, outputs []*big.Int, hintErr error) error {
+	size := fieldBytes(field)
+	var buf []byte
+	if hintErr != nil {
+		msg := hintErr.Error()
+		if len(msg) > maxHintErrorLen {
+			msg = msg[:maxHintErrorLen]
+		}
+		buf = append(buf, hintStatusError)
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(msg)))
+		buf = append(buf, msg...)
+	} else {
+		buf = make([]byte, 0, 1+size*len(outputs))
+		buf = append(buf, hintStatusOK)
+		var v big.Int
+		for _, out := range outputs {
+			buf = appendElement(buf, v.Mod(out, field), size)
+		}
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+func readHintResponse(r io.Reader, field *big.Int, outputs []*big.Int) error {
+	var status [1]byte
+	if err := readFull(r, status[:]); err != nil {
+		return err
+	}
+	switch status[0] {
+	case hintStatusOK:
+		elem := make([]byte, fieldBytes(field))
+		for i := range outputs {
+			if err := readFull(r, elem); err != nil {
+				return err
+			}
+			outputs[i].SetBytes(elem)
+		}
+		return nil
+	case hintStatusError:
+		n, err := readUint32(r)
+		if err != nil {
+			return err
+		}
+		if n > maxHintErrorLen {
+			return fmt.Errorf("hint error message too long (%d bytes)", n)
+		}
+		msg := make([]byte, n)
+		if err := readFull(r, msg); err != nil {
+			return err
+		}
+		return errors.New(string(msg))
+	default:
+		return fmt.Errorf("invalid hint response status %d", status[0])
+	}
+}
+
+func fieldBytes(field *big.Int) int {
+	return (field.BitLen() + 7) / 8
+}
+
+func appendElement(buf []byte, v *big.Int, size int) []byte {
+	n := len(buf)
+	buf = append(buf, make([]byte, size)...)
+	v.FillBytes(buf[n:])
+	return buf
+}
+
+func readUint32(r io.Reader) (uint32, error) {
+	var b [4]byte
+	if err := readFull(r, b[:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(b[:]), nil
+}
+
+// readFull reads len(b) bytes; an EOF in the middle of a message is unexpected.
+func readFull(r io.Reader, b []byte) error {
+	if _, err := io.ReadFull(r, b); err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package solver_test
+
+import (
+	"errors"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+)
+
+// cubeHint is only served by the hint server, it is not registered in the solver.
+func cubeHint(field *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	outputs[0].Exp(inputs[0], big.NewInt(3), field)
+	return nil
+}
+
+func failingHint(_ *big.Int, _ []*big.Int, _ []*big.Int) error {
+	return errors.New("failing hint")
+}
+
+func versionedSquareHint(field *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	outputs[0].Mul(inputs[0], inputs[0]).Mod(outputs[0], field)
+	return nil
+}
+
+var versionedSquareHintID = solver.RegisterVersionedHint("github.com/consensys/gnark/test", "square", 2, versionedSquareHint)
+
+type executorCircuit struct {
+	X, Y frontend.Variable
+}
+
+func (c *executorCircuit) Define(api frontend.API) error {
+	res, err := api.Compiler().NewHint(cubeHint, 1, c.X)
+	if err != nil {
+		return err
+	}
+	sq, err := api.Compiler().NewHint(versionedSquareHint, 1, c.X)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(sq[0], api.Mul(c.X, c.X))
+	api.AssertIsEqual(res[0], api.Mul(sq[0], c.X))
+	api.AssertIsEqual(res[0], c.Y)
+	return nil
+}
+
+func TestVersionedHint(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.Equal(versionedSquareHintID, solver.GetHintID(versionedSquareHint))
+	assert.Equal("github.com/consensys/gnark/test/square@v2", solver.GetHintName(versionedSquareHint))
+	assert.NotNil(solver.GetRegisteredHint(versionedSquareHintID))
+
+	// registering again is a no-op, registering under another name or version panics
+	assert.Equal(versionedSquareHintID, solver.RegisterVersionedHint("github.com/consensys/gnark/test", "square", 2, versionedSquareHint))
+	assert.Panics(func() {
+		solver.RegisterVersionedHint("github.com/consensys/gnark/test", "square", 3, versionedSquareHint)
+	})
+	assert.Panics(func() {
+		solver.RegisterVersionedHint("github.com/consensys/gnark/test", "square", 2, cubeHint)
+	})
+	assert.Panics(func() {
+		solver.RegisterVersionedHint("github.com/consensys/gnark/test", "sq@uare", 1, cubeHint)
+	})
+}
+
+func TestHintExecutor(t *testing.T) {
+	assert := test.NewAssert(t)
+	field := ecc.BN254.ScalarField()
+	ccs, err := frontend.Compile(field, scs.NewBuilder, &executorCircuit{})
+	assert.NoError(err)
+	witness, err := frontend.NewWitness(&executorCircuit{X: 3, Y: 27}, field)
+	assert.NoError(err)
+
+	// the missing hints are reported by name before solving
+	_, err = ccs.Solve(witness)
+	var missing *solver.MissingHintsError
+	assert.True(errors.As(err, &missing), "expected a missing hints error, got %v", err)
+	assert.Equal([]string{solver.GetHintName(cubeHint)}, missing.Names)
+
+	// serve the hint from another goroutine, over a pair of pipes
+	reqR, reqW := io.Pipe()
+	respR, respW := io.Pipe()
+	served := make(chan error, 1)
+	go func() {
+		served <- solver.ServeHints(reqR, respW, map[solver.HintID]solver.Hint{
+			solver.GetHintID(cubeHint):    cubeHint,
+			solver.GetHintID(failingHint): failingHint,
+		})
+	}()
+	executor := solver.NewStreamHintExecutor(respR, reqW)
+
+	_, err = ccs.Solve(witness, solver.WithHintExecutor(executor))
+	assert.NoError(err)
+	bad, err := frontend.NewWitness(&executorCircuit{X: 3, Y: 28}, field)
+	assert.NoError(err)
+	_, err = ccs.Solve(bad, solver.WithHintExecutor(executor))
+	assert.Error(err)
+
+	// errors of the hint and unknown hints are reported to the executor
+	out := []*big.Int{new(big.Int)}
+	err = executor.ExecuteHint(solver.GetHintID(failingHint), "failing", field, []*big.Int{big.NewInt(1)}, out)
+	assert.ErrorContains(err, "failing hint")
+	err = executor.ExecuteHint(1, "unknown", field, nil, out)
+	assert.ErrorContains(err, "unknown hint")
+
+	// the registered hints are served as well
+	err = executor.ExecuteHint(versionedSquareHintID, solver.GetHintName(versionedSquareHint), field, []*big.Int{big.NewInt(5)}, out)
+	assert.NoError(err)
+	assert.Equal(int64(25), out[0].Int64())
+
+	assert.NoError(reqW.Close())
+	assert.NoError(<-served)
+}
@@ -2,7 +2,10 @@ package solver
 
 import (
 	"fmt"
+	"hash/fnv"
 	"math/big"
+	"reflect"
+	"strings"
 	"sync"
 
 	"github.com/consensys/gnark/logger"
@@ -15,8 +18,18 @@ func init() {
 var (
 	registry  = make(map[HintID]Hint)
 	registryM sync.RWMutex
+
+	// versioned hint names, by function pointer (see RegisterVersionedHint). It has its
+	// own lock since it is read by GetHintID, which is called with registryM held.
+	versioned  = make(map[uintptr]versionedHint)
+	versionedM sync.RWMutex
 )
 
+type versionedHint struct {
+	id   HintID
+	name string
+}
+
 // RegisterHint registers a hint function in the global registry.
 func RegisterHint(hintFns ...Hint) {
 	registryM.Lock()
@@ -27,7 +40,7 @@ func RegisterHint(hintFns ...Hint) {
 		if _, ok := registry[key]; ok {
 			log := logger.Logger()
 			log.Debug().Str("name", name).Msg("function registered multiple times")
-			return
+			continue
 		}
 		registry[key] = hintFn
 	}
@@ -48,12 +61,84 @@ func RegisterNamedHint(hintFn Hint, key HintID) {
 	registry[key] = hintFn
 }
 
-// GetRegisteredHints returns all registered hint functions.
+// RegisterVersionedHint registers a hint function in the global registry under a stable
+// identifier derived from its namespace, name and version instead of its Go function
+// name, and returns this identifier. The qualified name of the hint is
+// "namespace/name@vVersion"; it is recorded in the compiled constraint systems and reported
+// when the hint is missing at solving time.
+//
+// Once registered, the hint is identified by its versioned identifier in circuits compiled
+// afterwards (see GetHintID and GetHintName), so renaming or moving the Go function does
+// not break serialized constraint systems. The version should be increased when the
+// semantics of the hint change. The hint is also registered under the identifier derived
+// from its Go function name, so that the systems compiled before the migration can still be
+// solved.
+//
+// It panics if the namespace or the name is invalid, or if the identifier is already taken
+// by another hint.
+func RegisterVersionedHint(namespace, name string, version uint32, hintFn Hint) HintID {
+	qualifiedName := VersionedHintName(namespace, name, version)
+	id := versionedHintID(qualifiedName)
+
+	registryM.Lock()
+	defer registryM.Unlock()
+	versionedM.Lock()
+	defer versionedM.Unlock()
+	ptr := reflect.ValueOf(hintFn).Pointer()
+	v, ok := versioned[ptr]
+	if ok && v.id != id {
+		panic(fmt.Errorf("hint function already registered as %s", v.name))
+	}
+	if _, taken := registry[id]; taken && !ok {
+		panic(fmt.Errorf("hint id %d of %s already taken", id, qualifiedName))
+	}
+	registry[id] = hintFn
+	if legacyID := funcHintID(hintFn); registry[legacyID] == nil {
+		registry[legacyID] = hintFn
+	}
+	versioned[ptr] = versionedHint{id: id, name: qualifiedName}
+	return id
+}
+
+// VersionedHintName returns the qualified name "namespace/name@vVersion" of a versioned
+// hint. It panics if the namespace or the name is empty, if the namespace contains '@' or if
+// the name contains '/' or '@'.
+func VersionedHintName(namespace, name string, version uint32) string {
+	if namespace == "" || name == "" || strings.Contains(namespace, "@") || strings.ContainsAny(name, "/@") {
+		panic(fmt.Errorf("invalid versioned hint name %q in namespace %q", name, namespace))
+	}
+	return fmt.Sprintf("%s/%s@v%d", namespace, name, version)
+}
+
+// versionedHintID returns the identifier of a versioned hint from its qualified name.
+func versionedHintID(qualifiedName string) HintID {
+	hf := fnv.New32a()
+	hf.Write([]byte(qualifiedName)) // #nosec G104 -- does not err
+	return HintID(hf.Sum32())
+}
+
+// lookupVersionedHint returns the versioned identifier and name of fn, if it was registered
+// with RegisterVersionedHint.
+func lookupVersionedHint(fn Hint) (versionedHint, bool) {
+	versionedM.RLock()
+	defer versionedM.RUnlock()
+	v, ok := versioned[reflect.ValueOf(fn).Pointer()]
+	return v, ok
+}
+
+// GetRegisteredHints returns all registered hint functions. A versioned hint, registered
+// under two identifiers, is returned once.
 func GetRegisteredHints() []Hint {
 	registryM.RLock()
 	defer registryM.RUnlock()
 	ret := make([]Hint, 0, len(registry))
+	seen := make(map[uintptr]struct{}, len(registry))
 	for _, v := range registry {
+		ptr := reflect.ValueOf(v).Pointer()
+		if _, ok := seen[ptr]; ok {
+			continue
+		}
+		seen[ptr] = struct{}{}
 		ret = append(ret, v)
 	}
 	return ret
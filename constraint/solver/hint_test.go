@@ -1,6 +1,9 @@
 package solver
 
-import "testing"
+import (
+	"math/big"
+	"testing"
+)
 
 func TestRegexpRename(t *testing.T) {
 	for i, v := range []struct{ input, expected string }{
@@ -17,3 +20,34 @@ func TestRegexpRename(t *testing.T) {
 	}
 
 }
+
+func legacyHint(_ *big.Int, _ []*big.Int, _ []*big.Int) error {
+	return nil
+}
+
+func firstHint(_ *big.Int, _ []*big.Int, _ []*big.Int) error {
+	return nil
+}
+
+func secondHint(_ *big.Int, _ []*big.Int, _ []*big.Int) error {
+	return nil
+}
+
+func TestRegisterHint(t *testing.T) {
+	// a duplicate does not prevent registering the following hints
+	RegisterHint(firstHint)
+	RegisterHint(firstHint, secondHint)
+	if GetRegisteredHint(GetHintID(secondHint)) == nil {
+		t.Error("hint after a duplicate not registered")
+	}
+
+	// the systems compiled before the versioning refer to the function name
+	legacyID := GetHintID(legacyHint)
+	id := RegisterVersionedHint("github.com/consensys/gnark/constraint/solver", "legacy", 1, legacyHint)
+	if id == legacyID {
+		t.Fatal("versioned ID equals the legacy ID")
+	}
+	if GetRegisteredHint(id) == nil || GetRegisteredHint(legacyID) == nil {
+		t.Error("versioned hint not registered under both IDs")
+	}
+}
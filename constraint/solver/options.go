@@ -1,8 +1,10 @@
 package solver
 
 import (
+	"context"
 	"fmt"
 	"runtime"
+	"time"
 
 	"github.com/consensys/gnark/logger"
 	"github.com/rs/zerolog"
@@ -16,8 +18,17 @@ type Option func(*Config) error
 // Config is the configuration for the solver with the options applied.
 type Config struct {
 	HintFunctions map[HintID]Hint // defaults to all built-in hint functions
+	HintExecutor  HintExecutor    // executes the hints missing from HintFunctions, if set
 	Logger        zerolog.Logger  // defaults to gnark.Logger
 	NbTasks       int             // defaults to runtime.NumCPU()
+
+	DiskStoreMemory  int           // if set, wire values are stored on disk (see WithDiskStore)
+	DiskStoreDir     string        // directory of the disk store, defaults to os.TempDir()
+	ProgressInterval time.Duration // defaults to 0 (no progress reporting)
+
+	Trace *Trace // if set, the solve is recorded in Trace (see WithTrace)
+
+	Context context.Context // if set, the solver stops when it is done (see WithContext)
 }
 
 // WithHints is a solver option that specifies additional hint functions to be used
@@ -47,6 +58,16 @@ func OverrideHint(id HintID, f Hint) Option {
 	}
 }
 
+// WithHintExecutor is a solver option which executes the hints required by the
+// constraint system and not available as hint functions (registered or given with
+// WithHints) with the executor e.
+func WithHintExecutor(e HintExecutor) Option {
+	return func(opt *Config) error {
+		opt.HintExecutor = e
+		return nil
+	}
+}
+
 // WithLogger is a prover option that specifies zerolog.Logger as a destination for the
 // logs printed by api.Println(). By default, uses gnark/logger.
 // zerolog.Nop() will disable logging
@@ -79,6 +100,63 @@ func WithNbTasks(nbTasks int) Option {
 	}
 }
 
+// WithDiskStore is a solver option that streams the wire values to a temporary file in
+// dir (os.TempDir() if empty) instead of keeping them in memory. At most maxMemory bytes of wire values are kept
+// in memory at once; the least recently used values are written back to the file.
+//
+// This trades solving speed for memory and is meant for circuits whose solution does not
+// fit in memory. Every wire access takes the lock of a shard of the store, also when the
+// value is resident, so solving is a few times slower than with the wire values in memory
+// even if maxMemory holds all of them (see BenchmarkSolveDiskStore in the constraint
+// packages).
+//
+// Only the wire values are bounded: the per-constraint vectors needed by the provers are
+// still allocated in full. For R1CS, the solution returned by Solve holds the wire values
+// in a WireStore which must be closed by the caller, but also the A, B and C vectors of
+// Groth16 in memory, one value per constraint each; the Groth16 prover reads the wire
+// values back chunk by chunk and closes the store. For SparseR1CS, the solution holds the
+// l, r and o vectors of PLONK in memory, read from the store once solved.
+func WithDiskStore(dir string, maxMemory int) Option {
+	return func(opt *Config) error {
+		if maxMemory <= 0 {
+			return fmt.Errorf("invalid disk store memory bound: %d", maxMemory)
+		}
+		opt.DiskStoreDir = dir
+		opt.DiskStoreMemory = maxMemory
+		return nil
+	}
+}
+
+// WithProgress is a solver option that reports the solver progress (solved wires and
+// levels) to the configured logger (see WithLogger), at most once every interval.
+func WithProgress(interval time.Duration) Option {
+	return func(opt *Config) error {
+		if interval <= 0 {
+			return fmt.Errorf("invalid progress interval: %s", interval)
+		}
+		opt.ProgressInterval = interval
+		return nil
+	}
+}
+
+// WithTrace is a solver option which records the trace of the solve in t, replacing its
+// content. The solver runs sequentially. See Trace.
+func WithTrace(t *Trace) Option {
+	return func(opt *Config) error {
+		opt.Trace = t
+		return nil
+	}
+}
+
+// WithContext is a solver option which stops the solver with ctx.Err() when ctx is
+// done. The context is checked between the levels of the constraint system.
+func WithContext(ctx context.Context) Option {
+	return func(opt *Config) error {
+		opt.Context = ctx
+		return nil
+	}
+}
+
 // NewConfig returns a default SolverConfig with given prover options opts applied.
 func NewConfig(opts ...Option) (Config, error) {
 	log := logger.Logger()
@@ -0,0 +1,138 @@
+package solver
+
+import (
+	"encoding/json"
+	"io"
+	"math/big"
+	"sort"
+)
+
+// Trace records the steps of a solve: each instruction executed, the wires it solved and
+// the first failure, if any, along with the values of the named wires when the solver
+// stopped. It is meant to debug failing witnesses (see WithTrace); recording the trace
+// forces the solver to run sequentially and keeps all the wire values in memory.
+//
+// A Trace can be exported and read back as JSON for offline inspection. Field elements are
+// encoded as decimal strings.
+type Trace struct {
+	// Field is the modulus of the scalar field, in decimal.
+	Field string `json:"field"`
+
+	// Inputs are the values of the witness wires (and of the constant wire in R1CS).
+	Inputs []TraceValue `json:"inputs"`
+
+	// Steps are the instructions executed, in execution order.
+	Steps []TraceStep `json:"steps"`
+
+	// Wires are the named wires: the public and secret inputs, named after the fields of
+	// the circuit, and the expressions logged with api.Println, named after the word
+	// preceding them (see constraint.LogEntry.VariableNames). Their values are the values
+	// when the solver stopped.
+	Wires []TraceWire `json:"wires"`
+
+	// Failure is set if solving failed.
+	Failure *TraceFailure `json:"failure,omitempty"`
+}
+
+// TraceValue is the value of a wire.
+type TraceValue struct {
+	Wire  int    `json:"wire"`
+	Value string `json:"value"`
+}
+
+// TraceStep is an instruction executed by the solver.
+type TraceStep struct {
+	Instruction int    `json:"instruction"`        // index of the instruction in the system
+	Level       int    `json:"level"`              // level of the instruction in the instruction tree
+	Blueprint   int    `json:"blueprint"`          // blueprint ID of the instruction
+	Type        string `json:"type"`               // type of the blueprint
+	Constraint  int    `json:"constraint"`         // index of the first constraint of the instruction
+	Symbolic    string `json:"symbolic,omitempty"` // constraint, if the blueprint encodes a R1C or a SparseR1C
+	Hint        string `json:"hint,omitempty"`     // hint name, if the blueprint encodes a hint
+	Error       string `json:"error,omitempty"`    // error of the instruction, if it failed
+
+	// Solved are the wires solved by the instruction.
+	Solved []TraceValue `json:"solved,omitempty"`
+}
+
+// TraceWire is a named wire, or a named linear expression of wires.
+type TraceWire struct {
+	Name   string `json:"name"`
+	Wire   int    `json:"wire"`             // wire ID, or -1 if it is a linear expression of several wires
+	Caller string `json:"caller,omitempty"` // location of the api.Println call
+	Value  string `json:"value,omitempty"`  // empty if it was not solved
+}
+
+// TraceFailure describes the instruction which failed.
+type TraceFailure struct {
+	Step       int    `json:"step"` // index of the failing step in Steps
+	Constraint int    `json:"constraint"`
+	Error      string `json:"error"`
+
+	// Stack is the call stack in the circuit definition of the failing constraint, if it
+	// was recorded with debug information (e.g. by api.AssertIsEqual).
+	Stack []string `json:"stack,omitempty"`
+}
+
+// Value returns the value of the named wire when the solver stopped. It returns false if
+// there is no wire with this name or if it was not solved.
+func (t *Trace) Value(name string) (*big.Int, bool) {
+	for i := range t.Wires {
+		if t.Wires[i].Name == name {
+			if t.Wires[i].Value == "" {
+				return nil, false
+			}
+			return new(big.Int).SetString(t.Wires[i].Value, 10)
+		}
+	}
+	return nil, false
+}
+
+// Names returns the sorted names of the named wires.
+func (t *Trace) Names() []string {
+	names := make([]string, 0, len(t.Wires))
+	for i := range t.Wires {
+		names = append(names, t.Wires[i].Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WireValueAt returns the value of the wire after the step (index in Steps) was executed,
+// replaying the trace. It returns false if the wire was not solved at this point.
+func (t *Trace) WireValueAt(wire, step int) (*big.Int, bool) {
+	for _, v := range t.Inputs {
+		if v.Wire == wire {
+			return new(big.Int).SetString(v.Value, 10)
+		}
+	}
+	for i := 0; i <= step && i < len(t.Steps); i++ {
+		for _, v := range t.Steps[i].Solved {
+			if v.Wire == wire {
+				return new(big.Int).SetString(v.Value, 10)
+			}
+		}
+	}
+	return nil, false
+}
+
+// WireValue returns the value of the wire when the solver stopped.
+func (t *Trace) WireValue(wire int) (*big.Int, bool) {
+	return t.WireValueAt(wire, len(t.Steps)-1)
+}
+
+// WriteJSON writes the trace as indented JSON to w.
+func (t *Trace) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(t)
+}
+
+// ReadTraceJSON reads a trace written with WriteJSON.
+func ReadTraceJSON(r io.Reader) (*Trace, error) {
+	var t Trace
+	if err := json.NewDecoder(r).Decode(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,97 @@
+package solver_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/debug"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+)
+
+type traceCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *traceCircuit) Define(api frontend.API) error {
+	x2 := api.Mul(c.X, c.X)
+	x3 := api.Mul(x2, c.X)
+	api.Println("x3", x3)
+	api.AssertIsEqual(api.Add(x3, c.X, 5), c.Y)
+	api.Println("after", api.Mul(x3, c.Y))
+	return nil
+}
+
+func TestTrace(t *testing.T) {
+	assert := test.NewAssert(t)
+	field := ecc.BN254.ScalarField()
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(field, builder, &traceCircuit{})
+		assert.NoError(err)
+
+		// a failing witness: x³ + x + 5 = 35 != 36
+		witness, err := frontend.NewWitness(&traceCircuit{X: 3, Y: 36}, field)
+		assert.NoError(err)
+		var trace solver.Trace
+		_, err = ccs.Solve(witness, solver.WithTrace(&trace))
+		assert.Error(err)
+
+		assert.NotNil(trace.Failure)
+		assert.Equal(len(trace.Steps)-1, trace.Failure.Step)
+		assert.Equal(err.Error(), trace.Failure.Error)
+		if debug.Debug {
+			// the assertions only record their stack in debug builds
+			assert.NotEmpty(trace.Failure.Stack)
+		}
+		assert.NotEmpty(trace.Steps[trace.Failure.Step].Symbolic)
+
+		// named wires at the failure point
+		x, ok := trace.Value("X")
+		assert.True(ok)
+		assert.Equal(int64(3), x.Int64())
+		y, ok := trace.Value("Y")
+		assert.True(ok)
+		assert.Equal(int64(36), y.Int64())
+		x3, ok := trace.Value("x3")
+		assert.True(ok)
+		assert.Equal(int64(27), x3.Int64())
+		assert.Subset(trace.Names(), []string{"X", "Y", "x3", "after"})
+
+		// replay: x3 is solved by one of the steps
+		var wire int
+		for _, w := range trace.Wires {
+			if w.Name == "x3" {
+				wire = w.Wire
+			}
+		}
+		if wire >= 0 {
+			v, ok := trace.WireValue(wire)
+			assert.True(ok)
+			assert.Equal(int64(27), v.Int64())
+			_, ok = trace.WireValueAt(wire, -1)
+			assert.False(ok, "wire solved before the first step")
+		}
+
+		// the trace is exported as JSON
+		var buf bytes.Buffer
+		assert.NoError(trace.WriteJSON(&buf))
+		read, err := solver.ReadTraceJSON(&buf)
+		assert.NoError(err)
+		assert.Equal(&trace, read)
+
+		// a valid witness has no failure and all named wires are solved
+		witness, err = frontend.NewWitness(&traceCircuit{X: 3, Y: 35}, field)
+		assert.NoError(err)
+		_, err = ccs.Solve(witness, solver.WithTrace(&trace))
+		assert.NoError(err)
+		assert.Nil(trace.Failure)
+		after, ok := trace.Value("after")
+		assert.True(ok)
+		assert.Equal(int64(27*35), after.Int64())
+	}
+}
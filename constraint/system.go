@@ -72,12 +72,23 @@ type ConstraintSystem interface {
 	AttachDebugInfo(debugInfo DebugInfo, constraintID []int)
 
 	// CheckUnconstrainedWires returns and error if the constraint system has wires that are not uniquely constrained.
-	// This is experimental.
+	// This is experimental. See Analyze for a static analysis of hint outputs, public inputs and boolean wires.
 	CheckUnconstrainedWires() error
 
 	GetInstruction(int) Instruction
 
+	// AddTemplateInstance adds the instructions of the template to the system, binding its
+	// inputs to the given wires. It returns a function mapping the template wires to the
+	// system wires.
+	AddTemplateInstance(t *Template, inputs []int, coeffs []uint32) (func(wireID int) int, error)
+
 	GetCoefficient(i int) Element
+
+	// ContentHash returns a deterministic SHA-256 hash of the system, covering its
+	// blueprints, instructions, coefficients, hints and commitments (see System.HashContent).
+	// Two systems with the same content hash encode the same relation with the same
+	// wire layout, independently of the gnark version and debug information.
+	ContentHash() ([]byte, error)
 }
 
 type CustomizableSystem interface {
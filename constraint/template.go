@@ -0,0 +1,243 @@
+package constraint
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/consensys/gnark/debug"
+)
+
+// Template is a constraint system compiled once and instantiated many times in another
+// constraint system of the same type and field (see System.AddTemplateInstance).
+//
+// The public then secret variables of the template are its inputs. At each
+// instantiation, they are bound to existing wires of the target system, and the internal
+// wires of the template are allocated as new internal wires.
+//
+// A template may only contain R1C, SparseR1C and hint instructions encoded with the
+// builtin blueprints; commitments, GKR and custom blueprints are not supported.
+type Template struct {
+	cs     ConstraintSystem
+	system *System
+
+	// location ids of the template symbol table imported in target's symbol table
+	target    *System
+	locations map[int]int
+}
+
+// NewTemplate returns a template from the compiled constraint system cs.
+func NewTemplate(cs ConstraintSystem) (*Template, error) {
+	s, ok := cs.(interface{ getSystem() *System })
+	if !ok {
+		return nil, fmt.Errorf("unsupported constraint system type %T", cs)
+	}
+	system := s.getSystem()
+	if len(system.CommitmentInfo.CommitmentIndexes()) != 0 {
+		return nil, errors.New("commitments are not supported in templates")
+	}
+	if system.GkrInfo.Is() {
+		return nil, errors.New("GKR is not supported in templates")
+	}
+	for _, b := range system.Blueprints {
+		if !isBuiltinBlueprint(b) {
+			return nil, fmt.Errorf("blueprint %T is not supported in templates", b)
+		}
+	}
+	return &Template{cs: cs, system: system}, nil
+}
+
+// ConstraintSystem returns the compiled constraint system of the template.
+func (t *Template) ConstraintSystem() ConstraintSystem {
+	return t.cs
+}
+
+// NbInputs returns the number of public and secret variables of the template.
+func (t *Template) NbInputs() int {
+	return t.system.GetNbPublicVariables() + t.system.GetNbSecretVariables()
+}
+
+// AddTemplateInstance adds the instructions of t to the system, using the blueprints of
+// the system. inputs are the wires bound to the public then secret variables of t, and
+// coeffs maps the coefficient ids of t to coefficient ids of the system. The internal
+// wires of t are allocated as new internal wires of the system.
+//
+// It returns a function mapping the wire ids of t to the wire ids of the system.
+func (system *System) AddTemplateInstance(t *Template, inputs []int, coeffs []uint32) (func(wireID int) int, error) {
+	nbInputs := t.NbInputs()
+	if len(inputs) != nbInputs {
+		return nil, fmt.Errorf("template expects %d inputs, got %d", nbInputs, len(inputs))
+	}
+	if len(coeffs) != t.cs.GetNbCoefficients() {
+		return nil, fmt.Errorf("template has %d coefficients, got %d", t.cs.GetNbCoefficients(), len(coeffs))
+	}
+
+	// register the hints of the template
+	for id, name := range t.system.MHintsDependencies {
+		if registeredName, ok := system.MHintsDependencies[id]; ok && registeredName != name {
+			return nil, fmt.Errorf("hint dependency registration failed; %s previously register with same UUID as %s", name, registeredName)
+		}
+		system.MHintsDependencies[id] = name
+	}
+
+	// the template blueprints are stateless, we use the system ones of the same type.
+	bIDs := make([]BlueprintID, len(t.system.Blueprints))
+	for i, b := range t.system.Blueprints {
+		bIDs[i] = system.blueprintOfType(b)
+	}
+
+	// allocate the internal wires
+	offset := system.NbInternalVariables + system.GetNbPublicVariables() + system.GetNbSecretVariables()
+	for i := 0; i < t.system.NbInternalVariables; i++ {
+		system.AddInternalVariable()
+	}
+	wire := func(wireID uint32) uint32 {
+		if int(wireID) < nbInputs {
+			return uint32(inputs[wireID])
+		}
+		return wireID - uint32(nbInputs) + uint32(offset)
+	}
+	remap := func(l LinearExpression) {
+		for i := range l {
+			l[i].CID = coeffs[l[i].CID]
+			if !l[i].IsConstant() {
+				l[i].VID = wire(l[i].VID)
+			}
+		}
+	}
+
+	calldata := getBuffer()
+	defer putBuffer(calldata)
+
+	var (
+		r1c  R1C
+		sr1c SparseR1C
+		hm   HintMapping
+	)
+	mDebug := make(map[int]int)
+	for i, pi := range t.system.Instructions {
+		inst := pi.Unpack(t.system)
+		bID := bIDs[pi.BlueprintID]
+		*calldata = (*calldata)[:0]
+
+		switch b := t.system.Blueprints[pi.BlueprintID].(type) {
+		case BlueprintR1C:
+			b.DecompressR1C(&r1c, inst)
+			remap(r1c.L)
+			remap(r1c.R)
+			remap(r1c.O)
+			system.Blueprints[bID].(BlueprintR1C).CompressR1C(&r1c, calldata)
+		case BlueprintSparseR1C:
+			b.DecompressSparseR1C(&sr1c, inst)
+			sr1c.XA, sr1c.XB, sr1c.XC = wire(sr1c.XA), wire(sr1c.XB), wire(sr1c.XC)
+			sr1c.QL, sr1c.QR, sr1c.QO = coeffs[sr1c.QL], coeffs[sr1c.QR], coeffs[sr1c.QO]
+			sr1c.QM, sr1c.QC = coeffs[sr1c.QM], coeffs[sr1c.QC]
+			system.Blueprints[bID].(BlueprintSparseR1C).CompressSparseR1C(&sr1c, calldata)
+		case BlueprintHint:
+			b.DecompressHint(&hm, inst)
+			for j := range hm.Inputs {
+				remap(hm.Inputs[j])
+			}
+			// internal wires are allocated contiguously, the output range is preserved.
+			hm.OutputRange.Start = wire(hm.OutputRange.Start)
+			hm.OutputRange.End = wire(hm.OutputRange.End-1) + 1
+			system.Blueprints[bID].(BlueprintHint).CompressHint(hm, calldata)
+		}
+
+		cID := system.NbConstraints
+		system.AddInstruction(bID, *calldata)
+
+		// debug info of the template constraints
+		if dID, ok := t.system.MDebug[int(inst.ConstraintOffset)]; ok && t.system.Blueprints[pi.BlueprintID].NbConstraints() != 0 {
+			if _, ok := mDebug[dID]; !ok {
+				system.DebugInfo = append(system.DebugInfo, system.importLogEntry(t, t.system.DebugInfo[dID], remap))
+				mDebug[dID] = len(system.DebugInfo) - 1
+			}
+			system.MDebug[cID] = mDebug[dID]
+		}
+		if dID, ok := t.system.MHintsDebug[i]; ok {
+			system.DebugInfo = append(system.DebugInfo, system.importLogEntry(t, t.system.DebugInfo[dID], remap))
+			system.MHintsDebug[len(system.Instructions)-1] = len(system.DebugInfo) - 1
+		}
+	}
+
+	// boolean assumptions, in wire order for determinism
+	booleans := make([]int, 0, len(t.system.MBooleanAssumptions))
+	for w := range t.system.MBooleanAssumptions {
+		booleans = append(booleans, w)
+	}
+	sort.Ints(booleans)
+	for _, w := range booleans {
+		dID := t.system.MBooleanAssumptions[w]
+		if dID >= 0 {
+			system.DebugInfo = append(system.DebugInfo, system.importLogEntry(t, t.system.DebugInfo[dID], remap))
+			dID = len(system.DebugInfo) - 1
+		}
+		system.MBooleanAssumptions[int(wire(uint32(w)))] = dID
+	}
+
+	for _, l := range t.system.Logs {
+		system.Logs = append(system.Logs, system.importLogEntry(t, l, remap))
+	}
+
+	return func(wireID int) int { return int(wire(uint32(wireID))) }, nil
+}
+
+// isBuiltinBlueprint returns true if b is one of the stateless R1C, SparseR1C and hint
+// blueprints used by the frontend builders.
+func isBuiltinBlueprint(b Blueprint) bool {
+	switch b.(type) {
+	case *BlueprintGenericHint, *BlueprintGenericR1C, *BlueprintGenericSparseR1C,
+		*BlueprintSparseR1CMul, *BlueprintSparseR1CAdd, *BlueprintSparseR1CBool:
+		return true
+	}
+	return false
+}
+
+// blueprintOfType returns the id of the first blueprint of the same type as b, registering
+// b if there is none.
+func (system *System) blueprintOfType(b Blueprint) BlueprintID {
+	tb := reflect.TypeOf(b)
+	for i := range system.Blueprints {
+		if reflect.TypeOf(system.Blueprints[i]) == tb {
+			return BlueprintID(i)
+		}
+	}
+	return system.AddBlueprint(b)
+}
+
+// importLogEntry returns a copy of the template log entry with remapped expressions and
+// a stack referring to the system symbol table.
+func (system *System) importLogEntry(t *Template, l LogEntry, remap func(LinearExpression)) LogEntry {
+	r := LogEntry{
+		Caller:    l.Caller,
+		Format:    l.Format,
+		ToResolve: make([]LinearExpression, len(l.ToResolve)),
+		Stack:     make([]int, len(l.Stack)),
+	}
+	for i, le := range l.ToResolve {
+		r.ToResolve[i] = le.Clone()
+		remap(r.ToResolve[i])
+	}
+
+	if t.target != system {
+		t.target = system
+		t.locations = make(map[int]int)
+	}
+	for i, lID := range l.Stack {
+		id, ok := t.locations[lID]
+		if !ok {
+			loc := t.system.SymbolTable.Locations[lID]
+			system.SymbolTable.Functions = append(system.SymbolTable.Functions, t.system.SymbolTable.Functions[loc.FunctionID])
+			system.SymbolTable.Locations = append(system.SymbolTable.Locations, debug.Location{
+				FunctionID: len(system.SymbolTable.Functions) - 1,
+				Line:       loc.Line,
+			})
+			id = len(system.SymbolTable.Locations) - 1
+			t.locations[lID] = id
+		}
+		r.Stack[i] = id
+	}
+	return r
+}
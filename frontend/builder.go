@@ -100,6 +100,17 @@ type Rangechecker interface {
 	Check(v Variable, bits int)
 }
 
+// SubCircuitCompiler compiles sub-circuits once into templates and instantiates them at
+// each call. Not all compilers implement this interface. Users should instead use
+// [CallSubCircuit] which falls back to calling Define directly.
+type SubCircuitCompiler interface {
+	// CallSubCircuit returns the outputs of the sub-circuit c on the given inputs. If c
+	// cannot be compiled into a template (for example when it is not comparable or uses
+	// commitments or deferred callbacks), c.Define is called directly and the reason is
+	// logged at debug level.
+	CallSubCircuit(c SubCircuit, inputs []Variable) ([]Variable, error)
+}
+
 // CanonicalVariable represents a variable that's encoded in a constraint system specific way.
 // For example a R1CS builder may represent this as a constraint.LinearExpression,
 // a PLONK builder --> constraint.Term
@@ -0,0 +1,10 @@
+package cs
+
+import "github.com/consensys/gnark/constraint"
+
+// BooleanAssumptions is implemented by the constraint systems which record the wires
+// marked as boolean with frontend.Builder.MarkBoolean, for the static analysis (see
+// constraint.Analyze). The debug information of the call site is optional.
+type BooleanAssumptions interface {
+	AddBooleanAssumption(wireID int, debugInfo ...constraint.DebugInfo)
+}
@@ -394,7 +394,7 @@ func (builder *builder) Xor(_a, _b frontend.Variable) frontend.Variable {
 	t := builder.Sub(builder.cstOne(), builder.Mul(b, 2))
 	t = builder.Add(builder.Mul(a, t), b)
 
-	builder.MarkBoolean(t)
+	builder.markBoolean(t)
 
 	return t
 }
@@ -411,7 +411,7 @@ func (builder *builder) Or(_a, _b frontend.Variable) frontend.Variable {
 
 	// the formulation used is for easing up the conversion to sparse r1cs
 	res := builder.newInternalVariable()
-	builder.MarkBoolean(res)
+	builder.markBoolean(res)
 	c := builder.Neg(res).(expr.LinearExpression)
 
 	c = append(c, a...)
@@ -432,7 +432,7 @@ func (builder *builder) And(_a, _b frontend.Variable) frontend.Variable {
 	builder.AssertIsBoolean(b)
 
 	res := builder.Mul(a, b)
-	builder.MarkBoolean(res)
+	builder.markBoolean(res)
 
 	return res
 }
@@ -568,7 +568,7 @@ func (builder *builder) IsZero(i1 frontend.Variable) frontend.Variable {
 		builder.cs.AttachDebugInfo(debug, []int{c1, c2})
 	}
 
-	builder.MarkBoolean(m)
+	builder.markBoolean(m)
 
 	return m
 }
@@ -74,7 +74,7 @@ func (builder *builder) AssertIsBoolean(i1 frontend.Variable) {
 	if builder.IsBoolean(v) {
 		return // linearExpression is already constrained
 	}
-	builder.MarkBoolean(v)
+	builder.markBoolean(v)
 
 	// ensure v * (1 - v) == 0
 	_v := builder.Sub(builder.cstOne(), v)
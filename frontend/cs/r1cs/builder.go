@@ -26,6 +26,7 @@ import (
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/debug"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs"
 	"github.com/consensys/gnark/frontend/internal/expr"
 	"github.com/consensys/gnark/frontend/schema"
 	"github.com/consensys/gnark/internal/circuitdefer"
@@ -47,7 +48,8 @@ import (
 )
 
 // NewBuilder returns a new R1CS builder which implements frontend.API.
-// Additionally, this builder also implements [frontend.Committer].
+// Additionally, this builder also implements [frontend.Committer] and
+// [frontend.SubCircuitCompiler].
 func NewBuilder(field *big.Int, config frontend.CompileConfig) (frontend.Builder, error) {
 	return newBuilder(field, config), nil
 }
@@ -72,6 +74,9 @@ type builder struct {
 	mbuf2 expr.LinearExpression
 
 	genericGate constraint.BlueprintID
+
+	// sub-circuits compiled into templates, see CallSubCircuit
+	subCircuits map[subCircuitKey]*subCircuitTemplate
 }
 
 // initialCapacity has quite some impact on frontend performance, especially on large circuits size
@@ -82,12 +87,13 @@ func newBuilder(field *big.Int, config frontend.CompileConfig) *builder {
 		macCapacity = config.CompressThreshold
 	}
 	builder := builder{
-		mtBooleans: make(map[[16]byte][]expr.LinearExpression, config.Capacity/10),
-		config:     config,
-		heap:       make(minHeap, 0, 100),
-		mbuf1:      make(expr.LinearExpression, 0, macCapacity),
-		mbuf2:      make(expr.LinearExpression, 0, macCapacity),
-		Store:      kvstore.New(),
+		mtBooleans:  make(map[[16]byte][]expr.LinearExpression, config.Capacity/10),
+		config:      config,
+		heap:        make(minHeap, 0, 100),
+		mbuf1:       make(expr.LinearExpression, 0, macCapacity),
+		mbuf2:       make(expr.LinearExpression, 0, macCapacity),
+		Store:       kvstore.New(),
+		subCircuits: make(map[subCircuitKey]*subCircuitTemplate),
 	}
 
 	// by default the circuit is given a public wire equal to 1
@@ -220,7 +226,26 @@ func (builder *builder) getLinearExpression(_l interface{}) constraint.LinearExp
 // MarkBoolean sets (but do not **constraint**!) v to be boolean
 // This is useful in scenarios where a variable is known to be boolean through a constraint
 // that is not api.AssertIsBoolean. If v is a constant, this is a no-op.
+//
+// The assumption is recorded in the constraint system, so that constraint.Analyze can check
+// that v is actually constrained to be boolean.
 func (builder *builder) MarkBoolean(v frontend.Variable) {
+	builder.markBoolean(v)
+	ba, ok := builder.cs.(cs.BooleanAssumptions)
+	if !ok {
+		return
+	}
+	if l, ok := v.(expr.LinearExpression); ok && len(l) == 1 && l[0].WireID() != 0 && builder.isCstOne(l[0].Coeff) {
+		if debug.Debug {
+			ba.AddBooleanAssumption(l[0].WireID(), builder.newDebugInfo("markBoolean", l))
+		} else {
+			ba.AddBooleanAssumption(l[0].WireID())
+		}
+	}
+}
+
+// markBoolean is MarkBoolean for the builder internals, on variables which are boolean by construction.
+func (builder *builder) markBoolean(v frontend.Variable) {
 	if b, ok := builder.constantValue(v); ok {
 		if !(b.IsZero() || builder.isCstOne(b)) {
 			panic("MarkBoolean called a non-boolean constant")
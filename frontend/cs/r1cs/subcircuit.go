@@ -0,0 +1,123 @@
+package r1cs
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/internal/expr"
+	"github.com/consensys/gnark/frontend/schema"
+	"github.com/consensys/gnark/internal/circuitdefer"
+	"github.com/consensys/gnark/logger"
+)
+
+type subCircuitKey struct {
+	c        frontend.SubCircuit
+	nbInputs int
+}
+
+// subCircuitTemplate is a sub-circuit compiled once. If template is nil, the sub-circuit
+// can not be compiled into a template and is defined inline at each call.
+type subCircuitTemplate struct {
+	template *constraint.Template
+	coeffs   []uint32                // template coefficient id -> builder coefficient id
+	outputs  []expr.LinearExpression // outputs, referring to template wires
+	booleans []bool                  // outputs marked as boolean in the template
+}
+
+// CallSubCircuit implements [frontend.SubCircuitCompiler].
+func (builder *builder) CallSubCircuit(c frontend.SubCircuit, inputs []frontend.Variable) ([]frontend.Variable, error) {
+	if !reflect.ValueOf(c).Comparable() {
+		return c.Define(builder, inputs)
+	}
+	key := subCircuitKey{c: c, nbInputs: len(inputs)}
+	t, ok := builder.subCircuits[key]
+	if !ok {
+		var err error
+		if t, err = builder.compileSubCircuit(c, len(inputs)); err != nil {
+			return nil, err
+		}
+		builder.subCircuits[key] = t
+	}
+	if t.template == nil {
+		return c.Define(builder, inputs)
+	}
+
+	// the template wire 0 is the constant wire, then come the inputs.
+	wires := make([]int, len(inputs)+1)
+	for i := range inputs {
+		wires[i+1] = builder.toWire(inputs[i])
+	}
+	wire, err := builder.cs.AddTemplateInstance(t.template, wires, t.coeffs)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := make([]frontend.Variable, len(t.outputs))
+	for i, o := range t.outputs {
+		l := make(expr.LinearExpression, len(o))
+		for j := range o {
+			l[j] = expr.Term{VID: wire(o[j].VID), Coeff: o[j].Coeff}
+		}
+		if t.booleans[i] {
+			builder.markBoolean(l)
+		}
+		outputs[i] = l
+	}
+	return outputs, nil
+}
+
+// compileSubCircuit defines c in a new builder and returns the resulting template.
+func (builder *builder) compileSubCircuit(c frontend.SubCircuit, nbInputs int) (*subCircuitTemplate, error) {
+	// the capacity is the one of the whole circuit, not of the sub-circuit
+	config := builder.config
+	config.Capacity = 0
+	sub := newBuilder(builder.Field(), config)
+	inputs := make([]frontend.Variable, nbInputs)
+	for i := range inputs {
+		name := "in" + strconv.Itoa(i)
+		inputs[i] = sub.SecretVariable(schema.LeafInfo{FullName: func() string { return name }, Visibility: schema.Secret})
+	}
+	outputs, err := c.Define(sub, inputs)
+	if err != nil {
+		return nil, err
+	}
+	if len(circuitdefer.GetAll[func(frontend.API) error](sub)) != 0 {
+		return &subCircuitTemplate{}, nil
+	}
+	template, err := constraint.NewTemplate(sub.cs)
+	if err != nil {
+		// e.g. the sub-circuit has a commitment or a custom blueprint
+		log := logger.Logger()
+		log.Debug().Err(err).Str("subcircuit", reflect.TypeOf(c).String()).Msg("sub-circuit can't be compiled into a template, it is defined inline")
+		return &subCircuitTemplate{}, nil
+	}
+
+	t := &subCircuitTemplate{
+		template: template,
+		coeffs:   make([]uint32, sub.cs.GetNbCoefficients()),
+		outputs:  make([]expr.LinearExpression, len(outputs)),
+		booleans: make([]bool, len(outputs)),
+	}
+	for i := range t.coeffs {
+		t.coeffs[i] = builder.cs.AddCoeff(sub.cs.GetCoefficient(i))
+	}
+	for i := range outputs {
+		t.outputs[i] = sub.toVariable(outputs[i]).Clone()
+		t.booleans[i] = sub.IsBoolean(outputs[i])
+	}
+	return t, nil
+}
+
+// toWire returns the wire of v if it is a single wire with coefficient one. Otherwise, it
+// returns a new internal wire constrained to be equal to v.
+func (builder *builder) toWire(v frontend.Variable) int {
+	l := builder.toVariable(v)
+	if len(l) == 1 && l[0].VID != 0 && builder.isCstOne(l[0].Coeff) {
+		return l[0].VID
+	}
+	t := builder.newInternalVariable()
+	builder.cs.AddR1C(builder.newR1C(l, builder.cstOne(), t), builder.genericGate)
+	return t[0].VID
+}
@@ -283,7 +283,7 @@ func (builder *builder) Xor(a, b frontend.Variable) frontend.Variable {
 	}
 
 	res := builder.newInternalVariable()
-	builder.MarkBoolean(res)
+	builder.markBoolean(res)
 
 	// if one input is constant, ensure we put it in b.
 	if aConstant {
@@ -366,7 +366,7 @@ func (builder *builder) Or(a, b frontend.Variable) frontend.Variable {
 		}
 	}
 	res := builder.newInternalVariable()
-	builder.MarkBoolean(res)
+	builder.markBoolean(res)
 	xa := a.(expr.Term)
 	xb := b.(expr.Term)
 	// -a - b + ab + res == 0
@@ -394,7 +394,7 @@ func (builder *builder) And(a, b frontend.Variable) frontend.Variable {
 	builder.AssertIsBoolean(a)
 	builder.AssertIsBoolean(b)
 	res := builder.Mul(a, b)
-	builder.MarkBoolean(res)
+	builder.markBoolean(res)
 	return res
 }
 
@@ -511,7 +511,7 @@ func (builder *builder) IsZero(i1 frontend.Variable) frontend.Variable {
 		qM: a.Coeff,
 	})
 
-	builder.MarkBoolean(m)
+	builder.markBoolean(m)
 
 	return m
 }
@@ -110,7 +110,7 @@ func (builder *builder) AssertIsBoolean(i1 frontend.Variable) {
 	if builder.IsBoolean(v) {
 		return
 	}
-	builder.MarkBoolean(v)
+	builder.markBoolean(v)
 
 	// ensure v * (1 - v) == 0
 	// that is v + -v*v == 0
@@ -26,6 +26,7 @@ import (
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/debug"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs"
 	"github.com/consensys/gnark/frontend/internal/expr"
 	"github.com/consensys/gnark/frontend/schema"
 	"github.com/consensys/gnark/internal/circuitdefer"
@@ -71,6 +72,9 @@ type builder struct {
 	genericGate                constraint.BlueprintID
 	mulGate, addGate, boolGate constraint.BlueprintID
 
+	// sub-circuits compiled into templates, see CallSubCircuit
+	subCircuits map[subCircuitKey]*subCircuitTemplate
+
 	// used to avoid repeated allocations
 	bufL expr.LinearExpression
 	bufH []constraint.LinearExpression
@@ -86,6 +90,7 @@ func newBuilder(field *big.Int, config frontend.CompileConfig) *builder {
 		config:           config,
 		Store:            kvstore.New(),
 		bufL:             make(expr.LinearExpression, 20),
+		subCircuits:      make(map[subCircuitKey]*subCircuitTemplate),
 	}
 	// init hint buffer.
 	_ = b.hintBuffer(256)
@@ -275,7 +280,26 @@ func (builder *builder) IsBoolean(v frontend.Variable) bool {
 // MarkBoolean sets (but do not constraint!) v to be boolean
 // This is useful in scenarios where a variable is known to be boolean through a constraint
 // that is not api.AssertIsBoolean. If v is a constant, this is a no-op.
+//
+// The assumption is recorded in the constraint system, so that constraint.Analyze can check
+// that v is actually constrained to be boolean.
 func (builder *builder) MarkBoolean(v frontend.Variable) {
+	builder.markBoolean(v)
+	ba, ok := builder.cs.(cs.BooleanAssumptions)
+	if !ok {
+		return
+	}
+	if t, ok := v.(expr.Term); ok && builder.cs.IsOne(t.Coeff) {
+		if debug.Debug {
+			ba.AddBooleanAssumption(t.WireID(), builder.newDebugInfo("markBoolean", t))
+		} else {
+			ba.AddBooleanAssumption(t.WireID())
+		}
+	}
+}
+
+// markBoolean is MarkBoolean for the builder internals, on variables which are boolean by construction.
+func (builder *builder) markBoolean(v frontend.Variable) {
 	if _, ok := builder.constantValue(v); ok {
 		if !builder.IsBoolean(v) {
 			panic("MarkBoolean called a non-boolean constant")
@@ -0,0 +1,24 @@
+package frontend
+
+// SubCircuit is a gadget which can be compiled once into a template and instantiated at
+// each call, instead of running Define for every call (see [CallSubCircuit]).
+//
+// Define must only depend on the sub-circuit value and on the given inputs, and must not
+// capture Variables of the calling circuit. The template is compiled with all inputs being
+// variables: no optimization is done for constant inputs.
+type SubCircuit interface {
+	// Define declares the constraints of the sub-circuit and returns its outputs.
+	Define(api API, inputs []Variable) ([]Variable, error)
+}
+
+// CallSubCircuit returns the outputs of the sub-circuit c on the given inputs.
+//
+// If the compiler implements [SubCircuitCompiler], c is compiled once per value and number
+// of inputs into a template which is then instantiated with the inputs. Otherwise (for
+// example in the test engine), c.Define is called directly.
+func CallSubCircuit(api API, c SubCircuit, inputs ...Variable) ([]Variable, error) {
+	if sc, ok := api.(SubCircuitCompiler); ok {
+		return sc.CallSubCircuit(c, inputs)
+	}
+	return c.Define(api, inputs)
+}
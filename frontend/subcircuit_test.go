@@ -0,0 +1,122 @@
+package frontend_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+// powRounds computes rounds iterations of x ↦ (x+k)⁵ on the first input, the inverse of
+// the second input (or 1 if it is zero), whether it is zero and a constant.
+type powRounds struct {
+	rounds int
+	k      int
+}
+
+func (p powRounds) Define(api frontend.API, inputs []frontend.Variable) ([]frontend.Variable, error) {
+	x := inputs[0]
+	for i := 0; i < p.rounds; i++ {
+		x = api.Add(x, p.k)
+		x2 := api.Mul(x, x)
+		x = api.Mul(x2, x2, x)
+	}
+	isZero := api.IsZero(inputs[1])
+	inv := api.DivUnchecked(1, api.Add(inputs[1], isZero))
+	return []frontend.Variable{x, inv, isZero, 3}, nil
+}
+
+func (p powRounds) eval(x *big.Int, field *big.Int) *big.Int {
+	r := new(big.Int).Set(x)
+	for i := 0; i < p.rounds; i++ {
+		r.Add(r, big.NewInt(int64(p.k)))
+		r.Exp(r, big.NewInt(5), field)
+	}
+	return r
+}
+
+// notComparable can not be used as a template key and is defined inline.
+type notComparable struct {
+	powRounds
+	unused []int
+}
+
+type subCircuitCircuit struct {
+	X, Y   [3]frontend.Variable
+	Res    [3]frontend.Variable `gnark:",public"`
+	inline bool
+}
+
+func (c *subCircuitCircuit) Define(api frontend.API) error {
+	for i := range c.X {
+		var sub frontend.SubCircuit = powRounds{rounds: 4, k: i % 2}
+		if c.inline {
+			sub = notComparable{powRounds: powRounds{rounds: 4, k: i % 2}}
+		}
+		// the first input is scaled and bound to a new wire
+		out, err := frontend.CallSubCircuit(api, sub, api.Mul(c.X[i], 2), c.Y[i])
+		if err != nil {
+			return err
+		}
+		api.AssertIsBoolean(out[2])
+		api.AssertIsEqual(api.Mul(api.Add(c.Y[i], out[2]), out[1]), 1)
+		api.AssertIsEqual(out[3], 3)
+		api.AssertIsEqual(api.Select(out[2], 0, out[0]), c.Res[i])
+	}
+	// constant input
+	out, err := frontend.CallSubCircuit(api, powRounds{rounds: 1}, 2, c.X[0])
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(out[0], 32)
+	return nil
+}
+
+func subCircuitAssignment(field *big.Int) *subCircuitCircuit {
+	var w subCircuitCircuit
+	for i := range w.X {
+		w.X[i] = i + 1
+		w.Y[i] = i
+		if i == 0 {
+			w.Res[i] = 0
+		} else {
+			w.Res[i] = powRounds{rounds: 4, k: i % 2}.eval(big.NewInt(int64(2*(i+1))), field)
+		}
+	}
+	return &w
+}
+
+func TestSubCircuit(t *testing.T) {
+	assert := require.New(t)
+	field := ecc.BN254.ScalarField()
+
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(field, builder, &subCircuitCircuit{})
+		assert.NoError(err)
+		inline, err := frontend.Compile(field, builder, &subCircuitCircuit{inline: true})
+		assert.NoError(err)
+		// binding the inputs costs at most one constraint per input
+		assert.LessOrEqual(ccs.GetNbConstraints(), inline.GetNbConstraints()+8)
+
+		assignment := subCircuitAssignment(field)
+		w, err := frontend.NewWitness(assignment, field)
+		assert.NoError(err)
+		_, err = ccs.Solve(w)
+		assert.NoError(err)
+		_, err = inline.Solve(w)
+		assert.NoError(err)
+
+		assignment.Res[1] = 1
+		w, err = frontend.NewWitness(assignment, field)
+		assert.NoError(err)
+		_, err = ccs.Solve(w)
+		assert.Error(err)
+	}
+
+	assert.NoError(test.IsSolved(&subCircuitCircuit{}, subCircuitAssignment(field), field))
+}
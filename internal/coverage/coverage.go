@@ -0,0 +1,21 @@
+// Package coverage lets gadgets which are not methods of frontend.API report the branch
+// they select to the test engine, for its coverage report (see test.WithCoverage).
+package coverage
+
+import "github.com/consensys/gnark/frontend"
+
+// BranchRecorder is implemented by the APIs recording branch coverage.
+type BranchRecorder interface {
+	// RecordBranch records that the gadget kind selected the branch sel among nbBranches.
+	// It must only be called through coverage.RecordBranch: the recorder attributes the
+	// branch to the caller of the gadget calling coverage.RecordBranch.
+	RecordBranch(kind string, sel frontend.Variable, nbBranches int)
+}
+
+// RecordBranch records that the gadget kind selected the branch sel among nbBranches, if
+// api records branch coverage. It is a no-op otherwise, in particular when compiling.
+func RecordBranch(api frontend.API, kind string, sel frontend.Variable, nbBranches int) {
+	if r, ok := api.(BranchRecorder); ok {
+		r.RecordBranch(kind, sel, nbBranches)
+	}
+}
@@ -0,0 +1,148 @@
+// Command evmchecker compiles a Solidity verifier with solc and calls it in an
+// in-memory EVM with the Prague precompiled contracts, including the BLS12-381
+// ones of EIP-2537.
+//
+// It is used by the solccheck tests of gnark for the curves gnark-solidity-checker
+// doesn't support, when the evmcheck build tag is set:
+//
+//	(cd internal/evmchecker && go install .)
+//	go test -tags solccheck,evmcheck ./backend/...
+//
+// It lives in its own module so that gnark doesn't depend on go-ethereum, and it
+// is not installed by the CI.
+//
+// Usage:
+//
+//	evmchecker -solidity verifier.sol -method Verify -calldata <hex>
+//
+// The calldata is the ABI encoding of the arguments of the method, without the
+// selector. The command fails if the call reverts or returns false.
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// gasLimit is the gas given to the call, the block gas limit of mainnet.
+const gasLimit = 30_000_000
+
+func main() {
+	solidity := flag.String("solidity", "", "path to the Solidity file")
+	contract := flag.String("contract", "", "name of the contract, optional if the file has a single deployable contract")
+	method := flag.String("method", "", "name of the method to call")
+	calldata := flag.String("calldata", "", "hex encoded ABI arguments of the method")
+	flag.Parse()
+
+	if err := run(*solidity, *contract, *method, *calldata); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(solidity, contract, method, calldata string) error {
+	if solidity == "" || method == "" {
+		return errors.New("-solidity and -method are required")
+	}
+	args, err := hex.DecodeString(strings.TrimPrefix(calldata, "0x"))
+	if err != nil {
+		return fmt.Errorf("decode calldata: %w", err)
+	}
+	bin, selector, err := compile(solidity, contract, method)
+	if err != nil {
+		return err
+	}
+
+	cfg := &runtime.Config{
+		ChainConfig: params.MergedTestChainConfig,
+		GasLimit:    gasLimit,
+	}
+	_, address, _, err := runtime.Create(bin, cfg)
+	if err != nil {
+		return fmt.Errorf("deploy contract: %w", err)
+	}
+	ret, leftOver, err := runtime.Call(address, append(selector, args...), cfg)
+	if err != nil {
+		return fmt.Errorf("call %s: %w%s", method, err, revertReason(ret))
+	}
+	// the verifiers either revert or return a boolean
+	if len(ret) == 32 && new(big.Int).SetBytes(ret).Sign() == 0 {
+		return fmt.Errorf("call %s: returned false", method)
+	}
+	fmt.Printf("%s succeeded, gas used: %d\n", method, gasLimit-leftOver)
+	return nil
+}
+
+// compile returns the creation bytecode of the contract and the selector of the
+// method.
+func compile(solidity, contract, method string) (bin, selector []byte, err error) {
+	cmd := exec.Command("solc", "--optimize", "--combined-json", "bin,hashes", solidity)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, nil, fmt.Errorf("solc: %w\n%s", err, stderr.String())
+	}
+	var res struct {
+		Contracts map[string]struct {
+			Bin    string            `json:"bin"`
+			Hashes map[string]string `json:"hashes"`
+		} `json:"contracts"`
+	}
+	if err := json.Unmarshal(out, &res); err != nil {
+		return nil, nil, fmt.Errorf("parse solc output: %w", err)
+	}
+
+	found := false
+	for name, c := range res.Contracts {
+		// the names are prefixed by the file path
+		if contract != "" && !strings.HasSuffix(name, ":"+contract) || c.Bin == "" {
+			continue
+		}
+		if found {
+			return nil, nil, errors.New("several contracts found, use -contract")
+		}
+		found = true
+		if bin, err = hex.DecodeString(c.Bin); err != nil {
+			return nil, nil, err
+		}
+		for signature, id := range c.Hashes {
+			if strings.HasPrefix(signature, method+"(") {
+				if selector, err = hex.DecodeString(id); err != nil {
+					return nil, nil, err
+				}
+			}
+		}
+	}
+	if !found {
+		return nil, nil, errors.New("contract not found")
+	}
+	if selector == nil {
+		return nil, nil, fmt.Errorf("method %s not found", method)
+	}
+	return bin, selector, nil
+}
+
+// revertReason formats the revert data: the message of Error(string) or the
+// selector of a custom error.
+func revertReason(ret []byte) string {
+	if reason, err := abi.UnpackRevert(ret); err == nil {
+		return ": " + reason
+	}
+	if len(ret) >= 4 {
+		return ": error 0x" + hex.EncodeToString(ret[:4])
+	}
+	return ""
+}
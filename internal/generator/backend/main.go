@@ -105,6 +105,8 @@ func main() {
 				groth16Dir         = strings.Replace(d.RootPath, "{?}", "groth16", 1)
 				groth16MpcSetupDir = filepath.Join(groth16Dir, "mpcsetup")
 				plonkDir           = strings.Replace(d.RootPath, "{?}", "plonk", 1)
+				plonkMpcSetupDir   = filepath.Join(plonkDir, "mpcsetup")
+				mpcSetupDir        = strings.Replace(d.RootPath, "backend/{?}", "internal/backend/mpcsetup", 1)
 			)
 
 			if err := os.MkdirAll(groth16Dir, 0700); err != nil {
@@ -122,6 +124,7 @@ func main() {
 				{File: filepath.Join(csDir, "marshal.go"), Templates: []string{"marshal.go.tmpl", importCurve}},
 				{File: filepath.Join(csDir, "coeff.go"), Templates: []string{"coeff.go.tmpl", importCurve}},
 				{File: filepath.Join(csDir, "solver.go"), Templates: []string{"solver.go.tmpl", importCurve}},
+				{File: filepath.Join(csDir, "wirestore.go"), Templates: []string{"wirestore.go.tmpl", importCurve}},
 			}
 			if err := bgen.Generate(d, "cs", "./template/representations/", entries...); err != nil {
 				panic(err)
@@ -154,6 +157,9 @@ func main() {
 			if err := os.MkdirAll(plonkDir, 0700); err != nil {
 				panic(err)
 			}
+			if err := os.MkdirAll(mpcSetupDir, 0700); err != nil {
+				panic(err)
+			}
 
 			entries = []bavard.Entry{
 				{File: filepath.Join(groth16Dir, "verify.go"), Templates: []string{"groth16/groth16.verify.go.tmpl", importCurve}},
@@ -173,6 +179,14 @@ func main() {
 				panic(err) // TODO handle
 			}
 
+			// helpers shared by the groth16 and plonk mpcsetup
+			entries = []bavard.Entry{
+				{File: filepath.Join(mpcSetupDir, "utils.go"), Templates: []string{"mpcsetup/utils.go.tmpl", importCurve}},
+			}
+			if err := bgen.Generate(d, "mpcsetup", "./template/zkpschemes/", entries...); err != nil {
+				panic(err)
+			}
+
 			// groth16 mpcsetup
 			entries = []bavard.Entry{
 				{File: filepath.Join(groth16MpcSetupDir, "lagrange.go"), Templates: []string{"groth16/mpcsetup/lagrange.go.tmpl", importCurve}},
@@ -189,6 +203,30 @@ func main() {
 				panic(err) // TODO handle
 			}
 
+			// groth16 aggregation, on the curves used in practice
+			if d.Curve == "BN254" || d.Curve == "BLS12-381" {
+				groth16AggregateDir := filepath.Join(groth16Dir, "aggregate")
+				if err := os.MkdirAll(groth16AggregateDir, 0700); err != nil {
+					panic(err)
+				}
+				entries = []bavard.Entry{
+					{File: filepath.Join(groth16AggregateDir, "marshal.go"), Templates: []string{"groth16/aggregate/marshal.go.tmpl", importCurve}},
+					{File: filepath.Join(groth16AggregateDir, "prove.go"), Templates: []string{"groth16/aggregate/prove.go.tmpl", importCurve}},
+					{File: filepath.Join(groth16AggregateDir, "srs.go"), Templates: []string{"groth16/aggregate/srs.go.tmpl", importCurve}},
+					{File: filepath.Join(groth16AggregateDir, "utils.go"), Templates: []string{"groth16/aggregate/utils.go.tmpl", importCurve}},
+					{File: filepath.Join(groth16AggregateDir, "verify.go"), Templates: []string{"groth16/aggregate/verify.go.tmpl", importCurve}},
+				}
+				if err := bgen.Generate(d, "aggregate", "./template/zkpschemes/", entries...); err != nil {
+					panic(err)
+				}
+				entries = []bavard.Entry{
+					{File: filepath.Join(groth16AggregateDir, "aggregate_test.go"), Templates: []string{"groth16/aggregate/aggregate_test.go.tmpl", importCurve}},
+				}
+				if err := bgen.Generate(d, "aggregate_test", "./template/zkpschemes/", entries...); err != nil {
+					panic(err)
+				}
+			}
+
 			// plonk
 			entries = []bavard.Entry{
 				{File: filepath.Join(plonkDir, "verify.go"), Templates: []string{"plonk/plonk.verify.go.tmpl", importCurve}},
@@ -201,6 +239,20 @@ func main() {
 				panic(err)
 			}
 
+			// plonk mpcsetup
+			entries = []bavard.Entry{
+				{File: filepath.Join(plonkMpcSetupDir, "marshal.go"), Templates: []string{"plonk/mpcsetup/marshal.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkMpcSetupDir, "srs.go"), Templates: []string{"plonk/mpcsetup/srs.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkMpcSetupDir, "srs_test.go"), Templates: []string{"plonk/mpcsetup/srs_test.go.tmpl", importCurve}},
+			}
+			if d.Curve == "BN254" {
+				// the perpetual powers of tau ceremony is only run on BN254
+				entries = append(entries, bavard.Entry{File: filepath.Join(plonkMpcSetupDir, "ppot.go"), Templates: []string{"plonk/mpcsetup/ppot.go.tmpl", importCurve}})
+			}
+			if err := bgen.Generate(d, "mpcsetup", "./template/zkpschemes/", entries...); err != nil {
+				panic(err)
+			}
+
 		}(d)
 
 	}
@@ -0,0 +1,118 @@
+// Package mmap maps files in memory, and writes and reads slices of fixed size
+// elements at aligned offsets, so that they can be used in place from the mapping.
+//
+// As gnark-crypto/utils/unsafe, the slices are written with their raw memory
+// representation: the files are platform dependent and the elements must not contain
+// pointers.
+package mmap
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"unsafe"
+)
+
+// Alignment of the slices data in the files written with WriteSlice, relative to the
+// start of the file. The mappings are page aligned.
+const Alignment = 64
+
+// Mapping is a read-only memory mapping of a file.
+type Mapping struct {
+	data []byte
+}
+
+// Bytes returns the mapped content of the file. It must not be modified, nor used
+// after the mapping is closed.
+func (m *Mapping) Bytes() []byte {
+	return m.data
+}
+
+// Writer counts the bytes written to the underlying writer, to align the slices.
+type Writer struct {
+	w io.Writer
+	n int64
+}
+
+// NewWriter returns a Writer writing to w, which is assumed to be at the start of
+// the file.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+// BytesWritten returns the number of bytes written to the underlying writer.
+func (w *Writer) BytesWritten() int64 {
+	return w.n
+}
+
+// WriteSlice writes the length of s, pads the file up to Alignment and writes the
+// memory of s.
+func WriteSlice[E any](w *Writer, s []E) error {
+	if err := binary.Write(w, binary.LittleEndian, uint64(len(s))); err != nil {
+		return err
+	}
+	if _, err := w.Write(make([]byte, padding(w.n))); err != nil {
+		return err
+	}
+	if len(s) == 0 {
+		return nil
+	}
+	var e E
+	_, err := w.Write(unsafe.Slice((*byte)(unsafe.Pointer(&s[0])), int(unsafe.Sizeof(e))*len(s)))
+	return err
+}
+
+// Reader reads the content of a mapping.
+type Reader struct {
+	data []byte
+	off  int
+}
+
+// NewReader returns a Reader reading data from its start.
+func NewReader(data []byte) *Reader {
+	return &Reader{data: data}
+}
+
+func (r *Reader) Read(p []byte) (int, error) {
+	if r.off == len(r.data) {
+		return 0, io.EOF
+	}
+	n := copy(p, r.data[r.off:])
+	r.off += n
+	return n, nil
+}
+
+// ReadSlice returns the slice written by WriteSlice at the position of r. The slice
+// is not copied: it points to the mapped memory.
+func ReadSlice[E any](r *Reader) ([]E, error) {
+	var buf [8]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return nil, err
+	}
+	length := binary.LittleEndian.Uint64(buf[:])
+	off := r.off + padding(int64(r.off))
+	var e E
+	size := uint64(unsafe.Sizeof(e))
+	if off > len(r.data) || length > uint64(len(r.data)-off)/size {
+		return nil, errors.New("slice exceeds the mapped file")
+	}
+	r.off = off + int(length*size)
+	if length == 0 {
+		return make([]E, 0), nil
+	}
+	if uintptr(unsafe.Pointer(&r.data[off]))%unsafe.Alignof(e) != 0 {
+		return nil, errors.New("misaligned slice in the mapped file")
+	}
+	return unsafe.Slice((*E)(unsafe.Pointer(&r.data[off])), int(length)), nil
+}
+
+// padding returns the number of bytes to add after offset n to reach Alignment.
+func padding(n int64) int {
+	return int((Alignment - n%Alignment) % Alignment)
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package mmap
+
+import "errors"
+
+// Open maps the file at path in memory. It is only supported on unix platforms.
+func Open(path string) (*Mapping, error) {
+	return nil, errors.New("memory mapping is not supported on this platform")
+}
+
+// Close unmaps the file.
+func (m *Mapping) Close() error {
+	return nil
+}
@@ -0,0 +1,60 @@
+//go:build unix
+
+package mmap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlices(t *testing.T) {
+	assert := require.New(t)
+	path := filepath.Join(t.TempDir(), "slices")
+	f, err := os.Create(path)
+	assert.NoError(err)
+
+	a := []uint64{1, 2, 3}
+	b := [][4]uint64{{4, 5, 6, 7}, {8, 9, 10, 11}}
+	w := NewWriter(f)
+	_, err = w.Write([]byte{0xff}) // unaligned header
+	assert.NoError(err)
+	assert.NoError(WriteSlice(w, a))
+	assert.NoError(WriteSlice(w, []uint64{}))
+	assert.NoError(WriteSlice(w, b))
+	assert.NoError(f.Close())
+
+	m, err := Open(path)
+	assert.NoError(err)
+	defer m.Close()
+	r := NewReader(m.Bytes())
+	var header [1]byte
+	_, err = r.Read(header[:])
+	assert.NoError(err)
+
+	readA, err := ReadSlice[uint64](r)
+	assert.NoError(err)
+	assert.Equal(a, readA)
+	empty, err := ReadSlice[uint64](r)
+	assert.NoError(err)
+	assert.Empty(empty)
+	readB, err := ReadSlice[[4]uint64](r)
+	assert.NoError(err)
+	assert.Equal(b, readB)
+
+	_, err = ReadSlice[uint64](r)
+	assert.Error(err, "nothing left to read")
+
+	// a length beyond the end of the file
+	r = NewReader(m.Bytes()[:len(m.Bytes())-8])
+	_, err = r.Read(header[:])
+	assert.NoError(err)
+	_, err = ReadSlice[uint64](r)
+	assert.NoError(err)
+	_, err = ReadSlice[uint64](r)
+	assert.NoError(err)
+	_, err = ReadSlice[[4]uint64](r)
+	assert.Error(err)
+}
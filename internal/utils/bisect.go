@@ -0,0 +1,36 @@
+package utils
+
+// Bisect returns the sorted indexes in [0, n) which fail a batched check, given that the
+// check of the whole range failed. check(start, end) reports whether the elements of
+// [start, end) are all valid. Bisecting the failing ranges finds k invalid elements with
+// O(k·log n) checks.
+func Bisect(n int, check func(start, end int) (bool, error)) ([]int, error) {
+	var invalid []int
+	var bisect func(start, end int, failed bool) error
+	bisect = func(start, end int, failed bool) error {
+		if !failed {
+			ok, err := check(start, end)
+			if err != nil || ok {
+				return err
+			}
+		}
+		if end-start == 1 {
+			invalid = append(invalid, start)
+			return nil
+		}
+		mid := (start + end) / 2
+		nbInvalid := len(invalid)
+		if err := bisect(start, mid, false); err != nil {
+			return err
+		}
+		// if the left half is valid, the right half is known to fail
+		return bisect(mid, end, len(invalid) == nbInvalid)
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	if err := bisect(0, n, true); err != nil {
+		return nil, err
+	}
+	return invalid, nil
+}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBisect(t *testing.T) {
+	invalid := map[int]bool{3: true, 4: true, 11: true}
+	nbChecks := 0
+	res, err := Bisect(16, func(start, end int) (bool, error) {
+		nbChecks++
+		for i := start; i < end; i++ {
+			if invalid[i] {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(res, []int{3, 4, 11}) {
+		t.Fatalf("expected [3 4 11], got %v", res)
+	}
+	if nbChecks >= 16 {
+		t.Fatalf("expected less checks than elements, got %d", nbChecks)
+	}
+}
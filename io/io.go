@@ -47,3 +47,14 @@ type BinaryDumper interface {
 	WriteDump(w io.Writer) error
 	ReadDump(r io.Reader) error
 }
+
+// MappedDumper is the interface that wraps the WriteMappedDump and ReadMappedDump methods.
+// WriteMappedDump writes the object to w, which must be at the start of a file, as a dump
+// whose large slices are aligned. ReadMappedDump maps the file at path in memory and reads
+// the object from it, without copying the large slices to the heap. The returned io.Closer
+// unmaps the file, after which the object must not be used.
+// As BinaryDumper, this is very unsafe and platform dependent.
+type MappedDumper interface {
+	WriteMappedDump(w io.Writer) error
+	ReadMappedDump(path string) (io.Closer, error)
+}
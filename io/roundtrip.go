@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"io"
+	"os"
 	"reflect"
 )
 
@@ -90,3 +91,31 @@ func DumpRoundTripCheck(from any, to func() any) error {
 	}
 	return nil
 }
+
+// MappedDumpRoundTripCheck is a helper to check that a mapped dump round trip is correct.
+// It writes the object to the file at path, then maps it back and checks that the
+// reconstructed object is equal to the original.
+func MappedDumpRoundTripCheck(from any, to func() any, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := from.(MappedDumper).WriteMappedDump(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	r := to().(MappedDumper)
+	closer, err := r.ReadMappedDump(path)
+	if err != nil {
+		return err
+	}
+	defer closer.Close()
+	if !reflect.DeepEqual(from, r) {
+		return errors.New("reconstructed object don't match original (ReadMappedDump)")
+	}
+	return nil
+}
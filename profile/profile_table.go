@@ -0,0 +1,122 @@
+package profile
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// label of the samples giving the backend the constraint was added for
+const (
+	backendLabel = "backend"
+	backendR1CS  = "r1cs"
+	backendSCS   = "scs"
+)
+
+// Gadget is the number of constraints attributed to a function of the circuit, per
+// backend. A constraint is attributed to all the functions in its call stack.
+type Gadget struct {
+	// Name of the function. Methods of the frontend builders are merged across
+	// backends and named "api.<Method>".
+	Name string
+
+	// R1CS is the number of constraints added when compiling with frontend/cs/r1cs.
+	R1CS int
+
+	// SCS is the number of constraints added when compiling with frontend/cs/scs.
+	SCS int
+}
+
+// Gadgets returns the number of constraints attributed to each function of the profiled
+// circuits, per backend. It is meant to compare the cost of the gadgets of a circuit in
+// each arithmetization: the same circuit is compiled with r1cs.NewBuilder and
+// scs.NewBuilder in one profiling session:
+//
+//	p := profile.Start(profile.WithNoOutput())
+//	_, _ = frontend.Compile(field, r1cs.NewBuilder, &circuit)
+//	_, _ = frontend.Compile(field, scs.NewBuilder, &circuit)
+//	p.Stop()
+//	fmt.Println(p.Table())
+//
+// Both compilations are needed: the profile only holds the constraints added while it
+// runs, and a backend which was not compiled has 0 constraints for every gadget. There is
+// no helper compiling the circuit for both backends, as this package is imported by the
+// constraint systems and can't import the frontend.
+//
+// The gadgets are sorted by decreasing number of constraints, then by name.
+func (p *Profile) Gadgets() []Gadget {
+	gadgets := make(map[string]*Gadget)
+	seen := make(map[string]struct{})
+	for _, s := range p.pprof.Sample {
+		var backend string
+		if b := s.Label[backendLabel]; len(b) == 1 {
+			backend = b[0]
+		}
+		if backend != backendR1CS && backend != backendSCS {
+			continue
+		}
+
+		// count each function once per sample, in case of recursive calls
+		clear(seen)
+		for _, l := range s.Location {
+			for _, line := range l.Line {
+				name := gadgetName(line.Function.Name)
+				if _, ok := seen[name]; ok {
+					continue
+				}
+				seen[name] = struct{}{}
+				g, ok := gadgets[name]
+				if !ok {
+					g = &Gadget{Name: name}
+					gadgets[name] = g
+				}
+				if backend == backendR1CS {
+					g.R1CS += int(s.Value[0])
+				} else {
+					g.SCS += int(s.Value[0])
+				}
+			}
+		}
+	}
+
+	res := make([]Gadget, 0, len(gadgets))
+	for _, g := range gadgets {
+		res = append(res, *g)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		mi, mj := max(res[i].R1CS, res[i].SCS), max(res[j].R1CS, res[j].SCS)
+		if mi != mj {
+			return mi > mj
+		}
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+// Table returns a table of the number of constraints per gadget and backend (see Gadgets),
+// with the ratio of the number of scs constraints to the number of r1cs constraints.
+func (p *Profile) Table() string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "r1cs\tscs\tscs/r1cs\t  gadget")
+	for _, g := range p.Gadgets() {
+		ratio := "-"
+		if g.R1CS != 0 {
+			ratio = fmt.Sprintf("%.2f", float64(g.SCS)/float64(g.R1CS))
+		}
+		fmt.Fprintf(w, "%d\t%d\t%s\t  %s\n", g.R1CS, g.SCS, ratio, g.Name)
+	}
+	w.Flush()
+	return sb.String()
+}
+
+// gadgetName merges the methods of the frontend builders across backends.
+func gadgetName(name string) string {
+	for _, prefix := range []string{"r1cs.(*builder).", "scs.(*builder)."} {
+		if method, ok := strings.CutPrefix(name, prefix); ok {
+			return "api." + method
+		}
+	}
+	return name
+}
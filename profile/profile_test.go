@@ -8,6 +8,7 @@ import (
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
 	"github.com/consensys/gnark/profile"
 )
 
@@ -59,3 +60,30 @@ func Example() {
 	// Output:
 	// 2
 }
+
+type sumCircuit struct {
+	A, B, C frontend.Variable
+}
+
+func (circuit *sumCircuit) Define(api frontend.API) error {
+	sum := api.Add(circuit.A, circuit.B, circuit.C)
+	api.AssertIsEqual(api.Mul(sum, circuit.A), circuit.B)
+	return nil
+}
+
+func ExampleProfile_Table() {
+	// compile the same circuit for both backends in one session to compare the cost of
+	// its gadgets
+	p := profile.Start(profile.WithNoOutput())
+	_, _ = frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &sumCircuit{})
+	_, _ = frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &sumCircuit{})
+	p.Stop()
+
+	fmt.Print(p.Table())
+	// Output:
+	// r1cs  scs  scs/r1cs  gadget
+	//      2    4      2.00  profile_test.(*sumCircuit).Define
+	//      0    2         -  api.Add
+	//      1    1      1.00  api.AssertIsEqual
+	//      1    1      1.00  api.Mul
+}
@@ -57,6 +57,7 @@ func collectSample(pc []uintptr) {
 	for i := 0; i < len(samples); i++ {
 		samples[i] = &profile.Sample{Value: []int64{1}} // for now, we just collect new constraints count
 	}
+	labelled := false
 
 	frames := runtime.CallersFrames(pc)
 	// Loop to get frames.
@@ -64,6 +65,16 @@ func collectSample(pc []uintptr) {
 	for {
 		frame, more := frames.Next()
 
+		// the first builder in the stack tells the backend the constraint is added for
+		if !labelled {
+			if b := builderBackend(frame.Function); b != "" {
+				for i := 0; i < len(samples); i++ {
+					samples[i].Label = map[string][]string{backendLabel: {b}}
+				}
+				labelled = true
+			}
+		}
+
 		if strings.Contains(frame.Function, "frontend.parseCircuit") {
 			// we stop; previous frame was the .Define definition of the circuit
 			break
@@ -115,6 +126,20 @@ func collectSample(pc []uintptr) {
 
 }
 
+// builderBackend returns "r1cs" or "scs" if f is a function of the corresponding frontend
+// builder package, and "" otherwise.
+func builderBackend(f string) string {
+	const prefix = "github.com/consensys/gnark/frontend/cs/"
+	if !strings.HasPrefix(f, prefix) {
+		return ""
+	}
+	switch b, _, _ := strings.Cut(f[len(prefix):], "."); b {
+	case backendR1CS, backendSCS:
+		return b
+	}
+	return ""
+}
+
 func filterSCSPrivateFunc(f string) bool {
 	const scsPrefix = "github.com/consensys/gnark/frontend/cs/scs.(*builder)."
 	if strings.HasPrefix(f, scsPrefix) && len(f) > len(scsPrefix) {
@@ -0,0 +1,203 @@
+package merkle
+
+import (
+	"errors"
+	"hash"
+)
+
+// SparseTree is an out-of-circuit sparse Merkle tree of fixed depth. It
+// computes the roots and the witnesses for [SparseMerkleProof],
+// [MerkleUpdateProof] and [BatchUpdateProof].
+//
+// The leaves and nodes are hashed with h in the same way as in-circuit: a leaf
+// hash is h(leaf) and a node hash is h(left || right). All the values are
+// big-endian encoded field elements of h.BlockSize() bytes and h must return
+// digests of the same size, as for example MiMC or Poseidon2 hashers.
+type SparseTree struct {
+	h     hash.Hash
+	depth int
+	// nodes[0] are the leaf hashes and nodes[depth] the root. Only non-empty
+	// nodes are stored.
+	nodes  []map[uint64][]byte
+	leaves map[uint64][]byte
+	// empty[i] is the hash of an empty subtree of height i.
+	empty [][]byte
+}
+
+// NativeUpdate is the out-of-circuit witness for a single leaf update.
+type NativeUpdate struct {
+	Index            uint64
+	OldLeaf, NewLeaf []byte
+	OldRoot, NewRoot []byte
+	Siblings         [][]byte
+}
+
+// NativeBatchUpdate is the out-of-circuit witness for a sequence of leaf
+// updates, see [BatchUpdateProof].
+type NativeBatchUpdate struct {
+	Indices              []uint64
+	OldLeaves, NewLeaves [][]byte
+	OldRoot, NewRoot     []byte
+	// Siblings[i] are the siblings of the i-th updated leaf in the tree before
+	// the batch.
+	Siblings [][][]byte
+}
+
+// NewSparseTree returns a new empty sparse Merkle tree with 2^depth leaves.
+func NewSparseTree(h hash.Hash, depth int) (*SparseTree, error) {
+	if depth <= 0 || depth > 64 {
+		return nil, errors.New("depth must be between 1 and 64")
+	}
+	if h.Size() != h.BlockSize() {
+		return nil, errors.New("hash digest size must equal block size")
+	}
+	t := &SparseTree{
+		h:      h,
+		depth:  depth,
+		nodes:  make([]map[uint64][]byte, depth+1),
+		leaves: make(map[uint64][]byte),
+		empty:  make([][]byte, depth+1),
+	}
+	for i := range t.nodes {
+		t.nodes[i] = make(map[uint64][]byte)
+	}
+	t.empty[0] = t.leafSum(make([]byte, h.BlockSize()))
+	for i := 1; i <= depth; i++ {
+		t.empty[i] = t.nodeSum(t.empty[i-1], t.empty[i-1])
+	}
+	return t, nil
+}
+
+// Depth returns the depth of the tree.
+func (t *SparseTree) Depth() int {
+	return t.depth
+}
+
+// Root returns the current root of the tree.
+func (t *SparseTree) Root() []byte {
+	return t.node(t.depth, 0)
+}
+
+// Get returns the leaf at position index. Unset leaves are zero.
+func (t *SparseTree) Get(index uint64) []byte {
+	if l, ok := t.leaves[index]; ok {
+		return clone(l)
+	}
+	return make([]byte, t.h.BlockSize())
+}
+
+// Set sets the leaf at position index to value and returns the update witness.
+// The value must be a canonical field element encoding of at most
+// h.BlockSize() bytes. Setting a leaf to zero ([EmptyLeaf]) removes it.
+func (t *SparseTree) Set(index uint64, value []byte) (*NativeUpdate, error) {
+	if err := t.checkIndex(index); err != nil {
+		return nil, err
+	}
+	if len(value) > t.h.BlockSize() {
+		return nil, errors.New("leaf value too long")
+	}
+	leaf := make([]byte, t.h.BlockSize())
+	copy(leaf[len(leaf)-len(value):], value)
+
+	u := &NativeUpdate{
+		Index:    index,
+		OldLeaf:  t.Get(index),
+		NewLeaf:  clone(leaf),
+		OldRoot:  t.Root(),
+		Siblings: t.siblings(index),
+	}
+	t.leaves[index] = leaf
+	t.nodes[0][index] = t.leafSum(leaf)
+	idx := index
+	for i := 1; i <= t.depth; i++ {
+		idx >>= 1
+		t.nodes[i][idx] = t.nodeSum(t.node(i-1, 2*idx), t.node(i-1, 2*idx+1))
+	}
+	u.NewRoot = t.Root()
+	return u, nil
+}
+
+// SetBatch sets the leaves at positions indices[i] to values[i] in order and
+// returns the batch update witness. See [SparseTree.Set] for the encoding of the
+// values.
+func (t *SparseTree) SetBatch(indices []uint64, values [][]byte) (*NativeBatchUpdate, error) {
+	if len(indices) != len(values) {
+		return nil, errors.New("number of indices and values mismatch")
+	}
+	u := &NativeBatchUpdate{
+		Indices:   append([]uint64{}, indices...),
+		OldLeaves: make([][]byte, len(indices)),
+		NewLeaves: make([][]byte, len(indices)),
+		OldRoot:   t.Root(),
+		Siblings:  make([][][]byte, len(indices)),
+	}
+	for i, index := range indices {
+		if err := t.checkIndex(index); err != nil {
+			return nil, err
+		}
+		if len(values[i]) > t.h.BlockSize() {
+			return nil, errors.New("leaf value too long")
+		}
+		u.Siblings[i] = t.siblings(index)
+	}
+	for i, index := range indices {
+		single, err := t.Set(index, values[i])
+		if err != nil {
+			return nil, err
+		}
+		u.OldLeaves[i], u.NewLeaves[i] = single.OldLeaf, single.NewLeaf
+	}
+	u.NewRoot = t.Root()
+	return u, nil
+}
+
+// Prove returns the leaf at position index and the siblings on its path to the
+// root. If the leaf is unset, then the proof is a non-membership proof.
+func (t *SparseTree) Prove(index uint64) (leaf []byte, siblings [][]byte, err error) {
+	if err := t.checkIndex(index); err != nil {
+		return nil, nil, err
+	}
+	return t.Get(index), t.siblings(index), nil
+}
+
+func (t *SparseTree) checkIndex(index uint64) error {
+	if t.depth < 64 && index>>t.depth != 0 {
+		return errors.New("index out of range")
+	}
+	return nil
+}
+
+func (t *SparseTree) siblings(index uint64) [][]byte {
+	res := make([][]byte, t.depth)
+	for i := 0; i < t.depth; i++ {
+		res[i] = t.node(i, index^1)
+		index >>= 1
+	}
+	return res
+}
+
+func (t *SparseTree) node(level int, index uint64) []byte {
+	if n, ok := t.nodes[level][index]; ok {
+		return clone(n)
+	}
+	return clone(t.empty[level])
+}
+
+func (t *SparseTree) leafSum(data []byte) []byte {
+	t.h.Reset()
+	t.h.Write(data)
+	return t.h.Sum(nil)
+}
+
+func (t *SparseTree) nodeSum(a, b []byte) []byte {
+	t.h.Reset()
+	t.h.Write(a)
+	t.h.Write(b)
+	return t.h.Sum(nil)
+}
+
+func clone(b []byte) []byte {
+	res := make([]byte, len(b))
+	copy(res, b)
+	return res
+}
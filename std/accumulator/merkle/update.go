@@ -0,0 +1,161 @@
+package merkle
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// EmptyLeaf is the value of the leaves which have not been set in a sparse
+// Merkle tree. Non-membership of an index is proven by showing that the leaf at
+// this index is empty.
+//
+// As there is no domain separation between empty and set leaves, a leaf set to
+// EmptyLeaf is indistinguishable from an unset one. [MerkleUpdateProof.VerifyInsert]
+// asserts that the inserted leaf is not empty, and setting a leaf to
+// EmptyLeaf with [MerkleUpdateProof.VerifyUpdate] removes it from the tree.
+const EmptyLeaf = 0
+
+// SparseMerkleProof is a Merkle proof for a leaf in a fixed-depth (sparse)
+// Merkle tree. Contrary to [MerkleProof], the leaf value is not part of the
+// path and the position of the leaf is given by its index, so that the same
+// proof can be used to prove membership of a value or non-membership of an
+// index.
+//
+// The leaves and nodes are hashed in the same way as for [MerkleProof], so a
+// sparse Merkle tree is a regular Merkle tree where the unset leaves have value
+// [EmptyLeaf]. Use [SparseTree] to build the witnesses.
+type SparseMerkleProof struct {
+	// RootHash root of the Merkle tree.
+	RootHash frontend.Variable
+
+	// Siblings are the sibling nodes on the path from the leaf to the root,
+	// starting from the sibling of the leaf. The depth of the tree is
+	// len(Siblings).
+	Siblings []frontend.Variable
+}
+
+// VerifyMembership asserts that the leaf at position index has value leaf. If
+// leaf is [EmptyLeaf], then this is a non-membership proof.
+func (mp *SparseMerkleProof) VerifyMembership(api frontend.API, h hash.FieldHasher, index, leaf frontend.Variable) {
+	binIndex := api.ToBinary(index, len(mp.Siblings))
+	root := computeRoot(api, h, binIndex, mp.Siblings, leaf)
+	api.AssertIsEqual(root, mp.RootHash)
+}
+
+// VerifyNonMembership asserts that the leaf at position index is empty.
+func (mp *SparseMerkleProof) VerifyNonMembership(api frontend.API, h hash.FieldHasher, index frontend.Variable) {
+	mp.VerifyMembership(api, h, index, EmptyLeaf)
+}
+
+// MerkleUpdateProof proves that changing a single leaf of a Merkle tree moves
+// the root from OldRoot to NewRoot. As only a single leaf changes, the
+// siblings are the same in the old and the new tree.
+type MerkleUpdateProof struct {
+	// OldRoot is the root of the tree before the update.
+	OldRoot frontend.Variable
+	// NewRoot is the root of the tree after the update.
+	NewRoot frontend.Variable
+	// Siblings are the sibling nodes on the path from the leaf to the root,
+	// starting from the sibling of the leaf.
+	Siblings []frontend.Variable
+}
+
+// VerifyUpdate asserts that the leaf at position index changed from oldLeaf
+// to newLeaf and that this is the only difference between the trees with roots
+// mp.OldRoot and mp.NewRoot.
+func (mp *MerkleUpdateProof) VerifyUpdate(api frontend.API, h hash.FieldHasher, index, oldLeaf, newLeaf frontend.Variable) {
+	binIndex := api.ToBinary(index, len(mp.Siblings))
+	oldRoot := computeRoot(api, h, binIndex, mp.Siblings, oldLeaf)
+	api.AssertIsEqual(oldRoot, mp.OldRoot)
+	newRoot := computeRoot(api, h, binIndex, mp.Siblings, newLeaf)
+	api.AssertIsEqual(newRoot, mp.NewRoot)
+}
+
+// VerifyInsert asserts that the leaf at position index was empty in the tree
+// with root mp.OldRoot and is set to leaf in the tree with root mp.NewRoot. The
+// inserted leaf must not be [EmptyLeaf].
+func (mp *MerkleUpdateProof) VerifyInsert(api frontend.API, h hash.FieldHasher, index, leaf frontend.Variable) {
+	api.AssertIsDifferent(leaf, EmptyLeaf)
+	mp.VerifyUpdate(api, h, index, EmptyLeaf, leaf)
+}
+
+// BatchUpdateProof proves that a sequence of leaf updates moves the root of a
+// Merkle tree from OldRoot to NewRoot.
+//
+// The updates are applied in order and the witness only contains the siblings
+// of the updated leaves in the tree before the batch. When the path of an
+// update crosses the path of an earlier update, the sibling nodes are instead
+// taken from the nodes computed in-circuit for the earlier update, so that
+// several updated leaves share path nodes. Use [SparseTree.SetBatch] to build
+// the witness.
+type BatchUpdateProof struct {
+	// OldRoot is the root of the tree before the updates.
+	OldRoot frontend.Variable
+	// NewRoot is the root of the tree after all the updates.
+	NewRoot frontend.Variable
+	// Siblings[i] are the siblings of the i-th updated leaf in the tree with
+	// root OldRoot. The siblings which are nodes on the path of an earlier
+	// update are not used.
+	Siblings [][]frontend.Variable
+}
+
+// VerifyBatchUpdate asserts that applying the updates leaves[i]: oldLeaves[i] ->
+// newLeaves[i] at positions indices[i] in order moves the root from mp.OldRoot
+// to mp.NewRoot. All slices must have the same length as mp.Siblings. When an
+// index is updated several times, oldLeaves[i] is the value set by the
+// previous update.
+func (mp *BatchUpdateProof) VerifyBatchUpdate(api frontend.API, h hash.FieldHasher, indices, oldLeaves, newLeaves []frontend.Variable) {
+	if len(indices) != len(mp.Siblings) || len(oldLeaves) != len(mp.Siblings) || len(newLeaves) != len(mp.Siblings) {
+		panic("number of updates mismatch")
+	}
+	binIndices := make([][]frontend.Variable, len(mp.Siblings))
+	// newPaths[j][l] is the node at level l on the path of the j-th update,
+	// after the update.
+	newPaths := make([][]frontend.Variable, len(mp.Siblings))
+	root := mp.OldRoot
+	for i := range mp.Siblings {
+		depth := len(mp.Siblings[i])
+		binIndices[i] = api.ToBinary(indices[i], depth)
+		siblings := make([]frontend.Variable, depth)
+		copy(siblings, mp.Siblings[i])
+		for j := 0; j < i; j++ {
+			if len(binIndices[j]) != depth {
+				panic("depth mismatch")
+			}
+			// the sibling of the i-th leaf at level l is on the path of the
+			// j-th leaf iff their indices agree on the bits above l and differ
+			// on bit l. The latest such update sets the sibling.
+			var above frontend.Variable = 1
+			for l := depth - 1; l >= 0; l-- {
+				differ := api.Xor(binIndices[i][l], binIndices[j][l])
+				siblings[l] = api.Select(api.Mul(above, differ), newPaths[j][l], siblings[l])
+				above = api.Sub(above, api.Mul(above, differ))
+			}
+		}
+		oldRoot := computeRoot(api, h, binIndices[i], siblings, oldLeaves[i])
+		api.AssertIsEqual(oldRoot, root)
+		newPaths[i] = computePath(api, h, binIndices[i], siblings, newLeaves[i])
+		root = newPaths[i][depth]
+	}
+	api.AssertIsEqual(root, mp.NewRoot)
+}
+
+// computeRoot returns the root of the tree with the given leaf at the position
+// given by binIndex (little-endian) and the siblings.
+func computeRoot(api frontend.API, h hash.FieldHasher, binIndex []frontend.Variable, siblings []frontend.Variable, leaf frontend.Variable) frontend.Variable {
+	path := computePath(api, h, binIndex, siblings, leaf)
+	return path[len(siblings)]
+}
+
+// computePath returns the nodes on the path from the leaf to the root, path[0]
+// being the leaf hash and path[len(siblings)] the root.
+func computePath(api frontend.API, h hash.FieldHasher, binIndex []frontend.Variable, siblings []frontend.Variable, leaf frontend.Variable) []frontend.Variable {
+	path := make([]frontend.Variable, len(siblings)+1)
+	path[0] = leafSum(api, h, leaf)
+	for i := range siblings {
+		d1 := api.Select(binIndex[i], siblings[i], path[i])
+		d2 := api.Select(binIndex[i], path[i], siblings[i])
+		path[i+1] = nodeSum(api, h, d1, d2)
+	}
+	return path
+}
@@ -0,0 +1,192 @@
+package merkle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+const testDepth = 8
+
+func toVars(in [][]byte) []frontend.Variable {
+	res := make([]frontend.Variable, len(in))
+	for i := range in {
+		res[i] = in[i]
+	}
+	return res
+}
+
+type sparseMembershipCircuit struct {
+	Proof         SparseMerkleProof
+	Index, Leaf   frontend.Variable
+	AbsentIndex   frontend.Variable
+	AbsentSibling SparseMerkleProof
+}
+
+func (c *sparseMembershipCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	c.Proof.VerifyMembership(api, &h, c.Index, c.Leaf)
+	c.AbsentSibling.VerifyNonMembership(api, &h, c.AbsentIndex)
+	return nil
+}
+
+func TestSparseMembership(t *testing.T) {
+	assert := test.NewAssert(t)
+	tree, err := NewSparseTree(hash.MIMC_BN254.New(), testDepth)
+	assert.NoError(err)
+	for _, i := range []uint64{3, 4, 200} {
+		_, err := tree.Set(i, big.NewInt(int64(i+10)).Bytes())
+		assert.NoError(err)
+	}
+	leaf, siblings, err := tree.Prove(4)
+	assert.NoError(err)
+	_, absentSiblings, err := tree.Prove(5)
+	assert.NoError(err)
+
+	circuit := sparseMembershipCircuit{
+		Proof:         SparseMerkleProof{Siblings: make([]frontend.Variable, testDepth)},
+		AbsentSibling: SparseMerkleProof{Siblings: make([]frontend.Variable, testDepth)},
+	}
+	witness := sparseMembershipCircuit{
+		Proof:         SparseMerkleProof{RootHash: tree.Root(), Siblings: toVars(siblings)},
+		Index:         4,
+		Leaf:          leaf,
+		AbsentIndex:   5,
+		AbsentSibling: SparseMerkleProof{RootHash: tree.Root(), Siblings: toVars(absentSiblings)},
+	}
+	_, presentSiblings, err := tree.Prove(3)
+	assert.NoError(err)
+	invalid := sparseMembershipCircuit{
+		Proof:         witness.Proof,
+		Index:         4,
+		Leaf:          leaf,
+		AbsentIndex:   3,
+		AbsentSibling: SparseMerkleProof{RootHash: tree.Root(), Siblings: toVars(presentSiblings)},
+	}
+	assert.CheckCircuit(&circuit, test.WithValidAssignment(&witness), test.WithInvalidAssignment(&invalid), test.WithCurves(ecc.BN254))
+}
+
+type updateCircuit struct {
+	Proof            MerkleUpdateProof
+	Index            frontend.Variable
+	OldLeaf, NewLeaf frontend.Variable
+	Insert           MerkleUpdateProof
+	InsertIndex      frontend.Variable
+	InsertLeaf       frontend.Variable
+}
+
+func (c *updateCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	c.Proof.VerifyUpdate(api, &h, c.Index, c.OldLeaf, c.NewLeaf)
+	c.Insert.VerifyInsert(api, &h, c.InsertIndex, c.InsertLeaf)
+	return nil
+}
+
+func updateAssignment(u *NativeUpdate) MerkleUpdateProof {
+	return MerkleUpdateProof{OldRoot: u.OldRoot, NewRoot: u.NewRoot, Siblings: toVars(u.Siblings)}
+}
+
+func TestUpdate(t *testing.T) {
+	assert := test.NewAssert(t)
+	tree, err := NewSparseTree(hash.MIMC_BN254.New(), testDepth)
+	assert.NoError(err)
+	_, err = tree.Set(17, []byte{1})
+	assert.NoError(err)
+	upd, err := tree.Set(17, []byte{2})
+	assert.NoError(err)
+	ins, err := tree.Set(18, []byte{3})
+	assert.NoError(err)
+
+	circuit := updateCircuit{
+		Proof:  MerkleUpdateProof{Siblings: make([]frontend.Variable, testDepth)},
+		Insert: MerkleUpdateProof{Siblings: make([]frontend.Variable, testDepth)},
+	}
+	witness := updateCircuit{
+		Proof:       updateAssignment(upd),
+		Index:       upd.Index,
+		OldLeaf:     upd.OldLeaf,
+		NewLeaf:     upd.NewLeaf,
+		Insert:      updateAssignment(ins),
+		InsertIndex: ins.Index,
+		InsertLeaf:  ins.NewLeaf,
+	}
+	invalid := witness
+	invalid.NewLeaf = 3
+	// inserting an empty leaf doesn't change the tree but is rejected
+	insZero, err := tree.Set(19, []byte{0})
+	assert.NoError(err)
+	assert.Equal(insZero.OldRoot, insZero.NewRoot)
+	invalidInsert := witness
+	invalidInsert.Insert = updateAssignment(insZero)
+	invalidInsert.InsertIndex = insZero.Index
+	invalidInsert.InsertLeaf = insZero.NewLeaf
+	assert.CheckCircuit(&circuit, test.WithValidAssignment(&witness), test.WithInvalidAssignment(&invalid), test.WithInvalidAssignment(&invalidInsert), test.WithCurves(ecc.BN254))
+}
+
+type batchUpdateCircuit struct {
+	Proof                         BatchUpdateProof
+	Indices, OldLeaves, NewLeaves []frontend.Variable
+}
+
+func (c *batchUpdateCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	c.Proof.VerifyBatchUpdate(api, &h, c.Indices, c.OldLeaves, c.NewLeaves)
+	return nil
+}
+
+func TestBatchUpdate(t *testing.T) {
+	assert := test.NewAssert(t)
+	tree, err := NewSparseTree(hash.MIMC_BN254.New(), testDepth)
+	assert.NoError(err)
+	_, err = tree.Set(6, []byte{1})
+	assert.NoError(err)
+	// leaves 6 and 7 share all path nodes except the leaves themselves, leaf 6
+	// is updated twice and leaf 128 only shares the root.
+	indices := []uint64{6, 7, 128, 6}
+	values := [][]byte{{5}, {6}, {7}, {8}}
+	nbUpdates := len(indices)
+	u, err := tree.SetBatch(indices, values)
+	assert.NoError(err)
+	assert.Equal(tree.Root(), u.NewRoot)
+
+	circuit := batchUpdateCircuit{
+		Proof:     BatchUpdateProof{Siblings: make([][]frontend.Variable, nbUpdates)},
+		Indices:   make([]frontend.Variable, nbUpdates),
+		OldLeaves: make([]frontend.Variable, nbUpdates),
+		NewLeaves: make([]frontend.Variable, nbUpdates),
+	}
+	witness := batchUpdateCircuit{
+		Proof:     BatchUpdateProof{OldRoot: u.OldRoot, NewRoot: u.NewRoot, Siblings: make([][]frontend.Variable, nbUpdates)},
+		Indices:   make([]frontend.Variable, nbUpdates),
+		OldLeaves: make([]frontend.Variable, nbUpdates),
+		NewLeaves: make([]frontend.Variable, nbUpdates),
+	}
+	for i := range indices {
+		circuit.Proof.Siblings[i] = make([]frontend.Variable, testDepth)
+		witness.Proof.Siblings[i] = toVars(u.Siblings[i])
+		witness.Indices[i] = u.Indices[i]
+		witness.OldLeaves[i] = u.OldLeaves[i]
+		witness.NewLeaves[i] = u.NewLeaves[i]
+	}
+	invalid := witness
+	invalid.Proof.NewRoot = witness.Proof.OldRoot
+	// the second update of leaf 6 must start from the value of the first one
+	invalidOld := witness
+	invalidOld.OldLeaves = append([]frontend.Variable{}, witness.OldLeaves...)
+	invalidOld.OldLeaves[3] = 1
+	assert.CheckCircuit(&circuit, test.WithValidAssignment(&witness), test.WithInvalidAssignment(&invalid), test.WithInvalidAssignment(&invalidOld), test.WithCurves(ecc.BN254))
+}
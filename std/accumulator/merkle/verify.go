@@ -43,6 +43,13 @@ limitations under the License.
 */
 
 // Package merkle provides a ZKP-circuit function to verify merkle proofs.
+//
+// Besides membership proofs in a Merkle tree built with gnark-crypto
+// ([MerkleProof]), the package provides gadgets for sparse Merkle trees:
+// membership and non-membership proofs ([SparseMerkleProof]), single leaf
+// updates and insertions ([MerkleUpdateProof]) and sequential updates of
+// several leaves ([BatchUpdateProof]). The witnesses for the sparse Merkle tree
+// gadgets are computed out-of-circuit using [SparseTree].
 package merkle
 
 import (
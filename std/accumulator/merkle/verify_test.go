@@ -26,7 +26,9 @@ import (
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/hash"
 	"github.com/consensys/gnark/frontend"
+	stdhash "github.com/consensys/gnark/std/hash"
 	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/hash/poseidon2"
 	"github.com/consensys/gnark/test"
 )
 
@@ -120,3 +122,50 @@ func TestVerify(t *testing.T) {
 	}
 
 }
+
+// namedHasherMerkleProofTest used for testing only
+type namedHasherMerkleProofTest struct {
+	M    MerkleProof
+	Leaf frontend.Variable
+}
+
+func (mp *namedHasherMerkleProofTest) Define(api frontend.API) error {
+	h, err := stdhash.GetFieldHasher(poseidon2.HashName, api)
+	if err != nil {
+		return err
+	}
+	mp.M.VerifyProof(api, h, mp.Leaf)
+	return nil
+}
+
+func TestVerifyPoseidon2(t *testing.T) {
+	assert := test.NewAssert(t)
+	const numLeaves, depth = 16, 4
+	mod := ecc.BN254.ScalarField()
+	modNbBytes := len(mod.Bytes())
+
+	var buf bytes.Buffer
+	for i := 0; i < numLeaves; i++ {
+		leaf, err := rand.Int(rand.Reader, mod)
+		assert.NoError(err)
+		b := leaf.Bytes()
+		buf.Write(make([]byte, modNbBytes-len(b)))
+		buf.Write(b)
+	}
+	hGo, err := poseidon2.NewNativeHasher(mod)
+	assert.NoError(err)
+	proofIndex := uint64(5)
+	merkleRoot, proofPath, nbLeaves, err := merkletree.BuildReaderProof(&buf, hGo, modNbBytes, proofIndex)
+	assert.NoError(err)
+	assert.True(merkletree.VerifyProof(hGo, merkleRoot, proofPath, proofIndex, nbLeaves))
+
+	var circuit, witness namedHasherMerkleProofTest
+	circuit.M.Path = make([]frontend.Variable, depth+1)
+	witness.Leaf = proofIndex
+	witness.M.RootHash = merkleRoot
+	witness.M.Path = make([]frontend.Variable, depth+1)
+	for i := range witness.M.Path {
+		witness.M.Path[i] = proofPath[i]
+	}
+	assert.CheckCircuit(&circuit, test.WithValidAssignment(&witness), test.WithCurves(ecc.BN254))
+}
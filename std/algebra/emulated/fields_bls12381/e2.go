@@ -346,3 +346,35 @@ func (e Ext2) Lookup2(s1, s2 frontend.Variable, a, b, c, d *E2) *E2 {
 	a1 := e.fp.Lookup2(s1, s2, &a.A1, &b.A1, &c.A1, &d.A1)
 	return &E2{A0: *a0, A1: *a1}
 }
+
+// Sqrt computes a square root of x and returns it. The returned root is
+// computed out-of-circuit and the circuit only asserts that its square is
+// equal to x, so it fails when x is not a quadratic residue.
+func (e Ext2) Sqrt(x *E2) *E2 {
+	res, err := e.fp.NewHint(sqrtE2Hint, 2, &x.A0, &x.A1)
+	if err != nil {
+		// err is non-nil only for invalid number of inputs
+		panic(err)
+	}
+
+	sqrt := E2{
+		A0: *res[0],
+		A1: *res[1],
+	}
+
+	// x == sqrt²
+	_x := e.Square(&sqrt)
+	e.AssertIsEqual(x, _x)
+
+	return &sqrt
+}
+
+// Sgn0 returns the sign of x as defined in RFC 9380 Section 4.1, that is the
+// parity of A0 if A0 is non-zero and the parity of A1 otherwise.
+func (e Ext2) Sgn0(x *E2) frontend.Variable {
+	sign0 := e.fp.ToBitsCanonical(&x.A0)[0]
+	zero0 := e.fp.IsZero(&x.A0)
+	sign1 := e.fp.ToBitsCanonical(&x.A1)[0]
+	// sign = sign_0 OR (zero_0 AND sign_1)
+	return e.api.Or(sign0, e.api.And(zero0, sign1))
+}
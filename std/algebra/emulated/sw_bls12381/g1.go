@@ -28,6 +28,7 @@ func NewG1Affine(v bls12381.G1Affine) G1Affine {
 }
 
 type G1 struct {
+	api    frontend.API
 	curveF *emulated.Field[BaseField]
 	w      *emulated.Element[BaseField]
 }
@@ -39,6 +40,7 @@ func NewG1(api frontend.API) (*G1, error) {
 	}
 	w := emulated.ValueOf[BaseField]("4002409555221667392624310435006688643935503118305586438271171395842971157480381377015405980053539358417135540939436")
 	return &G1{
+		api:    api,
 		curveF: ba,
 		w:      &w,
 	}, nil
@@ -168,6 +170,30 @@ func (g1 *G1) scalarMulBySeedSquare(q *G1Affine) *G1Affine {
 	return z
 }
 
+func (g1 *G1) scalarMulBySeed(q *G1Affine) *G1Affine {
+	z := g1.double(q)
+	z = g1.add(q, z)
+	z = g1.double(z)
+	z = g1.doubleAndAdd(z, q)
+	z = g1.doubleN(z, 2)
+	z = g1.doubleAndAdd(z, q)
+	z = g1.doubleN(z, 8)
+	z = g1.doubleAndAdd(z, q)
+	z = g1.doubleN(z, 31)
+	z = g1.doubleAndAdd(z, q)
+	z = g1.doubleN(z, 16)
+
+	return g1.neg(z)
+}
+
+func (g1 G1) neg(p *G1Affine) *G1Affine {
+	yr := g1.curveF.Neg(&p.Y)
+	return &G1Affine{
+		X: p.X,
+		Y: *yr,
+	}
+}
+
 // NewScalar allocates a witness from the native scalar and returns it.
 func NewScalar(v fr_bls12381.Element) Scalar {
 	return emulated.ValueOf[ScalarField](v)
@@ -0,0 +1,19 @@
+package sw_bls12381
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// hashToFieldL is the number of bytes expanded for every base field element.
+// It is computed as L = ceil((ceil(log2(p)) + k) / 8) for the security level
+// k=128.
+const hashToFieldL = 64
+
+// hashToFp hashes msg into count base field elements using the hash_to_field
+// method defined in RFC 9380 Section 5.2 with expand_message_xmd and SHA2-256.
+func hashToFp(api frontend.API, fp *emulated.Field[BaseField], msg []uints.U8, dst []byte, count int) ([]*emulated.Element[BaseField], error) {
+	return sw_emulated.HashToField(api, fp, msg, dst, count, hashToFieldL)
+}
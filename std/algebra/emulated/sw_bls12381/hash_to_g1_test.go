@@ -0,0 +1,103 @@
+package sw_bls12381
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type mapToG1Circuit struct {
+	U   emulated.Element[BaseField]
+	Res G1Affine
+}
+
+func (c *mapToG1Circuit) Define(api frontend.API) error {
+	g1, err := NewG1(api)
+	if err != nil {
+		return err
+	}
+	res := g1.MapToG1(&c.U)
+	g1.curveF.AssertIsEqual(&res.X, &c.Res.X)
+	g1.curveF.AssertIsEqual(&res.Y, &c.Res.Y)
+	return nil
+}
+
+func TestMapToG1TestSolve(t *testing.T) {
+	assert := test.NewAssert(t)
+	var u fp.Element
+	u.SetRandom()
+	res := bls12381.MapToG1(u)
+	witness := mapToG1Circuit{
+		U:   emulated.ValueOf[BaseField](u),
+		Res: NewG1Affine(res),
+	}
+	err := test.IsSolved(&mapToG1Circuit{}, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
+
+type hashToG1Circuit struct {
+	Msg []uints.U8
+	Res G1Affine
+
+	dst []byte
+}
+
+func (c *hashToG1Circuit) Define(api frontend.API) error {
+	g1, err := NewG1(api)
+	if err != nil {
+		return err
+	}
+	res, err := g1.HashToG1(c.Msg, c.dst)
+	if err != nil {
+		return err
+	}
+	g1.curveF.AssertIsEqual(&res.X, &c.Res.X)
+	g1.curveF.AssertIsEqual(&res.Y, &c.Res.Y)
+	return nil
+}
+
+func TestHashToG1TestSolve(t *testing.T) {
+	assert := test.NewAssert(t)
+	dst := []byte("QUUX-V01-CS02-with-BLS12381G1_XMD:SHA-256_SSWU_RO_")
+	msg := []byte("abcdef0123456789")
+	res, err := bls12381.HashToG1(msg, dst)
+	assert.NoError(err)
+	witness := hashToG1Circuit{
+		Msg: uints.NewU8Array(msg),
+		Res: NewG1Affine(res),
+	}
+	err = test.IsSolved(&hashToG1Circuit{Msg: make([]uints.U8, len(msg)), dst: dst}, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
+
+func TestHashToG1Vectors(t *testing.T) {
+	// test vectors from RFC 9380 Appendix J.9.1
+	assert := test.NewAssert(t)
+	dst := []byte("QUUX-V01-CS02-with-BLS12381G1_XMD:SHA-256_SSWU_RO_")
+	vectors := []struct {
+		msg  string
+		x, y string
+	}{
+		{"", "0x052926add2207b76ca4fa57a8734416c8dc95e24501772c814278700eed6d1e4e8cf62d9c09db0fac349612b759e79a1", "0x08ba738453bfed09cb546dbb0783dbb3a5f1f566ed67bb6be0e8c67e2e81a4cc68ee29813bb7994998f3eae0c9c6a265"},
+		{"abc", "0x03567bc5ef9c690c2ab2ecdf6a96ef1c139cc0b2f284dca0a9a7943388a49a3aee664ba5379a7655d3c68900be2f6903", "0x0b9c15f3fe6e5cf4211f346271d7b01c8f3b28be689c8429c85b67af215533311f0b8dfaaa154fa6b88176c229f2885d"},
+	}
+	for _, v := range vectors {
+		var res bls12381.G1Affine
+		_, err := res.X.SetString(v.x)
+		assert.NoError(err)
+		_, err = res.Y.SetString(v.y)
+		assert.NoError(err)
+		witness := hashToG1Circuit{
+			Msg: uints.NewU8Array([]byte(v.msg)),
+			Res: NewG1Affine(res),
+		}
+		err = test.IsSolved(&hashToG1Circuit{Msg: make([]uints.U8, len(v.msg)), dst: dst}, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err, v.msg)
+	}
+}
@@ -0,0 +1,196 @@
+package sw_bls12381
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/std/algebra/emulated/fields_bls12381"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// Coefficients of the 3-isogenous curve E2': y² = x³ + A'x + B' used in the
+// simplified SWU map and the non-square Z, see RFC 9380 Section 8.8.2.
+var (
+	g2SSWUIsoCurveCoeffA = [2]string{"0", "240"}
+	g2SSWUIsoCurveCoeffB = [2]string{"1012", "1012"}
+	g2SSWUZ              = [2]string{"4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559785", "4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559786"}
+)
+
+// Coefficients of the 3-isogeny map from E2' to E2, see RFC 9380 Appendix
+// E.3. The denominators are monic and their leading coefficients are omitted.
+var g2IsogenyXNumerator = [][2]string{
+	{"889424345604814976315064405719089812568196182208668418962679585805340366775741747653930584250892369786198727235542", "889424345604814976315064405719089812568196182208668418962679585805340366775741747653930584250892369786198727235542"},
+	{"0", "2668273036814444928945193217157269437704588546626005256888038757416021100327225242961791752752677109358596181706522"},
+	{"2668273036814444928945193217157269437704588546626005256888038757416021100327225242961791752752677109358596181706526", "1334136518407222464472596608578634718852294273313002628444019378708010550163612621480895876376338554679298090853261"},
+	{"3557697382419259905260257622876359250272784728834673675850718343221361467102966990615722337003569479144794908942033", "0"},
+}
+
+var g2IsogenyXDenominator = [][2]string{
+	{"0", "4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559715"},
+	{"12", "4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559775"},
+}
+
+var g2IsogenyYNumerator = [][2]string{
+	{"3261222600550988246488569487636662646083386001431784202863158481286248011511053074731078808919938689216061999863558", "3261222600550988246488569487636662646083386001431784202863158481286248011511053074731078808919938689216061999863558"},
+	{"0", "889424345604814976315064405719089812568196182208668418962679585805340366775741747653930584250892369786198727235518"},
+	{"2668273036814444928945193217157269437704588546626005256888038757416021100327225242961791752752677109358596181706524", "1334136518407222464472596608578634718852294273313002628444019378708010550163612621480895876376338554679298090853263"},
+	{"2816510427748580758331037284777117739799287910327449993381818688383577828123182200904113516794492504322962636245776", "0"},
+}
+
+var g2IsogenyYDenominator = [][2]string{
+	{"4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559355", "4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559355"},
+	{"0", "4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559571"},
+	{"18", "4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559769"},
+}
+
+func newE2(v [2]string) *fields_bls12381.E2 {
+	return &fields_bls12381.E2{
+		A0: emulated.ValueOf[BaseField](v[0]),
+		A1: emulated.ValueOf[BaseField](v[1]),
+	}
+}
+
+// HashToG2 hashes msg to a point in G2 using the hash_to_curve method
+// BLS12381G2_XMD:SHA-256_SSWU_RO_ defined in RFC 9380 with the domain
+// separation tag dst. The tag is a constant known at circuit compile time.
+func (g2 *G2) HashToG2(msg []uints.U8, dst []byte) (*G2Affine, error) {
+	u, err := hashToFp(g2.api, g2.fp, msg, dst, 4)
+	if err != nil {
+		return nil, fmt.Errorf("hash to field: %w", err)
+	}
+	q0 := g2.mapToCurve2(&fields_bls12381.E2{A0: *u[0], A1: *u[1]})
+	q1 := g2.mapToCurve2(&fields_bls12381.E2{A0: *u[2], A1: *u[3]})
+	// the isogeny is a group homomorphism and the addition formula does not
+	// depend on the curve coefficients, so we add the points on E2' and
+	// evaluate the isogeny only once.
+	q := g2.add(q0, q1)
+	q = g2.isogeny(q)
+	return g2.ClearCofactor(q), nil
+}
+
+// MapToG2 maps the field element u to a point in G2 using the simplified SWU
+// map to the 3-isogenous curve followed by the isogeny and cofactor clearing.
+// This corresponds to the encode_to_curve method of RFC 9380 when u is
+// obtained by hashing to the field.
+func (g2 *G2) MapToG2(u *fields_bls12381.E2) *G2Affine {
+	q := g2.mapToCurve2(u)
+	q = g2.isogeny(q)
+	return g2.ClearCofactor(q)
+}
+
+// mapToCurve2 implements the simplified SWU map to the curve E2', see RFC 9380
+// Section 6.6.2.
+func (g2 *G2) mapToCurve2(u *fields_bls12381.E2) *G2Affine {
+	a := newE2(g2SSWUIsoCurveCoeffA)
+	b := newE2(g2SSWUIsoCurveCoeffB)
+	z := newE2(g2SSWUZ)
+
+	// tv1 = Z * u²
+	tv1 := g2.Ext2.Mul(g2.Ext2.Square(u), z)
+	// tv2 = tv1² + tv1
+	tv2 := g2.Ext2.Add(g2.Ext2.Square(tv1), tv1)
+	// x1 = B * (tv2 + 1) / (A * CMOV(Z, -tv2, tv2 != 0))
+	num := g2.Ext2.Mul(g2.Ext2.Add(tv2, g2.Ext2.One()), b)
+	den := g2.Ext2.Select(g2.Ext2.IsZero(tv2), z, g2.Ext2.Neg(tv2))
+	den = g2.Ext2.Mul(den, a)
+	x1 := g2.Ext2.DivUnchecked(num, den)
+	// x2 = Z * u² * x1
+	x2 := g2.Ext2.Mul(tv1, x1)
+	gx1 := g2.evalCurve(x1, a, b)
+	gx2 := g2.evalCurve(x2, a, b)
+
+	// exactly one of gx1 and gx2 is a square as Z is not a square. We obtain
+	// the choice from a hint and the square root computation below fails if
+	// it is incorrect.
+	isSquare, err := g2.fp.NewHintWithNativeOutput(isSquareE2Hint, 1, &gx1.A0, &gx1.A1)
+	if err != nil {
+		panic(err)
+	}
+	g2.api.AssertIsBoolean(isSquare[0])
+	x := g2.Ext2.Select(isSquare[0], x1, x2)
+	y := g2.Ext2.Sqrt(g2.Ext2.Select(isSquare[0], gx1, gx2))
+
+	// fix the sign of y so that sgn0(u) == sgn0(y)
+	flip := g2.api.Xor(g2.Ext2.Sgn0(u), g2.Ext2.Sgn0(y))
+	y = g2.Ext2.Select(flip, g2.Ext2.Neg(y), y)
+
+	return &G2Affine{
+		P: g2AffP{
+			X: *x,
+			Y: *y,
+		},
+	}
+}
+
+// evalCurve returns x³ + a*x + b.
+func (g2 *G2) evalCurve(x, a, b *fields_bls12381.E2) *fields_bls12381.E2 {
+	res := g2.Ext2.Add(g2.Ext2.Square(x), a)
+	res = g2.Ext2.Mul(res, x)
+	return g2.Ext2.Add(res, b)
+}
+
+// isogeny maps a point on E2' to E2 using the 3-isogeny.
+func (g2 *G2) isogeny(p *G2Affine) *G2Affine {
+	xNum := g2.evalPolynomial(false, g2IsogenyXNumerator, &p.P.X)
+	xDen := g2.evalPolynomial(true, g2IsogenyXDenominator, &p.P.X)
+	yNum := g2.evalPolynomial(false, g2IsogenyYNumerator, &p.P.X)
+	yDen := g2.evalPolynomial(true, g2IsogenyYDenominator, &p.P.X)
+
+	x := g2.Ext2.DivUnchecked(xNum, xDen)
+	y := g2.Ext2.DivUnchecked(yNum, yDen)
+	y = g2.Ext2.Mul(y, &p.P.Y)
+
+	return &G2Affine{
+		P: g2AffP{
+			X: *x,
+			Y: *y,
+		},
+	}
+}
+
+// evalPolynomial evaluates the polynomial with the given coefficients at x
+// using Horner's method. If monic is set, then the leading coefficient 1 is
+// implicit and omitted from coefficients.
+func (g2 *G2) evalPolynomial(monic bool, coefficients [][2]string, x *fields_bls12381.E2) *fields_bls12381.E2 {
+	res := newE2(coefficients[len(coefficients)-1])
+	if monic {
+		res = g2.Ext2.Add(res, x)
+	}
+	for i := len(coefficients) - 2; i >= 0; i-- {
+		res = g2.Ext2.Mul(res, x)
+		res = g2.Ext2.Add(res, newE2(coefficients[i]))
+	}
+	return res
+}
+
+// ClearCofactor maps a point on the twist E2 to G2 by multiplying it by the
+// effective cofactor h_eff defined in RFC 9380 Section 8.8.2. We use the
+// endomorphism-based method of Budroni and Pintore:
+//
+//	[h_eff]Q = [x₀²-x₀-1]Q + [x₀-1]ψ(Q) + ψ²([2]Q)
+//
+// where x₀ is the curve seed.
+func (g2 *G2) ClearCofactor(q *G2Affine) *G2Affine {
+	// [x₀]Q
+	xq := g2.scalarMulBySeed(q)
+	// [x₀²]Q
+	xxq := g2.scalarMulBySeed(xq)
+	// [x₀²-x₀-1]Q
+	res := g2.sub(xxq, xq)
+	res = g2.sub(res, q)
+	// ψ([x₀-1]Q)
+	t := g2.sub(xq, q)
+	t = g2.psi(t)
+	res = g2.add(res, t)
+	// ψ²([2]Q) = (w * x, -y)
+	t = g2.double(q)
+	t = &G2Affine{
+		P: g2AffP{
+			X: *g2.Ext2.MulByElement(&t.P.X, g2.w),
+			Y: t.P.Y,
+		},
+	}
+	res = g2.sub(res, t)
+
+	return res
+}
@@ -0,0 +1,105 @@
+package sw_bls12381
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/fields_bls12381"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type mapToG2Circuit struct {
+	U   fields_bls12381.E2
+	Res G2Affine
+}
+
+func (c *mapToG2Circuit) Define(api frontend.API) error {
+	g2 := NewG2(api)
+	res := g2.MapToG2(&c.U)
+	g2.AssertIsEqual(res, &c.Res)
+	return nil
+}
+
+func TestMapToG2TestSolve(t *testing.T) {
+	assert := test.NewAssert(t)
+	var u bls12381.E2
+	u.SetRandom()
+	res := bls12381.MapToG2(u)
+	witness := mapToG2Circuit{
+		U: fields_bls12381.E2{
+			A0: emulated.ValueOf[BaseField](u.A0),
+			A1: emulated.ValueOf[BaseField](u.A1),
+		},
+		Res: NewG2Affine(res),
+	}
+	err := test.IsSolved(&mapToG2Circuit{}, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
+
+type hashToG2Circuit struct {
+	Msg []uints.U8
+	Res G2Affine
+
+	dst []byte
+}
+
+func (c *hashToG2Circuit) Define(api frontend.API) error {
+	g2 := NewG2(api)
+	res, err := g2.HashToG2(c.Msg, c.dst)
+	if err != nil {
+		return err
+	}
+	g2.AssertIsEqual(res, &c.Res)
+	return nil
+}
+
+func TestHashToG2TestSolve(t *testing.T) {
+	assert := test.NewAssert(t)
+	dst := []byte("QUUX-V01-CS02-with-BLS12381G2_XMD:SHA-256_SSWU_RO_")
+	msg := []byte("abc")
+	res, err := bls12381.HashToG2(msg, dst)
+	assert.NoError(err)
+	witness := hashToG2Circuit{
+		Msg: uints.NewU8Array(msg),
+		Res: NewG2Affine(res),
+	}
+	err = test.IsSolved(&hashToG2Circuit{Msg: make([]uints.U8, len(msg)), dst: dst}, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
+
+func TestHashToG2Vectors(t *testing.T) {
+	// test vectors from RFC 9380 Appendix J.10.1
+	assert := test.NewAssert(t)
+	dst := []byte("QUUX-V01-CS02-with-BLS12381G2_XMD:SHA-256_SSWU_RO_")
+	vectors := []struct {
+		msg            string
+		x0, x1, y0, y1 string
+	}{
+		{
+			"",
+			"0x0141ebfbdca40eb85b87142e130ab689c673cf60f1a3e98d69335266f30d9b8d4ac44c1038e9dcdd5393faf5c41fb78a", "0x05cb8437535e20ecffaef7752baddf98034139c38452458baeefab379ba13dff5bf5dd71b72418717047f5b0f37da03d",
+			"0x0503921d7f6a12805e72940b963c0cf3471c7b2a524950ca195d11062ee75ec076daf2d4bc358c4b190c0c98064fdd92", "0x12424ac32561493f3fe3c260708a12b7c620e7be00099a974e259ddc7d1f6395c3c811cdd19f1e8dbf3e9ecfdcbab8d6",
+		},
+	}
+	for _, v := range vectors {
+		var res bls12381.G2Affine
+		for _, c := range []struct {
+			e *fp.Element
+			s string
+		}{{&res.X.A0, v.x0}, {&res.X.A1, v.x1}, {&res.Y.A0, v.y0}, {&res.Y.A1, v.y1}} {
+			_, err := c.e.SetString(c.s)
+			assert.NoError(err)
+		}
+		witness := hashToG2Circuit{
+			Msg: uints.NewU8Array([]byte(v.msg)),
+			Res: NewG2Affine(res),
+		}
+		err := test.IsSolved(&hashToG2Circuit{Msg: make([]uints.U8, len(v.msg)), dst: dst}, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err, v.msg)
+	}
+}
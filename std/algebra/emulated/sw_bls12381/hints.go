@@ -0,0 +1,52 @@
+package sw_bls12381
+
+import (
+	"math/big"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hint functions used in the package.
+func GetHints() []solver.Hint {
+	return []solver.Hint{
+		isSquareHint,
+		isSquareE2Hint,
+	}
+}
+
+// isSquareHint returns 1 if the input is a quadratic residue in the base field
+// and 0 otherwise.
+func isSquareHint(nativeMod *big.Int, nativeInputs, nativeOutputs []*big.Int) error {
+	return emulated.UnwrapHintWithNativeOutput(nativeInputs, nativeOutputs,
+		func(mod *big.Int, inputs, outputs []*big.Int) error {
+			if big.Jacobi(inputs[0], mod) == -1 {
+				outputs[0].SetUint64(0)
+			} else {
+				outputs[0].SetUint64(1)
+			}
+			return nil
+		})
+}
+
+// isSquareE2Hint returns 1 if the input is a quadratic residue in the
+// quadratic extension of the base field and 0 otherwise.
+func isSquareE2Hint(nativeMod *big.Int, nativeInputs, nativeOutputs []*big.Int) error {
+	return emulated.UnwrapHintWithNativeOutput(nativeInputs, nativeOutputs,
+		func(mod *big.Int, inputs, outputs []*big.Int) error {
+			var a bls12381.E2
+			a.A0.SetBigInt(inputs[0])
+			a.A1.SetBigInt(inputs[1])
+			if a.Legendre() == -1 {
+				outputs[0].SetUint64(0)
+			} else {
+				outputs[0].SetUint64(1)
+			}
+			return nil
+		})
+}
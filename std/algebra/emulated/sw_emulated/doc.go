@@ -25,6 +25,14 @@ a particular curve parameter depending on the type parameter defining the base
 field. For now, we only have a single curve defined on every base field, but
 this may change in the future with the addition of additional curves.
 
+The package also implements hashing to the curve as defined in RFC 9380 using
+expand_message_xmd over SHA2-256. The Shallue-van de Woestijne map
+[Curve.MapToCurveSVDW] is applicable to any curve, whereas the simplified SWU
+map [Curve.MapToCurveSSWU] is applicable to curves with non-zero coefficients a
+and b (for example P-256 and P-384) and to secp256k1 through its standard
+3-isogeny. For BLS12-381 and BLS12-377, the simplified SWU maps using isogenies
+are implemented in the curve-specific packages.
+
 This package uses field emulation (unlike packages
 [github.com/consensys/gnark/std/algebra/native/sw_bls12377] and
 [github.com/consensys/gnark/std/algebra/native/sw_bls24315], which use 2-chains). This
@@ -0,0 +1,517 @@
+package sw_emulated
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/expand"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// hashToCurveParams are the curve specific parameters for hashing to the
+// curve. See RFC 9380 Section 8 for the values of the standardised suites.
+type hashToCurveParams struct {
+	// l is the number of bytes expanded for every base field element.
+	l int
+	// z is the non-square used in the simplified SWU map. It is nil when the
+	// simplified SWU map is not applicable to the curve.
+	z *big.Int
+	// isogeny is set when the curve has AB = 0 and the simplified SWU map is
+	// applied to an isogenous curve instead.
+	isogeny *isogenyParams
+	// cofactor is the effective cofactor used for clearing the cofactor.
+	cofactor *big.Int
+}
+
+// isogenyParams are the coefficients of the isogenous curve y² = x³ + A'x + B'
+// and of the rational maps of the isogeny to the curve. The denominators are
+// monic and their leading coefficients are omitted.
+type isogenyParams struct {
+	a, b                   *big.Int
+	xNum, xDen, yNum, yDen []*big.Int
+}
+
+// secp256k1Isogeny is the 3-isogeny of the suite secp256k1_XMD:SHA-256_SSWU_RO_,
+// see RFC 9380 Section 8.7 and Appendix E.1.
+var secp256k1Isogeny = &isogenyParams{
+	a: hexInt("3f8731abdd661adca08a5558f0f5d272e953d363cb6f0e5d405447c01a444533"),
+	b: big.NewInt(1771),
+	xNum: []*big.Int{
+		hexInt("8e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38daaaaa8c7"),
+		hexInt("07d3d4c80bc321d5b9f315cea7fd44c5d595d2fc0bf63b92dfff1044f17c6581"),
+		hexInt("534c328d23f234e6e2a413deca25caece4506144037c40314ecbd0b53d9dd262"),
+		hexInt("8e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38daaaaa88c"),
+	},
+	xDen: []*big.Int{
+		hexInt("d35771193d94918a9ca34ccbb7b640dd86cd409542f8487d9fe6b745781eb49b"),
+		hexInt("edadc6f64383dc1df7c4b2d51b54225406d36b641f5e41bbc52a56612a8c6d14"),
+	},
+	yNum: []*big.Int{
+		hexInt("4bda12f684bda12f684bda12f684bda12f684bda12f684bda12f684b8e38e23c"),
+		hexInt("c75e0c32d5cb7c0fa9d0a54b12a0a6d5647ab046d686da6fdffc90fc201d71a3"),
+		hexInt("29a6194691f91a73715209ef6512e576722830a201be2018a765e85a9ecee931"),
+		hexInt("2f684bda12f684bda12f684bda12f684bda12f684bda12f684bda12f38e38d84"),
+	},
+	yDen: []*big.Int{
+		hexInt("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffff93b"),
+		hexInt("7a06534bb8bdb49fd5e9e6632722c2989467c1bfc8e8d978dfb425d2685c2573"),
+		hexInt("6484aa716545ca2cf3a70c3fa8fe337e0a3d21162f0d6299a7bf8192bfd2a76f"),
+	},
+}
+
+func hexInt(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("invalid hex constant " + s)
+	}
+	return v
+}
+
+func getHashToCurveParams[Base emulated.FieldParams]() (hashToCurveParams, error) {
+	var t Base
+	switch t.Modulus().String() {
+	case emulated.Secp256k1Fp{}.Modulus().String():
+		return hashToCurveParams{l: 48, z: big.NewInt(-11), isogeny: secp256k1Isogeny, cofactor: big.NewInt(1)}, nil
+	case emulated.BN254Fp{}.Modulus().String():
+		return hashToCurveParams{l: 48, cofactor: big.NewInt(1)}, nil
+	case emulated.BLS12381Fp{}.Modulus().String():
+		h, _ := new(big.Int).SetString("d201000000010001", 16)
+		return hashToCurveParams{l: 64, cofactor: h}, nil
+	case emulated.P256Fp{}.Modulus().String():
+		return hashToCurveParams{l: 48, z: big.NewInt(-10), cofactor: big.NewInt(1)}, nil
+	case emulated.P384Fp{}.Modulus().String():
+		return hashToCurveParams{l: 72, z: big.NewInt(-12), cofactor: big.NewInt(1)}, nil
+	case emulated.BW6761Fp{}.Modulus().String():
+		h, _ := new(big.Int).SetString("26642435879335816683987677701488073867751118270052650655942102502312977592501693353047140953112195348280268661194876", 10)
+		return hashToCurveParams{l: 112, cofactor: h}, nil
+	default:
+		return hashToCurveParams{}, fmt.Errorf("no stored hash to curve parameters")
+	}
+}
+
+// HashToField hashes msg into count base field elements using the
+// hash_to_field method defined in RFC 9380 Section 5.2 with expand_message_xmd
+// over SHA2-256. The domain separation tag dst is a constant known at circuit
+// compile time.
+func (c *Curve[B, S]) HashToField(msg []uints.U8, dst []byte, count int) ([]*emulated.Element[B], error) {
+	h2c, err := getHashToCurveParams[B]()
+	if err != nil {
+		return nil, err
+	}
+	return HashToField(c.api, c.baseApi, msg, dst, count, h2c.l)
+}
+
+// HashToField hashes msg into count elements of the emulated field f using the
+// hash_to_field method defined in RFC 9380 Section 5.2 with
+// expand_message_xmd over SHA2-256, expanding l bytes for every element. The
+// domain separation tag dst is a constant known at circuit compile time.
+//
+// It is shared by the hash to curve methods of the emulated curves, including
+// the extension field ones which hash to pairs of elements.
+func HashToField[B emulated.FieldParams](api frontend.API, f *emulated.Field[B], msg []uints.U8, dst []byte, count, l int) ([]*emulated.Element[B], error) {
+	var fp B
+	if fp.BitsPerLimb()%8 != 0 {
+		return nil, fmt.Errorf("limb width not multiple of 8")
+	}
+	bts, err := expand.ExpandMsgXmd(api, msg, dst, count*l)
+	if err != nil {
+		return nil, fmt.Errorf("expand message: %w", err)
+	}
+	// every chunk of l bytes is interpreted as a big-endian integer. We split
+	// it into limbs and group the limbs into elements with the most
+	// significant limb set to zero, so that every element is well-formed. The
+	// elements are then combined by shifting modulo p.
+	bytesPerLimb := int(fp.BitsPerLimb() / 8)
+	nbLimbs := int(fp.NbLimbs())
+	limbsPerElement := nbLimbs - 1
+	nbChunkLimbs := (l + bytesPerLimb - 1) / bytesPerLimb
+	shift := emulated.ValueOf[B](new(big.Int).Lsh(big.NewInt(1), uint(limbsPerElement)*fp.BitsPerLimb()))
+	res := make([]*emulated.Element[B], count)
+	for i := range res {
+		chunk := bts[i*l : (i+1)*l]
+		limbs := make([]frontend.Variable, nbChunkLimbs)
+		for j := range limbs {
+			var limb frontend.Variable = 0
+			for k := 0; k < bytesPerLimb; k++ {
+				idx := l - bytesPerLimb*j - k - 1
+				if idx < 0 {
+					break
+				}
+				limb = api.Add(limb, api.Mul(chunk[idx].Val, new(big.Int).Lsh(big.NewInt(1), uint(8*k))))
+			}
+			limbs[j] = limb
+		}
+		var acc *emulated.Element[B]
+		for j := (nbChunkLimbs - 1) / limbsPerElement * limbsPerElement; j >= 0; j -= limbsPerElement {
+			elLimbs := make([]frontend.Variable, nbLimbs)
+			for k := range elLimbs {
+				elLimbs[k] = 0
+				if k < limbsPerElement && j+k < nbChunkLimbs {
+					elLimbs[k] = limbs[j+k]
+				}
+			}
+			el := f.NewElement(elLimbs)
+			if acc == nil {
+				acc = el
+			} else {
+				acc = f.Add(f.Mul(acc, &shift), el)
+			}
+		}
+		res[i] = acc
+	}
+	return res, nil
+}
+
+// HashToCurveSSWU hashes msg into a point in the prime order subgroup using
+// the hash_to_curve method defined in RFC 9380 Section 3 with
+// expand_message_xmd over SHA2-256 and the simplified SWU map. It returns an
+// error if the simplified SWU map is not applicable to the curve.
+func (c *Curve[B, S]) HashToCurveSSWU(msg []uints.U8, dst []byte) (*AffinePoint[B], error) {
+	u, err := c.HashToField(msg, dst, 2)
+	if err != nil {
+		return nil, fmt.Errorf("hash to field: %w", err)
+	}
+	q0, err := c.MapToCurveSSWU(u[0])
+	if err != nil {
+		return nil, err
+	}
+	q1, err := c.MapToCurveSSWU(u[1])
+	if err != nil {
+		return nil, err
+	}
+	return c.ClearCofactor(c.Add(q0, q1))
+}
+
+// HashToCurveSVDW hashes msg into a point in the prime order subgroup using
+// the hash_to_curve method defined in RFC 9380 Section 3 with
+// expand_message_xmd over SHA2-256 and the Shallue-van de Woestijne map.
+func (c *Curve[B, S]) HashToCurveSVDW(msg []uints.U8, dst []byte) (*AffinePoint[B], error) {
+	u, err := c.HashToField(msg, dst, 2)
+	if err != nil {
+		return nil, fmt.Errorf("hash to field: %w", err)
+	}
+	q0 := c.MapToCurveSVDW(u[0])
+	q1 := c.MapToCurveSVDW(u[1])
+	return c.ClearCofactor(c.Add(q0, q1))
+}
+
+// ClearCofactor maps the point p on the curve into the prime order subgroup by
+// multiplying it with the effective cofactor. It uses incomplete formulas and
+// assumes that the order of p is larger than the effective cofactor, which
+// holds with overwhelming probability for points obtained by mapping a hashed
+// value to the curve.
+func (c *Curve[B, S]) ClearCofactor(p *AffinePoint[B]) (*AffinePoint[B], error) {
+	h2c, err := getHashToCurveParams[B]()
+	if err != nil {
+		return nil, err
+	}
+	h := h2c.cofactor
+	if h.Cmp(big.NewInt(1)) == 0 {
+		return p, nil
+	}
+	// the first step is computed separately as doubleAndAdd is not defined
+	// for equal inputs.
+	n := h.BitLen()
+	var res *AffinePoint[B]
+	if h.Bit(n-2) == 1 {
+		res = c.triple(p)
+	} else {
+		res = c.double(p)
+	}
+	for i := n - 3; i >= 0; i-- {
+		if h.Bit(i) == 1 {
+			res = c.doubleAndAdd(res, p)
+		} else {
+			res = c.double(res)
+		}
+	}
+	return res, nil
+}
+
+// MapToCurveSSWU maps the base field element u to a point on the curve using
+// the simplified Shallue-van de Woestijne-Ulas map defined in RFC 9380 Section
+// 6.6.2. For curves with AB = 0 such as secp256k1, the map is applied to an
+// isogenous curve and the result is mapped back with the isogeny, as in RFC
+// 9380 Section 6.6.3. It returns an error if no such parameters are known for
+// the curve. The result is not necessarily in the prime order subgroup, see
+// [Curve.ClearCofactor].
+func (c *Curve[B, S]) MapToCurveSSWU(u *emulated.Element[B]) (*AffinePoint[B], error) {
+	h2c, err := getHashToCurveParams[B]()
+	if err != nil {
+		return nil, err
+	}
+	if h2c.z == nil {
+		return nil, fmt.Errorf("simplified SWU map not applicable to the curve")
+	}
+	z := emulated.ValueOf[B](h2c.z)
+	a, b := &c.a, &c.b
+	if h2c.isogeny != nil {
+		isoA := emulated.ValueOf[B](h2c.isogeny.a)
+		isoB := emulated.ValueOf[B](h2c.isogeny.b)
+		a, b = &isoA, &isoB
+	}
+	g := func(x *emulated.Element[B]) *emulated.Element[B] {
+		res := c.baseApi.Add(c.baseApi.Mul(x, x), a)
+		res = c.baseApi.Mul(res, x)
+		return c.baseApi.Add(res, b)
+	}
+
+	// tv1 = Z * u²
+	tv1 := c.baseApi.Mul(c.baseApi.Mul(u, u), &z)
+	// tv2 = tv1² + tv1
+	tv2 := c.baseApi.Add(c.baseApi.Mul(tv1, tv1), tv1)
+	// x1 = B * (tv2 + 1) / (A * CMOV(Z, -tv2, tv2 != 0))
+	num := c.baseApi.Mul(c.baseApi.Add(tv2, c.baseApi.One()), b)
+	den := c.baseApi.Select(c.baseApi.IsZero(tv2), &z, c.baseApi.Neg(tv2))
+	den = c.baseApi.Mul(den, a)
+	x1 := c.baseApi.Div(num, den)
+	// x2 = Z * u² * x1
+	x2 := c.baseApi.Mul(tv1, x1)
+	gx1 := g(x1)
+	gx2 := g(x2)
+
+	// exactly one of gx1 and gx2 is a square as Z is not a square. We obtain
+	// the choice from a hint and the square root computation below fails if
+	// it is incorrect.
+	isSquare, err := c.baseApi.NewHintWithNativeOutput(isSquareHint, 1, gx1)
+	if err != nil {
+		return nil, fmt.Errorf("is square hint: %w", err)
+	}
+	c.api.AssertIsBoolean(isSquare[0])
+	x := c.baseApi.Select(isSquare[0], x1, x2)
+	y := c.baseApi.Sqrt(c.baseApi.Select(isSquare[0], gx1, gx2))
+	y = c.fixSign(u, y)
+
+	res := &AffinePoint[B]{
+		X: *x,
+		Y: *y,
+	}
+	if h2c.isogeny != nil {
+		res = c.isogeny(h2c.isogeny, res)
+	}
+	return res, nil
+}
+
+// isogeny maps the point p on the isogenous curve to the curve.
+func (c *Curve[B, S]) isogeny(iso *isogenyParams, p *AffinePoint[B]) *AffinePoint[B] {
+	xNum := c.evalPolynomial(false, iso.xNum, &p.X)
+	xDen := c.evalPolynomial(true, iso.xDen, &p.X)
+	yNum := c.evalPolynomial(false, iso.yNum, &p.X)
+	yDen := c.evalPolynomial(true, iso.yDen, &p.X)
+
+	x := c.baseApi.Div(xNum, xDen)
+	y := c.baseApi.Div(yNum, yDen)
+	y = c.baseApi.Mul(y, &p.Y)
+
+	return &AffinePoint[B]{
+		X: *x,
+		Y: *y,
+	}
+}
+
+// evalPolynomial evaluates the polynomial with the given coefficients at x
+// using Horner's method. If monic is set, then the leading coefficient 1 is
+// implicit and omitted from coefficients.
+func (c *Curve[B, S]) evalPolynomial(monic bool, coefficients []*big.Int, x *emulated.Element[B]) *emulated.Element[B] {
+	lc := emulated.ValueOf[B](coefficients[len(coefficients)-1])
+	res := &lc
+	if monic {
+		res = c.baseApi.Add(res, x)
+	}
+	for i := len(coefficients) - 2; i >= 0; i-- {
+		ci := emulated.ValueOf[B](coefficients[i])
+		res = c.baseApi.Add(c.baseApi.Mul(res, x), &ci)
+	}
+	return res
+}
+
+// MapToCurveSVDW maps the base field element u to a point on the curve using
+// the Shallue-van de Woestijne map defined in RFC 9380 Section 6.6.1. The map
+// is applicable to any curve in short Weierstrass form. The result is not
+// necessarily in the prime order subgroup, see [Curve.ClearCofactor].
+func (c *Curve[B, S]) MapToCurveSVDW(u *emulated.Element[B]) *AffinePoint[B] {
+	var fp B
+	cs := getSVDWConstants(c.params.A, c.params.B, fp.Modulus())
+	z := emulated.ValueOf[B](cs.z)
+	c1 := emulated.ValueOf[B](cs.c1)
+	c2 := emulated.ValueOf[B](cs.c2)
+	c3 := emulated.ValueOf[B](cs.c3)
+	c4 := emulated.ValueOf[B](cs.c4)
+	one := c.baseApi.One()
+
+	// tv1 = u² * c1
+	tv1 := c.baseApi.Mul(c.baseApi.Mul(u, u), &c1)
+	// tv2 = 1 + tv1
+	tv2 := c.baseApi.Add(one, tv1)
+	// tv1 = 1 - tv1
+	tv1 = c.baseApi.Sub(one, tv1)
+	// tv3 = inv0(tv1 * tv2)
+	tv3 := c.baseApi.Mul(tv1, tv2)
+	isZero := c.baseApi.IsZero(tv3)
+	tv3 = c.baseApi.Inverse(c.baseApi.Select(isZero, one, tv3))
+	tv3 = c.baseApi.Select(isZero, c.baseApi.Zero(), tv3)
+	// tv4 = u * tv1 * tv3 * c3
+	tv4 := c.baseApi.Mul(c.baseApi.Mul(u, tv1), c.baseApi.Mul(tv3, &c3))
+	// x1 = c2 - tv4
+	x1 := c.baseApi.Sub(&c2, tv4)
+	// x2 = c2 + tv4
+	x2 := c.baseApi.Add(&c2, tv4)
+	// x3 = (tv2² * tv3)² * c4 + Z
+	x3 := c.baseApi.Mul(c.baseApi.Mul(tv2, tv2), tv3)
+	x3 = c.baseApi.Add(c.baseApi.Mul(c.baseApi.Mul(x3, x3), &c4), &z)
+
+	// x = x1 if gx1 is a square, otherwise x2 if gx2 is a square and x3
+	// otherwise, in which case gx3 is guaranteed to be a square. Differently
+	// from the simplified SWU map, both gx1 and gx2 may be squares, so we also
+	// constrain the negative outcomes.
+	nonSquare := emulated.ValueOf[B](cs.nonSquare)
+	e1 := c.isSquare(c.evalCurve(x1), &nonSquare)
+	e2 := c.isSquare(c.evalCurve(x2), &nonSquare)
+	x := c.baseApi.Select(e1, x1, c.baseApi.Select(e2, x2, x3))
+	y := c.baseApi.Sqrt(c.evalCurve(x))
+	y = c.fixSign(u, y)
+
+	return &AffinePoint[B]{
+		X: *x,
+		Y: *y,
+	}
+}
+
+// isSquare returns 1 if x is a square in the base field and 0 otherwise. The
+// hinted result is constrained by computing the square root of x if it is a
+// square and of nonSquare*x otherwise.
+//
+// When x is zero, then the result is not unique. This happens with negligible
+// probability when x is derived from a hashed input.
+func (c *Curve[B, S]) isSquare(x, nonSquare *emulated.Element[B]) frontend.Variable {
+	isSquare, err := c.baseApi.NewHintWithNativeOutput(isSquareHint, 1, x)
+	if err != nil {
+		panic(fmt.Sprintf("is square hint: %v", err))
+	}
+	c.api.AssertIsBoolean(isSquare[0])
+	c.baseApi.Sqrt(c.baseApi.Select(isSquare[0], x, c.baseApi.Mul(x, nonSquare)))
+	return isSquare[0]
+}
+
+// fixSign returns y or -y such that sgn0(u) == sgn0(y) as defined in RFC 9380
+// Section 4.1.
+func (c *Curve[B, S]) fixSign(u, y *emulated.Element[B]) *emulated.Element[B] {
+	flip := c.api.Xor(c.baseApi.ToBitsCanonical(u)[0], c.baseApi.ToBitsCanonical(y)[0])
+	return c.baseApi.Select(flip, c.baseApi.Neg(y), y)
+}
+
+// evalCurve returns x³ + a*x + b.
+func (c *Curve[B, S]) evalCurve(x *emulated.Element[B]) *emulated.Element[B] {
+	res := c.baseApi.Mul(x, x)
+	if c.addA {
+		res = c.baseApi.Add(res, &c.a)
+	}
+	res = c.baseApi.Mul(res, x)
+	return c.baseApi.Add(res, &c.b)
+}
+
+// svdwConstants are the constants of the Shallue-van de Woestijne map, see RFC
+// 9380 Section 6.6.1.
+type svdwConstants struct {
+	z, c1, c2, c3, c4 *big.Int
+	// nonSquare is a fixed non-square used for constraining the quadratic
+	// residuosity.
+	nonSquare *big.Int
+}
+
+// svdwConstantsCache caches the constants of the Shallue-van de Woestijne map
+// per curve, as finding Z requires many Legendre symbol computations and the
+// map is usually called several times per circuit.
+var svdwConstantsCache sync.Map
+
+// getSVDWConstants returns the cached constants of the Shallue-van de
+// Woestijne map for the curve y² = x³ + ax + b over the field of order p.
+func getSVDWConstants(a, b, p *big.Int) svdwConstants {
+	key := a.String() + "/" + b.String() + "/" + p.String()
+	if cs, ok := svdwConstantsCache.Load(key); ok {
+		return cs.(svdwConstants)
+	}
+	cs, _ := svdwConstantsCache.LoadOrStore(key, newSVDWConstants(a, b, p))
+	return cs.(svdwConstants)
+}
+
+// newSVDWConstants computes the constants of the Shallue-van de Woestijne map
+// for the curve y² = x³ + ax + b over the field of order p. Z is chosen using
+// find_z_svdw from RFC 9380 Appendix H.1.
+func newSVDWConstants(a, b, p *big.Int) svdwConstants {
+	g := func(x *big.Int) *big.Int {
+		res := new(big.Int).Mul(x, x)
+		res.Add(res, a)
+		res.Mul(res, x)
+		res.Add(res, b)
+		return res.Mod(res, p)
+	}
+	isSquare := func(x *big.Int) bool {
+		return big.Jacobi(x, p) != -1
+	}
+	// h(Z) = 3Z² + 4A
+	h := func(z *big.Int) *big.Int {
+		res := new(big.Int).Mul(z, z)
+		res.Mul(res, big.NewInt(3))
+		res.Add(res, new(big.Int).Lsh(a, 2))
+		return res.Mod(res, p)
+	}
+	two := big.NewInt(2)
+	var z *big.Int
+	for ctr := int64(1); z == nil; ctr++ {
+		for _, cand := range []*big.Int{big.NewInt(ctr), new(big.Int).Sub(p, big.NewInt(ctr))} {
+			gz := g(cand)
+			hz := h(cand)
+			if gz.Sign() == 0 || hz.Sign() == 0 {
+				continue
+			}
+			// -(3Z² + 4A) / (4g(Z)) must be a non-zero square
+			t := new(big.Int).Lsh(gz, 2)
+			t.ModInverse(t, p)
+			t.Mul(t, hz)
+			t.Neg(t)
+			t.Mod(t, p)
+			if !isSquare(t) {
+				continue
+			}
+			mz2 := new(big.Int).ModInverse(two, p)
+			mz2.Mul(mz2, cand)
+			mz2.Neg(mz2)
+			mz2.Mod(mz2, p)
+			if isSquare(gz) || isSquare(g(mz2)) {
+				z = cand
+				break
+			}
+		}
+	}
+	gz := g(z)
+	hz := h(z)
+	// c2 = -Z / 2
+	c2 := new(big.Int).ModInverse(two, p)
+	c2.Mul(c2, z)
+	c2.Neg(c2)
+	c2.Mod(c2, p)
+	// c3 = sqrt(-g(Z) * (3Z² + 4A)) with sgn0(c3) = 0
+	c3 := new(big.Int).Mul(gz, hz)
+	c3.Neg(c3)
+	c3.Mod(c3, p)
+	c3.ModSqrt(c3, p)
+	if c3.Bit(0) == 1 {
+		c3.Sub(p, c3)
+	}
+	// c4 = -4g(Z) / (3Z² + 4A)
+	c4 := new(big.Int).ModInverse(hz, p)
+	c4.Mul(c4, gz)
+	c4.Lsh(c4, 2)
+	c4.Neg(c4)
+	c4.Mod(c4, p)
+
+	nonSquare := big.NewInt(2)
+	for isSquare(nonSquare) {
+		nonSquare.Add(nonSquare, big.NewInt(1))
+	}
+	return svdwConstants{z: z, c1: gz, c2: c2, c3: c3, c4: c4, nonSquare: nonSquare}
+}
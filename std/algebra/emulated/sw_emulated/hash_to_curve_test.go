@@ -0,0 +1,247 @@
+package sw_emulated
+
+import (
+	"math/big"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	fp_bls381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	fp_bn "github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	fp_secp "github.com/consensys/gnark-crypto/ecc/secp256k1/fp"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type MapToCurveSVDWTest[T, S emulated.FieldParams] struct {
+	U emulated.Element[T]
+	R AffinePoint[T]
+}
+
+func (c *MapToCurveSVDWTest[T, S]) Define(api frontend.API) error {
+	cr, err := New[T, S](api, GetCurveParams[T]())
+	if err != nil {
+		return err
+	}
+	res := cr.MapToCurveSVDW(&c.U)
+	cr.AssertIsEqual(res, &c.R)
+	return nil
+}
+
+func TestMapToCurveSVDWBN254(t *testing.T) {
+	assert := test.NewAssert(t)
+	var u fp_bn.Element
+	u.SetRandom()
+	res := bn254.MapToCurve1(&u)
+	circuit := MapToCurveSVDWTest[emulated.BN254Fp, emulated.BN254Fr]{}
+	witness := MapToCurveSVDWTest[emulated.BN254Fp, emulated.BN254Fr]{
+		U: emulated.ValueOf[emulated.BN254Fp](u),
+		R: AffinePoint[emulated.BN254Fp]{
+			X: emulated.ValueOf[emulated.BN254Fp](res.X),
+			Y: emulated.ValueOf[emulated.BN254Fp](res.Y),
+		},
+	}
+	err := test.IsSolved(&circuit, &witness, testCurve.ScalarField())
+	assert.NoError(err)
+}
+
+func TestMapToCurveSVDWSecp256k1(t *testing.T) {
+	assert := test.NewAssert(t)
+	var u fp_secp.Element
+	u.SetRandom()
+	res := secp256k1.MapToCurve1(&u)
+	circuit := MapToCurveSVDWTest[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{}
+	witness := MapToCurveSVDWTest[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+		U: emulated.ValueOf[emulated.Secp256k1Fp](u),
+		R: AffinePoint[emulated.Secp256k1Fp]{
+			X: emulated.ValueOf[emulated.Secp256k1Fp](res.X),
+			Y: emulated.ValueOf[emulated.Secp256k1Fp](res.Y),
+		},
+	}
+	err := test.IsSolved(&circuit, &witness, testCurve.ScalarField())
+	assert.NoError(err)
+}
+
+type HashToCurveTest[T, S emulated.FieldParams] struct {
+	Msg []uints.U8
+	R   AffinePoint[T]
+
+	dst  []byte
+	sswu bool
+}
+
+func (c *HashToCurveTest[T, S]) Define(api frontend.API) error {
+	cr, err := New[T, S](api, GetCurveParams[T]())
+	if err != nil {
+		return err
+	}
+	var res *AffinePoint[T]
+	if c.sswu {
+		res, err = cr.HashToCurveSSWU(c.Msg, c.dst)
+	} else {
+		res, err = cr.HashToCurveSVDW(c.Msg, c.dst)
+	}
+	if err != nil {
+		return err
+	}
+	cr.AssertIsEqual(res, &c.R)
+	return nil
+}
+
+func TestHashToCurveSVDWBN254(t *testing.T) {
+	assert := test.NewAssert(t)
+	dst := []byte("QUUX-V01-CS02-with-BN254G1_XMD:SHA-256_SVDW_RO_")
+	msg := []byte("abc")
+	res, err := bn254.HashToG1(msg, dst)
+	assert.NoError(err)
+	circuit := HashToCurveTest[emulated.BN254Fp, emulated.BN254Fr]{
+		Msg: make([]uints.U8, len(msg)),
+		dst: dst,
+	}
+	witness := HashToCurveTest[emulated.BN254Fp, emulated.BN254Fr]{
+		Msg: uints.NewU8Array(msg),
+		R: AffinePoint[emulated.BN254Fp]{
+			X: emulated.ValueOf[emulated.BN254Fp](res.X),
+			Y: emulated.ValueOf[emulated.BN254Fp](res.Y),
+		},
+	}
+	err = test.IsSolved(&circuit, &witness, testCurve.ScalarField())
+	assert.NoError(err)
+}
+
+func TestHashToCurveSVDWSecp256k1(t *testing.T) {
+	assert := test.NewAssert(t)
+	dst := []byte("QUUX-V01-CS02-with-secp256k1_XMD:SHA-256_SVDW_RO_")
+	msg := []byte("abcdef0123456789")
+	res, err := secp256k1.HashToG1(msg, dst)
+	assert.NoError(err)
+	circuit := HashToCurveTest[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+		Msg: make([]uints.U8, len(msg)),
+		dst: dst,
+	}
+	witness := HashToCurveTest[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+		Msg: uints.NewU8Array(msg),
+		R: AffinePoint[emulated.Secp256k1Fp]{
+			X: emulated.ValueOf[emulated.Secp256k1Fp](res.X),
+			Y: emulated.ValueOf[emulated.Secp256k1Fp](res.Y),
+		},
+	}
+	err = test.IsSolved(&circuit, &witness, testCurve.ScalarField())
+	assert.NoError(err)
+}
+
+func TestHashToCurveSSWUP256(t *testing.T) {
+	// test vectors from RFC 9380 Appendix J.1.1
+	assert := test.NewAssert(t)
+	dst := []byte("QUUX-V01-CS02-with-P256_XMD:SHA-256_SSWU_RO_")
+	vectors := []struct {
+		msg  string
+		x, y string
+	}{
+		{"", "2c15230b26dbc6fc9a37051158c95b79656e17a1a920b11394ca91c44247d3e4", "8a7a74985cc5c776cdfe4b1f19884970453912e9d31528c060be9ab5c43e8415"},
+		{"abc", "0bb8b87485551aa43ed54f009230450b492fead5f1cc91658775dac4a3388a0f", "5c41b3d0731a27a7b14bc0bf0ccded2d8751f83493404c84a88e71ffd424212e"},
+	}
+	for _, v := range vectors {
+		x, _ := new(big.Int).SetString(v.x, 16)
+		y, _ := new(big.Int).SetString(v.y, 16)
+		circuit := HashToCurveTest[emulated.P256Fp, emulated.P256Fr]{
+			Msg:  make([]uints.U8, len(v.msg)),
+			dst:  dst,
+			sswu: true,
+		}
+		witness := HashToCurveTest[emulated.P256Fp, emulated.P256Fr]{
+			Msg: uints.NewU8Array([]byte(v.msg)),
+			R: AffinePoint[emulated.P256Fp]{
+				X: emulated.ValueOf[emulated.P256Fp](x),
+				Y: emulated.ValueOf[emulated.P256Fp](y),
+			},
+		}
+		err := test.IsSolved(&circuit, &witness, testCurve.ScalarField())
+		assert.NoError(err, v.msg)
+	}
+}
+
+type ClearCofactorTest[T, S emulated.FieldParams] struct {
+	P, R AffinePoint[T]
+}
+
+func (c *ClearCofactorTest[T, S]) Define(api frontend.API) error {
+	cr, err := New[T, S](api, GetCurveParams[T]())
+	if err != nil {
+		return err
+	}
+	res, err := cr.ClearCofactor(&c.P)
+	if err != nil {
+		return err
+	}
+	cr.AssertIsEqual(res, &c.R)
+	return nil
+}
+
+func TestClearCofactorBLS12381(t *testing.T) {
+	assert := test.NewAssert(t)
+	// sample a point on the curve which is not in the prime order subgroup
+	var p bls12381.G1Affine
+	for {
+		var y2, four fp_bls381.Element
+		p.X.SetRandom()
+		four.SetUint64(4)
+		y2.Square(&p.X).Mul(&y2, &p.X).Add(&y2, &four)
+		if y2.Legendre() == 1 {
+			p.Y.Sqrt(&y2)
+			break
+		}
+	}
+	assert.False(p.IsInSubGroup())
+	var r bls12381.G1Affine
+	r.ClearCofactor(&p)
+	assert.True(r.IsInSubGroup())
+	circuit := ClearCofactorTest[emulated.BLS12381Fp, emulated.BLS12381Fr]{}
+	witness := ClearCofactorTest[emulated.BLS12381Fp, emulated.BLS12381Fr]{
+		P: AffinePoint[emulated.BLS12381Fp]{
+			X: emulated.ValueOf[emulated.BLS12381Fp](p.X),
+			Y: emulated.ValueOf[emulated.BLS12381Fp](p.Y),
+		},
+		R: AffinePoint[emulated.BLS12381Fp]{
+			X: emulated.ValueOf[emulated.BLS12381Fp](r.X),
+			Y: emulated.ValueOf[emulated.BLS12381Fp](r.Y),
+		},
+	}
+	err := test.IsSolved(&circuit, &witness, testCurve.ScalarField())
+	assert.NoError(err)
+}
+
+func TestHashToCurveSSWUSecp256k1(t *testing.T) {
+	// test vectors from RFC 9380 Appendix J.8.1
+	assert := test.NewAssert(t)
+	dst := []byte("QUUX-V01-CS02-with-secp256k1_XMD:SHA-256_SSWU_RO_")
+	vectors := []struct {
+		msg  string
+		x, y string
+	}{
+		{"", "c1cae290e291aee617ebaef1be6d73861479c48b841eaba9b7b5852ddfeb1346", "64fa678e07ae116126f08b022a94af6de15985c996c3a91b64c406a960e51067"},
+		{"abc", "3377e01eab42db296b512293120c6cee72b6ecf9f9205760bd9ff11fb3cb2c4b", "7f95890f33efebd1044d382a01b1bee0900fb6116f94688d487c6c7b9c8371f6"},
+		{"abcdef0123456789", "bac54083f293f1fe08e4a70137260aa90783a5cb84d3f35848b324d0674b0e3a", "4436476085d4c3c4508b60fcf4389c40176adce756b398bdee27bca19758d828"},
+	}
+	for _, v := range vectors {
+		x, _ := new(big.Int).SetString(v.x, 16)
+		y, _ := new(big.Int).SetString(v.y, 16)
+		circuit := HashToCurveTest[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+			Msg:  make([]uints.U8, len(v.msg)),
+			dst:  dst,
+			sswu: true,
+		}
+		witness := HashToCurveTest[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+			Msg: uints.NewU8Array([]byte(v.msg)),
+			R: AffinePoint[emulated.Secp256k1Fp]{
+				X: emulated.ValueOf[emulated.Secp256k1Fp](x),
+				Y: emulated.ValueOf[emulated.Secp256k1Fp](y),
+			},
+		}
+		err := test.IsSolved(&circuit, &witness, testCurve.ScalarField())
+		assert.NoError(err, v.msg)
+	}
+}
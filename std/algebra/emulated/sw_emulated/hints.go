@@ -14,7 +14,7 @@ func init() {
 }
 
 func GetHints() []solver.Hint {
-	return []solver.Hint{decomposeScalarG1, decomposeScalarG1Signs, decomposeScalarG1Subscalars}
+	return []solver.Hint{decomposeScalarG1, decomposeScalarG1Signs, decomposeScalarG1Subscalars, isSquareHint}
 }
 
 func decomposeScalarG1Subscalars(mod *big.Int, inputs []*big.Int, outputs []*big.Int) error {
@@ -109,3 +109,22 @@ func decomposeScalarG1(mod *big.Int, inputs []*big.Int, outputs []*big.Int) erro
 		return nil
 	})
 }
+
+// isSquareHint returns 1 if the input is a quadratic residue in the base field
+// and 0 otherwise.
+func isSquareHint(nativeMod *big.Int, nativeInputs, nativeOutputs []*big.Int) error {
+	return emulated.UnwrapHintWithNativeOutput(nativeInputs, nativeOutputs, func(mod *big.Int, inputs, outputs []*big.Int) error {
+		if len(inputs) != 1 {
+			return fmt.Errorf("expecting one input")
+		}
+		if len(outputs) != 1 {
+			return fmt.Errorf("expecting one output")
+		}
+		if big.Jacobi(inputs[0], mod) == -1 {
+			outputs[0].SetUint64(0)
+		} else {
+			outputs[0].SetUint64(1)
+		}
+		return nil
+	})
+}
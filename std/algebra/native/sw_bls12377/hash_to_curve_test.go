@@ -0,0 +1,335 @@
+package sw_bls12377
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fp"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/fields_bls12377"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type mapToG1Circuit struct {
+	U   frontend.Variable
+	Res G1Affine
+}
+
+func (c *mapToG1Circuit) Define(api frontend.API) error {
+	res := MapToG1(api, c.U)
+	res.AssertIsEqual(api, c.Res)
+	return nil
+}
+
+func TestMapToG1TestSolve(t *testing.T) {
+	assert := test.NewAssert(t)
+	var u fp.Element
+	u.SetRandom()
+	res := bls12377.MapToG1(u)
+	witness := mapToG1Circuit{
+		U:   u.String(),
+		Res: NewG1Affine(res),
+	}
+	err := test.IsSolved(&mapToG1Circuit{}, &witness, ecc.BW6_761.ScalarField())
+	assert.NoError(err)
+}
+
+type hashToG1Circuit struct {
+	Msg []uints.U8
+	Res G1Affine
+
+	dst []byte
+}
+
+func (c *hashToG1Circuit) Define(api frontend.API) error {
+	res, err := HashToG1(api, c.Msg, c.dst)
+	if err != nil {
+		return err
+	}
+	res.AssertIsEqual(api, c.Res)
+	return nil
+}
+
+func TestHashToG1TestSolve(t *testing.T) {
+	assert := test.NewAssert(t)
+	dst := []byte("QUUX-V01-CS02-with-BLS12377G1_XMD:SHA-256_SSWU_RO_")
+	msg := []byte("abc")
+	res, err := bls12377.HashToG1(msg, dst)
+	assert.NoError(err)
+	witness := hashToG1Circuit{
+		Msg: uints.NewU8Array(msg),
+		Res: NewG1Affine(res),
+	}
+	err = test.IsSolved(&hashToG1Circuit{Msg: make([]uints.U8, len(msg)), dst: dst}, &witness, ecc.BW6_761.ScalarField())
+	assert.NoError(err)
+}
+
+type mapToG1SVDWCircuit struct {
+	U   frontend.Variable
+	Res G1Affine
+}
+
+func (c *mapToG1SVDWCircuit) Define(api frontend.API) error {
+	res := MapToG1SVDW(api, c.U)
+	res.AssertIsEqual(api, c.Res)
+	return nil
+}
+
+// mapToCurve1SVDWRef is a reference implementation of the Shallue-van de
+// Woestijne map, see RFC 9380 Appendix F.1.
+func mapToCurve1SVDWRef(u fp.Element) bls12377.G1Affine {
+	var z, c1, c2, c3, c4, one fp.Element
+	z.SetString(g1SVDWZ)
+	c1.SetString(g1SVDWC1)
+	c2.SetString(g1SVDWC2)
+	c3.SetString(g1SVDWC3)
+	c4.SetString(g1SVDWC4)
+	one.SetOne()
+	g := func(x fp.Element) fp.Element {
+		var res fp.Element
+		res.Square(&x).Mul(&res, &x).Add(&res, &one)
+		return res
+	}
+	var tv1, tv2, tv3, tv4, x1, x2, x3 fp.Element
+	tv1.Square(&u).Mul(&tv1, &c1)
+	tv2.Add(&one, &tv1)
+	tv1.Sub(&one, &tv1)
+	tv3.Mul(&tv1, &tv2).Inverse(&tv3)
+	tv4.Mul(&u, &tv1).Mul(&tv4, &tv3).Mul(&tv4, &c3)
+	x1.Sub(&c2, &tv4)
+	x2.Add(&c2, &tv4)
+	x3.Square(&tv2).Mul(&x3, &tv3).Square(&x3).Mul(&x3, &c4).Add(&x3, &z)
+	x := x3
+	if gx2 := g(x2); gx2.Legendre() != -1 {
+		x = x2
+	}
+	if gx1 := g(x1); gx1.Legendre() != -1 {
+		x = x1
+	}
+	gx := g(x)
+	var y fp.Element
+	y.Sqrt(&gx)
+	uBig, yBig := u.BigInt(new(big.Int)), y.BigInt(new(big.Int))
+	if uBig.Bit(0) != yBig.Bit(0) {
+		y.Neg(&y)
+	}
+	return bls12377.G1Affine{X: x, Y: y}
+}
+
+func TestMapToG1SVDWTestSolve(t *testing.T) {
+	assert := test.NewAssert(t)
+	var u fp.Element
+	u.SetRandom()
+	q := mapToCurve1SVDWRef(u)
+	assert.True(q.IsOnCurve())
+	var res bls12377.G1Affine
+	res.ClearCofactor(&q)
+	assert.True(res.IsInSubGroup())
+	witness := mapToG1SVDWCircuit{
+		U:   u.String(),
+		Res: NewG1Affine(res),
+	}
+	err := test.IsSolved(&mapToG1SVDWCircuit{}, &witness, ecc.BW6_761.ScalarField())
+	assert.NoError(err)
+}
+
+type mapToG2Circuit struct {
+	U   fields_bls12377.E2
+	Res G2Affine
+}
+
+func (c *mapToG2Circuit) Define(api frontend.API) error {
+	res := MapToG2(api, c.U)
+	res.P.AssertIsEqual(api, c.Res.P)
+	return nil
+}
+
+func TestMapToG2TestSolve(t *testing.T) {
+	assert := test.NewAssert(t)
+	var u bls12377.E2
+	u.SetRandom()
+	res := bls12377.MapToG2(u)
+	witness := mapToG2Circuit{
+		U:   fields_bls12377.E2{A0: u.A0.String(), A1: u.A1.String()},
+		Res: NewG2Affine(res),
+	}
+	err := test.IsSolved(&mapToG2Circuit{}, &witness, ecc.BW6_761.ScalarField())
+	assert.NoError(err)
+}
+
+type hashToG2Circuit struct {
+	Msg []uints.U8
+	Res G2Affine
+
+	dst []byte
+}
+
+func (c *hashToG2Circuit) Define(api frontend.API) error {
+	res, err := HashToG2(api, c.Msg, c.dst)
+	if err != nil {
+		return err
+	}
+	res.P.AssertIsEqual(api, c.Res.P)
+	return nil
+}
+
+func TestHashToG2TestSolve(t *testing.T) {
+	assert := test.NewAssert(t)
+	dst := []byte("QUUX-V01-CS02-with-BLS12377G2_XMD:SHA-256_SSWU_RO_")
+	msg := []byte("abc")
+	res, err := bls12377.HashToG2(msg, dst)
+	assert.NoError(err)
+	witness := hashToG2Circuit{
+		Msg: uints.NewU8Array(msg),
+		Res: NewG2Affine(res),
+	}
+	err = test.IsSolved(&hashToG2Circuit{Msg: make([]uints.U8, len(msg)), dst: dst}, &witness, ecc.BW6_761.ScalarField())
+	assert.NoError(err)
+}
+
+type hashToG1SVDWCircuit struct {
+	Msg []uints.U8
+	Res G1Affine
+
+	dst []byte
+}
+
+func (c *hashToG1SVDWCircuit) Define(api frontend.API) error {
+	res, err := HashToG1SVDW(api, c.Msg, c.dst)
+	if err != nil {
+		return err
+	}
+	res.AssertIsEqual(api, c.Res)
+	return nil
+}
+
+func TestHashToG1SVDWTestSolve(t *testing.T) {
+	assert := test.NewAssert(t)
+	dst := []byte("QUUX-V01-CS02-with-BLS12377G1_XMD:SHA-256_SVDW_RO_")
+	msg := []byte("abc")
+	u, err := fp.Hash(msg, dst, 2)
+	assert.NoError(err)
+	q0, q1 := mapToCurve1SVDWRef(u[0]), mapToCurve1SVDWRef(u[1])
+	var res bls12377.G1Affine
+	res.Add(&q0, &q1).ClearCofactor(&res)
+	witness := hashToG1SVDWCircuit{
+		Msg: uints.NewU8Array(msg),
+		Res: NewG1Affine(res),
+	}
+	err = test.IsSolved(&hashToG1SVDWCircuit{Msg: make([]uints.U8, len(msg)), dst: dst}, &witness, ecc.BW6_761.ScalarField())
+	assert.NoError(err)
+}
+
+type mapToG2SVDWCircuit struct {
+	U   fields_bls12377.E2
+	Res G2Affine
+}
+
+func (c *mapToG2SVDWCircuit) Define(api frontend.API) error {
+	res := MapToG2SVDW(api, c.U)
+	res.P.AssertIsEqual(api, c.Res.P)
+	return nil
+}
+
+// mapToCurve2SVDWRef is a reference implementation of the Shallue-van de
+// Woestijne map to E2, see RFC 9380 Appendix F.1.
+func mapToCurve2SVDWRef(u bls12377.E2) bls12377.G2Affine {
+	e2 := func(v [2]string) bls12377.E2 {
+		var res bls12377.E2
+		res.A0.SetString(v[0])
+		res.A1.SetString(v[1])
+		return res
+	}
+	z, c1, c2, c3, c4, b := e2(g2SVDWZ), e2(g2SVDWC1), e2(g2SVDWC2), e2(g2SVDWC3), e2(g2SVDWC4), e2(g2CurveCoeffB)
+	var one bls12377.E2
+	one.SetOne()
+	g := func(x bls12377.E2) bls12377.E2 {
+		var res bls12377.E2
+		res.Square(&x).Mul(&res, &x).Add(&res, &b)
+		return res
+	}
+	sgn0 := func(x bls12377.E2) uint {
+		a0, a1 := x.A0.BigInt(new(big.Int)), x.A1.BigInt(new(big.Int))
+		if a0.Sign() == 0 {
+			return a1.Bit(0)
+		}
+		return a0.Bit(0)
+	}
+	var tv1, tv2, tv3, tv4, x1, x2, x3 bls12377.E2
+	tv1.Square(&u).Mul(&tv1, &c1)
+	tv2.Add(&one, &tv1)
+	tv1.Sub(&one, &tv1)
+	tv3.Mul(&tv1, &tv2).Inverse(&tv3)
+	tv4.Mul(&u, &tv1).Mul(&tv4, &tv3).Mul(&tv4, &c3)
+	x1.Sub(&c2, &tv4)
+	x2.Add(&c2, &tv4)
+	x3.Square(&tv2).Mul(&x3, &tv3).Square(&x3).Mul(&x3, &c4).Add(&x3, &z)
+	x := x3
+	if gx2 := g(x2); gx2.Legendre() != -1 {
+		x = x2
+	}
+	if gx1 := g(x1); gx1.Legendre() != -1 {
+		x = x1
+	}
+	gx := g(x)
+	var y bls12377.E2
+	y.Sqrt(&gx)
+	if sgn0(u) != sgn0(y) {
+		y.Neg(&y)
+	}
+	return bls12377.G2Affine{X: x, Y: y}
+}
+
+func TestMapToG2SVDWTestSolve(t *testing.T) {
+	assert := test.NewAssert(t)
+	var u bls12377.E2
+	u.SetRandom()
+	q := mapToCurve2SVDWRef(u)
+	assert.True(q.IsOnCurve())
+	var res bls12377.G2Affine
+	res.ClearCofactor(&q)
+	assert.True(res.IsInSubGroup())
+	witness := mapToG2SVDWCircuit{
+		U:   fields_bls12377.E2{A0: u.A0.String(), A1: u.A1.String()},
+		Res: NewG2Affine(res),
+	}
+	err := test.IsSolved(&mapToG2SVDWCircuit{}, &witness, ecc.BW6_761.ScalarField())
+	assert.NoError(err)
+}
+
+type hashToG2SVDWCircuit struct {
+	Msg []uints.U8
+	Res G2Affine
+
+	dst []byte
+}
+
+func (c *hashToG2SVDWCircuit) Define(api frontend.API) error {
+	res, err := HashToG2SVDW(api, c.Msg, c.dst)
+	if err != nil {
+		return err
+	}
+	res.P.AssertIsEqual(api, c.Res.P)
+	return nil
+}
+
+func TestHashToG2SVDWTestSolve(t *testing.T) {
+	assert := test.NewAssert(t)
+	dst := []byte("QUUX-V01-CS02-with-BLS12377G2_XMD:SHA-256_SVDW_RO_")
+	msg := []byte("abc")
+	u, err := fp.Hash(msg, dst, 4)
+	assert.NoError(err)
+	q0 := mapToCurve2SVDWRef(bls12377.E2{A0: u[0], A1: u[1]})
+	q1 := mapToCurve2SVDWRef(bls12377.E2{A0: u[2], A1: u[3]})
+	var res bls12377.G2Affine
+	res.Add(&q0, &q1).ClearCofactor(&res)
+	witness := hashToG2SVDWCircuit{
+		Msg: uints.NewU8Array(msg),
+		Res: NewG2Affine(res),
+	}
+	err = test.IsSolved(&hashToG2SVDWCircuit{Msg: make([]uints.U8, len(msg)), dst: dst}, &witness, ecc.BW6_761.ScalarField())
+	assert.NoError(err)
+}
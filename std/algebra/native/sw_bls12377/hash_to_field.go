@@ -0,0 +1,34 @@
+package sw_bls12377
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/expand"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// hashToFieldL is the number of bytes expanded for every base field element.
+// It is computed as L = ceil((ceil(log2(p)) + k) / 8) for the security level
+// k=128.
+const hashToFieldL = 64
+
+// hashToFp hashes msg into count base field elements using the hash_to_field
+// method defined in RFC 9380 Section 5.2 with expand_message_xmd and SHA2-256.
+// As the base field of BLS12-377 is the native field, the modular reduction of
+// the 64-byte chunks is implicit.
+func hashToFp(api frontend.API, msg []uints.U8, dst []byte, count int) ([]frontend.Variable, error) {
+	bts, err := expand.ExpandMsgXmd(api, msg, dst, count*hashToFieldL)
+	if err != nil {
+		return nil, fmt.Errorf("expand message: %w", err)
+	}
+	res := make([]frontend.Variable, count)
+	for i := range res {
+		var acc frontend.Variable = 0
+		for _, b := range bts[i*hashToFieldL : (i+1)*hashToFieldL] {
+			acc = api.Add(api.Mul(acc, 256), b.Val)
+		}
+		res[i] = acc
+	}
+	return res, nil
+}
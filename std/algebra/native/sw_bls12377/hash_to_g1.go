@@ -0,0 +1,262 @@
+package sw_bls12377
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// Coefficients of the 2-isogenous curve E1': y² = x³ + A'x + B' used in the
+// simplified SWU map and the non-square Z.
+var (
+	g1SSWUIsoCurveCoeffA = "258664426012969092796408009721202742408018065645352501567204841856062976176281513834280849065051431927238430294002"
+	g1SSWUIsoCurveCoeffB = "22"
+	g1SSWUZ              = "5"
+)
+
+// Coefficients of the 2-isogeny map from E1' to E1. The denominators are
+// monic and their leading coefficients are omitted.
+var g1IsogenyXNumerator = []string{
+	"193998319509726820447277314072485610595876362210707887456279225959507476652652651634192264150953923683470146535424",
+	"40474824132456359704279181570318738632422647360355249739068643631356267969150730939906729705473",
+	"193998319509726820507989550271170150152295134566185995404913197000040351261255617081226666104680020093330241093633",
+}
+
+var g1IsogenyXDenominator = []string{
+	"161899296529825438817116726281274954529690589441420998956274574525425071876602923759626918821892",
+}
+
+var g1IsogenyYNumerator = []string{
+	"193998319509726820507989550271170150152295134566185995404913197000040351261255617081226666104680020093330241093631",
+	"32333053251621136903112182208573040583096119983059602439070460434672245065050016464457115901761911040205276577794",
+	"129332213006484547066038603046131306324615528732935438218576102373893108782773376834518846023512776472080255287298",
+	"226331372761347957259321141983031841844344323660550327972398729833380409804798219928097777122126690108885281275905",
+}
+
+var g1IsogenyYDenominator = []string{
+	"258664426012969094010652733694893533536393512754914660539884262666720468348340822774968888139573360124440321458169",
+	"971395779178952632902700357687649727178143536648525993737647447152550431259617542557761512931340",
+	"485697889589476316451350178843824863589071768324262996868823723576275215629808771278880756465676",
+}
+
+// Constants of the Shallue-van de Woestijne map for E1, see RFC 9380 Section
+// 6.6.1. Z is chosen using find_z_svdw from RFC 9380 Appendix H.1 and
+// g1SVDWNonSquare is a fixed non-square used for constraining the quadratic
+// residuosity.
+var (
+	g1SVDWZ         = "1"
+	g1SVDWC1        = "2"
+	g1SVDWC2        = "129332213006484547005326366847446766768196756377457330269942131333360234174170411387484444069786680062220160729088"
+	g1SVDWC3        = "161015587002303879183411490966296758198305781022063779778351547628381405527220668155877587635850550163400924107162"
+	g1SVDWC4        = "172442950675312729340435155796595689024262341836609773693256175111146978898893881849979258759715573416293547638782"
+	g1SVDWNonSquare = "5"
+)
+
+// HashToG1 hashes msg into a point in G1 using the hash_to_curve method
+// defined in RFC 9380 Section 3 with expand_message_xmd over SHA2-256, the
+// simplified SWU map and the domain separation tag dst.
+func HashToG1(api frontend.API, msg []uints.U8, dst []byte) (G1Affine, error) {
+	u, err := hashToFp(api, msg, dst, 2)
+	if err != nil {
+		return G1Affine{}, fmt.Errorf("hash to field: %w", err)
+	}
+	q0 := mapToCurve1(api, u[0])
+	q1 := mapToCurve1(api, u[1])
+	// the isogeny is a group homomorphism and the addition formula does not
+	// depend on the curve coefficients, so we add the points on E1' and
+	// evaluate the isogeny only once.
+	q0.AddAssign(api, q1)
+	q := g1Isogeny(api, q0)
+	var res G1Affine
+	res.ClearCofactor(api, q)
+	return res, nil
+}
+
+// MapToG1 maps the field element u to a point in G1 using the simplified SWU
+// map to the 2-isogenous curve followed by the isogeny and cofactor clearing.
+// This corresponds to the encode_to_curve method of RFC 9380 when u is
+// obtained by hashing to the field.
+func MapToG1(api frontend.API, u frontend.Variable) G1Affine {
+	q := mapToCurve1(api, u)
+	q = g1Isogeny(api, q)
+	var res G1Affine
+	res.ClearCofactor(api, q)
+	return res
+}
+
+// HashToG1SVDW hashes msg into a point in G1 using the hash_to_curve method
+// defined in RFC 9380 Section 3 with expand_message_xmd over SHA2-256, the
+// Shallue-van de Woestijne map and the domain separation tag dst.
+func HashToG1SVDW(api frontend.API, msg []uints.U8, dst []byte) (G1Affine, error) {
+	u, err := hashToFp(api, msg, dst, 2)
+	if err != nil {
+		return G1Affine{}, fmt.Errorf("hash to field: %w", err)
+	}
+	q0 := mapToCurve1SVDW(api, u[0])
+	q1 := mapToCurve1SVDW(api, u[1])
+	q0.AddAssign(api, q1)
+	var res G1Affine
+	res.ClearCofactor(api, q0)
+	return res, nil
+}
+
+// MapToG1SVDW maps the field element u to a point in G1 using the
+// Shallue-van de Woestijne map followed by cofactor clearing.
+func MapToG1SVDW(api frontend.API, u frontend.Variable) G1Affine {
+	q := mapToCurve1SVDW(api, u)
+	var res G1Affine
+	res.ClearCofactor(api, q)
+	return res
+}
+
+// mapToCurve1SVDW implements the Shallue-van de Woestijne map to the curve E1,
+// see RFC 9380 Section 6.6.1.
+func mapToCurve1SVDW(api frontend.API, u frontend.Variable) G1Affine {
+	// tv1 = u² * c1
+	tv1 := api.Mul(u, u, g1SVDWC1)
+	// tv2 = 1 + tv1
+	tv2 := api.Add(1, tv1)
+	// tv1 = 1 - tv1
+	tv1 = api.Sub(1, tv1)
+	// tv3 = inv0(tv1 * tv2)
+	tv3 := api.Mul(tv1, tv2)
+	isZero := api.IsZero(tv3)
+	tv3 = api.Inverse(api.Select(isZero, 1, tv3))
+	tv3 = api.Select(isZero, 0, tv3)
+	// tv4 = u * tv1 * tv3 * c3
+	tv4 := api.Mul(u, tv1, tv3, g1SVDWC3)
+	// x1 = c2 - tv4
+	x1 := api.Sub(g1SVDWC2, tv4)
+	// x2 = c2 + tv4
+	x2 := api.Add(g1SVDWC2, tv4)
+	// x3 = (tv2² * tv3)² * c4 + Z
+	x3 := api.Mul(tv2, tv2, tv3)
+	x3 = api.Add(api.Mul(x3, x3, g1SVDWC4), g1SVDWZ)
+
+	// x = x1 if gx1 is a square, otherwise x2 if gx2 is a square and x3
+	// otherwise, in which case gx3 is guaranteed to be a square.
+	e1, _ := isSquare(api, g1EvalCurveE1(api, x1))
+	e2, _ := isSquare(api, g1EvalCurveE1(api, x2))
+	x := api.Select(e1, x1, api.Select(e2, x2, x3))
+	e3, y := isSquare(api, g1EvalCurveE1(api, x))
+	api.AssertIsEqual(e3, 1)
+
+	// fix the sign of y so that sgn0(u) == sgn0(y)
+	flip := api.Xor(sgn0(api, u), sgn0(api, y))
+	y = api.Select(flip, api.Neg(y), y)
+
+	return G1Affine{X: x, Y: y}
+}
+
+// isSquare returns 1 and the square root of x if x is a square and 0 and the
+// square root of g1SVDWNonSquare*x otherwise.
+//
+// When x is zero, then the result is not unique. This happens with negligible
+// probability when x is derived from a hashed input.
+func isSquare(api frontend.API, x frontend.Variable) (frontend.Variable, frontend.Variable) {
+	res, err := api.Compiler().NewHint(isSquareHint, 2, x, g1SVDWNonSquare)
+	if err != nil {
+		panic(err)
+	}
+	api.AssertIsBoolean(res[0])
+	api.AssertIsEqual(api.Mul(res[1], res[1]), api.Select(res[0], x, api.Mul(x, g1SVDWNonSquare)))
+	return res[0], res[1]
+}
+
+// g1EvalCurveE1 returns x³ + 1, the right-hand side of the equation of E1.
+func g1EvalCurveE1(api frontend.API, x frontend.Variable) frontend.Variable {
+	return api.Add(api.Mul(x, x, x), 1)
+}
+
+// mapToCurve1 implements the simplified SWU map to the curve E1', see RFC 9380
+// Section 6.6.2.
+func mapToCurve1(api frontend.API, u frontend.Variable) G1Affine {
+	// tv1 = Z * u²
+	tv1 := api.Mul(u, u, g1SSWUZ)
+	// tv2 = tv1² + tv1
+	tv2 := api.Add(api.Mul(tv1, tv1), tv1)
+	// x1 = B * (tv2 + 1) / (A * CMOV(Z, -tv2, tv2 != 0))
+	num := api.Mul(api.Add(tv2, 1), g1SSWUIsoCurveCoeffB)
+	den := api.Select(api.IsZero(tv2), g1SSWUZ, api.Neg(tv2))
+	den = api.Mul(den, g1SSWUIsoCurveCoeffA)
+	// den is non-zero as both A and Z are non-zero
+	x1 := api.DivUnchecked(num, den)
+	// x2 = Z * u² * x1
+	x2 := api.Mul(tv1, x1)
+	gx1 := g1EvalCurve(api, x1)
+	gx2 := g1EvalCurve(api, x2)
+
+	// exactly one of gx1 and gx2 is a square as Z is not a square. We obtain
+	// the choice and the square root from a hint and check that the square of
+	// the root matches the chosen value.
+	res, err := api.Compiler().NewHint(sswuSqrtHint, 2, gx1, gx2)
+	if err != nil {
+		panic(err)
+	}
+	isSquare, y := res[0], res[1]
+	api.AssertIsBoolean(isSquare)
+	api.AssertIsEqual(api.Mul(y, y), api.Select(isSquare, gx1, gx2))
+	x := api.Select(isSquare, x1, x2)
+
+	// fix the sign of y so that sgn0(u) == sgn0(y)
+	flip := api.Xor(sgn0(api, u), sgn0(api, y))
+	y = api.Select(flip, api.Neg(y), y)
+
+	return G1Affine{X: x, Y: y}
+}
+
+// sgn0 returns the parity of the canonical representation of x as defined in
+// RFC 9380 Section 4.1.
+func sgn0(api frontend.API, x frontend.Variable) frontend.Variable {
+	return api.ToBinary(x)[0]
+}
+
+// g1EvalCurve returns x³ + A'x + B'.
+func g1EvalCurve(api frontend.API, x frontend.Variable) frontend.Variable {
+	res := api.Add(api.Mul(x, x), g1SSWUIsoCurveCoeffA)
+	res = api.Mul(res, x)
+	return api.Add(res, g1SSWUIsoCurveCoeffB)
+}
+
+// g1Isogeny maps a point on E1' to E1 using the 2-isogeny.
+func g1Isogeny(api frontend.API, p G1Affine) G1Affine {
+	xNum := evalPolynomial(api, false, g1IsogenyXNumerator, p.X)
+	xDen := evalPolynomial(api, true, g1IsogenyXDenominator, p.X)
+	yNum := evalPolynomial(api, false, g1IsogenyYNumerator, p.X)
+	yDen := evalPolynomial(api, true, g1IsogenyYDenominator, p.X)
+
+	return G1Affine{
+		X: api.DivUnchecked(xNum, xDen),
+		Y: api.Mul(api.DivUnchecked(yNum, yDen), p.Y),
+	}
+}
+
+// evalPolynomial evaluates the polynomial with the given coefficients at x
+// using Horner's method. If monic is set, then the leading coefficient 1 is
+// implicit and omitted from coefficients.
+func evalPolynomial(api frontend.API, monic bool, coefficients []string, x frontend.Variable) frontend.Variable {
+	var res frontend.Variable = coefficients[len(coefficients)-1]
+	if monic {
+		res = api.Add(res, x)
+	}
+	for i := len(coefficients) - 2; i >= 0; i-- {
+		res = api.Add(api.Mul(res, x), coefficients[i])
+	}
+	return res
+}
+
+// ClearCofactor maps a point on E1 to G1 by multiplying it by the effective
+// cofactor h_eff = 1-x₀, where x₀ is the curve seed. It sets p to the result
+// and returns p.
+func (p *G1Affine) ClearCofactor(api frontend.API, q G1Affine) *G1Affine {
+	// [x₀]Q
+	var xq G1Affine
+	xq.scalarMulBySeed(api, &q)
+	// Q - [x₀]Q
+	xq.Neg(api, xq)
+	res := q
+	res.AddAssign(api, xq)
+	p.X, p.Y = res.X, res.Y
+	return p
+}
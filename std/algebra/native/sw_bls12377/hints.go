@@ -5,6 +5,8 @@ import (
 	"math/big"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fp"
 	"github.com/consensys/gnark/constraint/solver"
 )
 
@@ -13,6 +15,10 @@ func GetHints() []solver.Hint {
 		decomposeScalarG1,
 		decomposeScalarG1Simple,
 		decomposeScalarG2,
+		sswuSqrtHint,
+		sswuSqrtE2Hint,
+		isSquareHint,
+		isSquareE2Hint,
 	}
 }
 
@@ -88,3 +94,108 @@ func decomposeScalarG2(scalarField *big.Int, inputs []*big.Int, outputs []*big.I
 
 	return nil
 }
+
+// sswuSqrtHint returns 1 and the square root of the first input if it is a
+// square and 0 and the square root of the second input otherwise.
+func sswuSqrtHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 2 {
+		return fmt.Errorf("expecting two inputs")
+	}
+	if len(outputs) != 2 {
+		return fmt.Errorf("expecting two outputs")
+	}
+	var gx1, gx2, y fp.Element
+	gx1.SetBigInt(inputs[0])
+	gx2.SetBigInt(inputs[1])
+	if gx1.Legendre() != -1 {
+		outputs[0].SetUint64(1)
+		y.Sqrt(&gx1)
+	} else {
+		outputs[0].SetUint64(0)
+		if y.Sqrt(&gx2) == nil {
+			return fmt.Errorf("no square root")
+		}
+	}
+	y.BigInt(outputs[1])
+	return nil
+}
+
+// sswuSqrtE2Hint is the quadratic extension variant of sswuSqrtHint.
+func sswuSqrtE2Hint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 4 {
+		return fmt.Errorf("expecting four inputs")
+	}
+	if len(outputs) != 3 {
+		return fmt.Errorf("expecting three outputs")
+	}
+	var gx1, gx2, y bls12377.E2
+	gx1.A0.SetBigInt(inputs[0])
+	gx1.A1.SetBigInt(inputs[1])
+	gx2.A0.SetBigInt(inputs[2])
+	gx2.A1.SetBigInt(inputs[3])
+	if gx1.Legendre() != -1 {
+		outputs[0].SetUint64(1)
+		y.Sqrt(&gx1)
+	} else {
+		if gx2.Legendre() == -1 {
+			return fmt.Errorf("no square root")
+		}
+		outputs[0].SetUint64(0)
+		y.Sqrt(&gx2)
+	}
+	y.A0.BigInt(outputs[1])
+	y.A1.BigInt(outputs[2])
+	return nil
+}
+
+// isSquareHint returns 1 and the square root of the first input if it is a
+// square and 0 and the square root of the product of the inputs otherwise.
+// The second input must be a non-square.
+func isSquareHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 2 {
+		return fmt.Errorf("expecting two inputs")
+	}
+	if len(outputs) != 2 {
+		return fmt.Errorf("expecting two outputs")
+	}
+	var x, n, y fp.Element
+	x.SetBigInt(inputs[0])
+	n.SetBigInt(inputs[1])
+	if x.Legendre() != -1 {
+		outputs[0].SetUint64(1)
+	} else {
+		outputs[0].SetUint64(0)
+		x.Mul(&x, &n)
+	}
+	if y.Sqrt(&x) == nil {
+		return fmt.Errorf("no square root")
+	}
+	y.BigInt(outputs[1])
+	return nil
+}
+
+// isSquareE2Hint is the quadratic extension variant of isSquareHint. The inputs
+// are the coordinates of x and of the non-square.
+func isSquareE2Hint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 4 {
+		return fmt.Errorf("expecting four inputs")
+	}
+	if len(outputs) != 3 {
+		return fmt.Errorf("expecting three outputs")
+	}
+	var x, n, y bls12377.E2
+	x.A0.SetBigInt(inputs[0])
+	x.A1.SetBigInt(inputs[1])
+	n.A0.SetBigInt(inputs[2])
+	n.A1.SetBigInt(inputs[3])
+	if x.Legendre() != -1 {
+		outputs[0].SetUint64(1)
+	} else {
+		outputs[0].SetUint64(0)
+		x.Mul(&x, &n)
+	}
+	y.Sqrt(&x)
+	y.A0.BigInt(outputs[1])
+	y.A1.BigInt(outputs[2])
+	return nil
+}
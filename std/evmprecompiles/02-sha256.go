@@ -1,3 +1,27 @@
 package evmprecompiles
 
-// will implement later
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// SHA256 implements [SHA256] precompile contract at address 0x02.
+//
+// The length of the input is fixed at compile time. For inputs which length is
+// known only at proving time use [sha2.New] and
+// [hash.BinaryFixedLengthHasher.FixedLengthSum] directly.
+//
+// [SHA256]: https://ethereum.github.io/execution-specs/autoapi/ethereum/paris/vm/precompiled_contracts/sha256/index.html
+func SHA256(api frontend.API, data []uints.U8) [32]uints.U8 {
+	h, err := sha2.New(api)
+	if err != nil {
+		panic(fmt.Sprintf("new sha2: %v", err))
+	}
+	h.Write(data)
+	var res [32]uints.U8
+	copy(res[:], h.Sum())
+	return res
+}
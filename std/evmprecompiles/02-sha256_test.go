@@ -0,0 +1,51 @@
+package evmprecompiles
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+// input and output of the go-ethereum precompile tests for SHA256, RIPEMD160
+// and IDENTITY.
+const hashPrecompileInput = "38d18acb67d25c8bb9942764b62f18e17054f66a817bd4295423adf9ed98873e000000000000000000000000000000000000000000000000000000000000001b38d18acb67d25c8bb9942764b62f18e17054f66a817bd4295423adf9ed98873e789d1dd423d25f0772d2748d60f7e4b81bb14d086eba8e8e8efb6dcff8a4ae02"
+
+type hashPrecompileCircuit struct {
+	In       []uints.U8
+	Expected [32]uints.U8
+	isRIPEMD bool
+}
+
+func (c *hashPrecompileCircuit) Define(api frontend.API) error {
+	var res [32]uints.U8
+	if c.isRIPEMD {
+		res = RIPEMD160(api, c.In)
+	} else {
+		res = SHA256(api, c.In)
+	}
+	for i := range res {
+		api.AssertIsEqual(res[i].Val, c.Expected[i].Val)
+	}
+	return nil
+}
+
+func testHashPrecompile(t *testing.T, isRIPEMD bool, input, expected string) {
+	assert := test.NewAssert(t)
+	in, err := hex.DecodeString(input)
+	assert.NoError(err)
+	out, err := hex.DecodeString(expected)
+	assert.NoError(err)
+	circuit := hashPrecompileCircuit{In: make([]uints.U8, len(in)), isRIPEMD: isRIPEMD}
+	witness := hashPrecompileCircuit{In: uints.NewU8Array(in)}
+	copy(witness.Expected[:], uints.NewU8Array(out))
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
+
+func TestSHA256(t *testing.T) {
+	testHashPrecompile(t, false, hashPrecompileInput, "811c7003375852fabd0d362e40e68607a12bdabae61a7d068fe5fdd1dbbf2a5d")
+}
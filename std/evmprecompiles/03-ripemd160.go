@@ -0,0 +1,29 @@
+package evmprecompiles
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/ripemd160"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// RIPEMD160 implements [RIPEMD160] precompile contract at address 0x03.
+//
+// As the precompile, it returns the 20 byte digest left-padded with zeros to
+// 32 bytes. The length of the input is fixed at compile time.
+//
+// [RIPEMD160]: https://ethereum.github.io/execution-specs/autoapi/ethereum/paris/vm/precompiled_contracts/ripemd160/index.html
+func RIPEMD160(api frontend.API, data []uints.U8) [32]uints.U8 {
+	h, err := ripemd160.New(api)
+	if err != nil {
+		panic(fmt.Sprintf("new ripemd160: %v", err))
+	}
+	h.Write(data)
+	var res [32]uints.U8
+	for i := 0; i < 12; i++ {
+		res[i] = uints.NewU8(0)
+	}
+	copy(res[12:], h.Sum())
+	return res
+}
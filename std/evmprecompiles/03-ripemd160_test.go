@@ -0,0 +1,9 @@
+package evmprecompiles
+
+import (
+	"testing"
+)
+
+func TestRIPEMD160(t *testing.T) {
+	testHashPrecompile(t, true, hashPrecompileInput, "0000000000000000000000009215b8d9882ff46f0dfde6684d78e831467f65e6")
+}
@@ -1,3 +1,18 @@
 package evmprecompiles
 
-// not going to implement. It is trivial.
+import (
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// ID implements [IDENTITY] precompile contract at address 0x04.
+//
+// It returns a copy of the input and does not create any constraints. It is
+// provided for completeness, so that all precompiles can be dispatched in the
+// same way.
+//
+// [IDENTITY]: https://ethereum.github.io/execution-specs/autoapi/ethereum/paris/vm/precompiled_contracts/identity/index.html
+func ID(data []uints.U8) []uints.U8 {
+	res := make([]uints.U8, len(data))
+	copy(res, data)
+	return res
+}
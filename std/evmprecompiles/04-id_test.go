@@ -0,0 +1,34 @@
+package evmprecompiles
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type idCircuit struct {
+	In       []uints.U8
+	Expected []uints.U8
+}
+
+func (c *idCircuit) Define(api frontend.API) error {
+	res := ID(c.In)
+	for i := range res {
+		api.AssertIsEqual(res[i].Val, c.Expected[i].Val)
+	}
+	return nil
+}
+
+func TestID(t *testing.T) {
+	assert := test.NewAssert(t)
+	in, err := hex.DecodeString(hashPrecompileInput)
+	assert.NoError(err)
+	circuit := idCircuit{In: make([]uints.U8, len(in)), Expected: make([]uints.U8, len(in))}
+	witness := idCircuit{In: uints.NewU8Array(in), Expected: uints.NewU8Array(in)}
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
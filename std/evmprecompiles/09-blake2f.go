@@ -0,0 +1,103 @@
+package evmprecompiles
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+var blake2bIV = [8]uints.U64{
+	uints.NewU64(0x6a09e667f3bcc908), uints.NewU64(0xbb67ae8584caa73b),
+	uints.NewU64(0x3c6ef372fe94f82b), uints.NewU64(0xa54ff53a5f1d36f1),
+	uints.NewU64(0x510e527fade682d1), uints.NewU64(0x9b05688c2b3e6c1f),
+	uints.NewU64(0x1f83d9abfb41bd6b), uints.NewU64(0x5be0cd19137e2179),
+}
+
+var blake2bSigma = [10][16]int{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3},
+	{11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4},
+	{7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8},
+	{9, 0, 5, 7, 2, 4, 10, 15, 14, 1, 11, 12, 6, 8, 3, 13},
+	{2, 12, 6, 10, 0, 11, 8, 3, 4, 13, 7, 5, 15, 14, 1, 9},
+	{12, 5, 1, 15, 14, 13, 4, 10, 0, 7, 6, 3, 9, 2, 8, 11},
+	{13, 11, 7, 14, 12, 1, 3, 9, 5, 0, 15, 4, 8, 6, 2, 10},
+	{6, 15, 14, 9, 11, 3, 0, 8, 12, 2, 13, 7, 1, 4, 10, 5},
+	{10, 2, 8, 4, 7, 6, 1, 5, 15, 11, 9, 14, 3, 12, 13, 0},
+}
+
+// BLAKE2F implements [BLAKE2F] precompile contract at address 0x09.
+//
+// It computes the BLAKE2b compression function F with the state h, the message
+// block m, the offset counters t and the final block indicator flag f
+// (boolean), applying rounds rounds. All words are in the little-endian
+// encoding of the precompile input.
+//
+// As the number of rounds is a variable, the circuit applies maxRounds rounds
+// and keeps only the first rounds of them. The circuit is not satisfiable when
+// rounds > maxRounds. The cost is linear in maxRounds, BLAKE2b uses 12 rounds.
+//
+// [BLAKE2F]: https://eips.ethereum.org/EIPS/eip-152
+func BLAKE2F(api frontend.API, rounds frontend.Variable, h [8]uints.U64, m [16]uints.U64, t [2]uints.U64, f frontend.Variable, maxRounds int) [8]uints.U64 {
+	uapi, err := uints.New[uints.U64](api)
+	if err != nil {
+		panic(fmt.Sprintf("new uints api: %v", err))
+	}
+	api.AssertIsBoolean(f)
+	var v [16]uints.U64
+	copy(v[:8], h[:])
+	copy(v[8:], blake2bIV[:])
+	v[12] = uapi.Xor(v[12], t[0])
+	v[13] = uapi.Xor(v[13], t[1])
+	var fMask uints.U64
+	for i := range fMask {
+		fMask[i] = uints.U8{Val: api.Mul(f, 0xff)}
+	}
+	v[14] = uapi.Xor(v[14], fMask)
+
+	// isActive is 1 as long as the current round index is less than rounds.
+	var isActive frontend.Variable = 1
+	for i := 0; i < maxRounds; i++ {
+		isActive = api.Mul(isActive, api.Sub(1, api.IsZero(api.Sub(rounds, i))))
+		next := blake2bRound(uapi, v, m, &blake2bSigma[i%10])
+		for j := range v {
+			for k := range v[j] {
+				v[j][k].Val = api.Select(isActive, next[j][k].Val, v[j][k].Val)
+			}
+		}
+	}
+	// if isActive is still 1 then rounds >= maxRounds and we only accept
+	// rounds == maxRounds.
+	api.AssertIsEqual(api.Mul(isActive, api.Sub(rounds, maxRounds)), 0)
+
+	var res [8]uints.U64
+	for i := range res {
+		res[i] = uapi.Xor(h[i], v[i], v[i+8])
+	}
+	return res
+}
+
+func blake2bRound(uapi *uints.BinaryField[uints.U64], v [16]uints.U64, m [16]uints.U64, s *[16]int) [16]uints.U64 {
+	blake2bG(uapi, &v, 0, 4, 8, 12, m[s[0]], m[s[1]])
+	blake2bG(uapi, &v, 1, 5, 9, 13, m[s[2]], m[s[3]])
+	blake2bG(uapi, &v, 2, 6, 10, 14, m[s[4]], m[s[5]])
+	blake2bG(uapi, &v, 3, 7, 11, 15, m[s[6]], m[s[7]])
+	blake2bG(uapi, &v, 0, 5, 10, 15, m[s[8]], m[s[9]])
+	blake2bG(uapi, &v, 1, 6, 11, 12, m[s[10]], m[s[11]])
+	blake2bG(uapi, &v, 2, 7, 8, 13, m[s[12]], m[s[13]])
+	blake2bG(uapi, &v, 3, 4, 9, 14, m[s[14]], m[s[15]])
+	return v
+}
+
+// blake2bG is the BLAKE2b mixing function G.
+func blake2bG(uapi *uints.BinaryField[uints.U64], v *[16]uints.U64, a, b, c, d int, x, y uints.U64) {
+	v[a] = uapi.Add(v[a], v[b], x)
+	v[d] = uapi.Lrot(uapi.Xor(v[d], v[a]), -32)
+	v[c] = uapi.Add(v[c], v[d])
+	v[b] = uapi.Lrot(uapi.Xor(v[b], v[c]), -24)
+	v[a] = uapi.Add(v[a], v[b], y)
+	v[d] = uapi.Lrot(uapi.Xor(v[d], v[a]), -16)
+	v[c] = uapi.Add(v[c], v[d])
+	v[b] = uapi.Lrot(uapi.Xor(v[b], v[c]), -63)
+}
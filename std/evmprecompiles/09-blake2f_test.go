@@ -0,0 +1,94 @@
+package evmprecompiles
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type blake2fCircuit struct {
+	Rounds    frontend.Variable
+	H         [8]uints.U64
+	M         [16]uints.U64
+	T         [2]uints.U64
+	F         frontend.Variable
+	Expected  [8]uints.U64
+	maxRounds int
+}
+
+func (c *blake2fCircuit) Define(api frontend.API) error {
+	res := BLAKE2F(api, c.Rounds, c.H, c.M, c.T, c.F, c.maxRounds)
+	for i := range res {
+		for j := range res[i] {
+			api.AssertIsEqual(res[i][j].Val, c.Expected[i][j].Val)
+		}
+	}
+	return nil
+}
+
+// blake2fAssignment parses the 213 bytes precompile input and the 64 bytes
+// output.
+func blake2fAssignment(input, output string) (*blake2fCircuit, error) {
+	in, err := hex.DecodeString(input)
+	if err != nil {
+		return nil, err
+	}
+	out, err := hex.DecodeString(output)
+	if err != nil {
+		return nil, err
+	}
+	if len(in) != 213 || len(out) != 64 {
+		return nil, fmt.Errorf("invalid length")
+	}
+	word := func(b []byte) uints.U64 { return uints.NewU64(binary.LittleEndian.Uint64(b)) }
+	res := &blake2fCircuit{
+		Rounds: binary.BigEndian.Uint32(in[0:4]),
+		F:      in[212],
+	}
+	for i := range res.H {
+		res.H[i] = word(in[4+8*i:])
+		res.Expected[i] = word(out[8*i:])
+	}
+	for i := range res.M {
+		res.M[i] = word(in[68+8*i:])
+	}
+	for i := range res.T {
+		res.T[i] = word(in[196+8*i:])
+	}
+	return res, nil
+}
+
+func TestBLAKE2F(t *testing.T) {
+	assert := test.NewAssert(t)
+	// go-ethereum (EIP-152) test vectors 4 to 7.
+	// state h, message block "abc" and offset counter t = 3
+	input := "48c9bdf267e6096a3ba7ca8485ae67bb2bf894fe72f36e3cf1361d5f3af54fa5d182e6ad7f520e511f6c3e2b8c68059b6bbd41fbabd9831f79217e1319cde05b" +
+		"616263" + strings.Repeat("00", 125) +
+		"03" + strings.Repeat("00", 15)
+	vectors := []struct {
+		rounds, f, output string
+	}{
+		{"00000000", "01", "08c9bcf367e6096a3ba7ca8485ae67bb2bf894fe72f36e3cf1361d5f3af54fa5d282e6ad7f520e511f6c3e2b8c68059b9442be0454267ce079217e1319cde05b"},
+		{"0000000c", "01", "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923"},
+		{"0000000c", "00", "75ab69d3190a562c51aef8d88f1c2775876944407270c42c9844252c26d2875298743e7f6d5ea2f2d3e8d226039cd31b4e426ac4f2d3d666a610c2116fde4735"},
+		{"00000001", "01", "b63a380cb2897d521994a85234ee2c181b5f844d2c624c002677e9703449d2fba551b3a8333bcdf5f2f7e08993d53923de3d64fcc68c034e717b9293fed7a421"},
+	}
+	for i, v := range vectors {
+		witness, err := blake2fAssignment(v.rounds+input+v.f, v.output)
+		assert.NoError(err)
+		err = test.IsSolved(&blake2fCircuit{maxRounds: 12}, witness, ecc.BN254.ScalarField())
+		assert.NoError(err, "vector %d", i)
+	}
+	// more rounds than supported by the circuit
+	witness, err := blake2fAssignment("0000000d"+input+"01", vectors[1].output)
+	assert.NoError(err)
+	err = test.IsSolved(&blake2fCircuit{maxRounds: 12}, witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
@@ -0,0 +1,75 @@
+// Package expand implements in-circuit message expansion functions as defined
+// in [RFC 9380] for hashing to finite fields and elliptic curves.
+//
+// [RFC 9380]: https://datatracker.ietf.org/doc/html/rfc9380#section-5.3
+package expand
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+const sha256BlockSize = 64
+const sha256Size = 32
+
+// ExpandMsgXmd expands the message msg into lenInBytes pseudorandom bytes using
+// SHA2-256 as defined in RFC 9380 Section 5.3.1. The domain separation tag dst
+// is a constant known at circuit compile time and has to be at most 255 bytes
+// long.
+func ExpandMsgXmd(api frontend.API, msg []uints.U8, dst []byte, lenInBytes int) ([]uints.U8, error) {
+	if len(dst) > 255 {
+		return nil, fmt.Errorf("invalid domain separation tag length %d", len(dst))
+	}
+	ell := (lenInBytes + sha256Size - 1) / sha256Size
+	if ell > 255 || lenInBytes > 65535 || lenInBytes <= 0 {
+		return nil, fmt.Errorf("invalid output length %d", lenInBytes)
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, fmt.Errorf("new uints api: %w", err)
+	}
+	dstPrime := uints.NewU8Array(append(append([]byte{}, dst...), byte(len(dst))))
+
+	// b_0 = H(Z_pad || msg || l_i_b_str || I2OSP(0, 1) || DST_prime)
+	h, err := sha2.New(api)
+	if err != nil {
+		return nil, fmt.Errorf("new hasher: %w", err)
+	}
+	h.Write(uints.NewU8Array(make([]byte, sha256BlockSize)))
+	h.Write(msg)
+	h.Write(uints.NewU8Array([]byte{byte(lenInBytes >> 8), byte(lenInBytes), 0}))
+	h.Write(dstPrime)
+	b0 := h.Sum()
+
+	// b_1 = H(b_0 || I2OSP(1, 1) || DST_prime)
+	h, err = sha2.New(api)
+	if err != nil {
+		return nil, fmt.Errorf("new hasher: %w", err)
+	}
+	h.Write(b0)
+	h.Write([]uints.U8{uints.NewU8(1)})
+	h.Write(dstPrime)
+	bi := h.Sum()
+
+	res := make([]uints.U8, 0, ell*sha256Size)
+	res = append(res, bi...)
+	for i := 2; i <= ell; i++ {
+		// b_i = H(strxor(b_0, b_(i - 1)) || I2OSP(i, 1) || DST_prime)
+		h, err = sha2.New(api)
+		if err != nil {
+			return nil, fmt.Errorf("new hasher: %w", err)
+		}
+		for j := 0; j < sha256Size; j += 4 {
+			x := uapi.Xor(uapi.PackLSB(b0[j:j+4]...), uapi.PackLSB(bi[j:j+4]...))
+			h.Write(uapi.UnpackLSB(x))
+		}
+		h.Write([]uints.U8{uints.NewU8(uint8(i))})
+		h.Write(dstPrime)
+		bi = h.Sum()
+		res = append(res, bi...)
+	}
+	return res[:lenInBytes], nil
+}
@@ -0,0 +1,93 @@
+package expand
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/field/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type expandMsgXmdCircuit struct {
+	Msg      []uints.U8
+	Expected []uints.U8
+
+	dst []byte
+}
+
+func (c *expandMsgXmdCircuit) Define(api frontend.API) error {
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return err
+	}
+	res, err := ExpandMsgXmd(api, c.Msg, c.dst, len(c.Expected))
+	if err != nil {
+		return err
+	}
+	for i := range c.Expected {
+		uapi.ByteAssertEq(c.Expected[i], res[i])
+	}
+	return nil
+}
+
+func TestExpandMsgXmd(t *testing.T) {
+	assert := test.NewAssert(t)
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	for _, msgLen := range []int{0, 3, 32, 133} {
+		for _, outLen := range []int{32, 64, 128, 255} {
+			msgLen, outLen := msgLen, outLen
+			assert.Run(func(assert *test.Assert) {
+				msg := make([]byte, msgLen)
+				_, err := rand.Read(msg)
+				assert.NoError(err)
+				expected, err := hash.ExpandMsgXmd(msg, dst, outLen)
+				assert.NoError(err)
+				circuit := &expandMsgXmdCircuit{
+					Msg:      make([]uints.U8, msgLen),
+					Expected: make([]uints.U8, outLen),
+					dst:      dst,
+				}
+				witness := &expandMsgXmdCircuit{
+					Msg:      uints.NewU8Array(msg),
+					Expected: uints.NewU8Array(expected),
+				}
+				err = test.IsSolved(circuit, witness, ecc.BN254.ScalarField())
+				assert.NoError(err)
+			}, fmt.Sprintf("msg=%d/out=%d", msgLen, outLen))
+		}
+	}
+}
+
+func TestExpandMsgXmdVectors(t *testing.T) {
+	// test vectors from RFC 9380 Appendix K.1
+	assert := test.NewAssert(t)
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	vectors := []struct {
+		msg      string
+		expected string
+	}{
+		{"", "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235"},
+		{"abc", "d8ccab23b5985ccea865c6c97b6e5b8350e794e603b4b97902f53a8a0d605615"},
+		{"abcdef0123456789", "eff31487c770a893cfb36f912fbfcbff40d5661771ca4b2cb4eafe524333f5c1"},
+	}
+	for _, v := range vectors {
+		expected, err := hex.DecodeString(v.expected)
+		assert.NoError(err)
+		circuit := &expandMsgXmdCircuit{
+			Msg:      make([]uints.U8, len(v.msg)),
+			Expected: make([]uints.U8, len(expected)),
+			dst:      dst,
+		}
+		witness := &expandMsgXmdCircuit{
+			Msg:      uints.NewU8Array([]byte(v.msg)),
+			Expected: uints.NewU8Array(expected),
+		}
+		err = test.IsSolved(circuit, witness, ecc.BN254.ScalarField())
+		assert.NoError(err, v.msg)
+	}
+}
@@ -0,0 +1,127 @@
+package hash
+
+import (
+	"errors"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// Compressor is a 2-to-1 compression function over native field elements. It
+// is the building block of [NewMerkleDamgardHasher].
+type Compressor interface {
+	Compress(left, right frontend.Variable) frontend.Variable
+}
+
+type merkleDamgardHasher struct {
+	state frontend.Variable
+	iv    frontend.Variable
+	f     Compressor
+	api   frontend.API
+}
+
+// NewMerkleDamgardHasher returns a [FieldHasher] which computes the hash of
+// the inputs by iteratively applying the compression function f:
+//
+//	h_0 = iv, h_{i+1} = f(h_i, x_i)
+//
+// and returns the final state. As for MiMC, the construction is subject to
+// length extension and the caller should use domain separation when needed.
+func NewMerkleDamgardHasher(api frontend.API, f Compressor, iv frontend.Variable) FieldHasher {
+	return &merkleDamgardHasher{
+		state: iv,
+		iv:    iv,
+		f:     f,
+		api:   api,
+	}
+}
+
+func (h *merkleDamgardHasher) Reset() {
+	h.state = h.iv
+}
+
+func (h *merkleDamgardHasher) Write(data ...frontend.Variable) {
+	for _, d := range data {
+		h.state = h.f.Compress(h.state, d)
+	}
+}
+
+func (h *merkleDamgardHasher) Sum() frontend.Variable {
+	return h.state
+}
+
+// NativeCompressor is the out-of-circuit counterpart of [Compressor].
+type NativeCompressor interface {
+	Compress(left, right *big.Int) *big.Int
+}
+
+type nativeMerkleDamgardHasher struct {
+	modulus *big.Int
+	state   *big.Int
+	iv      *big.Int
+	f       NativeCompressor
+	buf     []byte
+}
+
+// NewNativeMerkleDamgardHasher returns the out-of-circuit counterpart of
+// [NewMerkleDamgardHasher] implementing the standard library hash interface.
+// The written bytes are interpreted as big-endian encoded field elements of
+// [hash.Hash.BlockSize] bytes each, the same as for the MiMC implementation in
+// gnark-crypto. Writing a non-canonical element returns an error and leaves
+// the hasher unchanged. A trailing partial block is left-padded with zeros
+// by Sum.
+func NewNativeMerkleDamgardHasher(modulus *big.Int, f NativeCompressor, iv *big.Int) hash.Hash {
+	return &nativeMerkleDamgardHasher{
+		modulus: modulus,
+		state:   new(big.Int).Set(iv),
+		iv:      new(big.Int).Set(iv),
+		f:       f,
+	}
+}
+
+func (h *nativeMerkleDamgardHasher) Write(p []byte) (int, error) {
+	buf := append(h.buf[:len(h.buf):len(h.buf)], p...)
+	bs := h.BlockSize()
+	n := len(buf) - len(buf)%bs
+	// check all the complete blocks first so that an error leaves the hasher
+	// unchanged.
+	var x big.Int
+	for i := 0; i < n; i += bs {
+		if x.SetBytes(buf[i:i+bs]).Cmp(h.modulus) >= 0 {
+			return 0, errors.New("non-canonical field element")
+		}
+	}
+	for i := 0; i < n; i += bs {
+		h.state = h.f.Compress(h.state, x.SetBytes(buf[i:i+bs]))
+	}
+	h.buf = buf[n:]
+	return len(p), nil
+}
+
+// Sum appends the current hash to b. A trailing partial block is interpreted
+// as a big-endian encoded field element, i.e. it is left-padded with zeros,
+// and compressed into a copy of the state, so that the hasher can still be
+// written to afterwards.
+func (h *nativeMerkleDamgardHasher) Sum(b []byte) []byte {
+	state := h.state
+	if len(h.buf) != 0 {
+		state = h.f.Compress(state, new(big.Int).SetBytes(h.buf))
+	}
+	res := make([]byte, h.Size())
+	state.FillBytes(res)
+	return append(b, res...)
+}
+
+func (h *nativeMerkleDamgardHasher) Reset() {
+	h.state = new(big.Int).Set(h.iv)
+	h.buf = nil
+}
+
+func (h *nativeMerkleDamgardHasher) Size() int {
+	return (h.modulus.BitLen() + 7) / 8
+}
+
+func (h *nativeMerkleDamgardHasher) BlockSize() int {
+	return h.Size()
+}
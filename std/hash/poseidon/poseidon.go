@@ -0,0 +1,53 @@
+// Package poseidon implements a SNARK-friendly field hasher using the
+// Poseidon permutation.
+//
+// The hash function is a Merkle-Damgård construction over the 2-to-1
+// compression function of [poseidon.Permutation.Compress] with a zero
+// initial value. It is registered under the name [HashName] in
+// [github.com/consensys/gnark/std/hash] so that it can be retrieved with
+// [hash.GetFieldHasher].
+//
+// The out-of-circuit counterpart is provided by [NewNativeHasher].
+//
+// NB! The Merkle-Damgård construction is vulnerable to length extension
+// attacks, see the documentation of [github.com/consensys/gnark/std/hash/mimc]
+// for considerations.
+package poseidon
+
+import (
+	stdhash "hash"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/permutation/poseidon"
+)
+
+// HashName is the name under which the hasher is registered.
+const HashName = "poseidon"
+
+func init() {
+	hash.Register(HashName, func(api frontend.API) (hash.FieldHasher, error) {
+		return NewMerkleDamgardHasher(api)
+	})
+}
+
+// NewMerkleDamgardHasher returns a Poseidon based hasher for the native
+// field of the circuit.
+func NewMerkleDamgardHasher(api frontend.API) (hash.FieldHasher, error) {
+	f, err := poseidon.NewPoseidon(api)
+	if err != nil {
+		return nil, err
+	}
+	return hash.NewMerkleDamgardHasher(api, f, 0), nil
+}
+
+// NewNativeHasher returns the out-of-circuit counterpart of
+// [NewMerkleDamgardHasher] for the field of the given modulus.
+func NewNativeHasher(modulus *big.Int) (stdhash.Hash, error) {
+	params, err := poseidon.NewDefaultParameters(modulus, 2)
+	if err != nil {
+		return nil, err
+	}
+	return hash.NewNativeMerkleDamgardHasher(modulus, params, new(big.Int)), nil
+}
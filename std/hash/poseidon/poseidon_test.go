@@ -0,0 +1,62 @@
+package poseidon
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/test"
+)
+
+type hashCircuit struct {
+	Data     [5]frontend.Variable
+	Expected frontend.Variable `gnark:",public"`
+}
+
+func (c *hashCircuit) Define(api frontend.API) error {
+	h, err := hash.GetFieldHasher(HashName, api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.Data[:]...)
+	api.AssertIsEqual(h.Sum(), c.Expected)
+	// hasher must be reusable after reset
+	h.Reset()
+	h.Write(c.Data[:]...)
+	api.AssertIsEqual(h.Sum(), c.Expected)
+	return nil
+}
+
+func TestHash(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BLS12_377, ecc.BLS12_381, ecc.BW6_761, ecc.BW6_633, ecc.BLS24_315, ecc.BLS24_317} {
+		modulus := curve.ScalarField()
+		h, err := NewNativeHasher(modulus)
+		assert.NoError(err)
+		var witness hashCircuit
+		buf := make([]byte, h.BlockSize())
+		for i := range witness.Data {
+			var x big.Int
+			x.Sub(modulus, big.NewInt(int64(i+1)))
+			witness.Data[i] = x
+			x.FillBytes(buf)
+			_, err := h.Write(buf)
+			assert.NoError(err)
+		}
+		witness.Expected = new(big.Int).SetBytes(h.Sum(nil))
+		assert.CheckCircuit(&hashCircuit{}, test.WithValidAssignment(&witness), test.WithCurves(curve))
+	}
+}
+
+func TestNativeNonCanonical(t *testing.T) {
+	assert := test.NewAssert(t)
+	modulus := ecc.BN254.ScalarField()
+	h, err := NewNativeHasher(modulus)
+	assert.NoError(err)
+	buf := make([]byte, h.BlockSize())
+	modulus.FillBytes(buf)
+	_, err = h.Write(buf)
+	assert.Error(err)
+}
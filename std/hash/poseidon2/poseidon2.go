@@ -0,0 +1,53 @@
+// Package poseidon2 implements a SNARK-friendly field hasher using the
+// Poseidon2 permutation.
+//
+// The hash function is a Merkle-Damgård construction over the 2-to-1
+// compression function of [poseidon2.Permutation.Compress] with a zero
+// initial value. It is registered under the name [HashName] in
+// [github.com/consensys/gnark/std/hash] so that it can be retrieved with
+// [hash.GetFieldHasher].
+//
+// The out-of-circuit counterpart is provided by [NewNativeHasher].
+//
+// NB! The Merkle-Damgård construction is vulnerable to length extension
+// attacks, see the documentation of [github.com/consensys/gnark/std/hash/mimc]
+// for considerations.
+package poseidon2
+
+import (
+	stdhash "hash"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/permutation/poseidon2"
+)
+
+// HashName is the name under which the hasher is registered.
+const HashName = "poseidon2"
+
+func init() {
+	hash.Register(HashName, func(api frontend.API) (hash.FieldHasher, error) {
+		return NewMerkleDamgardHasher(api)
+	})
+}
+
+// NewMerkleDamgardHasher returns a Poseidon2 based hasher for the native
+// field of the circuit.
+func NewMerkleDamgardHasher(api frontend.API) (hash.FieldHasher, error) {
+	f, err := poseidon2.NewPoseidon2(api)
+	if err != nil {
+		return nil, err
+	}
+	return hash.NewMerkleDamgardHasher(api, f, 0), nil
+}
+
+// NewNativeHasher returns the out-of-circuit counterpart of
+// [NewMerkleDamgardHasher] for the field of the given modulus.
+func NewNativeHasher(modulus *big.Int) (stdhash.Hash, error) {
+	params, err := poseidon2.NewDefaultParameters(modulus, 2)
+	if err != nil {
+		return nil, err
+	}
+	return hash.NewNativeMerkleDamgardHasher(modulus, params, new(big.Int)), nil
+}
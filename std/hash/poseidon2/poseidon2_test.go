@@ -0,0 +1,91 @@
+package poseidon2
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/test"
+)
+
+type hashCircuit struct {
+	Data     [5]frontend.Variable
+	Expected frontend.Variable `gnark:",public"`
+}
+
+func (c *hashCircuit) Define(api frontend.API) error {
+	h, err := hash.GetFieldHasher(HashName, api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.Data[:]...)
+	api.AssertIsEqual(h.Sum(), c.Expected)
+	// hasher must be reusable after reset
+	h.Reset()
+	h.Write(c.Data[:]...)
+	api.AssertIsEqual(h.Sum(), c.Expected)
+	return nil
+}
+
+func TestHash(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BLS12_377, ecc.BLS12_381, ecc.BW6_761, ecc.BW6_633, ecc.BLS24_315, ecc.BLS24_317} {
+		modulus := curve.ScalarField()
+		h, err := NewNativeHasher(modulus)
+		assert.NoError(err)
+		var witness hashCircuit
+		buf := make([]byte, h.BlockSize())
+		for i := range witness.Data {
+			var x big.Int
+			x.Sub(modulus, big.NewInt(int64(i+1)))
+			witness.Data[i] = x
+			x.FillBytes(buf)
+			_, err := h.Write(buf)
+			assert.NoError(err)
+		}
+		witness.Expected = new(big.Int).SetBytes(h.Sum(nil))
+		assert.CheckCircuit(&hashCircuit{}, test.WithValidAssignment(&witness), test.WithCurves(curve))
+	}
+}
+
+func TestNativeNonCanonical(t *testing.T) {
+	assert := test.NewAssert(t)
+	modulus := ecc.BN254.ScalarField()
+	h, err := NewNativeHasher(modulus)
+	assert.NoError(err)
+	buf := make([]byte, h.BlockSize())
+	modulus.FillBytes(buf)
+	_, err = h.Write(buf)
+	assert.Error(err)
+
+	// a valid block followed by a non-canonical one is rejected as a whole
+	h.Reset()
+	empty := h.Sum(nil)
+	valid := make([]byte, h.BlockSize())
+	valid[len(valid)-1] = 1
+	n, err := h.Write(append(valid, buf...))
+	assert.Error(err)
+	assert.Equal(0, n)
+	assert.Equal(empty, h.Sum(nil))
+}
+
+func TestNativePartialBlock(t *testing.T) {
+	assert := test.NewAssert(t)
+	modulus := ecc.BN254.ScalarField()
+	h, err := NewNativeHasher(modulus)
+	assert.NoError(err)
+	// the partial block is left-padded with zeros
+	_, err = h.Write([]byte{1, 2, 3})
+	assert.NoError(err)
+	partial := h.Sum(nil)
+	assert.Equal(partial, h.Sum(nil), "Sum must not change the state")
+
+	padded := make([]byte, h.BlockSize())
+	copy(padded[len(padded)-3:], []byte{1, 2, 3})
+	h.Reset()
+	_, err = h.Write(padded)
+	assert.NoError(err)
+	assert.Equal(partial, h.Sum(nil))
+}
@@ -0,0 +1,161 @@
+// Package ripemd160 implements in-circuit RIPEMD-160 hash computation.
+//
+// RIPEMD-160 is not recommended for new applications. It is provided for
+// compatibility with existing protocols, for example the RIPEMD160 precompile
+// in Ethereum and Bitcoin addresses.
+//
+// For the reference implementation, see [golang.org/x/crypto/ripemd160].
+package ripemd160
+
+import (
+	"encoding/binary"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+var _seed = uints.NewU32Array([]uint32{
+	0x67452301, 0xefcdab89, 0x98badcfe, 0x10325476, 0xc3d2e1f0,
+})
+
+// message word indices and rotation amounts for the left line
+var _n = [80]int{
+	0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15,
+	7, 4, 13, 1, 10, 6, 15, 3, 12, 0, 9, 5, 2, 14, 11, 8,
+	3, 10, 14, 4, 9, 15, 8, 1, 2, 7, 0, 6, 13, 11, 5, 12,
+	1, 9, 11, 10, 0, 8, 12, 4, 13, 3, 7, 15, 14, 5, 6, 2,
+	4, 0, 5, 9, 7, 12, 2, 10, 14, 1, 3, 8, 11, 6, 15, 13,
+}
+
+var _r = [80]int{
+	11, 14, 15, 12, 5, 8, 7, 9, 11, 13, 14, 15, 6, 7, 9, 8,
+	7, 6, 8, 13, 11, 9, 7, 15, 7, 12, 15, 9, 11, 7, 13, 12,
+	11, 13, 6, 7, 14, 9, 13, 15, 14, 8, 13, 6, 5, 12, 7, 5,
+	11, 12, 14, 15, 14, 15, 9, 8, 9, 14, 5, 6, 8, 6, 5, 12,
+	9, 15, 5, 11, 6, 8, 13, 12, 5, 12, 13, 14, 11, 8, 5, 6,
+}
+
+// same for the right (parallel) line
+var n_ = [80]int{
+	5, 14, 7, 0, 9, 2, 11, 4, 13, 6, 15, 8, 1, 10, 3, 12,
+	6, 11, 3, 7, 0, 13, 5, 10, 14, 15, 8, 12, 4, 9, 1, 2,
+	15, 5, 1, 3, 7, 14, 6, 9, 11, 8, 12, 2, 10, 0, 4, 13,
+	8, 6, 4, 1, 3, 11, 15, 0, 5, 12, 2, 13, 9, 7, 10, 14,
+	12, 15, 10, 4, 1, 5, 8, 7, 6, 2, 13, 14, 0, 3, 9, 11,
+}
+
+var r_ = [80]int{
+	8, 9, 9, 11, 13, 15, 15, 5, 7, 7, 8, 11, 14, 14, 12, 6,
+	9, 13, 15, 7, 12, 8, 9, 11, 7, 7, 12, 7, 6, 15, 13, 11,
+	9, 7, 15, 11, 8, 6, 6, 14, 12, 13, 5, 14, 13, 13, 7, 5,
+	15, 5, 8, 11, 14, 14, 6, 14, 6, 9, 12, 9, 12, 5, 15, 8,
+	8, 5, 12, 9, 12, 5, 14, 6, 8, 13, 6, 5, 15, 13, 11, 11,
+}
+
+var _k = uints.NewU32Array([]uint32{0x00000000, 0x5a827999, 0x6ed9eba1, 0x8f1bbcdc, 0xa953fd4e})
+var k_ = uints.NewU32Array([]uint32{0x50a28be6, 0x5c4dd124, 0x6d703ef3, 0x7a6d76e9, 0x00000000})
+
+type digest struct {
+	uapi *uints.BinaryField[uints.U32]
+	in   []uints.U8
+}
+
+// New returns a new RIPEMD-160 hasher.
+func New(api frontend.API) (hash.BinaryHasher, error) {
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, err
+	}
+	return &digest{uapi: uapi}, nil
+}
+
+func (d *digest) Write(data []uints.U8) {
+	d.in = append(d.in, data...)
+}
+
+func (d *digest) Size() int { return 20 }
+
+func (d *digest) Sum() []uints.U8 {
+	// padding is the same as for MD4 and SHA2, but the length is encoded
+	// little-endian.
+	zeroPadLen := 55 - len(d.in)%64
+	if zeroPadLen < 0 {
+		zeroPadLen += 64
+	}
+	buf := make([]uints.U8, len(d.in), len(d.in)+9+zeroPadLen)
+	copy(buf, d.in)
+	buf = append(buf, uints.NewU8(0x80))
+	buf = append(buf, uints.NewU8Array(make([]uint8, zeroPadLen))...)
+	lenbuf := make([]uint8, 8)
+	binary.LittleEndian.PutUint64(lenbuf, uint64(8*len(d.in)))
+	buf = append(buf, uints.NewU8Array(lenbuf)...)
+
+	var runningDigest [5]uints.U32
+	copy(runningDigest[:], _seed)
+	var block [64]uints.U8
+	for i := 0; i < len(buf)/64; i++ {
+		copy(block[:], buf[i*64:(i+1)*64])
+		runningDigest = Compress(d.uapi, runningDigest, block)
+	}
+	var ret []uints.U8
+	for i := range runningDigest {
+		ret = append(ret, d.uapi.UnpackLSB(runningDigest[i])...)
+	}
+	return ret
+}
+
+// Compress applies the RIPEMD-160 compression function on a single block.
+func Compress(uapi *uints.BinaryField[uints.U32], currentHash [5]uints.U32, p [64]uints.U8) (newHash [5]uints.U32) {
+	var x [16]uints.U32
+	for i := range x {
+		x[i] = uapi.PackLSB(p[4*i], p[4*i+1], p[4*i+2], p[4*i+3])
+	}
+	a, b, c, d, e := currentHash[0], currentHash[1], currentHash[2], currentHash[3], currentHash[4]
+	aa, bb, cc, dd, ee := a, b, c, d, e
+	for i := 0; i < 80; i++ {
+		round := i / 16
+		// left line
+		alpha := uapi.Add(a, f(uapi, round, b, c, d), x[_n[i]], _k[round])
+		alpha = uapi.Add(uapi.Lrot(alpha, _r[i]), e)
+		a, b, c, d, e = e, alpha, b, uapi.Lrot(c, 10), d
+		// right line
+		alpha = uapi.Add(aa, f(uapi, 4-round, bb, cc, dd), x[n_[i]], k_[round])
+		alpha = uapi.Add(uapi.Lrot(alpha, r_[i]), ee)
+		aa, bb, cc, dd, ee = ee, alpha, bb, uapi.Lrot(cc, 10), dd
+	}
+	newHash[0] = uapi.Add(currentHash[1], c, dd)
+	newHash[1] = uapi.Add(currentHash[2], d, ee)
+	newHash[2] = uapi.Add(currentHash[3], e, aa)
+	newHash[3] = uapi.Add(currentHash[4], a, bb)
+	newHash[4] = uapi.Add(currentHash[0], b, cc)
+	return
+}
+
+// f returns the boolean function of the given round.
+func f(uapi *uints.BinaryField[uints.U32], round int, x, y, z uints.U32) uints.U32 {
+	switch round {
+	case 0:
+		return uapi.Xor(x, y, z)
+	case 1:
+		// the terms have disjoint bits, so OR is XOR
+		return uapi.Xor(uapi.And(x, y), uapi.And(uapi.Not(x), z))
+	case 2:
+		return uapi.Xor(or(uapi, x, uapi.Not(y)), z)
+	case 3:
+		return uapi.Xor(uapi.And(x, z), uapi.And(y, uapi.Not(z)))
+	case 4:
+		return uapi.Xor(x, or(uapi, y, uapi.Not(z)))
+	default:
+		panic("invalid round")
+	}
+}
+
+// or computes a | b = a ^ b ^ (a & b).
+func or(uapi *uints.BinaryField[uints.U32], a, b uints.U32) uints.U32 {
+	return uapi.Xor(a, b, uapi.And(a, b))
+}
+
+func (d *digest) Reset() {
+	d.in = nil
+}
@@ -2,4 +2,9 @@
 // Keccak f-[1600] permutation function.
 //
 // Instances correspond golang.org/x/crypto/sha3, except SHA224, which is not x64 compatible.
+//
+// All instances implement [github.com/consensys/gnark/std/hash.BinaryFixedLengthHasher].
+// The number of bytes written to the hasher fixes the maximum input length at
+// compile time, while FixedLengthSum computes the digest of an input which
+// length is only known at proving time.
 package sha3
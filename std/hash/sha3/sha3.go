@@ -1,11 +1,13 @@
 package sha3
 
 import (
+	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/math/uints"
 	"github.com/consensys/gnark/std/permutation/keccakf"
 )
 
 type digest struct {
+	api       frontend.API
 	uapi      *uints.BinaryField[uints.U64]
 	state     [25]uints.U64 // 1600 bits state: 25 x 64
 	in        []uints.U8    // input to be digested
@@ -32,6 +34,76 @@ func (d *digest) Sum() []uints.U8 {
 	return d.squeezeBlocks()
 }
 
+// FixedLengthSum returns the digest of the first length bytes written to the
+// hasher. The total number of bytes written defines the maximum length at
+// compile time and the circuit is not satisfiable if length exceeds it.
+//
+// The padding is placed in-circuit depending on length and all the blocks
+// which could be used for the maximum length are absorbed. The digest is taken
+// from the state after absorbing the last block used for length bytes.
+func (d *digest) FixedLengthSum(length frontend.Variable) []uints.U8 {
+	nbBlocks := len(d.in)/d.rate + 1
+	padded := make([]uints.U8, nbBlocks*d.rate)
+
+	// isPast is 1 when the current position is at or after length. As length
+	// can take only one value, summing the equality indicators gives a
+	// monotone 0/1 flag.
+	var isPast frontend.Variable = 0
+	// isWithin is 1 when the padding starts at or before len(d.in), i.e. when
+	// length is at most the number of bytes written.
+	var isWithin frontend.Variable = 0
+	isLastBlock := make([]frontend.Variable, nbBlocks)
+	var isPastBlockStart frontend.Variable = 0
+	for i := range padded {
+		isPadStart := d.api.IsZero(d.api.Sub(i, length))
+		isPast = d.api.Add(isPast, isPadStart)
+		if i <= len(d.in) {
+			isWithin = d.api.Add(isWithin, isPadStart)
+		}
+		var v frontend.Variable = 0
+		if i < len(d.in) {
+			v = d.api.Mul(d.api.Sub(1, isPast), d.in[i].Val)
+		}
+		v = d.api.Add(v, d.api.Mul(isPadStart, d.dsbyte))
+		if (i+1)%d.rate == 0 {
+			// the last byte of the block. The block is the last block when the
+			// padding starts in it.
+			b := i / d.rate
+			isLastBlock[b] = d.api.Sub(isPast, isPastBlockStart)
+			isPastBlockStart = isPast
+			v = d.api.Add(v, d.api.Mul(isLastBlock[b], 0x80))
+		}
+		padded[i] = uints.U8{Val: v}
+	}
+	// length must be at most the number of bytes written. Checking isPast only
+	// would allow the padding to start after the written bytes, hashing zeros.
+	d.api.AssertIsEqual(isWithin, 1)
+
+	blocks := d.composeBlocks(padded)
+	state := newState()
+	var result [25]uints.U64
+	for b, block := range blocks {
+		for i := range block {
+			state[i] = d.uapi.Xor(state[i], block[i])
+		}
+		state = keccakf.Permute(d.uapi, state)
+		for i := 0; i < d.outputLen/8; i++ {
+			for j := range state[i] {
+				if b == 0 {
+					result[i][j] = state[i][j]
+				} else {
+					result[i][j].Val = d.api.Select(isLastBlock[b], state[i][j].Val, result[i][j].Val)
+				}
+			}
+		}
+	}
+	var res []uints.U8
+	for i := 0; i < d.outputLen/8; i++ {
+		res = append(res, d.uapi.UnpackLSB(result[i])...)
+	}
+	return res
+}
+
 func (d *digest) padding() []uints.U8 {
 	padded := make([]uints.U8, len(d.in))
 	copy(padded[:], d.in[:])
@@ -15,7 +15,7 @@ import (
 )
 
 type testCase struct {
-	zk     func(api frontend.API) (zkhash.BinaryHasher, error)
+	zk     func(api frontend.API) (zkhash.BinaryFixedLengthHasher, error)
 	native func() hash.Hash
 }
 
@@ -88,3 +88,91 @@ func TestSHA3(t *testing.T) {
 		}, name)
 	}
 }
+
+type sha3FixedLengthSumCircuit struct {
+	In       []uints.U8
+	Length   frontend.Variable
+	Expected []uints.U8
+
+	hasher string
+}
+
+func (c *sha3FixedLengthSumCircuit) Define(api frontend.API) error {
+	newHasher, ok := testCases[c.hasher]
+	if !ok {
+		return fmt.Errorf("hash function unknown: %s", c.hasher)
+	}
+	h, err := newHasher.zk(api)
+	if err != nil {
+		return err
+	}
+	uapi, err := uints.New[uints.U64](api)
+	if err != nil {
+		return err
+	}
+
+	h.Write(c.In)
+	res := h.FixedLengthSum(c.Length)
+
+	for i := range c.Expected {
+		uapi.ByteAssertEq(c.Expected[i], res[i])
+	}
+	return nil
+}
+
+func TestSHA3FixedLengthSum(t *testing.T) {
+	assert := test.NewAssert(t)
+	in := make([]byte, 310)
+	_, err := rand.Reader.Read(in)
+	assert.NoError(err)
+
+	for name := range testCases {
+		for _, length := range []int{0, 71, 136, 310} {
+			name, length := name, length
+			assert.Run(func(assert *test.Assert) {
+				strategy := testCases[name]
+				h := strategy.native()
+				h.Write(in[:length])
+				expected := h.Sum(nil)
+
+				circuit := &sha3FixedLengthSumCircuit{
+					In:       make([]uints.U8, len(in)),
+					Expected: make([]uints.U8, len(expected)),
+					hasher:   name,
+				}
+
+				witness := &sha3FixedLengthSumCircuit{
+					In:       uints.NewU8Array(in),
+					Length:   length,
+					Expected: uints.NewU8Array(expected),
+				}
+
+				if err := test.IsSolved(circuit, witness, ecc.BN254.ScalarField()); err != nil {
+					t.Fatalf("%s: %s", name, err)
+				}
+			}, name, fmt.Sprintf("length=%d", length))
+		}
+	}
+}
+
+func TestSHA3FixedLengthSumTooLong(t *testing.T) {
+	in := make([]byte, 100)
+	// the digest of the input extended with zeros, which must not be accepted
+	for _, length := range []int{len(in) + 1, len(in) + 20} {
+		h := sha3.NewLegacyKeccak256()
+		h.Write(make([]byte, length))
+		circuit := &sha3FixedLengthSumCircuit{
+			In:       make([]uints.U8, len(in)),
+			Expected: make([]uints.U8, 32),
+			hasher:   "Keccak-256",
+		}
+		witness := &sha3FixedLengthSumCircuit{
+			In:       uints.NewU8Array(in),
+			Length:   length,
+			Expected: uints.NewU8Array(h.Sum(nil)),
+		}
+		if err := test.IsSolved(circuit, witness, ecc.BN254.ScalarField()); err == nil {
+			t.Fatalf("expected error for length %d exceeding the maximum", length)
+		}
+	}
+}
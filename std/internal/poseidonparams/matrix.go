@@ -0,0 +1,348 @@
+package poseidonparams
+
+import (
+	"math/big"
+)
+
+// IsSecureMatrix reports whether the minimal polynomials of m, m², ..., m^2t
+// are irreducible of degree t, where t is the size of the square matrix m. It
+// is the sufficient condition of the Poseidon2 paper for the linear layer not
+// to have infinitely long invariant subspace trails. Such a matrix is
+// invertible.
+func IsSecureMatrix(modulus *big.Int, m [][]*big.Int) bool {
+	t := len(m)
+	pow := m
+	for i := 1; i <= 2*t; i++ {
+		// the characteristic polynomial has degree t, it is the minimal
+		// polynomial when it is irreducible.
+		if !isIrreducible(modulus, charPoly(modulus, pow)) {
+			return false
+		}
+		pow = matMul(modulus, m, pow)
+	}
+	return true
+}
+
+func matMul(modulus *big.Int, a, b [][]*big.Int) [][]*big.Int {
+	res := make([][]*big.Int, len(a))
+	var tmp big.Int
+	for i := range a {
+		res[i] = make([]*big.Int, len(b[0]))
+		for j := range res[i] {
+			res[i][j] = new(big.Int)
+			for k := range b {
+				res[i][j].Add(res[i][j], tmp.Mul(a[i][k], b[k][j]))
+			}
+			res[i][j].Mod(res[i][j], modulus)
+		}
+	}
+	return res
+}
+
+// charPoly returns the monic characteristic polynomial of m, coefficients in
+// increasing degree. The matrix is first reduced to Hessenberg form.
+func charPoly(modulus *big.Int, m [][]*big.Int) []*big.Int {
+	n := len(m)
+	h := make([][]*big.Int, n)
+	for i := range m {
+		h[i] = make([]*big.Int, n)
+		for j := range m[i] {
+			h[i][j] = new(big.Int).Mod(m[i][j], modulus)
+		}
+	}
+	var u, tmp big.Int
+	for c := 1; c < n-1; c++ {
+		pivot := c
+		for pivot < n && h[pivot][c-1].Sign() == 0 {
+			pivot++
+		}
+		if pivot == n {
+			continue
+		}
+		if pivot != c {
+			h[pivot], h[c] = h[c], h[pivot]
+			for j := range h {
+				h[j][pivot], h[j][c] = h[j][c], h[j][pivot]
+			}
+		}
+		inv := new(big.Int).ModInverse(h[c][c-1], modulus)
+		for i := c + 1; i < n; i++ {
+			u.Mul(h[i][c-1], inv).Mod(&u, modulus)
+			if u.Sign() == 0 {
+				continue
+			}
+			for j := range h {
+				h[i][j].Sub(h[i][j], tmp.Mul(&u, h[c][j])).Mod(h[i][j], modulus)
+			}
+			for j := range h {
+				h[j][c].Add(h[j][c], tmp.Mul(&u, h[j][i])).Mod(h[j][c], modulus)
+			}
+		}
+	}
+
+	// p_k = (x - h[k][k]) p_{k-1} - Σ h[k-i][k] Π_{j=k-i+1}^{k} h[j][j-1] p_{k-i-1}
+	polys := make([][]*big.Int, n+1)
+	polys[0] = []*big.Int{big.NewInt(1)}
+	var prod big.Int
+	for k := 1; k <= n; k++ {
+		kk := k - 1 // 0-indexed
+		p := make([]*big.Int, k+1)
+		for i := range p {
+			p[i] = new(big.Int)
+		}
+		for i, c := range polys[k-1] {
+			p[i+1].Add(p[i+1], c)
+			p[i].Sub(p[i], tmp.Mul(h[kk][kk], c))
+		}
+		prod.SetInt64(1)
+		for i := 1; i < k; i++ {
+			prod.Mul(&prod, h[kk-i+1][kk-i]).Mod(&prod, modulus)
+			u.Mul(&prod, h[kk-i][kk])
+			for j, c := range polys[k-i-1] {
+				p[j].Sub(p[j], tmp.Mul(&u, c))
+			}
+		}
+		for i := range p {
+			p[i].Mod(p[i], modulus)
+		}
+		polys[k] = p
+	}
+	return polys[n]
+}
+
+// isIrreducible runs Rabin's test on the monic polynomial f of degree t: f is
+// irreducible iff x^(p^t) = x mod f and gcd(x^(p^(t/q)) - x, f) = 1 for all
+// the prime divisors q of t.
+func isIrreducible(modulus *big.Int, f []*big.Int) bool {
+	t := len(f) - 1
+	if t <= 1 {
+		return t == 1
+	}
+	x := make([]*big.Int, t)
+	for i := range x {
+		x[i] = new(big.Int)
+	}
+	x[1].SetInt64(1)
+
+	// xp = x^p mod f, then the Frobenius map g -> g^p is linear and given by
+	// the images x^(ip) of the monomials.
+	xp := polyExpMod(modulus, x, modulus, f)
+	frob := make([][]*big.Int, t)
+	frob[0] = make([]*big.Int, t)
+	for i := range frob[0] {
+		frob[0][i] = new(big.Int)
+	}
+	frob[0][0].SetInt64(1)
+	for i := 1; i < t; i++ {
+		frob[i] = polyMulMod(modulus, frob[i-1], xp, f)
+	}
+	applyFrob := func(g []*big.Int) []*big.Int {
+		res := make([]*big.Int, t)
+		var tmp big.Int
+		for i := range res {
+			res[i] = new(big.Int)
+		}
+		for i, c := range g {
+			for j := range res {
+				res[j].Add(res[j], tmp.Mul(c, frob[i][j]))
+			}
+		}
+		for i := range res {
+			res[i].Mod(res[i], modulus)
+		}
+		return res
+	}
+
+	// powers[k] = x^(p^k) mod f
+	powers := make([][]*big.Int, t+1)
+	powers[0] = x
+	for k := 1; k <= t; k++ {
+		powers[k] = applyFrob(powers[k-1])
+	}
+	for i := range x {
+		if powers[t][i].Cmp(x[i]) != 0 {
+			return false
+		}
+	}
+	for q := 2; q <= t; q++ {
+		if t%q != 0 || !isPrime(q) {
+			continue
+		}
+		g := make([]*big.Int, t)
+		for i := range g {
+			g[i] = new(big.Int).Sub(powers[t/q][i], x[i])
+			g[i].Mod(g[i], modulus)
+		}
+		if d := polyGCD(modulus, f, g); len(d) != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+func isPrime(q int) bool {
+	for d := 2; d*d <= q; d++ {
+		if q%d == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// polyMulMod returns a*b mod f, for a and b of degree less than deg(f) and f
+// monic. The result has deg(f) coefficients.
+func polyMulMod(modulus *big.Int, a, b, f []*big.Int) []*big.Int {
+	t := len(f) - 1
+	prod := make([]*big.Int, len(a)+len(b)-1)
+	for i := range prod {
+		prod[i] = new(big.Int)
+	}
+	var tmp big.Int
+	for i, ai := range a {
+		if ai.Sign() == 0 {
+			continue
+		}
+		for j, bj := range b {
+			prod[i+j].Add(prod[i+j], tmp.Mul(ai, bj))
+		}
+	}
+	for i := len(prod) - 1; i >= t; i-- {
+		c := prod[i].Mod(prod[i], modulus)
+		if c.Sign() == 0 {
+			continue
+		}
+		for j := 0; j < t; j++ {
+			prod[i-t+j].Sub(prod[i-t+j], tmp.Mul(c, f[j]))
+		}
+	}
+	res := make([]*big.Int, t)
+	for i := range res {
+		res[i] = new(big.Int)
+		if i < len(prod) {
+			res[i].Mod(prod[i], modulus)
+		}
+	}
+	return res
+}
+
+func polyExpMod(modulus *big.Int, a []*big.Int, e *big.Int, f []*big.Int) []*big.Int {
+	res := make([]*big.Int, len(f)-1)
+	for i := range res {
+		res[i] = new(big.Int)
+	}
+	res[0].SetInt64(1)
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		res = polyMulMod(modulus, res, res, f)
+		if e.Bit(i) == 1 {
+			res = polyMulMod(modulus, res, a, f)
+		}
+	}
+	return res
+}
+
+// polyGCD returns the gcd of a and b, with trailing zero coefficients removed.
+// The zero polynomial has no coefficient.
+func polyGCD(modulus *big.Int, a, b []*big.Int) []*big.Int {
+	a, b = polyTrim(polyCopy(a)), polyTrim(polyCopy(b))
+	var inv, c, tmp big.Int
+	for len(b) != 0 {
+		// a = a mod b
+		inv.ModInverse(b[len(b)-1], modulus)
+		for len(a) >= len(b) {
+			c.Mul(a[len(a)-1], &inv).Mod(&c, modulus)
+			shift := len(a) - len(b)
+			for j := range b {
+				a[shift+j].Sub(a[shift+j], tmp.Mul(&c, b[j])).Mod(a[shift+j], modulus)
+			}
+			a = polyTrim(a)
+		}
+		a, b = b, a
+	}
+	return a
+}
+
+func polyCopy(a []*big.Int) []*big.Int {
+	res := make([]*big.Int, len(a))
+	for i := range a {
+		res[i] = new(big.Int).Set(a[i])
+	}
+	return res
+}
+
+func polyTrim(a []*big.Int) []*big.Int {
+	for len(a) > 0 && a[len(a)-1].Sign() == 0 {
+		a = a[:len(a)-1]
+	}
+	return a
+}
+
+// HasNoSubspaceTrail reports whether the partial rounds of a permutation with
+// the linear layer m and a single S-box on the first element have no
+// infinitely long invariant subspace trail. As in the algorithms of eprint
+// 2020/500 run by the reference implementation of Poseidon, the condition is
+// checked on the powers m^r for r ≤ 4t: the first basis vector must generate
+// the whole space under m^r and its transpose, so that no non-trivial
+// subspace on which the S-box is inactive is invariant.
+func HasNoSubspaceTrail(modulus *big.Int, m [][]*big.Int) bool {
+	t := len(m)
+	pow := m
+	for r := 1; r <= 4*t; r++ {
+		if !isCyclic(modulus, pow, false) || !isCyclic(modulus, pow, true) {
+			return false
+		}
+		pow = matMul(modulus, m, pow)
+	}
+	return true
+}
+
+// isCyclic reports whether the Krylov space of the first basis vector under m
+// (or its transpose) is the whole space.
+func isCyclic(modulus *big.Int, m [][]*big.Int, transpose bool) bool {
+	t := len(m)
+	v := make([]*big.Int, t)
+	for i := range v {
+		v[i] = new(big.Int)
+	}
+	v[0].SetInt64(1)
+	// basis in echelon form, basis[i] has its pivot at pivots[i]
+	var basis [][]*big.Int
+	var pivots []int
+	var c, tmp big.Int
+	for len(basis) < t {
+		w := polyCopy(v)
+		for i, b := range basis {
+			c.Set(w[pivots[i]])
+			for j := range w {
+				w[j].Sub(w[j], tmp.Mul(&c, b[j])).Mod(w[j], modulus)
+			}
+		}
+		pivot := 0
+		for pivot < t && w[pivot].Sign() == 0 {
+			pivot++
+		}
+		if pivot == t {
+			return false
+		}
+		c.ModInverse(w[pivot], modulus)
+		for j := range w {
+			w[j].Mul(w[j], &c).Mod(w[j], modulus)
+		}
+		basis = append(basis, w)
+		pivots = append(pivots, pivot)
+
+		next := make([]*big.Int, t)
+		for i := range next {
+			next[i] = new(big.Int)
+			for j := range v {
+				if transpose {
+					next[i].Add(next[i], tmp.Mul(m[j][i], v[j]))
+				} else {
+					next[i].Add(next[i], tmp.Mul(m[i][j], v[j]))
+				}
+			}
+			next[i].Mod(next[i], modulus)
+		}
+		v = next
+	}
+	return true
+}
@@ -0,0 +1,185 @@
+// Package poseidonparams derives the parameters shared by the Poseidon and
+// Poseidon2 permutations: the S-box degree, the number of rounds, the
+// pseudo-random constants and the security check of the linear layers.
+//
+// The derivation follows the reference implementations of the Poseidon and
+// Poseidon2 papers (the generate_parameters_grain.sage and
+// calc_round_numbers.py scripts): the constants are drawn from the Grain
+// LFSR initialised with the shape of the instance and the number of rounds is
+// the cheapest one satisfying the security inequalities of the papers, with
+// their security margin.
+package poseidonparams
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// SecurityLevel is the security level (in bits) targeted by the default
+// number of rounds.
+const SecurityLevel = 128
+
+// SBoxDegree returns the smallest odd prime d such that x -> x^d is a
+// permutation of the field of order p, i.e. such that gcd(d, p-1) = 1.
+func SBoxDegree(p *big.Int) int {
+	var pMinusOne, gcd, bd big.Int
+	pMinusOne.Sub(p, big.NewInt(1))
+	for _, d := range []int64{3, 5, 7, 11, 13, 17, 19, 23, 29, 31} {
+		bd.SetInt64(d)
+		if gcd.GCD(nil, nil, &bd, &pMinusOne).IsInt64() && gcd.Int64() == 1 {
+			return int(d)
+		}
+	}
+	panic(fmt.Sprintf("no small S-box degree for modulus %s", p.String()))
+}
+
+// NbRounds returns the number of full and partial rounds of a permutation of
+// the given width with S-box degree d over the field of order p, for
+// [SecurityLevel] bits of security.
+//
+// As in the reference script, it is the instance with the fewest S-boxes
+// satisfying the statistical, interpolation and Gröbner basis bounds of the
+// Poseidon paper and the additional Gröbner bound of eprint 2023/537, to
+// which the security margin of the paper is added: two full rounds and 7.5%
+// more partial rounds.
+func NbRounds(p *big.Int, d, width int) (nbFullRounds, nbPartialRounds int) {
+	minCost := math.MaxInt
+	for rP := 1; rP < 500; rP++ {
+		for rF := 4; rF < 100; rF += 2 {
+			if !isSecure(p, width, rF, rP, d) {
+				continue
+			}
+			// the bounds are monotonic in rF, larger values are more costly
+			f, r := rF+2, int(math.Ceil(float64(rP)*1.075))
+			if cost := f*width + r; cost < minCost || (cost == minCost && f < nbFullRounds) {
+				nbFullRounds, nbPartialRounds, minCost = f, r, cost
+			}
+			break
+		}
+	}
+	return
+}
+
+// isSecure checks the bounds of sat_inequiv_alpha in the reference script.
+func isSecure(p *big.Int, t, rF, rP, alpha int) bool {
+	const M = SecurityLevel
+	n := float64(p.BitLen())
+	fp, _ := new(big.Float).SetInt(p).Float64()
+	log2p := math.Log2(fp)
+	a, tf, rf, rp := float64(alpha), float64(t), float64(rF), float64(rP)
+	logA := func(x float64) float64 { return math.Log(x) / math.Log(a) }
+
+	// statistical
+	rF1 := 10.0
+	if M <= math.Floor(log2p-(a-1)/2)*(tf+1) {
+		rF1 = 6
+	}
+	// interpolation
+	rF2 := 1 + math.Ceil(logA(2)*math.Min(M, n)) + math.Ceil(logA(tf)) - rp
+	// Gröbner basis
+	rF3 := logA(2)*math.Min(M, log2p) - rp
+	rF4 := tf - 1 + logA(2)*math.Min(M/(tf+1), log2p/2) - rp
+	rF5 := (tf - 2 + M/(2*math.Log2(a)) - rp) / (tf - 1)
+	for _, b := range []float64{rF1, rF2, rF3, rF4, rF5} {
+		if rf < math.Ceil(b) {
+			return false
+		}
+	}
+
+	// eprint 2023/537
+	r := math.Floor(tf / 3)
+	over := (rf-1)*tf + rp + r + r*(rf/2) + rp + a
+	under := r*(rf/2) + rp + a
+	binomLog := log2Binomial(over, under)
+	if math.IsInf(binomLog, 0) {
+		binomLog = M + 1
+	}
+	return math.Ceil(2*binomLog) >= M
+}
+
+// log2Binomial returns log2(n choose k).
+func log2Binomial(n, k float64) float64 {
+	a, _ := math.Lgamma(n + 1)
+	b, _ := math.Lgamma(k + 1)
+	c, _ := math.Lgamma(n - k + 1)
+	return (a - b - c) / math.Ln2
+}
+
+// Grain is the self-shrinking Grain LFSR used by the reference
+// implementations to generate the constants of an instance.
+type Grain struct {
+	state [80]uint8
+}
+
+// NewGrain returns the generator for an instance over a prime field of nbBits
+// bits with a power S-box x -> x^d. The state is initialised with the shape of
+// the instance and the first 160 bits are discarded.
+func NewGrain(nbBits, width, nbFullRounds, nbPartialRounds int) *Grain {
+	g := new(Grain)
+	i := 0
+	push := func(v, size int) {
+		for j := size - 1; j >= 0; j-- {
+			g.state[i] = uint8(v>>j) & 1
+			i++
+		}
+	}
+	push(1, 2) // prime field
+	push(0, 4) // x^d S-box
+	push(nbBits, 12)
+	push(width, 12)
+	push(nbFullRounds, 10)
+	push(nbPartialRounds, 10)
+	push(1<<30-1, 30)
+	for j := 0; j < 160; j++ {
+		g.update()
+	}
+	return g
+}
+
+func (g *Grain) update() uint8 {
+	s := &g.state
+	b := s[62] ^ s[51] ^ s[38] ^ s[23] ^ s[13] ^ s[0]
+	copy(s[:], s[1:])
+	s[79] = b
+	return b
+}
+
+// nextBit returns the next output bit: the LFSR bits are read in pairs and the
+// second one is output when the first one is set.
+func (g *Grain) nextBit() uint8 {
+	for g.update() == 0 {
+		g.update()
+	}
+	return g.update()
+}
+
+// NextBits returns the integer whose nbBits big-endian bits are the next
+// output bits.
+func (g *Grain) NextBits(nbBits int) *big.Int {
+	res := new(big.Int)
+	for i := 0; i < nbBits; i++ {
+		res.Lsh(res, 1)
+		if g.nextBit() == 1 {
+			res.SetBit(res, 0, 1)
+		}
+	}
+	return res
+}
+
+// NextElement returns the next field element by rejection sampling, as for
+// the round constants.
+func (g *Grain) NextElement(modulus *big.Int) *big.Int {
+	for {
+		if res := g.NextBits(modulus.BitLen()); res.Cmp(modulus) < 0 {
+			return res
+		}
+	}
+}
+
+// NextReducedElement returns the next field element by reducing the next
+// output bits modulo the field, as for the MDS matrix of Poseidon.
+func (g *Grain) NextReducedElement(modulus *big.Int) *big.Int {
+	res := g.NextBits(modulus.BitLen())
+	return res.Mod(res, modulus)
+}
@@ -0,0 +1,191 @@
+package poseidon
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark/std/internal/poseidonparams"
+)
+
+var (
+	// ErrInvalidWidth is returned when the permutation width is smaller than 2.
+	ErrInvalidWidth = errors.New("invalid poseidon width: must be at least 2")
+	// ErrInvalidStateLength is returned when the length of the state given to
+	// the permutation does not match its width.
+	ErrInvalidStateLength = errors.New("invalid state length")
+)
+
+// Parameters describes a Poseidon permutation instance over a prime field.
+// The round keys and the MDS matrix are generated as in the reference
+// implementation of the Poseidon paper, see [NewParameters].
+type Parameters struct {
+	// Modulus is the order of the field the permutation operates on.
+	Modulus *big.Int
+	// Width is the size of the state.
+	Width int
+	// DegreeSBox is the degree d of the S-box x -> x^d.
+	DegreeSBox int
+	// NbFullRounds is the total number of full rounds. Half of them are
+	// applied before the partial rounds and the other half after.
+	NbFullRounds int
+	// NbPartialRounds is the number of partial rounds.
+	NbPartialRounds int
+	// RoundKeys are the additive round constants, Width elements per round.
+	RoundKeys [][]*big.Int
+	// MDS is the Cauchy matrix M[i][j] = 1/(x_i + y_j) used as the linear
+	// layer.
+	MDS [][]*big.Int
+}
+
+type parametersKey struct {
+	modulus                        string
+	width, nbFullRounds, nbPartial int
+}
+
+var (
+	parametersCache   = make(map[parametersKey]*Parameters)
+	parametersCacheMu sync.Mutex
+)
+
+// NewParameters returns the Poseidon instance over the field with the given
+// modulus. As in the reference implementation, the round keys and then the MDS
+// matrix are drawn from the Grain LFSR initialised with the shape of the
+// instance. The parameters are cached, so repeated calls are cheap and the
+// result must not be modified.
+func NewParameters(modulus *big.Int, width, nbFullRounds, nbPartialRounds int) (*Parameters, error) {
+	if width < 2 {
+		return nil, ErrInvalidWidth
+	}
+	if nbFullRounds <= 0 || nbFullRounds%2 != 0 {
+		return nil, fmt.Errorf("number of full rounds must be positive and even, got %d", nbFullRounds)
+	}
+	if nbPartialRounds < 0 {
+		return nil, fmt.Errorf("number of partial rounds must be non-negative, got %d", nbPartialRounds)
+	}
+	key := parametersKey{modulus.Text(16), width, nbFullRounds, nbPartialRounds}
+	parametersCacheMu.Lock()
+	defer parametersCacheMu.Unlock()
+	if p, ok := parametersCache[key]; ok {
+		return p, nil
+	}
+	p := &Parameters{
+		Modulus:         new(big.Int).Set(modulus),
+		Width:           width,
+		DegreeSBox:      poseidonparams.SBoxDegree(modulus),
+		NbFullRounds:    nbFullRounds,
+		NbPartialRounds: nbPartialRounds,
+	}
+	gen := poseidonparams.NewGrain(modulus.BitLen(), width, nbFullRounds, nbPartialRounds)
+	p.RoundKeys = make([][]*big.Int, nbFullRounds+nbPartialRounds)
+	for i := range p.RoundKeys {
+		p.RoundKeys[i] = make([]*big.Int, width)
+		for j := range p.RoundKeys[i] {
+			p.RoundKeys[i][j] = gen.NextElement(modulus)
+		}
+	}
+	p.MDS = newMDS(gen, modulus, width)
+	parametersCache[key] = p
+	return p, nil
+}
+
+// NewDefaultParameters returns the Poseidon instance with the given width over
+// the field with the given modulus with the default number of rounds for 128
+// bits of security. As in the reference instances, the number of partial
+// rounds is rounded up to a multiple of the width.
+func NewDefaultParameters(modulus *big.Int, width int) (*Parameters, error) {
+	d := poseidonparams.SBoxDegree(modulus)
+	rF, rP := poseidonparams.NbRounds(modulus, d, width)
+	rP = (rP + width - 1) / width * width
+	return NewParameters(modulus, width, rF, rP)
+}
+
+// newMDS draws the Cauchy matrix 1/(x_i + y_j) from gen, until the x_i and y_j
+// are distinct, the sums are non-zero and the matrix passes
+// [poseidonparams.IsSecureMatrix].
+func newMDS(gen *poseidonparams.Grain, modulus *big.Int, width int) [][]*big.Int {
+	for {
+		xy := make([]*big.Int, 2*width)
+		for distinct := false; !distinct; {
+			seen := make(map[string]struct{}, len(xy))
+			for i := range xy {
+				xy[i] = gen.NextReducedElement(modulus)
+				seen[xy[i].String()] = struct{}{}
+			}
+			distinct = len(seen) == len(xy)
+		}
+		mds := make([][]*big.Int, width)
+		ok := true
+		for i := 0; i < width && ok; i++ {
+			mds[i] = make([]*big.Int, width)
+			for j := range mds[i] {
+				e := new(big.Int).Add(xy[i], xy[width+j])
+				if e.ModInverse(e, modulus) == nil {
+					ok = false
+					break
+				}
+				mds[i][j] = e
+			}
+		}
+		if ok && poseidonparams.HasNoSubspaceTrail(modulus, mds) {
+			return mds
+		}
+	}
+}
+
+// String returns a human readable description of the instance.
+func (p *Parameters) String() string {
+	return fmt.Sprintf("Poseidon[t=%d,rF=%d,rP=%d,d=%d]", p.Width, p.NbFullRounds, p.NbPartialRounds, p.DegreeSBox)
+}
+
+// Permutation applies the permutation in place on the native state. It is the
+// out-of-circuit counterpart of [Permutation.Permutation].
+func (p *Parameters) Permutation(state []*big.Int) error {
+	if len(state) != p.Width {
+		return ErrInvalidStateLength
+	}
+	rf := p.NbFullRounds / 2
+	exp := big.NewInt(int64(p.DegreeSBox))
+	for i := range p.RoundKeys {
+		for j := range state {
+			state[j].Add(state[j], p.RoundKeys[i][j])
+		}
+		if i < rf || i >= rf+p.NbPartialRounds {
+			for j := range state {
+				state[j].Exp(state[j], exp, p.Modulus)
+			}
+		} else {
+			state[0].Exp(state[0], exp, p.Modulus)
+		}
+		p.mix(state)
+	}
+	return nil
+}
+
+func (p *Parameters) mix(state []*big.Int) {
+	res := make([]*big.Int, len(state))
+	var tmp big.Int
+	for i := range res {
+		res[i] = new(big.Int)
+		for j := range state {
+			res[i].Add(res[i], tmp.Mul(p.MDS[i][j], state[j]))
+		}
+		res[i].Mod(res[i], p.Modulus)
+	}
+	for i := range state {
+		state[i].Set(res[i])
+	}
+}
+
+// Compress is the out-of-circuit counterpart of [Permutation.Compress].
+func (p *Parameters) Compress(left, right *big.Int) *big.Int {
+	if p.Width != 2 {
+		panic("compression requires width 2")
+	}
+	state := []*big.Int{new(big.Int).Set(left), new(big.Int).Set(right)}
+	if err := p.Permutation(state); err != nil {
+		panic(err) // can't error, width checked above
+	}
+	return state[1].Add(state[1], right).Mod(state[1], p.Modulus)
+}
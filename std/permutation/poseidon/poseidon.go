@@ -0,0 +1,130 @@
+// Package poseidon implements the original Poseidon permutation.
+//
+// Poseidon is a SNARK-friendly permutation described in [eprint 2019/458]. For
+// new applications prefer [github.com/consensys/gnark/std/permutation/poseidon2]
+// which is cheaper in PLONK-ish arithmetisations.
+//
+// This package exposes only the permutation primitive. For a hash function
+// built on top of it, see [github.com/consensys/gnark/std/hash/poseidon].
+//
+// The round keys and the Cauchy MDS matrix are generated as in the reference
+// implementation, so that the BN254 instances match circomlib, see
+// [NewParameters]. The out-of-circuit counterpart of the gadget is
+// [Parameters.Permutation].
+//
+// [eprint 2019/458]: https://eprint.iacr.org/2019/458
+package poseidon
+
+import (
+	"github.com/consensys/gnark/frontend"
+)
+
+// Permutation is the in-circuit Poseidon permutation.
+type Permutation struct {
+	api    frontend.API
+	params *Parameters
+}
+
+// NewPoseidon returns a new Poseidon permutation of width 2 with the default
+// parameters for the native field of the circuit. Width 2 is used for 2-to-1
+// compression, see [Permutation.Compress].
+func NewPoseidon(api frontend.API) (*Permutation, error) {
+	return NewPoseidonWithWidth(api, 2)
+}
+
+// NewPoseidonWithWidth returns a new Poseidon permutation of the given width
+// with the default number of rounds for the native field of the circuit.
+func NewPoseidonWithWidth(api frontend.API, width int) (*Permutation, error) {
+	params, err := NewDefaultParameters(api.Compiler().Field(), width)
+	if err != nil {
+		return nil, err
+	}
+	return &Permutation{api: api, params: params}, nil
+}
+
+// NewPoseidonFromParameters returns a new Poseidon permutation with the given
+// shape over the native field of the circuit.
+func NewPoseidonFromParameters(api frontend.API, width, nbFullRounds, nbPartialRounds int) (*Permutation, error) {
+	params, err := NewParameters(api.Compiler().Field(), width, nbFullRounds, nbPartialRounds)
+	if err != nil {
+		return nil, err
+	}
+	return &Permutation{api: api, params: params}, nil
+}
+
+// Parameters returns the parameters of the permutation instance.
+func (h *Permutation) Parameters() *Parameters {
+	return h.params
+}
+
+// Permutation applies the permutation in place on the state. The length of
+// the state must equal the width of the permutation.
+func (h *Permutation) Permutation(state []frontend.Variable) error {
+	if len(state) != h.params.Width {
+		return ErrInvalidStateLength
+	}
+	rf := h.params.NbFullRounds / 2
+	for i := range h.params.RoundKeys {
+		for j := range state {
+			state[j] = h.api.Add(state[j], h.params.RoundKeys[i][j])
+		}
+		if i < rf || i >= rf+h.params.NbPartialRounds {
+			for j := range state {
+				state[j] = h.sBox(state[j])
+			}
+		} else {
+			state[0] = h.sBox(state[0])
+		}
+		h.mix(state)
+	}
+	return nil
+}
+
+// Compress implements the 2-to-1 compression function
+//
+//	(left, right) -> P(left, right)[1] + right
+//
+// where P is the permutation. The feed-forward of the right input makes the
+// function non-invertible. The permutation must have width 2.
+func (h *Permutation) Compress(left, right frontend.Variable) frontend.Variable {
+	if h.params.Width != 2 {
+		panic("compression requires width 2")
+	}
+	state := []frontend.Variable{left, right}
+	if err := h.Permutation(state); err != nil {
+		panic(err) // can't error, width checked above
+	}
+	return h.api.Add(state[1], right)
+}
+
+// sBox computes x^d using square-and-multiply.
+func (h *Permutation) sBox(x frontend.Variable) frontend.Variable {
+	var res frontend.Variable
+	sq := x
+	for d := h.params.DegreeSBox; d > 0; {
+		if d&1 == 1 {
+			if res == nil {
+				res = sq
+			} else {
+				res = h.api.Mul(res, sq)
+			}
+		}
+		d >>= 1
+		if d > 0 {
+			sq = h.api.Mul(sq, sq)
+		}
+	}
+	return res
+}
+
+func (h *Permutation) mix(state []frontend.Variable) {
+	res := make([]frontend.Variable, len(state))
+	terms := make([]frontend.Variable, len(state))
+	for i := range res {
+		for j := range state {
+			terms[j] = h.api.Mul(state[j], h.params.MDS[i][j])
+		}
+		res[i] = h.api.Add(terms[0], terms[1], terms[2:]...)
+	}
+	copy(state, res)
+}
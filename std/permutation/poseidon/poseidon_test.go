@@ -0,0 +1,117 @@
+package poseidon
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type permutationCircuit struct {
+	In       []frontend.Variable
+	Expected []frontend.Variable `gnark:",public"`
+}
+
+func (c *permutationCircuit) Define(api frontend.API) error {
+	h, err := NewPoseidonWithWidth(api, len(c.In))
+	if err != nil {
+		return err
+	}
+	state := make([]frontend.Variable, len(c.In))
+	copy(state, c.In)
+	if err := h.Permutation(state); err != nil {
+		return err
+	}
+	for i := range state {
+		api.AssertIsEqual(state[i], c.Expected[i])
+	}
+	return nil
+}
+
+func TestPermutation(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BLS12_377, ecc.BLS12_381, ecc.BW6_761, ecc.BW6_633, ecc.BLS24_315, ecc.BLS24_317} {
+		for _, width := range []int{2, 3, 5} {
+			curve, width := curve, width
+			assert.Run(func(assert *test.Assert) {
+				params, err := NewDefaultParameters(curve.ScalarField(), width)
+				assert.NoError(err)
+				in := make([]*big.Int, width)
+				out := make([]*big.Int, width)
+				for i := range in {
+					in[i] = big.NewInt(int64(i + 1))
+					out[i] = new(big.Int).Set(in[i])
+				}
+				assert.NoError(params.Permutation(out))
+				circuit := permutationCircuit{In: make([]frontend.Variable, width), Expected: make([]frontend.Variable, width)}
+				witness := permutationCircuit{In: make([]frontend.Variable, width), Expected: make([]frontend.Variable, width)}
+				for i := range in {
+					witness.In[i] = in[i]
+					witness.Expected[i] = out[i]
+				}
+				assert.CheckCircuit(&circuit, test.WithValidAssignment(&witness), test.WithCurves(curve))
+			}, curve.String(), fmt.Sprintf("width=%d", width))
+		}
+	}
+}
+
+func TestParametersDeterministic(t *testing.T) {
+	assert := test.NewAssert(t)
+	modulus := ecc.BN254.ScalarField()
+	p1, err := NewDefaultParameters(modulus, 3)
+	assert.NoError(err)
+	assert.Equal(5, p1.DegreeSBox)
+	assert.Equal(len(p1.RoundKeys), p1.NbFullRounds+p1.NbPartialRounds)
+
+	// bypass the cache to check the derivation itself is deterministic
+	parametersCacheMu.Lock()
+	parametersCache = make(map[parametersKey]*Parameters)
+	parametersCacheMu.Unlock()
+	p2, err := NewDefaultParameters(modulus, 3)
+	assert.NoError(err)
+	for i := range p1.RoundKeys {
+		for j := range p1.RoundKeys[i] {
+			assert.Equal(0, p1.RoundKeys[i][j].Cmp(p2.RoundKeys[i][j]))
+		}
+	}
+
+	_, err = NewDefaultParameters(modulus, 1)
+	assert.ErrorIs(err, ErrInvalidWidth)
+}
+
+func TestReferenceVectors(t *testing.T) {
+	// test vectors of the reference implementation (poseidonperm_x5_254_3 and
+	// poseidonperm_x5_254_5), also matched by circomlib.
+	assert := test.NewAssert(t)
+	modulus := ecc.BN254.ScalarField()
+	for _, tc := range []struct {
+		width    int
+		nbRounds [2]int
+		expected []string
+	}{
+		{3, [2]int{8, 57}, []string{
+			"0x115cc0f5e7d690413df64c6b9662e9cf2a3617f2743245519e19607a4417189a",
+			"0x0fca49b798923ab0239de1c9e7a4a9a2210312b6a2f616d18b5a87f9b628ae29",
+			"0x0e7ae82e40091e63cbd4f16a6d16310b3729d4b6e138fcf54110e2867045a30c",
+		}},
+		{5, [2]int{8, 60}, []string{
+			"0x299c867db6c1fdd79dcefa40e4510b9837e60ebb1ce0663dbaa525df65250465",
+		}},
+	} {
+		params, err := NewDefaultParameters(modulus, tc.width)
+		assert.NoError(err)
+		assert.Equal(tc.nbRounds, [2]int{params.NbFullRounds, params.NbPartialRounds})
+		state := make([]*big.Int, tc.width)
+		for i := range state {
+			state[i] = big.NewInt(int64(i))
+		}
+		assert.NoError(params.Permutation(state))
+		for i, e := range tc.expected {
+			expected, _ := new(big.Int).SetString(e[2:], 16)
+			assert.Equal(0, expected.Cmp(state[i]), "element %d", i)
+		}
+	}
+}
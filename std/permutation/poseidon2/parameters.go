@@ -0,0 +1,271 @@
+package poseidon2
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark/std/internal/poseidonparams"
+)
+
+var (
+	// ErrInvalidWidth is returned when the permutation width is not supported.
+	// Supported widths are 2, 3 and multiples of 4 up to 24.
+	ErrInvalidWidth = errors.New("invalid poseidon2 width: must be 2, 3 or a multiple of 4 up to 24")
+	// ErrInvalidStateLength is returned when the length of the state given to
+	// the permutation does not match its width.
+	ErrInvalidStateLength = errors.New("invalid state length")
+)
+
+// Parameters describes a Poseidon2 permutation instance over a prime field.
+// The round keys are generated as in the reference implementation of the
+// Poseidon2 paper, see [NewParameters].
+type Parameters struct {
+	// Modulus is the order of the field the permutation operates on.
+	Modulus *big.Int
+	// Width is the size of the state.
+	Width int
+	// DegreeSBox is the degree d of the S-box x -> x^d.
+	DegreeSBox int
+	// NbFullRounds is the total number of full rounds. Half of them are
+	// applied before the partial rounds and the other half after.
+	NbFullRounds int
+	// NbPartialRounds is the number of partial rounds.
+	NbPartialRounds int
+	// RoundKeys are the additive round constants. RoundKeys[i] has Width
+	// elements for full rounds and a single element for partial rounds.
+	RoundKeys [][]*big.Int
+	// InternalDiagonal is the diagonal of the internal matrix minus the
+	// identity, i.e. the internal matrix is J + diag(InternalDiagonal) where J
+	// is the all-ones matrix.
+	InternalDiagonal []*big.Int
+}
+
+type parametersKey struct {
+	modulus                        string
+	width, nbFullRounds, nbPartial int
+}
+
+var (
+	parametersCache   = make(map[parametersKey]*Parameters)
+	parametersCacheMu sync.Mutex
+)
+
+// NewParameters returns the Poseidon2 instance over the field with the given
+// modulus. As in the reference implementation, the round keys are drawn from
+// the Grain LFSR initialised with the shape of the instance, with a single key
+// per partial round. For widths larger than 3 the diagonal of the internal
+// matrix is drawn next from the same stream; checking its security takes a few
+// seconds for the largest widths. The parameters are cached, so repeated calls are cheap and the
+// result must not be modified.
+func NewParameters(modulus *big.Int, width, nbFullRounds, nbPartialRounds int) (*Parameters, error) {
+	if !isValidWidth(width) {
+		return nil, ErrInvalidWidth
+	}
+	if nbFullRounds <= 0 || nbFullRounds%2 != 0 {
+		return nil, fmt.Errorf("number of full rounds must be positive and even, got %d", nbFullRounds)
+	}
+	if nbPartialRounds < 0 {
+		return nil, fmt.Errorf("number of partial rounds must be non-negative, got %d", nbPartialRounds)
+	}
+	key := parametersKey{modulus.Text(16), width, nbFullRounds, nbPartialRounds}
+	parametersCacheMu.Lock()
+	defer parametersCacheMu.Unlock()
+	if p, ok := parametersCache[key]; ok {
+		return p, nil
+	}
+	p := &Parameters{
+		Modulus:         new(big.Int).Set(modulus),
+		Width:           width,
+		DegreeSBox:      poseidonparams.SBoxDegree(modulus),
+		NbFullRounds:    nbFullRounds,
+		NbPartialRounds: nbPartialRounds,
+	}
+	gen := poseidonparams.NewGrain(modulus.BitLen(), width, nbFullRounds, nbPartialRounds)
+	rf := nbFullRounds / 2
+	p.RoundKeys = make([][]*big.Int, nbFullRounds+nbPartialRounds)
+	for i := range p.RoundKeys {
+		n := width
+		if i >= rf && i < rf+nbPartialRounds {
+			n = 1
+		}
+		p.RoundKeys[i] = make([]*big.Int, n)
+		for j := range p.RoundKeys[i] {
+			p.RoundKeys[i][j] = gen.NextElement(modulus)
+		}
+	}
+	p.InternalDiagonal = newInternalDiagonal(gen, modulus, width)
+	parametersCache[key] = p
+	return p, nil
+}
+
+// NewDefaultParameters returns the Poseidon2 instance with the given width
+// over the field with the given modulus with the default number of rounds for
+// 128 bits of security.
+func NewDefaultParameters(modulus *big.Int, width int) (*Parameters, error) {
+	d := poseidonparams.SBoxDegree(modulus)
+	rF, rP := poseidonparams.NbRounds(modulus, d, width)
+	return NewParameters(modulus, width, rF, rP)
+}
+
+// newInternalDiagonal returns the diagonal of the internal matrix minus the
+// identity. The matrices for widths 2 and 3 are fixed by the Poseidon2 paper.
+// For larger widths the diagonal is drawn from gen until the internal matrix
+// passes [poseidonparams.IsSecureMatrix].
+func newInternalDiagonal(gen *poseidonparams.Grain, modulus *big.Int, width int) []*big.Int {
+	switch width {
+	case 2:
+		return []*big.Int{big.NewInt(1), big.NewInt(2)}
+	case 3:
+		return []*big.Int{big.NewInt(1), big.NewInt(1), big.NewInt(2)}
+	}
+	for {
+		diag := make([]*big.Int, width)
+		m := make([][]*big.Int, width)
+		for i := range diag {
+			diag[i] = gen.NextElement(modulus)
+			m[i] = make([]*big.Int, width)
+			for j := range m[i] {
+				m[i][j] = big.NewInt(1)
+			}
+			m[i][i] = new(big.Int).Add(m[i][i], diag[i])
+		}
+		if poseidonparams.IsSecureMatrix(modulus, m) {
+			return diag
+		}
+	}
+}
+
+// String returns a human readable description of the instance.
+func (p *Parameters) String() string {
+	return fmt.Sprintf("Poseidon2[t=%d,rF=%d,rP=%d,d=%d]", p.Width, p.NbFullRounds, p.NbPartialRounds, p.DegreeSBox)
+}
+
+func isValidWidth(width int) bool {
+	return width == 2 || width == 3 || (width%4 == 0 && width > 0 && width <= 24)
+}
+
+// Permutation applies the permutation in place on the native state. It is the
+// out-of-circuit counterpart of [Permutation.Permutation].
+func (p *Parameters) Permutation(state []*big.Int) error {
+	if len(state) != p.Width {
+		return ErrInvalidStateLength
+	}
+	for i := range state {
+		state[i].Mod(state[i], p.Modulus)
+	}
+	p.matMulExternal(state)
+	rf := p.NbFullRounds / 2
+	for i := 0; i < rf; i++ {
+		p.addRoundKeys(state, i)
+		for j := range state {
+			p.sBox(state[j])
+		}
+		p.matMulExternal(state)
+	}
+	for i := rf; i < rf+p.NbPartialRounds; i++ {
+		state[0].Add(state[0], p.RoundKeys[i][0])
+		p.sBox(state[0])
+		p.matMulInternal(state)
+	}
+	for i := rf + p.NbPartialRounds; i < p.NbFullRounds+p.NbPartialRounds; i++ {
+		p.addRoundKeys(state, i)
+		for j := range state {
+			p.sBox(state[j])
+		}
+		p.matMulExternal(state)
+	}
+	return nil
+}
+
+func (p *Parameters) addRoundKeys(state []*big.Int, round int) {
+	for j := range state {
+		state[j].Add(state[j], p.RoundKeys[round][j])
+	}
+}
+
+func (p *Parameters) sBox(x *big.Int) {
+	x.Exp(x, big.NewInt(int64(p.DegreeSBox)), p.Modulus)
+}
+
+func (p *Parameters) matMulInternal(state []*big.Int) {
+	sum := new(big.Int)
+	for i := range state {
+		sum.Add(sum, state[i])
+	}
+	for i := range state {
+		state[i].Mul(state[i], p.InternalDiagonal[i]).Add(state[i], sum).Mod(state[i], p.Modulus)
+	}
+}
+
+func (p *Parameters) matMulExternal(state []*big.Int) {
+	switch p.Width {
+	case 2:
+		// [2 1]
+		// [1 2]
+		sum := new(big.Int).Add(state[0], state[1])
+		state[0].Add(state[0], sum).Mod(state[0], p.Modulus)
+		state[1].Add(state[1], sum).Mod(state[1], p.Modulus)
+	case 3:
+		// [2 1 1]
+		// [1 2 1]
+		// [1 1 2]
+		sum := new(big.Int).Add(state[0], state[1])
+		sum.Add(sum, state[2])
+		for i := range state {
+			state[i].Add(state[i], sum).Mod(state[i], p.Modulus)
+		}
+	default:
+		// circ(2M4, M4, ..., M4)
+		for i := 0; i < p.Width; i += 4 {
+			v := matMul4(state[i : i+4])
+			copy(state[i:i+4], v[:])
+		}
+		var sums [4]big.Int
+		for i := 0; i < p.Width; i += 4 {
+			for j := 0; j < 4; j++ {
+				sums[j].Add(&sums[j], state[i+j])
+			}
+		}
+		for i := range state {
+			state[i].Add(state[i], &sums[i%4]).Mod(state[i], p.Modulus)
+		}
+	}
+}
+
+// matMul4 multiplies the input by the 4x4 MDS matrix
+//
+//	[5 7 1 3]
+//	[4 6 1 1]
+//	[1 3 5 7]
+//	[1 1 4 6]
+func matMul4(in []*big.Int) [4]*big.Int {
+	var res [4]*big.Int
+	for i := range res {
+		res[i] = new(big.Int)
+		for j := range in {
+			res[i].Add(res[i], new(big.Int).Mul(in[j], big.NewInt(m4[i][j])))
+		}
+	}
+	return res
+}
+
+var m4 = [4][4]int64{
+	{5, 7, 1, 3},
+	{4, 6, 1, 1},
+	{1, 3, 5, 7},
+	{1, 1, 4, 6},
+}
+
+// Compress is the out-of-circuit counterpart of [Permutation.Compress].
+func (p *Parameters) Compress(left, right *big.Int) *big.Int {
+	if p.Width != 2 {
+		panic("compression requires width 2")
+	}
+	state := []*big.Int{new(big.Int).Set(left), new(big.Int).Set(right)}
+	if err := p.Permutation(state); err != nil {
+		panic(err) // can't error, width checked above
+	}
+	return state[1].Add(state[1], right).Mod(state[1], p.Modulus)
+}
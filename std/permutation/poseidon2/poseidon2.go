@@ -0,0 +1,175 @@
+// Package poseidon2 implements the Poseidon2 permutation.
+//
+// Poseidon2 is a SNARK-friendly permutation described in [eprint 2023/323]. It
+// differs from the original Poseidon permutation by using cheap linear layers,
+// which reduces the number of constraints in PLONK-ish arithmetisations.
+//
+// This package exposes only the permutation primitive. For a hash function
+// built on top of it, see [github.com/consensys/gnark/std/hash/poseidon2].
+//
+// The permutation is defined over any prime field. The round keys are
+// generated as in the reference implementation of the paper, see
+// [NewParameters]. The out-of-circuit counterpart of the gadget is
+// [Parameters.Permutation].
+//
+// [eprint 2023/323]: https://eprint.iacr.org/2023/323
+package poseidon2
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// Permutation is the in-circuit Poseidon2 permutation.
+type Permutation struct {
+	api    frontend.API
+	params *Parameters
+}
+
+// NewPoseidon2 returns a new Poseidon2 permutation of width 2 with the default
+// parameters for the native field of the circuit. Width 2 is used for 2-to-1
+// compression, see [Permutation.Compress].
+func NewPoseidon2(api frontend.API) (*Permutation, error) {
+	return NewPoseidon2WithWidth(api, 2)
+}
+
+// NewPoseidon2WithWidth returns a new Poseidon2 permutation of the given width
+// with the default number of rounds for the native field of the circuit.
+func NewPoseidon2WithWidth(api frontend.API, width int) (*Permutation, error) {
+	params, err := NewDefaultParameters(api.Compiler().Field(), width)
+	if err != nil {
+		return nil, err
+	}
+	return &Permutation{api: api, params: params}, nil
+}
+
+// NewPoseidon2FromParameters returns a new Poseidon2 permutation with the
+// given shape over the native field of the circuit.
+func NewPoseidon2FromParameters(api frontend.API, width, nbFullRounds, nbPartialRounds int) (*Permutation, error) {
+	params, err := NewParameters(api.Compiler().Field(), width, nbFullRounds, nbPartialRounds)
+	if err != nil {
+		return nil, err
+	}
+	return &Permutation{api: api, params: params}, nil
+}
+
+// Parameters returns the parameters of the permutation instance.
+func (h *Permutation) Parameters() *Parameters {
+	return h.params
+}
+
+// Permutation applies the permutation in place on the state. The length of
+// the state must equal the width of the permutation.
+func (h *Permutation) Permutation(state []frontend.Variable) error {
+	if len(state) != h.params.Width {
+		return ErrInvalidStateLength
+	}
+	h.matMulExternal(state)
+	rf := h.params.NbFullRounds / 2
+	for i := 0; i < rf; i++ {
+		h.fullRound(state, i)
+	}
+	for i := rf; i < rf+h.params.NbPartialRounds; i++ {
+		state[0] = h.api.Add(state[0], h.params.RoundKeys[i][0])
+		state[0] = h.sBox(state[0])
+		h.matMulInternal(state)
+	}
+	for i := rf + h.params.NbPartialRounds; i < h.params.NbFullRounds+h.params.NbPartialRounds; i++ {
+		h.fullRound(state, i)
+	}
+	return nil
+}
+
+// Compress implements the 2-to-1 compression function
+//
+//	(left, right) -> P(left, right)[1] + right
+//
+// where P is the permutation. The feed-forward of the right input makes the
+// function non-invertible. The permutation must have width 2.
+func (h *Permutation) Compress(left, right frontend.Variable) frontend.Variable {
+	if h.params.Width != 2 {
+		panic("compression requires width 2")
+	}
+	state := []frontend.Variable{left, right}
+	if err := h.Permutation(state); err != nil {
+		panic(err) // can't error, width checked above
+	}
+	return h.api.Add(state[1], right)
+}
+
+func (h *Permutation) fullRound(state []frontend.Variable, round int) {
+	for j := range state {
+		state[j] = h.api.Add(state[j], h.params.RoundKeys[round][j])
+		state[j] = h.sBox(state[j])
+	}
+	h.matMulExternal(state)
+}
+
+// sBox computes x^d using square-and-multiply.
+func (h *Permutation) sBox(x frontend.Variable) frontend.Variable {
+	return sBox(h.api, x, h.params.DegreeSBox)
+}
+
+func sBox(api frontend.API, x frontend.Variable, d int) frontend.Variable {
+	var res frontend.Variable
+	sq := x
+	for d > 0 {
+		if d&1 == 1 {
+			if res == nil {
+				res = sq
+			} else {
+				res = api.Mul(res, sq)
+			}
+		}
+		d >>= 1
+		if d > 0 {
+			sq = api.Mul(sq, sq)
+		}
+	}
+	return res
+}
+
+func (h *Permutation) matMulInternal(state []frontend.Variable) {
+	sum := h.api.Add(state[0], state[1], state[2:]...)
+	for i := range state {
+		state[i] = h.api.Add(h.api.Mul(state[i], h.params.InternalDiagonal[i]), sum)
+	}
+}
+
+func (h *Permutation) matMulExternal(state []frontend.Variable) {
+	switch h.params.Width {
+	case 2, 3:
+		sum := h.api.Add(state[0], state[1], state[2:]...)
+		for i := range state {
+			state[i] = h.api.Add(state[i], sum)
+		}
+	default:
+		for i := 0; i < len(state); i += 4 {
+			h.matMul4(state[i : i+4])
+		}
+		var sums [4]frontend.Variable
+		for j := range sums {
+			sums[j] = state[j]
+			for i := 4; i < len(state); i += 4 {
+				sums[j] = h.api.Add(sums[j], state[i+j])
+			}
+		}
+		for i := range state {
+			state[i] = h.api.Add(state[i], sums[i%4])
+		}
+	}
+}
+
+// matMul4 multiplies the block in place by the 4x4 MDS matrix M4.
+func (h *Permutation) matMul4(s []frontend.Variable) {
+	var res [4]frontend.Variable
+	for i := range res {
+		terms := make([]frontend.Variable, 4)
+		for j := range terms {
+			terms[j] = h.api.Mul(s[j], big.NewInt(m4[i][j]))
+		}
+		res[i] = h.api.Add(terms[0], terms[1], terms[2:]...)
+	}
+	copy(s, res[:])
+}
@@ -0,0 +1,102 @@
+package poseidon2
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type permutationCircuit struct {
+	In       []frontend.Variable
+	Expected []frontend.Variable `gnark:",public"`
+}
+
+func (c *permutationCircuit) Define(api frontend.API) error {
+	h, err := NewPoseidon2WithWidth(api, len(c.In))
+	if err != nil {
+		return err
+	}
+	state := make([]frontend.Variable, len(c.In))
+	copy(state, c.In)
+	if err := h.Permutation(state); err != nil {
+		return err
+	}
+	for i := range state {
+		api.AssertIsEqual(state[i], c.Expected[i])
+	}
+	return nil
+}
+
+func TestPermutation(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BLS12_377, ecc.BLS12_381, ecc.BW6_761, ecc.BW6_633, ecc.BLS24_315, ecc.BLS24_317} {
+		for _, width := range []int{2, 3, 4, 8} {
+			curve, width := curve, width
+			assert.Run(func(assert *test.Assert) {
+				params, err := NewDefaultParameters(curve.ScalarField(), width)
+				assert.NoError(err)
+				in := make([]*big.Int, width)
+				out := make([]*big.Int, width)
+				for i := range in {
+					in[i] = big.NewInt(int64(i + 1))
+					out[i] = new(big.Int).Set(in[i])
+				}
+				assert.NoError(params.Permutation(out))
+				circuit := permutationCircuit{In: make([]frontend.Variable, width), Expected: make([]frontend.Variable, width)}
+				witness := permutationCircuit{In: make([]frontend.Variable, width), Expected: make([]frontend.Variable, width)}
+				for i := range in {
+					witness.In[i] = in[i]
+					witness.Expected[i] = out[i]
+				}
+				assert.CheckCircuit(&circuit, test.WithValidAssignment(&witness), test.WithCurves(curve))
+			}, curve.String(), fmt.Sprintf("width=%d", width))
+		}
+	}
+}
+
+func TestParametersDeterministic(t *testing.T) {
+	assert := test.NewAssert(t)
+	modulus := ecc.BN254.ScalarField()
+	p1, err := NewDefaultParameters(modulus, 3)
+	assert.NoError(err)
+	assert.Equal(5, p1.DegreeSBox)
+	assert.Equal(len(p1.RoundKeys), p1.NbFullRounds+p1.NbPartialRounds)
+
+	// bypass the cache to check the derivation itself is deterministic
+	parametersCacheMu.Lock()
+	parametersCache = make(map[parametersKey]*Parameters)
+	parametersCacheMu.Unlock()
+	p2, err := NewDefaultParameters(modulus, 3)
+	assert.NoError(err)
+	for i := range p1.RoundKeys {
+		for j := range p1.RoundKeys[i] {
+			assert.Equal(0, p1.RoundKeys[i][j].Cmp(p2.RoundKeys[i][j]))
+		}
+	}
+
+	_, err = NewDefaultParameters(modulus, 5)
+	assert.ErrorIs(err, ErrInvalidWidth)
+}
+
+func TestReferenceVectors(t *testing.T) {
+	// test vector of the reference implementation of the Poseidon2 paper.
+	assert := test.NewAssert(t)
+	params, err := NewDefaultParameters(ecc.BN254.ScalarField(), 3)
+	assert.NoError(err)
+	assert.Equal(8, params.NbFullRounds)
+	assert.Equal(56, params.NbPartialRounds)
+	state := []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(2)}
+	assert.NoError(params.Permutation(state))
+	for i, e := range []string{
+		"0x0bb61d24daca55eebcb1929a82650f328134334da98ea4f847f760054f4a3033",
+		"0x303b6f7c86d043bfcbcc80214f26a30277a15d3f74ca654992defe7ff8d03570",
+		"0x1ed25194542b12eef8617361c3ba7c52e660b145994427cc86296242cf766ec8",
+	} {
+		expected, _ := new(big.Int).SetString(e[2:], 16)
+		assert.Equal(0, expected.Cmp(state[i]), "element %d", i)
+	}
+}
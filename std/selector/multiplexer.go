@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"github.com/consensys/gnark/constraint/solver"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/coverage"
 	"github.com/consensys/gnark/std/math/bits"
 	"math/big"
 	binary "math/bits"
@@ -53,6 +54,8 @@ func Map(api frontend.API, queryKey frontend.Variable,
 // sel needs to be between 0 and n - 1 (inclusive), where n is the number of
 // inputs, otherwise the proof will fail.
 func Mux(api frontend.API, sel frontend.Variable, inputs ...frontend.Variable) frontend.Variable {
+	coverage.RecordBranch(api, "Mux", sel, len(inputs))
+
 	// we use BinaryMux when len(inputs) is a power of 2.
 	if binary.OnesCount(uint(len(inputs))) == 1 {
 		selBits := bits.ToBinary(api, sel, bits.WithNbDigits(binary.Len(uint(len(inputs)))-1))
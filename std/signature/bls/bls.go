@@ -0,0 +1,200 @@
+package bls
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// Domain separation tags of the proof-of-possession ciphersuites over
+// BLS12-381 defined in the IETF BLS signature draft. DSTMinPkPoP is used in
+// Ethereum consensus.
+const (
+	DSTMinPkPoP  = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+	DSTMinSigPoP = "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_"
+)
+
+// MinPk verifies BLS signatures in the minimal-pubkey-size variant where the
+// public keys are in G1 and the signatures and hashed messages are in G2.
+type MinPk[G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT] struct {
+	pairing algebra.Pairing[G1El, G2El, GtEl]
+	groups  groups[G1El, G2El]
+	dst     []byte
+}
+
+// NewMinPk returns a new verifier for the minimal-pubkey-size variant. The
+// messages are hashed to G2 using the domain separation tag dst.
+func NewMinPk[G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT](api frontend.API, dst []byte) (*MinPk[G1El, G2El, GtEl], error) {
+	pairing, err := algebra.GetPairing[G1El, G2El, GtEl](api)
+	if err != nil {
+		return nil, fmt.Errorf("get pairing: %w", err)
+	}
+	groups, err := getGroups[G1El, G2El](api)
+	if err != nil {
+		return nil, fmt.Errorf("get groups: %w", err)
+	}
+	return &MinPk[G1El, G2El, GtEl]{
+		pairing: pairing,
+		groups:  groups,
+		dst:     dst,
+	}, nil
+}
+
+// Verify asserts that sig is a valid signature on msg for the public key pk.
+func (v *MinPk[G1El, G2El, GtEl]) Verify(pk *G1El, msg []uints.U8, sig *G2El) error {
+	h, err := v.groups.hashToG2(msg, v.dst)
+	if err != nil {
+		return fmt.Errorf("hash to G2: %w", err)
+	}
+	return v.verify([]*G1El{pk}, []*G2El{h}, sig)
+}
+
+// FastAggregateVerify asserts that sig is a valid aggregate signature on msg
+// for the public keys pks. The public keys are aggregated using complete
+// addition formulas, so they may contain duplicates.
+func (v *MinPk[G1El, G2El, GtEl]) FastAggregateVerify(pks []*G1El, msg []uints.U8, sig *G2El) error {
+	apk, err := v.AggregatePublicKeys(pks)
+	if err != nil {
+		return fmt.Errorf("aggregate public keys: %w", err)
+	}
+	return v.Verify(apk, msg, sig)
+}
+
+// AggregateVerify asserts that sig is a valid aggregate signature on the
+// messages msgs, where the i-th message is signed by the i-th public key in
+// pks.
+func (v *MinPk[G1El, G2El, GtEl]) AggregateVerify(pks []*G1El, msgs [][]uints.U8, sig *G2El) error {
+	if len(pks) == 0 {
+		return fmt.Errorf("no public keys")
+	}
+	if len(pks) != len(msgs) {
+		return fmt.Errorf("mismatching number of public keys and messages")
+	}
+	hs := make([]*G2El, len(msgs))
+	for i := range msgs {
+		h, err := v.groups.hashToG2(msgs[i], v.dst)
+		if err != nil {
+			return fmt.Errorf("hash to G2: %w", err)
+		}
+		hs[i] = h
+	}
+	return v.verify(pks, hs, sig)
+}
+
+// AggregatePublicKeys returns the sum of the public keys pks.
+func (v *MinPk[G1El, G2El, GtEl]) AggregatePublicKeys(pks []*G1El) (*G1El, error) {
+	if len(pks) == 0 {
+		return nil, fmt.Errorf("no public keys")
+	}
+	apk := pks[0]
+	for i := 1; i < len(pks); i++ {
+		apk = v.groups.addG1(apk, pks[i])
+	}
+	return apk, nil
+}
+
+// verify checks e(-g₁, sig) * ∏ e(pks[i], hs[i]) == 1.
+func (v *MinPk[G1El, G2El, GtEl]) verify(pks []*G1El, hs []*G2El, sig *G2El) error {
+	v.pairing.AssertIsOnG2(sig)
+	P := append([]*G1El{v.groups.g1GenNeg()}, pks...)
+	Q := append([]*G2El{sig}, hs...)
+	if err := v.pairing.PairingCheck(P, Q); err != nil {
+		return fmt.Errorf("pairing check: %w", err)
+	}
+	return nil
+}
+
+// MinSig verifies BLS signatures in the minimal-signature-size variant where
+// the public keys are in G2 and the signatures and hashed messages are in G1.
+type MinSig[G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT] struct {
+	pairing algebra.Pairing[G1El, G2El, GtEl]
+	groups  groups[G1El, G2El]
+	dst     []byte
+}
+
+// NewMinSig returns a new verifier for the minimal-signature-size variant. The
+// messages are hashed to G1 using the domain separation tag dst.
+func NewMinSig[G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT](api frontend.API, dst []byte) (*MinSig[G1El, G2El, GtEl], error) {
+	pairing, err := algebra.GetPairing[G1El, G2El, GtEl](api)
+	if err != nil {
+		return nil, fmt.Errorf("get pairing: %w", err)
+	}
+	groups, err := getGroups[G1El, G2El](api)
+	if err != nil {
+		return nil, fmt.Errorf("get groups: %w", err)
+	}
+	return &MinSig[G1El, G2El, GtEl]{
+		pairing: pairing,
+		groups:  groups,
+		dst:     dst,
+	}, nil
+}
+
+// Verify asserts that sig is a valid signature on msg for the public key pk.
+func (v *MinSig[G1El, G2El, GtEl]) Verify(pk *G2El, msg []uints.U8, sig *G1El) error {
+	h, err := v.groups.hashToG1(msg, v.dst)
+	if err != nil {
+		return fmt.Errorf("hash to G1: %w", err)
+	}
+	return v.verify([]*G2El{pk}, []*G1El{h}, sig)
+}
+
+// FastAggregateVerify asserts that sig is a valid aggregate signature on msg
+// for the public keys pks.
+func (v *MinSig[G1El, G2El, GtEl]) FastAggregateVerify(pks []*G2El, msg []uints.U8, sig *G1El) error {
+	apk, err := v.AggregatePublicKeys(pks)
+	if err != nil {
+		return fmt.Errorf("aggregate public keys: %w", err)
+	}
+	return v.Verify(apk, msg, sig)
+}
+
+// AggregateVerify asserts that sig is a valid aggregate signature on the
+// messages msgs, where the i-th message is signed by the i-th public key in
+// pks.
+func (v *MinSig[G1El, G2El, GtEl]) AggregateVerify(pks []*G2El, msgs [][]uints.U8, sig *G1El) error {
+	if len(pks) == 0 {
+		return fmt.Errorf("no public keys")
+	}
+	if len(pks) != len(msgs) {
+		return fmt.Errorf("mismatching number of public keys and messages")
+	}
+	hs := make([]*G1El, len(msgs))
+	for i := range msgs {
+		h, err := v.groups.hashToG1(msgs[i], v.dst)
+		if err != nil {
+			return fmt.Errorf("hash to G1: %w", err)
+		}
+		hs[i] = h
+	}
+	return v.verify(pks, hs, sig)
+}
+
+// AggregatePublicKeys returns the sum of the public keys pks.
+func (v *MinSig[G1El, G2El, GtEl]) AggregatePublicKeys(pks []*G2El) (*G2El, error) {
+	if len(pks) == 0 {
+		return nil, fmt.Errorf("no public keys")
+	}
+	apk := pks[0]
+	for i := 1; i < len(pks); i++ {
+		apk = v.groups.addG2(apk, pks[i])
+	}
+	return apk, nil
+}
+
+// verify checks e(sig, g₂) * ∏ e(-hs[i], pks[i]) == 1.
+func (v *MinSig[G1El, G2El, GtEl]) verify(pks []*G2El, hs []*G1El, sig *G1El) error {
+	v.pairing.AssertIsOnG1(sig)
+	P := make([]*G1El, len(hs)+1)
+	P[0] = sig
+	for i := range hs {
+		P[i+1] = v.groups.negG1(hs[i])
+	}
+	Q := append([]*G2El{v.groups.g2Gen()}, pks...)
+	if err := v.pairing.PairingCheck(P, Q); err != nil {
+		return fmt.Errorf("pairing check: %w", err)
+	}
+	return nil
+}
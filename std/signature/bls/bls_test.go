@@ -0,0 +1,331 @@
+package bls
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	fr_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	fr_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/algebra/native/sw_bls12377"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type minPkCircuit[G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT] struct {
+	Pks  []G1El
+	Msgs [][]uints.U8
+	Sig  G2El
+
+	dst      []byte
+	sameMsgs bool
+}
+
+func (c *minPkCircuit[G1El, G2El, GtEl]) Define(api frontend.API) error {
+	v, err := NewMinPk[G1El, G2El, GtEl](api, c.dst)
+	if err != nil {
+		return err
+	}
+	pks := make([]*G1El, len(c.Pks))
+	for i := range c.Pks {
+		pks[i] = &c.Pks[i]
+	}
+	switch {
+	case len(pks) == 1:
+		return v.Verify(pks[0], c.Msgs[0], &c.Sig)
+	case c.sameMsgs:
+		return v.FastAggregateVerify(pks, c.Msgs[0], &c.Sig)
+	default:
+		return v.AggregateVerify(pks, c.Msgs, &c.Sig)
+	}
+}
+
+type minSigCircuit[G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT] struct {
+	Pks  []G2El
+	Msgs [][]uints.U8
+	Sig  G1El
+
+	dst      []byte
+	sameMsgs bool
+}
+
+func (c *minSigCircuit[G1El, G2El, GtEl]) Define(api frontend.API) error {
+	v, err := NewMinSig[G1El, G2El, GtEl](api, c.dst)
+	if err != nil {
+		return err
+	}
+	pks := make([]*G2El, len(c.Pks))
+	for i := range c.Pks {
+		pks[i] = &c.Pks[i]
+	}
+	switch {
+	case len(pks) == 1:
+		return v.Verify(pks[0], c.Msgs[0], &c.Sig)
+	case c.sameMsgs:
+		return v.FastAggregateVerify(pks, c.Msgs[0], &c.Sig)
+	default:
+		return v.AggregateVerify(pks, c.Msgs, &c.Sig)
+	}
+}
+
+func randomMessages(nb int, same bool) [][]byte {
+	msgs := make([][]byte, nb)
+	for i := range msgs {
+		if same && i > 0 {
+			msgs[i] = msgs[0]
+			continue
+		}
+		msgs[i] = make([]byte, 32)
+		rand.Read(msgs[i]) //nolint:errcheck
+	}
+	return msgs
+}
+
+func u8Messages(msgs [][]byte, same bool) (placeholder, witness [][]uints.U8) {
+	if same {
+		msgs = msgs[:1]
+	}
+	placeholder = make([][]uints.U8, len(msgs))
+	witness = make([][]uints.U8, len(msgs))
+	for i := range msgs {
+		placeholder[i] = make([]uints.U8, len(msgs[i]))
+		witness[i] = uints.NewU8Array(msgs[i])
+	}
+	return
+}
+
+func signBLS12381MinPk(assert *test.Assert, msgs [][]byte, dst []byte) ([]bls12381.G1Affine, bls12381.G2Affine) {
+	_, _, g1, _ := bls12381.Generators()
+	pks := make([]bls12381.G1Affine, len(msgs))
+	var sig bls12381.G2Affine
+	for i := range msgs {
+		var sk fr_bls12381.Element
+		sk.SetRandom() //nolint:errcheck
+		skb := sk.BigInt(new(big.Int))
+		pks[i].ScalarMultiplication(&g1, skb)
+		h, err := bls12381.HashToG2(msgs[i], dst)
+		assert.NoError(err)
+		h.ScalarMultiplication(&h, skb)
+		if i == 0 {
+			sig = h
+		} else {
+			sig.Add(&sig, &h)
+		}
+	}
+	return pks, sig
+}
+
+func testBLS12381MinPk(assert *test.Assert, nb int, same bool) {
+	dst := []byte(DSTMinPkPoP)
+	msgs := randomMessages(nb, same)
+	pks, sig := signBLS12381MinPk(assert, msgs, dst)
+	circuit := minPkCircuit[sw_bls12381.G1Affine, sw_bls12381.G2Affine, sw_bls12381.GTEl]{
+		Pks:      make([]sw_bls12381.G1Affine, nb),
+		dst:      dst,
+		sameMsgs: same,
+	}
+	witness := minPkCircuit[sw_bls12381.G1Affine, sw_bls12381.G2Affine, sw_bls12381.GTEl]{
+		Pks: make([]sw_bls12381.G1Affine, nb),
+		Sig: sw_bls12381.NewG2Affine(sig),
+	}
+	for i := range pks {
+		witness.Pks[i] = sw_bls12381.NewG1Affine(pks[i])
+	}
+	circuit.Msgs, witness.Msgs = u8Messages(msgs, same)
+	err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// signature for a different message must not verify
+	_, witness.Msgs = u8Messages(randomMessages(nb, same), same)
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+func TestBLS12381MinPkVerify(t *testing.T) {
+	testBLS12381MinPk(test.NewAssert(t), 1, false)
+}
+
+func TestBLS12381MinPkFastAggregateVerify(t *testing.T) {
+	testBLS12381MinPk(test.NewAssert(t), 3, true)
+}
+
+func TestBLS12381MinPkAggregateVerify(t *testing.T) {
+	testBLS12381MinPk(test.NewAssert(t), 2, false)
+}
+
+func TestBLS12381MinSigVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+	dst := []byte(DSTMinSigPoP)
+	msgs := randomMessages(1, false)
+	_, _, _, g2 := bls12381.Generators()
+	var sk fr_bls12381.Element
+	sk.SetRandom() //nolint:errcheck
+	skb := sk.BigInt(new(big.Int))
+	var pk bls12381.G2Affine
+	pk.ScalarMultiplication(&g2, skb)
+	sig, err := bls12381.HashToG1(msgs[0], dst)
+	assert.NoError(err)
+	sig.ScalarMultiplication(&sig, skb)
+
+	circuit := minSigCircuit[sw_bls12381.G1Affine, sw_bls12381.G2Affine, sw_bls12381.GTEl]{
+		Pks: make([]sw_bls12381.G2Affine, 1),
+		dst: dst,
+	}
+	witness := minSigCircuit[sw_bls12381.G1Affine, sw_bls12381.G2Affine, sw_bls12381.GTEl]{
+		Pks: []sw_bls12381.G2Affine{sw_bls12381.NewG2Affine(pk)},
+		Sig: sw_bls12381.NewG1Affine(sig),
+	}
+	circuit.Msgs, witness.Msgs = u8Messages(msgs, false)
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
+
+func TestBLS12381MinSigFastAggregateVerifyDuplicateKey(t *testing.T) {
+	// the same public key appears twice: the aggregation must double it
+	// instead of leaving the addition unconstrained.
+	assert := test.NewAssert(t)
+	dst := []byte(DSTMinSigPoP)
+	msgs := randomMessages(1, false)
+	_, _, _, g2 := bls12381.Generators()
+	var sk fr_bls12381.Element
+	sk.SetRandom() //nolint:errcheck
+	skb := sk.BigInt(new(big.Int))
+	var pk bls12381.G2Affine
+	pk.ScalarMultiplication(&g2, skb)
+	h, err := bls12381.HashToG1(msgs[0], dst)
+	assert.NoError(err)
+	var sig bls12381.G1Affine
+	sig.ScalarMultiplication(&h, skb)
+	sig.Add(&sig, &sig)
+
+	circuit := minSigCircuit[sw_bls12381.G1Affine, sw_bls12381.G2Affine, sw_bls12381.GTEl]{
+		Pks:      make([]sw_bls12381.G2Affine, 2),
+		dst:      dst,
+		sameMsgs: true,
+	}
+	witness := minSigCircuit[sw_bls12381.G1Affine, sw_bls12381.G2Affine, sw_bls12381.GTEl]{
+		Pks: []sw_bls12381.G2Affine{sw_bls12381.NewG2Affine(pk), sw_bls12381.NewG2Affine(pk)},
+		Sig: sw_bls12381.NewG1Affine(sig),
+	}
+	circuit.Msgs, witness.Msgs = u8Messages(msgs, true)
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// the signature of a single key must not verify for the doubled key
+	sig.ScalarMultiplication(&h, skb)
+	witness.Sig = sw_bls12381.NewG1Affine(sig)
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+func testBLS12377MinPk(assert *test.Assert, nb int, same bool) {
+	dst := []byte("BLS_SIG_BLS12377G2_XMD:SHA-256_SSWU_RO_POP_")
+	msgs := randomMessages(nb, same)
+	_, _, g1, _ := bls12377.Generators()
+	pks := make([]bls12377.G1Affine, nb)
+	var sig bls12377.G2Affine
+	for i := range msgs {
+		var sk fr_bls12377.Element
+		sk.SetRandom() //nolint:errcheck
+		skb := sk.BigInt(new(big.Int))
+		pks[i].ScalarMultiplication(&g1, skb)
+		h, err := bls12377.HashToG2(msgs[i], dst)
+		assert.NoError(err)
+		h.ScalarMultiplication(&h, skb)
+		if i == 0 {
+			sig = h
+		} else {
+			sig.Add(&sig, &h)
+		}
+	}
+	circuit := minPkCircuit[sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT]{
+		Pks:      make([]sw_bls12377.G1Affine, nb),
+		dst:      dst,
+		sameMsgs: same,
+	}
+	witness := minPkCircuit[sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT]{
+		Pks: make([]sw_bls12377.G1Affine, nb),
+		Sig: sw_bls12377.NewG2Affine(sig),
+	}
+	for i := range pks {
+		witness.Pks[i] = sw_bls12377.NewG1Affine(pks[i])
+	}
+	circuit.Msgs, witness.Msgs = u8Messages(msgs, same)
+	err := test.IsSolved(&circuit, &witness, ecc.BW6_761.ScalarField())
+	assert.NoError(err)
+
+	_, witness.Msgs = u8Messages(randomMessages(nb, same), same)
+	err = test.IsSolved(&circuit, &witness, ecc.BW6_761.ScalarField())
+	assert.Error(err)
+}
+
+func TestBLS12377MinPkVerify(t *testing.T) {
+	testBLS12377MinPk(test.NewAssert(t), 1, false)
+}
+
+func TestBLS12377MinPkFastAggregateVerify(t *testing.T) {
+	testBLS12377MinPk(test.NewAssert(t), 3, true)
+}
+
+func TestBLS12377MinPkAggregateVerify(t *testing.T) {
+	testBLS12377MinPk(test.NewAssert(t), 2, false)
+}
+
+func testBLS12377MinSig(assert *test.Assert, nb int, same bool) {
+	dst := []byte("BLS_SIG_BLS12377G1_XMD:SHA-256_SSWU_RO_POP_")
+	msgs := randomMessages(nb, same)
+	_, _, _, g2 := bls12377.Generators()
+	pks := make([]bls12377.G2Affine, nb)
+	var sig bls12377.G1Affine
+	for i := range msgs {
+		var sk fr_bls12377.Element
+		sk.SetRandom() //nolint:errcheck
+		skb := sk.BigInt(new(big.Int))
+		pks[i].ScalarMultiplication(&g2, skb)
+		h, err := bls12377.HashToG1(msgs[i], dst)
+		assert.NoError(err)
+		h.ScalarMultiplication(&h, skb)
+		if i == 0 {
+			sig = h
+		} else {
+			sig.Add(&sig, &h)
+		}
+	}
+	circuit := minSigCircuit[sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT]{
+		Pks:      make([]sw_bls12377.G2Affine, nb),
+		dst:      dst,
+		sameMsgs: same,
+	}
+	witness := minSigCircuit[sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT]{
+		Pks: make([]sw_bls12377.G2Affine, nb),
+		Sig: sw_bls12377.NewG1Affine(sig),
+	}
+	for i := range pks {
+		witness.Pks[i] = sw_bls12377.NewG2Affine(pks[i])
+	}
+	circuit.Msgs, witness.Msgs = u8Messages(msgs, same)
+	err := test.IsSolved(&circuit, &witness, ecc.BW6_761.ScalarField())
+	assert.NoError(err)
+
+	_, witness.Msgs = u8Messages(randomMessages(nb, same), same)
+	err = test.IsSolved(&circuit, &witness, ecc.BW6_761.ScalarField())
+	assert.Error(err)
+}
+
+func TestBLS12377MinSigVerify(t *testing.T) {
+	testBLS12377MinSig(test.NewAssert(t), 1, false)
+}
+
+func TestBLS12377MinSigFastAggregateVerify(t *testing.T) {
+	testBLS12377MinSig(test.NewAssert(t), 3, true)
+}
+
+func TestBLS12377MinSigAggregateVerify(t *testing.T) {
+	testBLS12377MinSig(test.NewAssert(t), 2, false)
+}
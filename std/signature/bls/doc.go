@@ -0,0 +1,30 @@
+// Package bls implements BLS signature verification over pairing-friendly
+// elliptic curves.
+//
+// The package supports both variants defined in the IETF BLS signature draft:
+// in the minimal-pubkey-size variant [MinPk] the public keys are in G1 and the
+// signatures in G2, and in the minimal-signature-size variant [MinSig] the
+// roles of the groups are swapped. For both variants it is possible to verify
+// single signatures, aggregate signatures on the same message (with public
+// key aggregation) and aggregate signatures on distinct messages. The messages
+// are hashed to the curve in-circuit using the hash_to_curve method of RFC
+// 9380 with expand_message_xmd over SHA2-256 and the simplified SWU map.
+//
+// Currently BLS12-381 (using emulated arithmetic, see [emulated/sw_bls12381])
+// and BLS12-377 (using native arithmetic over BW6-761, see
+// [native/sw_bls12377]) are supported. The verifier is generic over the group
+// types and the concrete implementation is chosen from the type parameters.
+//
+// The verifier asserts that the signatures are in the correct subgroup, but
+// assumes that the public keys have been validated and that the proof of
+// possession has been checked when aggregating public keys. For example, the
+// Ethereum sync committee public keys are already validated in the beacon
+// chain state. If the public keys are untrusted, then use the subgroup
+// membership check of the pairing implementation on them.
+//
+// See [BLS signatures] for the signature scheme and [RFC 9380] for hashing to
+// the curves.
+//
+// [BLS signatures]: https://datatracker.ietf.org/doc/draft-irtf-cfrg-bls-signature/
+// [RFC 9380]: https://www.rfc-editor.org/rfc/rfc9380.html
+package bls
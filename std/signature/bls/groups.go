@@ -0,0 +1,192 @@
+package bls
+
+import (
+	"fmt"
+
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra"
+	"github.com/consensys/gnark/std/algebra/emulated/fields_bls12381"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/algebra/native/sw_bls12377"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// groups provides the curve-specific group operations which are not part of
+// the [algebra.Pairing] interface but are required for verifying signatures.
+type groups[G1El algebra.G1ElementT, G2El algebra.G2ElementT] interface {
+	// hashToG1 hashes the message to G1 using the domain separation tag dst.
+	hashToG1(msg []uints.U8, dst []byte) (*G1El, error)
+	// hashToG2 hashes the message to G2 using the domain separation tag dst.
+	hashToG2(msg []uints.U8, dst []byte) (*G2El, error)
+	// addG1 returns the sum of the G1 elements.
+	addG1(p, q *G1El) *G1El
+	// addG2 returns the sum of the G2 elements.
+	addG2(p, q *G2El) *G2El
+	// negG1 returns the negation of the G1 element.
+	negG1(p *G1El) *G1El
+	// g1GenNeg returns the negated generator of G1.
+	g1GenNeg() *G1El
+	// g2Gen returns the generator of G2.
+	g2Gen() *G2El
+}
+
+// getGroups returns the [groups] implementation corresponding to the type
+// parameters.
+func getGroups[G1El algebra.G1ElementT, G2El algebra.G2ElementT](api frontend.API) (groups[G1El, G2El], error) {
+	var ret groups[G1El, G2El]
+	switch s := any(&ret).(type) {
+	case *groups[sw_bls12381.G1Affine, sw_bls12381.G2Affine]:
+		g, err := newBLS12381Groups(api)
+		if err != nil {
+			return ret, err
+		}
+		*s = g
+	case *groups[sw_bls12377.G1Affine, sw_bls12377.G2Affine]:
+		*s = &bls12377Groups{api: api}
+	default:
+		return ret, fmt.Errorf("unknown type parametrisation")
+	}
+	return ret, nil
+}
+
+type bls12381Groups struct {
+	api   frontend.API
+	curve *sw_emulated.Curve[emparams.BLS12381Fp, emparams.BLS12381Fr]
+	g1    *sw_bls12381.G1
+	g2    *sw_bls12381.G2
+	ext2  *fields_bls12381.Ext2
+}
+
+func newBLS12381Groups(api frontend.API) (*bls12381Groups, error) {
+	curve, err := sw_emulated.New[emparams.BLS12381Fp, emparams.BLS12381Fr](api, sw_emulated.GetBLS12381Params())
+	if err != nil {
+		return nil, fmt.Errorf("new curve: %w", err)
+	}
+	g1, err := sw_bls12381.NewG1(api)
+	if err != nil {
+		return nil, fmt.Errorf("new G1: %w", err)
+	}
+	return &bls12381Groups{
+		api:   api,
+		curve: curve,
+		g1:    g1,
+		g2:    sw_bls12381.NewG2(api),
+		ext2:  fields_bls12381.NewExt2(api),
+	}, nil
+}
+
+func (g *bls12381Groups) hashToG1(msg []uints.U8, dst []byte) (*sw_bls12381.G1Affine, error) {
+	return g.g1.HashToG1(msg, dst)
+}
+
+func (g *bls12381Groups) hashToG2(msg []uints.U8, dst []byte) (*sw_bls12381.G2Affine, error) {
+	return g.g2.HashToG2(msg, dst)
+}
+
+func (g *bls12381Groups) addG1(p, q *sw_bls12381.G1Affine) *sw_bls12381.G1Affine {
+	return g.curve.AddUnified(p, q)
+}
+
+// addG2 uses the unified addition formula, which also handles p = q, p = -q
+// and the point at infinity (0,0).
+func (g *bls12381Groups) addG2(p, q *sw_bls12381.G2Affine) *sw_bls12381.G2Affine {
+	// selector1 = 1 when p is (0,0) and 0 otherwise
+	selector1 := g.api.And(g.ext2.IsZero(&p.P.X), g.ext2.IsZero(&p.P.Y))
+	// selector2 = 1 when q is (0,0) and 0 otherwise
+	selector2 := g.api.And(g.ext2.IsZero(&q.P.X), g.ext2.IsZero(&q.P.Y))
+
+	// λ = ((p.x+q.x)² - p.x*q.x)/(p.y + q.y), the twist has a = 0
+	pxqx := g.ext2.Mul(&p.P.X, &q.P.X)
+	pxplusqx := g.ext2.Add(&p.P.X, &q.P.X)
+	num := g.ext2.Sub(g.ext2.Square(pxplusqx), pxqx)
+	denum := g.ext2.Add(&p.P.Y, &q.P.Y)
+	// if p.y + q.y = 0, assign dummy 1 to denum and continue
+	selector3 := g.ext2.IsZero(denum)
+	denum = g.ext2.Select(selector3, g.ext2.One(), denum)
+	λ := g.ext2.DivUnchecked(num, denum)
+
+	// x = λ²-p.x-q.x
+	x := g.ext2.Sub(g.ext2.Square(λ), pxplusqx)
+	// y = λ(p.x-x)-p.y
+	y := g.ext2.Sub(g.ext2.Mul(λ, g.ext2.Sub(&p.P.X, x)), &p.P.Y)
+
+	zero := g.ext2.Zero()
+	// if p=(0,0) return q, if q=(0,0) return p, if p.y + q.y = 0 return (0,0)
+	for _, c := range []struct {
+		sel  frontend.Variable
+		x, y *fields_bls12381.E2
+	}{{selector1, &q.P.X, &q.P.Y}, {selector2, &p.P.X, &p.P.Y}, {selector3, zero, zero}} {
+		x = g.ext2.Select(c.sel, c.x, x)
+		y = g.ext2.Select(c.sel, c.y, y)
+	}
+	var res sw_bls12381.G2Affine
+	res.P.X = *x
+	res.P.Y = *y
+	return &res
+}
+
+func (g *bls12381Groups) negG1(p *sw_bls12381.G1Affine) *sw_bls12381.G1Affine {
+	return g.curve.Neg(p)
+}
+
+func (g *bls12381Groups) g1GenNeg() *sw_bls12381.G1Affine {
+	_, _, g1, _ := bls12381.Generators()
+	g1.Neg(&g1)
+	res := sw_bls12381.NewG1Affine(g1)
+	return &res
+}
+
+func (g *bls12381Groups) g2Gen() *sw_bls12381.G2Affine {
+	_, _, _, g2 := bls12381.Generators()
+	res := sw_bls12381.NewG2AffineFixed(g2)
+	return &res
+}
+
+type bls12377Groups struct {
+	api frontend.API
+}
+
+func (g *bls12377Groups) hashToG1(msg []uints.U8, dst []byte) (*sw_bls12377.G1Affine, error) {
+	res, err := sw_bls12377.HashToG1(g.api, msg, dst)
+	return &res, err
+}
+
+func (g *bls12377Groups) hashToG2(msg []uints.U8, dst []byte) (*sw_bls12377.G2Affine, error) {
+	res, err := sw_bls12377.HashToG2(g.api, msg, dst)
+	return &res, err
+}
+
+func (g *bls12377Groups) addG1(p, q *sw_bls12377.G1Affine) *sw_bls12377.G1Affine {
+	res := *p
+	res.AddUnified(g.api, *q)
+	return &res
+}
+
+func (g *bls12377Groups) addG2(p, q *sw_bls12377.G2Affine) *sw_bls12377.G2Affine {
+	res := sw_bls12377.G2Affine{P: p.P}
+	res.P.AddUnified(g.api, q.P)
+	return &res
+}
+
+func (g *bls12377Groups) negG1(p *sw_bls12377.G1Affine) *sw_bls12377.G1Affine {
+	var res sw_bls12377.G1Affine
+	res.Neg(g.api, *p)
+	return &res
+}
+
+func (g *bls12377Groups) g1GenNeg() *sw_bls12377.G1Affine {
+	_, _, g1, _ := bls12377.Generators()
+	g1.Neg(&g1)
+	res := sw_bls12377.NewG1Affine(g1)
+	return &res
+}
+
+func (g *bls12377Groups) g2Gen() *sw_bls12377.G2Affine {
+	_, _, _, g2 := bls12377.Generators()
+	res := sw_bls12377.NewG2AffineFixed(g2)
+	return &res
+}
@@ -0,0 +1,118 @@
+package ecdsa
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/algopts"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// BatchVerify asserts that the signatures sigs verify for the messages msgs
+// and public keys pks. The curve parameters params define the elliptic curve.
+//
+// For every signature (r_i, s_i) the signature commitment R_i = (r_i, y_i) is
+// given by the prover and we check the random linear combination
+//
+//	∑ c^i ([m_i/s_i]G + [r_i/s_i]PK_i - R_i) = 0
+//
+// using a single multi-scalar multiplication. The challenge c is derived from a
+// commitment to the public keys, messages, signatures and commitments R_i, so
+// the builder must implement [frontend.Committer].
+//
+// Compared to [PublicKey.Verify], the check additionally requires that the
+// x-coordinate of R_i is r_i and not r_i + n. This holds for all but a
+// negligible fraction of signatures on curves where n is close to the base
+// field modulus, such as secp256k1 and P-256.
+//
+// The options opts are passed to the multi-scalar multiplication. We assume
+// that the messages msgs are already hashed to the scalar field.
+func BatchVerify[T, S emulated.FieldParams](api frontend.API, params sw_emulated.CurveParams, pks []*PublicKey[T, S], msgs []*emulated.Element[S], sigs []*Signature[S], opts ...algopts.AlgebraOption) error {
+	if len(pks) != len(msgs) || len(pks) != len(sigs) {
+		return fmt.Errorf("mismatching number of public keys (%d), messages (%d) and signatures (%d)", len(pks), len(msgs), len(sigs))
+	}
+	if len(pks) == 0 {
+		return fmt.Errorf("no signatures to verify")
+	}
+	cr, err := sw_emulated.New[T, S](api, params)
+	if err != nil {
+		return fmt.Errorf("new curve: %w", err)
+	}
+	scalarApi, err := emulated.NewField[S](api)
+	if err != nil {
+		return fmt.Errorf("new scalar field: %w", err)
+	}
+	baseApi, err := emulated.NewField[T](api)
+	if err != nil {
+		return fmt.Errorf("new base field: %w", err)
+	}
+
+	committer, ok := api.Compiler().(frontend.Committer)
+	if !ok {
+		return fmt.Errorf("builder does not implement frontend.Committer")
+	}
+
+	var fp T
+	var fr S
+	Rs := make([]*sw_emulated.AffinePoint[T], len(pks))
+	var toCommit []frontend.Variable
+	for i := range pks {
+		// the hint returns an error if the signature does not verify.
+		ylimbs, err := api.Compiler().NewHint(commitmentYHint, int(fp.NbLimbs()), commitmentYHintArgs(baseApi, scalarApi, params.A, params.Gx, params.Gy, pks[i], msgs[i], sigs[i])...)
+		if err != nil {
+			return fmt.Errorf("commitment hint: %w", err)
+		}
+		rbits := scalarApi.ToBitsCanonical(&sigs[i].R)
+		Rs[i] = &sw_emulated.AffinePoint[T]{
+			X: *baseApi.FromBits(rbits...),
+			Y: *baseApi.NewElement(ylimbs),
+		}
+		cr.AssertIsOnCurve(Rs[i])
+		toCommit = append(toCommit, pks[i].X.Limbs...)
+		toCommit = append(toCommit, pks[i].Y.Limbs...)
+		toCommit = append(toCommit, msgs[i].Limbs...)
+		toCommit = append(toCommit, sigs[i].R.Limbs...)
+		toCommit = append(toCommit, sigs[i].S.Limbs...)
+		toCommit = append(toCommit, ylimbs...)
+	}
+	cmt, err := committer.Commit(toCommit...)
+	if err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	// the challenge is smaller than the scalar field modulus
+	cbits := bits.ToBinary(api, cmt)
+	challenge := scalarApi.FromBits(cbits[:min(len(cbits), fr.Modulus().BitLen()-1)]...)
+
+	points := make([]*sw_emulated.AffinePoint[T], 1, 2*len(pks)+1)
+	scalars := make([]*emulated.Element[S], 1, 2*len(pks)+1)
+	points[0] = cr.Generator()
+	// we accumulate the coefficient of the generator starting from 1. Then
+	// the result of the multi-scalar multiplication is the generator instead
+	// of the point at infinity which we cannot represent in affine
+	// coordinates.
+	gScalar := scalarApi.One()
+	coef := challenge
+	for i := range pks {
+		sInv := scalarApi.Inverse(&sigs[i].S)
+		msInv := scalarApi.Mul(msgs[i], sInv)
+		rsInv := scalarApi.Mul(&sigs[i].R, sInv)
+		gScalar = scalarApi.Add(gScalar, scalarApi.Mul(coef, msInv))
+		pkpt := sw_emulated.AffinePoint[T](*pks[i])
+		points = append(points, &pkpt, cr.Neg(Rs[i]))
+		scalars = append(scalars, scalarApi.Mul(coef, rsInv), coef)
+		if i < len(pks)-1 {
+			coef = scalarApi.Mul(coef, challenge)
+		}
+	}
+	// the scalar multiplication uses the non-canonical bit decomposition of
+	// the scalars, so we need to reduce the accumulated sum.
+	scalars[0] = scalarApi.Reduce(gScalar)
+	res, err := cr.MultiScalarMul(points, scalars, opts...)
+	if err != nil {
+		return fmt.Errorf("multi-scalar multiplication: %w", err)
+	}
+	cr.AssertIsEqual(res, cr.Generator())
+	return nil
+}
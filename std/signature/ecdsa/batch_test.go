@@ -0,0 +1,100 @@
+package ecdsa
+
+import (
+	cryptoecdsa "crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/ecdsa"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+)
+
+type BatchVerifyCircuit[T, S emulated.FieldParams] struct {
+	Sigs []Signature[S]
+	Msgs []emulated.Element[S]
+	Pubs []PublicKey[T, S]
+}
+
+func (c *BatchVerifyCircuit[T, S]) Define(api frontend.API) error {
+	pks := make([]*PublicKey[T, S], len(c.Pubs))
+	msgs := make([]*emulated.Element[S], len(c.Msgs))
+	sigs := make([]*Signature[S], len(c.Sigs))
+	for i := range c.Pubs {
+		pks[i], msgs[i], sigs[i] = &c.Pubs[i], &c.Msgs[i], &c.Sigs[i]
+	}
+	return BatchVerify(api, sw_emulated.GetCurveParams[T](), pks, msgs, sigs)
+}
+
+func newBatchVerifyCircuit[T, S emulated.FieldParams](nbSigs int) *BatchVerifyCircuit[T, S] {
+	return &BatchVerifyCircuit[T, S]{
+		Sigs: make([]Signature[S], nbSigs),
+		Msgs: make([]emulated.Element[S], nbSigs),
+		Pubs: make([]PublicKey[T, S], nbSigs),
+	}
+}
+
+func TestBatchVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+	const nbSigs = 3
+	circuit := newBatchVerifyCircuit[emulated.Secp256k1Fp, emulated.Secp256k1Fr](nbSigs)
+	witness := newBatchVerifyCircuit[emulated.Secp256k1Fp, emulated.Secp256k1Fr](nbSigs)
+	for i := 0; i < nbSigs; i++ {
+		privKey, _ := ecdsa.GenerateKey(rand.Reader)
+		msg := []byte(fmt.Sprintf("testing ECDSA (batch %d)", i))
+		_, r, s, err := privKey.SignForRecover(msg, nil)
+		assert.NoError(err)
+		witness.Sigs[i] = Signature[emulated.Secp256k1Fr]{
+			R: emulated.ValueOf[emulated.Secp256k1Fr](r),
+			S: emulated.ValueOf[emulated.Secp256k1Fr](s),
+		}
+		witness.Msgs[i] = emulated.ValueOf[emulated.Secp256k1Fr](ecdsa.HashToInt(msg))
+		witness.Pubs[i] = PublicKey[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+			X: emulated.ValueOf[emulated.Secp256k1Fp](privKey.PublicKey.A.X),
+			Y: emulated.ValueOf[emulated.Secp256k1Fp](privKey.PublicKey.A.Y),
+		}
+	}
+	err := test.IsSolved(circuit, witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// a single invalid signature fails the batch
+	witness.Msgs[1] = emulated.ValueOf[emulated.Secp256k1Fr](1)
+	err = test.IsSolved(circuit, witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+func TestBatchVerifyP256(t *testing.T) {
+	assert := test.NewAssert(t)
+	const nbSigs = 2
+	circuit := newBatchVerifyCircuit[emulated.P256Fp, emulated.P256Fr](nbSigs)
+	witness := newBatchVerifyCircuit[emulated.P256Fp, emulated.P256Fr](nbSigs)
+	for i := 0; i < nbSigs; i++ {
+		privKey, _ := cryptoecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		msgHash := sha256.Sum256([]byte(fmt.Sprintf("testing ECDSA (batch %d)", i)))
+		r, s, err := cryptoecdsa.Sign(rand.Reader, privKey, msgHash[:])
+		assert.NoError(err)
+		witness.Sigs[i] = Signature[emulated.P256Fr]{
+			R: emulated.ValueOf[emulated.P256Fr](r),
+			S: emulated.ValueOf[emulated.P256Fr](s),
+		}
+		witness.Msgs[i] = emulated.ValueOf[emulated.P256Fr](msgHash[:])
+		witness.Pubs[i] = PublicKey[emulated.P256Fp, emulated.P256Fr]{
+			X: emulated.ValueOf[emulated.P256Fp](privKey.PublicKey.X),
+			Y: emulated.ValueOf[emulated.P256Fp](privKey.PublicKey.Y),
+		}
+	}
+	err := test.IsSolved(circuit, witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
+
+func TestBatchVerifyMismatch(t *testing.T) {
+	assert := test.NewAssert(t)
+	err := BatchVerify[emulated.Secp256k1Fp, emulated.Secp256k1Fr](nil, sw_emulated.GetSecp256k1Params(), make([]*PublicKey[emulated.Secp256k1Fp, emulated.Secp256k1Fr], 2), nil, nil)
+	assert.Error(err)
+}
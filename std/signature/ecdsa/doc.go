@@ -6,6 +6,11 @@
 // verification in a BN254-SNARK is approximately 122k constraints in R1CS and
 // 453k constraints in PLONKish.
 //
+// When verifying many signatures, [BatchVerify] checks a random linear
+// combination of the verification equations using a single multi-scalar
+// multiplication. [Recover] computes the public key from the signature and
+// recovery identifier.
+//
 // See [ECDSA] for the signature verification algorithm.
 //
 // [ECDSA]:
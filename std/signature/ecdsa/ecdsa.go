@@ -1,8 +1,11 @@
 package ecdsa
 
 import (
+	"math/big"
+
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/bits"
 	"github.com/consensys/gnark/std/math/emulated"
 )
 
@@ -48,3 +51,63 @@ func (pk PublicKey[T, S]) Verify(api frontend.API, params sw_emulated.CurveParam
 		api.AssertIsEqual(rbits[i], qxBits[i])
 	}
 }
+
+// Recover returns the public key which verifies the signature sig for the
+// message msg. The recovery identifier v is in [0, 3] where the least
+// significant bit is the parity of the y-coordinate of the signature
+// commitment R and the most significant bit indicates that R.x = r + n, where
+// n is the scalar field modulus. The curve parameters params define the
+// elliptic curve. See [github.com/consensys/gnark/std/evmprecompiles.ECRecover]
+// for secp256k1 public key recovery with Ethereum-specific checks and failure
+// handling.
+//
+// We assume that the message msg is already hashed to the scalar field. The
+// circuit is not satisfiable when R.x is not a valid x-coordinate of the curve
+// or when r+n is not smaller than the base field modulus.
+func Recover[T, S emulated.FieldParams](api frontend.API, params sw_emulated.CurveParams, msg *emulated.Element[S], sig *Signature[S], v frontend.Variable) *PublicKey[T, S] {
+	cr, err := sw_emulated.New[T, S](api, params)
+	if err != nil {
+		panic(err)
+	}
+	scalarApi, err := emulated.NewField[S](api)
+	if err != nil {
+		panic(err)
+	}
+	baseApi, err := emulated.NewField[T](api)
+	if err != nil {
+		panic(err)
+	}
+	var fr S
+	vbits := bits.ToBinary(api, v, bits.WithNbDigits(2))
+
+	// R.x = r + v[1]*n
+	rbits := scalarApi.ToBitsCanonical(&sig.R)
+	rx := baseApi.FromBits(rbits...)
+	// when v[1] is set, r+n must not wrap around the base field modulus p.
+	var fp T
+	if bound := new(big.Int).Sub(fp.Modulus(), fr.Modulus()); bound.Sign() <= 0 {
+		api.AssertIsEqual(vbits[1], 0)
+	} else {
+		bound.Sub(bound, big.NewInt(1))
+		baseApi.AssertIsLessOrEqual(baseApi.Select(vbits[1], rx, baseApi.Zero()), baseApi.NewElement(bound))
+	}
+	rx = baseApi.Select(vbits[1], baseApi.Add(rx, baseApi.NewElement(fr.Modulus())), rx)
+	// R.y = ±sqrt(x^3 + ax + b) with parity v[0]
+	ry := baseApi.Mul(rx, rx)
+	ry = baseApi.Mul(ry, rx)
+	if params.A.Sign() != 0 {
+		ry = baseApi.Add(ry, baseApi.Mul(rx, baseApi.NewElement(params.A)))
+	}
+	ry = baseApi.Add(ry, baseApi.NewElement(params.B))
+	ry = baseApi.Sqrt(ry)
+	rybits := baseApi.ToBitsCanonical(ry)
+	ry = baseApi.Select(api.Xor(vbits[0], rybits[0]), baseApi.Neg(ry), ry)
+	R := sw_emulated.AffinePoint[T]{X: *rx, Y: *ry}
+
+	// pk = [-msg/r]g + [s/r]R
+	u1 := scalarApi.Reduce(scalarApi.Neg(scalarApi.Div(msg, &sig.R)))
+	u2 := scalarApi.Div(&sig.S, &sig.R)
+	pkpt := cr.JointScalarMulBase(&R, u2, u1)
+	pk := PublicKey[T, S](*pkpt)
+	return &pk
+}
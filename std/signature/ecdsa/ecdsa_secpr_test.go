@@ -109,3 +109,49 @@ func TestEcdsaP384PreHashed(t *testing.T) {
 	assert.NoError(err)
 
 }
+
+// p256RecoveryID computes the recovery identifier for the P-256 signature (r,
+// s) of the pre-hashed message msgHash.
+func p256RecoveryID(pk *cryptoecdsa.PublicKey, msgHash []byte, r, s *big.Int) uint {
+	curve := elliptic.P256()
+	n := curve.Params().N
+	sInv := new(big.Int).ModInverse(s, n)
+	u1 := new(big.Int).SetBytes(msgHash)
+	u1.Mul(u1, sInv).Mod(u1, n)
+	u2 := new(big.Int).Mul(r, sInv)
+	u2.Mod(u2, n)
+	x1, y1 := curve.ScalarBaseMult(u1.Bytes())
+	x2, y2 := curve.ScalarMult(pk.X, pk.Y, u2.Bytes())
+	rx, ry := curve.Add(x1, y1, x2, y2)
+	v := ry.Bit(0)
+	if rx.Cmp(n) >= 0 {
+		v |= 2
+	}
+	return v
+}
+
+func TestRecoverP256(t *testing.T) {
+	assert := test.NewAssert(t)
+	privKey, _ := cryptoecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	msg := []byte("testing ECDSA (recover)")
+	msgHash := sha256.Sum256(msg)
+	r, s, err := cryptoecdsa.Sign(rand.Reader, privKey, msgHash[:])
+	assert.NoError(err)
+	v := p256RecoveryID(&privKey.PublicKey, msgHash[:], r, s)
+
+	circuit := RecoverCircuit[emulated.P256Fp, emulated.P256Fr]{}
+	witness := RecoverCircuit[emulated.P256Fp, emulated.P256Fr]{
+		Sig: Signature[emulated.P256Fr]{
+			R: emulated.ValueOf[emulated.P256Fr](r),
+			S: emulated.ValueOf[emulated.P256Fr](s),
+		},
+		Msg: emulated.ValueOf[emulated.P256Fr](msgHash[:]),
+		V:   v,
+		Pub: PublicKey[emulated.P256Fp, emulated.P256Fr]{
+			X: emulated.ValueOf[emulated.P256Fp](privKey.PublicKey.X),
+			Y: emulated.ValueOf[emulated.P256Fp](privKey.PublicKey.Y),
+		},
+	}
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
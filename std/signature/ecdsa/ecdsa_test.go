@@ -158,3 +158,92 @@ func ExamplePublicKey_Verify_create() {
 	// can continue in the PublicKey Verify example
 	_, _, _, _, _ = sig.R, sig.S, msg, pubx, puby
 }
+
+type RecoverCircuit[T, S emulated.FieldParams] struct {
+	Sig Signature[S]
+	Msg emulated.Element[S]
+	V   frontend.Variable
+	Pub PublicKey[T, S]
+}
+
+func (c *RecoverCircuit[T, S]) Define(api frontend.API) error {
+	cr, err := sw_emulated.New[T, S](api, sw_emulated.GetCurveParams[T]())
+	if err != nil {
+		return err
+	}
+	pk := Recover[T, S](api, sw_emulated.GetCurveParams[T](), &c.Msg, &c.Sig, c.V)
+	expected := sw_emulated.AffinePoint[T](c.Pub)
+	cr.AssertIsEqual((*sw_emulated.AffinePoint[T])(pk), &expected)
+	return nil
+}
+
+func TestRecover(t *testing.T) {
+	assert := test.NewAssert(t)
+	privKey, _ := ecdsa.GenerateKey(rand.Reader)
+	msg := []byte("testing ECDSA (recover)")
+	v, r, s, err := privKey.SignForRecover(msg, nil)
+	assert.NoError(err)
+	hash := ecdsa.HashToInt(msg)
+
+	circuit := RecoverCircuit[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{}
+	witness := RecoverCircuit[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+		Sig: Signature[emulated.Secp256k1Fr]{
+			R: emulated.ValueOf[emulated.Secp256k1Fr](r),
+			S: emulated.ValueOf[emulated.Secp256k1Fr](s),
+		},
+		Msg: emulated.ValueOf[emulated.Secp256k1Fr](hash),
+		V:   v,
+		Pub: PublicKey[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+			X: emulated.ValueOf[emulated.Secp256k1Fp](privKey.PublicKey.A.X),
+			Y: emulated.ValueOf[emulated.Secp256k1Fp](privKey.PublicKey.A.Y),
+		},
+	}
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// wrong parity recovers a different public key
+	witness.V = v ^ 1
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+type RecoverOverflowCircuit struct {
+	Sig Signature[emulated.Secp256k1Fr]
+	Msg emulated.Element[emulated.Secp256k1Fr]
+	V   frontend.Variable
+}
+
+func (c *RecoverOverflowCircuit) Define(api frontend.API) error {
+	Recover[emulated.Secp256k1Fp, emulated.Secp256k1Fr](api, sw_emulated.GetSecp256k1Params(), &c.Msg, &c.Sig, c.V)
+	return nil
+}
+
+func TestRecoverOverflow(t *testing.T) {
+	assert := test.NewAssert(t)
+	var fp emulated.Secp256k1Fp
+	var fr emulated.Secp256k1Fr
+	p, n := fp.Modulus(), fr.Modulus()
+	// find r such that r+n-p is a valid x-coordinate. Without the range check
+	// R.x = r+n would wrap around p to this point.
+	r := new(big.Int).Sub(n, big.NewInt(1))
+	rhs := new(big.Int)
+	for ; ; r.Sub(r, big.NewInt(1)) {
+		x := new(big.Int).Add(r, n)
+		x.Sub(x, p)
+		rhs.Exp(x, big.NewInt(3), p).Add(rhs, big.NewInt(7))
+		if big.Jacobi(rhs, p) == 1 {
+			break
+		}
+	}
+	circuit := RecoverOverflowCircuit{}
+	witness := RecoverOverflowCircuit{
+		Sig: Signature[emulated.Secp256k1Fr]{
+			R: emulated.ValueOf[emulated.Secp256k1Fr](r),
+			S: emulated.ValueOf[emulated.Secp256k1Fr](1),
+		},
+		Msg: emulated.ValueOf[emulated.Secp256k1Fr](1),
+		V:   2,
+	}
+	err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
@@ -0,0 +1,171 @@
+package ecdsa
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hint functions used in the package.
+func GetHints() []solver.Hint {
+	return []solver.Hint{commitmentYHint}
+}
+
+// commitmentYHintArgs packs the inputs for [commitmentYHint]. The hint works
+// over two emulated fields at once and thus we cannot use the field emulation
+// hint wrappers. Instead, we prepend every element with its number of limbs.
+func commitmentYHintArgs[B, S emulated.FieldParams](baseApi *emulated.Field[B], scalarApi *emulated.Field[S], a, gx, gy *big.Int, pk *PublicKey[B, S], msg *emulated.Element[S], sig *Signature[S]) []frontend.Variable {
+	var fp B
+	var fr S
+	args := []frontend.Variable{fp.BitsPerLimb(), fr.BitsPerLimb()}
+	for _, e := range []*emulated.Element[B]{
+		baseApi.Modulus(), baseApi.NewElement(a), baseApi.NewElement(gx), baseApi.NewElement(gy), &pk.X, &pk.Y,
+	} {
+		args = append(args, len(e.Limbs))
+		args = append(args, e.Limbs...)
+	}
+	for _, e := range []*emulated.Element[S]{
+		scalarApi.Modulus(), msg, &sig.R, &sig.S,
+	} {
+		args = append(args, len(e.Limbs))
+		args = append(args, e.Limbs...)
+	}
+	return args
+}
+
+// commitmentYHint computes the y-coordinate of the signature commitment R =
+// [msg/s]G + [r/s]PK. It returns an error when the signature doesn't verify,
+// i.e. when R.x != r.
+func commitmentYHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) < 2 {
+		return fmt.Errorf("expected at least 2 inputs, got %d", len(inputs))
+	}
+	if !inputs[0].IsUint64() || !inputs[1].IsUint64() {
+		return fmt.Errorf("limb widths must be small integers")
+	}
+	baseBits, scalarBits := uint(inputs[0].Uint64()), uint(inputs[1].Uint64())
+	elems := make([]*big.Int, 10)
+	ptr := 2
+	for i := range elems {
+		nbBits := baseBits
+		if i >= 6 {
+			nbBits = scalarBits
+		}
+		if ptr >= len(inputs) || !inputs[ptr].IsUint64() {
+			return fmt.Errorf("malformed input %d", i)
+		}
+		nbLimbs := int(inputs[ptr].Uint64())
+		if ptr+1+nbLimbs > len(inputs) {
+			return fmt.Errorf("not enough limbs for input %d", i)
+		}
+		elems[i] = recompose(inputs[ptr+1:ptr+1+nbLimbs], nbBits)
+		ptr += 1 + nbLimbs
+	}
+	if ptr != len(inputs) {
+		return fmt.Errorf("expected %d inputs, got %d", ptr, len(inputs))
+	}
+	p, a := elems[0], elems[1]
+	g := &nativePoint{x: elems[2], y: elems[3]}
+	pk := &nativePoint{x: elems[4], y: elems[5]}
+	n, msg, r, s := elems[6], elems[7], elems[8], elems[9]
+
+	sInv := new(big.Int).ModInverse(s, n)
+	if sInv == nil {
+		return errors.New("s is not invertible")
+	}
+	u1 := new(big.Int).Mul(msg, sInv)
+	u1.Mod(u1, n)
+	u2 := new(big.Int).Mul(r, sInv)
+	u2.Mod(u2, n)
+	R := nativeAdd(p, a, nativeScalarMul(p, a, g, u1), nativeScalarMul(p, a, pk, u2))
+	if R == nil || R.x.Cmp(new(big.Int).Mod(r, n)) != 0 {
+		return errors.New("signature does not verify")
+	}
+	return decompose(R.y, baseBits, outputs)
+}
+
+// nativePoint is a short Weierstrass point in affine coordinates. The point at
+// infinity is represented by nil.
+type nativePoint struct {
+	x, y *big.Int
+}
+
+func nativeAdd(p, a *big.Int, P, Q *nativePoint) *nativePoint {
+	if P == nil {
+		return Q
+	}
+	if Q == nil {
+		return P
+	}
+	lambda := new(big.Int)
+	if P.x.Cmp(Q.x) == 0 {
+		if sum := new(big.Int).Add(P.y, Q.y); sum.Mod(sum, p).Sign() == 0 {
+			return nil
+		}
+		// λ = (3x^2+a)/(2y)
+		lambda.Mul(P.x, P.x)
+		lambda.Mul(lambda, big.NewInt(3))
+		lambda.Add(lambda, a)
+		den := new(big.Int).Lsh(P.y, 1)
+		den.ModInverse(den, p)
+		lambda.Mul(lambda, den)
+	} else {
+		// λ = (y2-y1)/(x2-x1)
+		lambda.Sub(Q.y, P.y)
+		den := new(big.Int).Sub(Q.x, P.x)
+		den.Mod(den, p)
+		den.ModInverse(den, p)
+		lambda.Mul(lambda, den)
+	}
+	lambda.Mod(lambda, p)
+	x := new(big.Int).Mul(lambda, lambda)
+	x.Sub(x, P.x)
+	x.Sub(x, Q.x)
+	x.Mod(x, p)
+	y := new(big.Int).Sub(P.x, x)
+	y.Mul(y, lambda)
+	y.Sub(y, P.y)
+	y.Mod(y, p)
+	return &nativePoint{x: x, y: y}
+}
+
+func nativeScalarMul(p, a *big.Int, P *nativePoint, s *big.Int) *nativePoint {
+	var res *nativePoint
+	for i := s.BitLen() - 1; i >= 0; i-- {
+		res = nativeAdd(p, a, res, res)
+		if s.Bit(i) == 1 {
+			res = nativeAdd(p, a, res, P)
+		}
+	}
+	return res
+}
+
+func recompose(inputs []*big.Int, nbBits uint) *big.Int {
+	res := new(big.Int)
+	for i := range inputs {
+		res.Lsh(res, nbBits)
+		res.Add(res, inputs[len(inputs)-i-1])
+	}
+	return res
+}
+
+func decompose(input *big.Int, nbBits uint, res []*big.Int) error {
+	if input.BitLen() > len(res)*int(nbBits) {
+		return fmt.Errorf("decomposed integer does not fit into res")
+	}
+	base := new(big.Int).Lsh(big.NewInt(1), nbBits)
+	tmp := new(big.Int).Set(input)
+	for i := range res {
+		res[i].Mod(tmp, base)
+		tmp.Rsh(tmp, nbBits)
+	}
+	return nil
+}
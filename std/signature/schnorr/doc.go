@@ -0,0 +1,18 @@
+// Package schnorr implements BIP-340 Schnorr signature verification over the
+// secp256k1 curve.
+//
+// The package depends on the [emulated/sw_emulated] package for elliptic curve
+// group operations using non-native arithmetic and on the [hash/sha2] package
+// for computing the tagged challenge hash. Public keys are given in x-only form
+// and lifted in-circuit to the curve point with even y-coordinate. Many
+// signatures can be verified at once using [BatchVerify], which checks a random
+// linear combination of the verification equations using a single multi-scalar
+// multiplication.
+//
+// For creating signatures and witness assignments out-circuit see [Sign],
+// [ValueOfPublicKey] and [ValueOfSignature].
+//
+// See [BIP-340] for the signature scheme.
+//
+// [BIP-340]: https://github.com/bitcoin/bips/blob/master/bip-0340.mediawiki
+package schnorr
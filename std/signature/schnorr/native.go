@@ -0,0 +1,106 @@
+package schnorr
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fp"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// taggedHash computes sha256(sha256(tag) || sha256(tag) || data...).
+func taggedHash(tag string, data ...[]byte) []byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for i := range data {
+		h.Write(data[i])
+	}
+	return h.Sum(nil)
+}
+
+// XOnlyPublicKey returns the 32-byte x-only public key corresponding to the
+// secret key sk.
+func XOnlyPublicKey(sk *big.Int) ([]byte, error) {
+	if sk.Sign() <= 0 || sk.Cmp(fr.Modulus()) >= 0 {
+		return nil, errors.New("secret key out of range")
+	}
+	var P secp256k1.G1Affine
+	P.ScalarMultiplicationBase(sk)
+	px := P.X.Bytes()
+	return px[:], nil
+}
+
+// Sign signs the message msg using the secret key sk and auxiliary randomness
+// auxRand as defined in BIP-340. It returns the 64-byte signature. The
+// auxiliary randomness should be 32 bytes of fresh randomness, but signing is
+// still secure with all-zero auxRand.
+func Sign(sk *big.Int, msg []byte, auxRand []byte) ([]byte, error) {
+	if sk.Sign() <= 0 || sk.Cmp(fr.Modulus()) >= 0 {
+		return nil, errors.New("secret key out of range")
+	}
+	if len(auxRand) != 32 {
+		return nil, fmt.Errorf("auxiliary randomness must be 32 bytes, got %d", len(auxRand))
+	}
+	var P secp256k1.G1Affine
+	P.ScalarMultiplicationBase(sk)
+	d := new(big.Int).Set(sk)
+	if P.Y.BigInt(new(big.Int)).Bit(0) == 1 {
+		d.Sub(fr.Modulus(), d)
+	}
+	var dbuf [fr.Bytes]byte
+	d.FillBytes(dbuf[:])
+	t := taggedHash(TagAux, auxRand)
+	for i := range t {
+		t[i] ^= dbuf[i]
+	}
+	px := P.X.Bytes()
+	k := new(big.Int).SetBytes(taggedHash(TagNonce, t, px[:], msg))
+	k.Mod(k, fr.Modulus())
+	if k.Sign() == 0 {
+		return nil, errors.New("zero nonce")
+	}
+	var R secp256k1.G1Affine
+	R.ScalarMultiplicationBase(k)
+	if R.Y.BigInt(new(big.Int)).Bit(0) == 1 {
+		k.Sub(fr.Modulus(), k)
+	}
+	rx := R.X.Bytes()
+	e := new(big.Int).SetBytes(taggedHash(TagChallenge, rx[:], px[:], msg))
+	e.Mod(e, fr.Modulus())
+	s := new(big.Int).Mul(e, d)
+	s.Add(s, k)
+	s.Mod(s, fr.Modulus())
+	sig := make([]byte, 64)
+	copy(sig[:32], rx[:])
+	s.FillBytes(sig[32:])
+	return sig, nil
+}
+
+// ValueOfPublicKey returns the witness assignment for the 32-byte x-only public
+// key pk.
+func ValueOfPublicKey(pk []byte) (PublicKey, error) {
+	if len(pk) != fp.Bytes {
+		return PublicKey{}, fmt.Errorf("public key must be %d bytes, got %d", fp.Bytes, len(pk))
+	}
+	return PublicKey{
+		X: emulated.ValueOf[emulated.Secp256k1Fp](pk),
+	}, nil
+}
+
+// ValueOfSignature returns the witness assignment for the 64-byte signature
+// sig.
+func ValueOfSignature(sig []byte) (Signature, error) {
+	if len(sig) != fp.Bytes+fr.Bytes {
+		return Signature{}, fmt.Errorf("signature must be %d bytes, got %d", fp.Bytes+fr.Bytes, len(sig))
+	}
+	return Signature{
+		R: emulated.ValueOf[emulated.Secp256k1Fp](sig[:fp.Bytes]),
+		S: emulated.ValueOf[emulated.Secp256k1Fr](sig[fp.Bytes:]),
+	}, nil
+}
@@ -0,0 +1,212 @@
+package schnorr
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/algopts"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// Tags used for domain separation in the tagged hashes of BIP-340.
+const (
+	TagAux       = "BIP0340/aux"
+	TagNonce     = "BIP0340/nonce"
+	TagChallenge = "BIP0340/challenge"
+)
+
+// PublicKey represents the x-only public key to verify the signature for. The
+// corresponding curve point is the one with the even y-coordinate.
+type PublicKey struct {
+	X emulated.Element[emulated.Secp256k1Fp]
+}
+
+// Signature represents the signature for some message. R is the x-coordinate
+// of the nonce commitment with even y-coordinate.
+type Signature struct {
+	R emulated.Element[emulated.Secp256k1Fp]
+	S emulated.Element[emulated.Secp256k1Fr]
+}
+
+// verifier holds the gadgets used for signature verification.
+type verifier struct {
+	api       frontend.API
+	curve     *sw_emulated.Curve[emulated.Secp256k1Fp, emulated.Secp256k1Fr]
+	baseApi   *emulated.Field[emulated.Secp256k1Fp]
+	scalarApi *emulated.Field[emulated.Secp256k1Fr]
+	uapi      *uints.BinaryField[uints.U32]
+}
+
+func newVerifier(api frontend.API) (*verifier, error) {
+	curve, err := sw_emulated.New[emulated.Secp256k1Fp, emulated.Secp256k1Fr](api, sw_emulated.GetSecp256k1Params())
+	if err != nil {
+		return nil, fmt.Errorf("new curve: %w", err)
+	}
+	baseApi, err := emulated.NewField[emulated.Secp256k1Fp](api)
+	if err != nil {
+		return nil, fmt.Errorf("new base field: %w", err)
+	}
+	scalarApi, err := emulated.NewField[emulated.Secp256k1Fr](api)
+	if err != nil {
+		return nil, fmt.Errorf("new scalar field: %w", err)
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, fmt.Errorf("new uints: %w", err)
+	}
+	return &verifier{api: api, curve: curve, baseApi: baseApi, scalarApi: scalarApi, uapi: uapi}, nil
+}
+
+// liftX returns the curve point with the x-coordinate x and even
+// y-coordinate. The circuit is not satisfiable if such point doesn't exist.
+func (v *verifier) liftX(x *emulated.Element[emulated.Secp256k1Fp]) *sw_emulated.AffinePoint[emulated.Secp256k1Fp] {
+	// y^2 = x^3 + 7
+	y := v.baseApi.Mul(x, x)
+	y = v.baseApi.Mul(y, x)
+	y = v.baseApi.Add(y, v.baseApi.NewElement(7))
+	y = v.baseApi.Sqrt(y)
+	ybits := v.baseApi.ToBitsCanonical(y)
+	y = v.baseApi.Select(ybits[0], v.baseApi.Neg(y), y)
+	return &sw_emulated.AffinePoint[emulated.Secp256k1Fp]{X: *x, Y: *y}
+}
+
+// bytes returns the 32-byte big-endian encoding of the field element x.
+func (v *verifier) bytes(x *emulated.Element[emulated.Secp256k1Fp]) []uints.U8 {
+	xbits := v.baseApi.ToBitsCanonical(x)
+	res := make([]uints.U8, 32)
+	for i := range res {
+		res[len(res)-1-i] = v.uapi.ByteValueOf(v.api.FromBinary(xbits[8*i : 8*i+8]...))
+	}
+	return res
+}
+
+// challenge computes e = int(hash_challenge(r || px || msg)) mod n.
+func (v *verifier) challenge(r, px *emulated.Element[emulated.Secp256k1Fp], msg []uints.U8) (*emulated.Element[emulated.Secp256k1Fr], error) {
+	h, err := sha2.New(v.api)
+	if err != nil {
+		return nil, fmt.Errorf("new hasher: %w", err)
+	}
+	// tagged hash prefix sha256(tag) || sha256(tag) is constant
+	tag := sha256.Sum256([]byte(TagChallenge))
+	h.Write(uints.NewU8Array(tag[:]))
+	h.Write(uints.NewU8Array(tag[:]))
+	h.Write(v.bytes(r))
+	h.Write(v.bytes(px))
+	h.Write(msg)
+	digest := h.Sum()
+	ebits := make([]frontend.Variable, 0, 8*len(digest))
+	for i := len(digest) - 1; i >= 0; i-- {
+		ebits = append(ebits, v.api.ToBinary(digest[i].Val, 8)...)
+	}
+	return v.scalarApi.Reduce(v.scalarApi.FromBits(ebits...)), nil
+}
+
+// Verify asserts that the signature sig verifies for the message msg and
+// x-only public key pk as defined in BIP-340. It returns an error only when
+// the hash function cannot be initialised.
+func (pk PublicKey) Verify(api frontend.API, msg []uints.U8, sig *Signature) error {
+	v, err := newVerifier(api)
+	if err != nil {
+		return err
+	}
+	v.baseApi.AssertIsInRange(&pk.X)
+	v.baseApi.AssertIsInRange(&sig.R)
+	v.scalarApi.AssertIsInRange(&sig.S)
+	P := v.liftX(&pk.X)
+	e, err := v.challenge(&sig.R, &pk.X, msg)
+	if err != nil {
+		return err
+	}
+	// R = [s]G - [e]P
+	R := v.curve.JointScalarMulBase(P, v.scalarApi.Reduce(v.scalarApi.Neg(e)), &sig.S)
+	v.baseApi.AssertIsEqual(&R.X, &sig.R)
+	rybits := v.baseApi.ToBitsCanonical(&R.Y)
+	api.AssertIsEqual(rybits[0], 0)
+	return nil
+}
+
+// BatchVerify asserts that the signatures sigs verify for the messages msgs
+// and x-only public keys pks as defined in BIP-340.
+//
+// Instead of verifying every signature separately, we check the random linear
+// combination
+//
+//	∑ c^i ([s_i]G - [e_i]P_i - R_i) = 0
+//
+// using a single multi-scalar multiplication. The coefficient c is derived
+// from a commitment to the lifted points P_i and R_i, the scalars s_i and the
+// challenges e_i (which bind the messages), so the builder must implement
+// [frontend.Committer]. The options opts are passed to
+// the multi-scalar multiplication.
+func BatchVerify(api frontend.API, pks []*PublicKey, msgs [][]uints.U8, sigs []*Signature, opts ...algopts.AlgebraOption) error {
+	if len(pks) != len(msgs) || len(pks) != len(sigs) {
+		return fmt.Errorf("mismatching number of public keys (%d), messages (%d) and signatures (%d)", len(pks), len(msgs), len(sigs))
+	}
+	if len(pks) == 0 {
+		return fmt.Errorf("no signatures to verify")
+	}
+	v, err := newVerifier(api)
+	if err != nil {
+		return err
+	}
+	committer, ok := api.Compiler().(frontend.Committer)
+	if !ok {
+		return fmt.Errorf("builder does not implement frontend.Committer")
+	}
+	Ps := make([]*sw_emulated.AffinePoint[emulated.Secp256k1Fp], len(pks))
+	Rs := make([]*sw_emulated.AffinePoint[emulated.Secp256k1Fp], len(pks))
+	es := make([]*emulated.Element[emulated.Secp256k1Fr], len(pks))
+	var toCommit []frontend.Variable
+	for i := range pks {
+		v.baseApi.AssertIsInRange(&pks[i].X)
+		v.baseApi.AssertIsInRange(&sigs[i].R)
+		v.scalarApi.AssertIsInRange(&sigs[i].S)
+		Ps[i] = v.liftX(&pks[i].X)
+		Rs[i] = v.liftX(&sigs[i].R)
+		if es[i], err = v.challenge(&sigs[i].R, &pks[i].X, msgs[i]); err != nil {
+			return err
+		}
+		for _, e := range []*emulated.Element[emulated.Secp256k1Fp]{&Ps[i].X, &Ps[i].Y, &Rs[i].X, &Rs[i].Y} {
+			toCommit = append(toCommit, e.Limbs...)
+		}
+		toCommit = append(toCommit, sigs[i].S.Limbs...)
+		toCommit = append(toCommit, es[i].Limbs...)
+	}
+	cmt, err := committer.Commit(toCommit...)
+	if err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	var fr emulated.Secp256k1Fr
+	cbits := bits.ToBinary(api, cmt)
+	c := v.scalarApi.FromBits(cbits[:min(len(cbits), fr.Modulus().BitLen()-1)]...)
+
+	points := make([]*sw_emulated.AffinePoint[emulated.Secp256k1Fp], 1, 2*len(pks)+1)
+	scalars := make([]*emulated.Element[emulated.Secp256k1Fr], 1, 2*len(pks)+1)
+	// G with coefficient 1 offsets the sum so that it is not the point at
+	// infinity.
+	points[0] = v.curve.Generator()
+	gScalar := v.scalarApi.One()
+	coef := c
+	for i := range pks {
+		gScalar = v.scalarApi.Add(gScalar, v.scalarApi.Mul(coef, &sigs[i].S))
+		points = append(points, v.curve.Neg(Ps[i]), v.curve.Neg(Rs[i]))
+		scalars = append(scalars, v.scalarApi.Mul(coef, es[i]), coef)
+		if i < len(pks)-1 {
+			coef = v.scalarApi.Mul(coef, c)
+		}
+	}
+	// the scalar multiplication uses the non-canonical bit decomposition of
+	// the scalars, so we need to reduce the accumulated sum.
+	scalars[0] = v.scalarApi.Reduce(gScalar)
+	res, err := v.curve.MultiScalarMul(points, scalars, opts...)
+	if err != nil {
+		return fmt.Errorf("multi-scalar multiplication: %w", err)
+	}
+	v.curve.AssertIsEqual(res, v.curve.Generator())
+	return nil
+}
@@ -0,0 +1,146 @@
+package schnorr
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+func TestSignVectors(t *testing.T) {
+	// test vectors from BIP-340
+	vectors := []struct {
+		sk, pk, aux, msg, sig string
+	}{
+		{
+			sk:  "0000000000000000000000000000000000000000000000000000000000000003",
+			pk:  "F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+			aux: "0000000000000000000000000000000000000000000000000000000000000000",
+			msg: "0000000000000000000000000000000000000000000000000000000000000000",
+			sig: "E907831F80848D1069A5371B402410364BDF1C5F8307B0084C55F1CE2DCA821525F66A4A85EA8B71E482A74F382D2CE5EBEEE8FDB2172F477DF4900D310536C0",
+		},
+		{
+			sk:  "B7E151628AED2A6ABF7158809CF4F3C762E7160F38B4DA56A784D9045190CFEF",
+			pk:  "DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+			aux: "0000000000000000000000000000000000000000000000000000000000000001",
+			msg: "243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+			sig: "6896BD60EEAE296DB48A229FF71DFE071BDE413E6D43F917DC8DCF8C78DE33418906D11AC976ABCCB20B091292BFF4EA897EFCB639EA871CFA95F6DE339E4B0A",
+		},
+	}
+	assert := test.NewAssert(t)
+	for _, v := range vectors {
+		sk, _ := new(big.Int).SetString(v.sk, 16)
+		aux, _ := hex.DecodeString(v.aux)
+		msg, _ := hex.DecodeString(v.msg)
+		pk, err := XOnlyPublicKey(sk)
+		assert.NoError(err)
+		assert.Equal(v.pk, fmt.Sprintf("%X", pk))
+		sig, err := Sign(sk, msg, aux)
+		assert.NoError(err)
+		assert.Equal(v.sig, fmt.Sprintf("%X", sig))
+	}
+}
+
+type SchnorrCircuit struct {
+	Pub PublicKey
+	Sig Signature
+	Msg []uints.U8
+}
+
+func (c *SchnorrCircuit) Define(api frontend.API) error {
+	return c.Pub.Verify(api, c.Msg, &c.Sig)
+}
+
+func randomSignature(t *testing.T, msg []byte) (pk, sig []byte) {
+	sk, err := rand.Int(rand.Reader, fr.Modulus())
+	if err != nil {
+		t.Fatal(err)
+	}
+	aux := make([]byte, 32)
+	if _, err := rand.Read(aux); err != nil {
+		t.Fatal(err)
+	}
+	if pk, err = XOnlyPublicKey(sk); err != nil {
+		t.Fatal(err)
+	}
+	if sig, err = Sign(sk, msg, aux); err != nil {
+		t.Fatal(err)
+	}
+	return pk, sig
+}
+
+func TestSchnorr(t *testing.T) {
+	assert := test.NewAssert(t)
+	msg := []byte("testing BIP-340 Schnorr signature")
+	pk, sig := randomSignature(t, msg)
+	pub, err := ValueOfPublicKey(pk)
+	assert.NoError(err)
+	s, err := ValueOfSignature(sig)
+	assert.NoError(err)
+
+	circuit := SchnorrCircuit{Msg: make([]uints.U8, len(msg))}
+	witness := SchnorrCircuit{Pub: pub, Sig: s, Msg: uints.NewU8Array(msg)}
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// tampered message does not verify
+	tampered := append([]byte{}, msg...)
+	tampered[0] ^= 1
+	witness.Msg = uints.NewU8Array(tampered)
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+type BatchVerifyCircuit struct {
+	Pubs []PublicKey
+	Sigs []Signature
+	Msgs [][]uints.U8
+}
+
+func (c *BatchVerifyCircuit) Define(api frontend.API) error {
+	pks := make([]*PublicKey, len(c.Pubs))
+	sigs := make([]*Signature, len(c.Sigs))
+	for i := range c.Pubs {
+		pks[i], sigs[i] = &c.Pubs[i], &c.Sigs[i]
+	}
+	return BatchVerify(api, pks, c.Msgs, sigs)
+}
+
+func TestBatchVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+	const nbSigs = 3
+	circuit := BatchVerifyCircuit{
+		Pubs: make([]PublicKey, nbSigs),
+		Sigs: make([]Signature, nbSigs),
+		Msgs: make([][]uints.U8, nbSigs),
+	}
+	witness := BatchVerifyCircuit{
+		Pubs: make([]PublicKey, nbSigs),
+		Sigs: make([]Signature, nbSigs),
+		Msgs: make([][]uints.U8, nbSigs),
+	}
+	for i := 0; i < nbSigs; i++ {
+		msg := []byte(fmt.Sprintf("testing BIP-340 Schnorr (batch %d)", i))
+		pk, sig := randomSignature(t, msg)
+		pub, err := ValueOfPublicKey(pk)
+		assert.NoError(err)
+		s, err := ValueOfSignature(sig)
+		assert.NoError(err)
+		circuit.Msgs[i] = make([]uints.U8, len(msg))
+		witness.Pubs[i], witness.Sigs[i], witness.Msgs[i] = pub, s, uints.NewU8Array(msg)
+	}
+	err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// swapping the signatures fails the batch
+	witness.Sigs[0], witness.Sigs[1] = witness.Sigs[1], witness.Sigs[0]
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
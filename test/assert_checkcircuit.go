@@ -1,8 +1,6 @@
 package test
 
 import (
-	"crypto/sha256"
-
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/groth16"
@@ -28,11 +26,19 @@ import (
 //   - the circuit can be solved with the constraint system solver
 //   - the circuit can be solved with the prover
 //   - the circuit can be verified with the verifier
-//   - the circuit can be verified with gnark-solidity-checker
+//   - the circuit can be verified with the exported Solidity verifier, on BN254 and, with the
+//     evmcheck build tag, on BLS12-381
 //   - the circuit, witness, proving and verifying keys can be serialized and deserialized
 func (assert *Assert) CheckCircuit(circuit frontend.Circuit, opts ...TestingOption) {
 	// get the testing configuration
 	opt := assert.options(opts...)
+	var engineOpts []TestEngineOption
+	if opt.coverage != nil {
+		engineOpts = append(engineOpts, WithEngineCoverage(opt.coverage))
+		defer func() {
+			assert.Log(opt.coverage.String())
+		}()
+	}
 
 	// for each {curve, backend} tuple
 	for _, curve := range opt.curves {
@@ -49,7 +55,7 @@ func (assert *Assert) CheckCircuit(circuit frontend.Circuit, opts ...TestingOpti
 
 				// check that the assignment is valid with the test engine
 				if !opt.skipTestEngine {
-					err := IsSolved(circuit, w.assignment, curve.ScalarField())
+					err := IsSolved(circuit, w.assignment, curve.ScalarField(), engineOpts...)
 					assert.noError(err, &w)
 				}
 			}
@@ -60,7 +66,7 @@ func (assert *Assert) CheckCircuit(circuit frontend.Circuit, opts ...TestingOpti
 
 				// check that the assignment is invalid with the test engine
 				if !opt.skipTestEngine {
-					err := IsSolved(circuit, w.assignment, curve.ScalarField())
+					err := IsSolved(circuit, w.assignment, curve.ScalarField(), engineOpts...)
 					assert.error(err, &w)
 				}
 			}
@@ -122,33 +128,38 @@ func (assert *Assert) CheckCircuit(circuit frontend.Circuit, opts ...TestingOpti
 					for _, w := range validWitnesses {
 						w := w
 						assert.Run(func(assert *Assert) {
-							checkSolidity := opt.checkSolidity && curve == ecc.BN254
-							proverOpts := opt.proverOpts
-							verifierOpts := opt.verifierOpts
-							if b == backend.GROTH16 {
+							checkSolidity := opt.checkSolidity && (curve == ecc.BN254 || curve == ecc.BLS12_381 && EVMCheck)
+							if b == backend.GROTH16 && curve == ecc.BN254 {
 								// currently groth16 Solidity checker only supports circuits with up to 1 commitment
 								checkSolidity = checkSolidity && (len(ccs.GetCommitments().CommitmentIndexes()) <= 1)
-								// additionally, we use sha256 as hash to field (fixed in Solidity contract)
-								proverOpts = append(proverOpts, backend.WithProverHashToFieldFunction(sha256.New()))
-								verifierOpts = append(verifierOpts, backend.WithVerifierHashToFieldFunction(sha256.New()))
 							}
-							proof, err := concreteBackend.prove(ccs, pk, w.full, proverOpts...)
-							assert.noError(err, &w)
-
-							err = concreteBackend.verify(proof, vk, w.public, verifierOpts...)
-							assert.noError(err, &w)
-
-							if checkSolidity {
-								// check that the proof can be verified by gnark-solidity-checker
-								if _vk, ok := vk.(solidity.VerifyingKey); ok {
-									assert.Run(func(assert *Assert) {
-										assert.solidityVerification(b, _vk, proof, w.public, opt.solidityOpts)
-									}, "solidity")
+							for _, htf := range hashToFieldFunctions(b, checkSolidity) {
+								proverOpts := opt.proverOpts
+								verifierOpts := opt.verifierOpts
+								solidityOpts := opt.solidityOpts
+								if htf.h != 0 {
+									proverOpts = append(proverOpts, backend.WithProverHashToFieldFunction(htf.h.New()))
+									verifierOpts = append(verifierOpts, backend.WithVerifierHashToFieldFunction(htf.h.New()))
+									solidityOpts = append(solidityOpts, solidity.WithHashToFieldFunction(htf.h))
+								}
+								proof, err := concreteBackend.prove(ccs, pk, w.full, proverOpts...)
+								assert.noError(err, &w)
+
+								err = concreteBackend.verify(proof, vk, w.public, verifierOpts...)
+								assert.noError(err, &w)
+
+								if checkSolidity {
+									// check that the proof can be verified by the exported contract
+									if _vk, ok := vk.(solidity.VerifyingKey); ok {
+										assert.Run(func(assert *Assert) {
+											assert.solidityVerification(b, _vk, proof, w.public, solidityOpts)
+										}, "solidity", htf.name)
+									}
 								}
-							}
 
-							// check proof serialization
-							assert.roundTripCheck(proof, proofBuilder, "proof")
+								// check proof serialization
+								assert.roundTripCheck(proof, proofBuilder, "proof")
+							}
 						}, "valid_witness")
 					}
 
@@ -183,6 +194,32 @@ func (assert *Assert) CheckCircuit(circuit frontend.Circuit, opts ...TestingOpti
 	}
 }
 
+// hashToField is a hash to field function of the commitments, h is zero for
+// the default one of the backend.
+type hashToField struct {
+	name string
+	h    solidity.Hash
+}
+
+// hashToFieldFunctions returns the hash to field functions to prove with. When
+// checking the Solidity verifiers, we prove with all the functions they
+// support. Otherwise Groth16 uses sha256, as it is the one of the Solidity
+// verifier.
+func hashToFieldFunctions(b backend.ID, checkSolidity bool) []hashToField {
+	sha256HTF := hashToField{"sha256", solidity.SHA256}
+	keccakHTF := hashToField{"keccak256", solidity.Keccak256}
+	switch {
+	case !checkSolidity && b == backend.GROTH16:
+		return []hashToField{sha256HTF}
+	case !checkSolidity:
+		return []hashToField{{name: "default"}}
+	case b == backend.PLONK:
+		return []hashToField{{name: "default"}, sha256HTF, keccakHTF}
+	default:
+		return []hashToField{sha256HTF, keccakHTF}
+	}
+}
+
 type _witness struct {
 	full       witness.Witness
 	public     witness.Witness
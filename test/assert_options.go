@@ -25,6 +25,8 @@ type testingConfig struct {
 
 	validAssignments   []frontend.Circuit
 	invalidAssignments []frontend.Circuit
+
+	coverage *Coverage
 }
 
 // default options
@@ -130,8 +132,10 @@ func NoTestEngine() TestingOption {
 // NoSolidityChecks is a testing option which disables solidity checks,
 // even when the build tags "solccheck" and "release_checks" are set.
 //
-// When the tags are set; this requires gnark-solidity-checker to be installed, which in turns
-// requires solc and abigen to be reachable in the PATH.
+// When the tags are set; this requires gnark-solidity-checker to be installed, which in
+// turns requires solc and abigen to be reachable in the PATH. The BLS12-381 verifiers are
+// only checked with the additional "evmcheck" build tag, which requires evmchecker to be
+// installed with "go install ." from internal/evmchecker.
 //
 // See https://github.com/ConsenSys/gnark-solidity-checker for more details.
 func NoSolidityChecks() TestingOption {
@@ -187,3 +191,14 @@ func WithSolidityExportOptions(solidityOpts ...solidity.ExportOption) TestingOpt
 		return nil
 	}
 }
+
+// WithCoverage is a testing option which records in cov the branches of Select, Lookup2
+// and selector.Mux selected, and the hints executed, when running the assignments with the
+// test engine. The coverage accumulates across the assignments and the assertions using
+// cov, and is reported in the test log.
+func WithCoverage(cov *Coverage) TestingOption {
+	return func(opt *testingConfig) error {
+		opt.coverage = cov
+		return nil
+	}
+}
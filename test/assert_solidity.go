@@ -2,11 +2,14 @@ package test
 
 import (
 	"encoding/hex"
+	"fmt"
+	"math/big"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
 
+	fr_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/solidity"
@@ -15,16 +18,26 @@ import (
 
 // solidityVerification checks that the exported solidity contract can verify the proof
 // and that the proof is valid.
-// It uses gnark-solidity-checker see test.WithSolidity option.
+// On BN254 it uses gnark-solidity-checker, on BLS12-381 evmchecker if the evmcheck build tag
+// is set, see test.NoSolidityChecks option.
 func (assert *Assert) solidityVerification(b backend.ID, vk solidity.VerifyingKey,
 	proof any,
 	validPublicWitness witness.Witness,
 	opts []solidity.ExportOption,
 ) {
-	if !SolcCheck || len(validPublicWitness.Vector().(fr_bn254.Vector)) == 0 {
-		return // nothing to check, will make solc fail.
+	if !SolcCheck {
+		return
 	}
 	assert.t.Helper()
+	if v, ok := validPublicWitness.Vector().(fr_bls12381.Vector); ok {
+		if EVMCheck && len(v) != 0 {
+			assert.evmVerification(b, vk, proof, validPublicWitness, opts)
+		}
+		return
+	}
+	if len(validPublicWitness.Vector().(fr_bn254.Vector)) == 0 {
+		return // nothing to check, will make solc fail.
+	}
 
 	// set default options for CI when none are provided
 	if len(opts) == 0 {
@@ -99,3 +112,107 @@ func (assert *Assert) solidityVerification(b backend.ID, vk solidity.VerifyingKe
 	out, err = cmd.CombinedOutput()
 	assert.NoError(err, string(out))
 }
+
+// evmVerification checks that the exported solidity contract accepts the proof and
+// rejects it for a different public input. It compiles the contract with solc and runs
+// it in an EVM with the EIP-2537 precompiled contracts using evmchecker, which must be
+// installed from internal/evmchecker.
+func (assert *Assert) evmVerification(b backend.ID, vk solidity.VerifyingKey,
+	proof any,
+	validPublicWitness witness.Witness,
+	opts []solidity.ExportOption,
+) {
+	if len(opts) == 0 {
+		opts = append(opts, solidity.WithPragmaVersion("^0.8.0"))
+	}
+
+	tmpDir, err := os.MkdirTemp("", "gnark-solidity-check*")
+	assert.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	contract := filepath.Join(tmpDir, "gnark_verifier.sol")
+	fSolidity, err := os.Create(contract)
+	assert.NoError(err)
+	err = vk.ExportSolidity(fSolidity, opts...)
+	assert.NoError(err)
+	err = fSolidity.Close()
+	assert.NoError(err)
+
+	_proof, ok := proof.(interface{ MarshalSolidity() []byte })
+	if !ok {
+		panic("proof does not implement MarshalSolidity()")
+	}
+	proofBytes := _proof.MarshalSolidity()
+	inputs := validPublicWitness.Vector().(fr_bls12381.Vector)
+
+	// points in the EIP-2537 encoding: 64 bytes per base field element
+	const g1Size = 2 * 64
+	const groth16ProofSize = g1Size + 2*g1Size + g1Size // A, B in G2 and C
+	nbCommitments := vk.NbPublicWitness() - len(inputs)
+	if b == backend.GROTH16 {
+		expected := groth16ProofSize
+		if nbCommitments > 0 {
+			expected += (nbCommitments + 1) * g1Size
+		}
+		assert.Equal(expected, len(proofBytes), "unexpected size of the proof with %d commitments", nbCommitments)
+	}
+
+	call := func(inputs fr_bls12381.Vector) error {
+		var method string
+		var calldata []byte
+		switch b {
+		case backend.GROTH16:
+			// verifyProof(uint256[16] proof, [uint256[4*n] commitments, uint256[4] pok,] uint256[k] input)
+			// The arguments are static arrays, encoded in place one after the other.
+			// MarshalSolidity gives the points of the proof, followed by the n commitments
+			// and their proof of knowledge when the circuit has commitments.
+			method = "verifyProof"
+			calldata = append(calldata, proofBytes[:groth16ProofSize]...)
+			if nbCommitments > 0 {
+				commitments := proofBytes[groth16ProofSize : groth16ProofSize+nbCommitments*g1Size]
+				pok := proofBytes[groth16ProofSize+nbCommitments*g1Size:]
+				calldata = append(calldata, commitments...)
+				calldata = append(calldata, pok...)
+			}
+			for i := range inputs {
+				calldata = append(calldata, abiWord(inputs[i].BigInt(new(big.Int)))...)
+			}
+		case backend.PLONK:
+			// Verify(bytes proof, uint256[] public_inputs)
+			method = "Verify"
+			paddedLen := (len(proofBytes) + 31) / 32 * 32
+			calldata = append(calldata, abiWord(big.NewInt(0x40))...)
+			calldata = append(calldata, abiWord(big.NewInt(int64(0x60+paddedLen)))...)
+			calldata = append(calldata, abiWord(big.NewInt(int64(len(proofBytes))))...)
+			calldata = append(calldata, proofBytes...)
+			calldata = append(calldata, make([]byte, paddedLen-len(proofBytes))...)
+			calldata = append(calldata, abiWord(big.NewInt(int64(len(inputs))))...)
+			for i := range inputs {
+				calldata = append(calldata, abiWord(inputs[i].BigInt(new(big.Int)))...)
+			}
+		default:
+			panic("not implemented")
+		}
+		cmd := exec.Command("evmchecker", "-solidity", contract, "-method", method, "-calldata", hex.EncodeToString(calldata))
+		assert.t.Log("running ", cmd.String())
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, out)
+		}
+		assert.t.Log(string(out))
+		return nil
+	}
+
+	assert.NoError(call(inputs))
+
+	wrongInputs := make(fr_bls12381.Vector, len(inputs))
+	copy(wrongInputs, inputs)
+	wrongInputs[0].SetUint64(1).Add(&wrongInputs[0], &inputs[0])
+	assert.Error(call(wrongInputs), "the contract accepts a wrong public input")
+}
+
+// abiWord returns the 32 bytes big-endian encoding of x.
+func abiWord(x *big.Int) []byte {
+	var buf [32]byte
+	return x.FillBytes(buf[:])
+}
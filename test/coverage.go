@@ -0,0 +1,187 @@
+package test
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+// Coverage collects, across all the assignments run by the test engine, the branches
+// selected by the calls to Select, Lookup2 and selector.Mux, and the hints executed.
+//
+// See WithCoverage and WithEngineCoverage. It is safe for concurrent use.
+type Coverage struct {
+	lock     sync.Mutex
+	branches map[branchKey]*BranchCoverage
+	hints    map[hintKey]*HintCoverage
+}
+
+// BranchCoverage is the coverage of a call site of a selection gadget.
+type BranchCoverage struct {
+	Kind     string // "Select", "Lookup2" or "Mux"
+	Location string // call site, as dir/file.go:line
+
+	// Taken is the number of times each branch was selected, indexed by the position of
+	// the selected input: for Select(b, i1, i2), Taken[0] counts i1 (b=1) and Taken[1]
+	// counts i2 (b=0); for Lookup2 and Mux, Taken[i] counts the input i.
+	Taken []int
+}
+
+// Covered returns true if all the branches were selected at least once.
+func (b *BranchCoverage) Covered() bool {
+	return len(b.Missing()) == 0
+}
+
+// Missing returns the branches which were never selected.
+func (b *BranchCoverage) Missing() []int {
+	var missing []int
+	for i, n := range b.Taken {
+		if n == 0 {
+			missing = append(missing, i)
+		}
+	}
+	return missing
+}
+
+// HintCoverage is the coverage of a call site of a hint.
+type HintCoverage struct {
+	Name     string // hint name, see solver.GetHintName
+	Location string // call site, as dir/file.go:line
+
+	Executions int // number of executions
+	Errors     int // number of executions which returned an error
+}
+
+type branchKey struct {
+	kind, location string
+}
+
+type hintKey struct {
+	name, location string
+}
+
+// NewCoverage returns an empty coverage.
+func NewCoverage() *Coverage {
+	return &Coverage{
+		branches: make(map[branchKey]*BranchCoverage),
+		hints:    make(map[hintKey]*HintCoverage),
+	}
+}
+
+// Branches returns the coverage of the selection gadgets, sorted by call site.
+func (c *Coverage) Branches() []BranchCoverage {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	res := make([]BranchCoverage, 0, len(c.branches))
+	for _, b := range c.branches {
+		r := *b
+		r.Taken = append([]int(nil), b.Taken...)
+		res = append(res, r)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Location != res[j].Location {
+			return res[i].Location < res[j].Location
+		}
+		return res[i].Kind < res[j].Kind
+	})
+	return res
+}
+
+// Hints returns the coverage of the hints, sorted by call site.
+func (c *Coverage) Hints() []HintCoverage {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	res := make([]HintCoverage, 0, len(c.hints))
+	for _, h := range c.hints {
+		res = append(res, *h)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Location != res[j].Location {
+			return res[i].Location < res[j].Location
+		}
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+// String returns a report of the coverage, listing for each call site the branches
+// selected and the hints executed.
+func (c *Coverage) String() string {
+	branches, hints := c.Branches(), c.Hints()
+	covered := 0
+	for i := range branches {
+		if branches[i].Covered() {
+			covered++
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "branch coverage: %d/%d call sites with all branches selected\n", covered, len(branches))
+	w := tabwriter.NewWriter(&sb, 0, 4, 2, ' ', 0)
+	for _, b := range branches {
+		status := "covered"
+		if missing := b.Missing(); len(missing) != 0 {
+			status = fmt.Sprintf("missing %v", missing)
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%v\t%s\n", b.Location, b.Kind, b.Taken, status)
+	}
+	w.Flush()
+
+	fmt.Fprintf(&sb, "hint coverage: %d call sites executed\n", len(hints))
+	w = tabwriter.NewWriter(&sb, 0, 4, 2, ' ', 0)
+	for _, h := range hints {
+		fmt.Fprintf(w, "  %s\t%s\t%d executions\t%d errors\n", h.Location, h.Name, h.Executions, h.Errors)
+	}
+	w.Flush()
+	return sb.String()
+}
+
+func (c *Coverage) recordBranch(kind, location string, branch, nbBranches int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	key := branchKey{kind: kind, location: location}
+	b, ok := c.branches[key]
+	if !ok {
+		b = &BranchCoverage{Kind: kind, Location: location}
+		c.branches[key] = b
+	}
+	// a call site may select among a varying number of inputs
+	for len(b.Taken) < nbBranches {
+		b.Taken = append(b.Taken, 0)
+	}
+	if branch >= 0 && branch < nbBranches {
+		b.Taken[branch]++
+	}
+}
+
+func (c *Coverage) recordHint(f solver.Hint, location string, err error) {
+	name := solver.GetHintName(f)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	key := hintKey{name: name, location: location}
+	h, ok := c.hints[key]
+	if !ok {
+		h = &HintCoverage{Name: name, Location: location}
+		c.hints[key] = h
+	}
+	h.Executions++
+	if err != nil {
+		h.Errors++
+	}
+}
+
+// callerLocation returns the location of the caller skip frames above the caller of
+// callerLocation, as dir/file.go:line.
+func callerLocation(skip int) string {
+	_, file, line, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s/%s:%d", filepath.Base(filepath.Dir(file)), filepath.Base(file), line)
+}
@@ -0,0 +1,70 @@
+package test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/selector"
+)
+
+func doubleHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	outputs[0].Lsh(inputs[0], 1)
+	return nil
+}
+
+type coverageCircuit struct {
+	B0, B1, Sel, X frontend.Variable
+}
+
+func (c *coverageCircuit) Define(api frontend.API) error {
+	s := api.Select(c.B0, 1, 2)
+	l := api.Lookup2(c.B0, c.B1, 3, 4, 5, 6)
+	m := selector.Mux(api, c.Sel, 7, 8, 9)
+	res, err := api.Compiler().NewHint(doubleHint, 1, c.X)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(res[0], api.Add(c.X, c.X))
+	api.AssertIsDifferent(api.Add(s, l, m), 0)
+	return nil
+}
+
+func TestCoverage(t *testing.T) {
+	assert := NewAssert(t)
+	cov := NewCoverage()
+	assert.CheckCircuit(&coverageCircuit{},
+		WithCoverage(cov),
+		WithBackends(backend.PLONK),
+		WithSolverOpts(solver.WithHints(doubleHint)),
+		WithCurves(ecc.BN254),
+		WithValidAssignment(&coverageCircuit{B0: 1, B1: 0, Sel: 0, X: 3}),
+		WithValidAssignment(&coverageCircuit{B0: 0, B1: 1, Sel: 2, X: 4}),
+	)
+
+	branches := cov.Branches()
+	assert.Len(branches, 3)
+	taken := make(map[string][]int)
+	for _, b := range branches {
+		if strings.HasPrefix(b.Location, "test/coverage_test.go") {
+			taken[b.Kind] = b.Taken
+		}
+	}
+	assert.Equal([]int{1, 1}, taken["Select"])
+	assert.Equal([]int{0, 1, 1, 0}, taken["Lookup2"])
+	assert.Equal([]int{1, 0, 1}, taken["Mux"])
+
+	var executions int
+	for _, h := range cov.Hints() {
+		if strings.HasSuffix(h.Name, "doubleHint") {
+			assert.Equal(0, h.Errors)
+			executions += h.Executions
+		}
+	}
+	assert.Equal(2, executions)
+	assert.Contains(cov.String(), "missing [1]")
+}
@@ -59,6 +59,7 @@ type engine struct {
 	kvstore.Store
 	blueprints        []constraint.Blueprint
 	internalVariables []*big.Int
+	coverage          *Coverage
 }
 
 // TestEngineOption defines an option for the test engine.
@@ -88,6 +89,15 @@ func WithBackendProverOptions(opts ...backend.ProverOption) TestEngineOption {
 	}
 }
 
+// WithEngineCoverage is a test engine option which records the branches selected by the
+// calls to Select, Lookup2 and selector.Mux, and the hints executed, in cov.
+func WithEngineCoverage(cov *Coverage) TestEngineOption {
+	return func(e *engine) error {
+		e.coverage = cov
+		return nil
+	}
+}
+
 // IsSolved returns an error if the test execution engine failed to execute the given circuit
 // with provided witness as input.
 //
@@ -404,6 +414,7 @@ func (e *engine) And(i1, i2 frontend.Variable) frontend.Variable {
 func (e *engine) Select(b frontend.Variable, i1, i2 frontend.Variable) frontend.Variable {
 	b1 := e.toBigInt(b)
 	e.mustBeBoolean(b1)
+	e.recordBranch("Select", 1-int(b1.Uint64()), 2, 1)
 
 	if b1.Uint64() == 1 {
 		return e.toBigInt(i1)
@@ -421,6 +432,7 @@ func (e *engine) Lookup2(b0, b1 frontend.Variable, i0, i1, i2, i3 frontend.Varia
 	e.mustBeBoolean(s1)
 	lookup := new(big.Int).Lsh(s1, 1)
 	lookup.Or(lookup, s0)
+	e.recordBranch("Lookup2", int(lookup.Uint64()), 4, 1)
 	return e.toBigInt([]frontend.Variable{i0, i1, i2, i3}[lookup.Uint64()])
 }
 
@@ -529,7 +541,12 @@ func (e *engine) print(sbb *strings.Builder, x interface{}) {
 }
 
 func (e *engine) NewHint(f solver.Hint, nbOutputs int, inputs ...frontend.Variable) ([]frontend.Variable, error) {
+	return e.newHint(f, nbOutputs, inputs, 1)
+}
 
+// newHint executes the hint f; skip is the number of frames above the caller of newHint
+// of the call site of the hint, for the coverage.
+func (e *engine) newHint(f solver.Hint, nbOutputs int, inputs []frontend.Variable, skip int) ([]frontend.Variable, error) {
 	if nbOutputs <= 0 {
 		return nil, fmt.Errorf("hint function must return at least one output")
 	}
@@ -545,6 +562,9 @@ func (e *engine) NewHint(f solver.Hint, nbOutputs int, inputs ...frontend.Variab
 	}
 
 	err := f(e.Field(), in, res)
+	if e.coverage != nil {
+		e.coverage.recordHint(f, callerLocation(skip+1), err)
+	}
 
 	if err != nil {
 		panic("NewHint: " + err.Error())
@@ -561,12 +581,35 @@ func (e *engine) NewHint(f solver.Hint, nbOutputs int, inputs ...frontend.Variab
 
 func (e *engine) NewHintForId(id solver.HintID, nbOutputs int, inputs ...frontend.Variable) ([]frontend.Variable, error) {
 	if f := solver.GetRegisteredHint(id); f != nil {
-		return e.NewHint(f, nbOutputs, inputs...)
+		return e.newHint(f, nbOutputs, inputs, 1)
 	}
 
 	return nil, fmt.Errorf("no hint registered with id #%d. Use solver.RegisterHint or solver.RegisterNamedHint", id)
 }
 
+// RecordBranch implements coverage.BranchRecorder.
+func (e *engine) RecordBranch(kind string, sel frontend.Variable, nbBranches int) {
+	if e.coverage == nil {
+		return
+	}
+	// the gadget calls coverage.RecordBranch, which calls us
+	v := e.toBigInt(sel)
+	branch := -1
+	if v.IsUint64() && v.Uint64() < uint64(nbBranches) {
+		branch = int(v.Uint64())
+	}
+	e.recordBranch(kind, branch, nbBranches, 3)
+}
+
+// recordBranch records the branch selected by a gadget; skip is the number of frames
+// above the caller of recordBranch of the call site of the gadget.
+func (e *engine) recordBranch(kind string, branch, nbBranches, skip int) {
+	if e.coverage == nil {
+		return
+	}
+	e.coverage.recordBranch(kind, callerLocation(skip+1), branch, nbBranches)
+}
+
 // IsConstant returns true if v is a constant known at compile time
 func (e *engine) IsConstant(v frontend.Variable) bool {
 	return e.constVars
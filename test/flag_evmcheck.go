@@ -0,0 +1,5 @@
+//go:build evmcheck
+
+package test
+
+const EVMCheck = true